@@ -0,0 +1,39 @@
+package main
+
+import (
+	"flag"
+
+	"github.com/go-mclib/client/pkg/client/modules/physics"
+	"github.com/go-mclib/client/pkg/client/modules/self"
+	"github.com/go-mclib/client/pkg/helpers"
+)
+
+// runAfk connects and idles, reconnecting indefinitely. With -jiggle it
+// looks around a little every 30-60s (reproducible with -seed).
+func runAfk(args []string) {
+	fs := flag.NewFlagSet("afk", flag.ExitOnError)
+	var f helpers.Flags
+	helpers.RegisterFlagsOn(fs, &f)
+	jiggle := fs.Bool("jiggle", false, "look around periodically to avoid AFK kicks")
+	fs.Parse(args)
+
+	f.MaxReconnectAttempts = -1
+
+	c := helpers.NewClient(f)
+
+	if *jiggle {
+		s := self.From(c)
+		rng := c.Rand("afk")
+		nextJiggle := func() int { return 600 + rng.IntN(600) }
+		ticks, next := 0, nextJiggle()
+		physics.From(c).OnTick(func() {
+			if ticks++; ticks < next {
+				return
+			}
+			ticks, next = 0, nextJiggle()
+			s.Rotate(rng.Between(-30, 30), rng.NormFloat64()*5)
+		})
+	}
+
+	helpers.Run(c)
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/go-mclib/client/pkg/auth"
+	"github.com/go-mclib/client/pkg/client"
+	"github.com/go-mclib/client/pkg/client/modules/chat"
+	"github.com/go-mclib/client/pkg/client/modules/self"
+	"github.com/go-mclib/client/pkg/helpers"
+	ns "github.com/go-mclib/protocol/java_protocol/net_structures"
+)
+
+// runChatbot answers a few chat commands addressed with a prefix (default "!").
+// !ping, !pos and !health are public, !say needs the trusted role.
+func runChatbot(args []string) {
+	fs := flag.NewFlagSet("chatbot", flag.ExitOnError)
+	var f helpers.Flags
+	helpers.RegisterFlagsOn(fs, &f)
+	prefix := fs.String("prefix", "!", "command prefix")
+	owners := fs.String("owners", "", "comma-separated UUIDs of owners")
+	trusted := fs.String("trusted", "", "comma-separated UUIDs of trusted players")
+	fs.Parse(args)
+
+	c := helpers.NewClient(f)
+	ch := chat.From(c)
+	s := self.From(c)
+
+	a := auth.New()
+	a.Audit = log.New(os.Stderr, "", log.LstdFlags)
+	grantAll(a, *owners, auth.RoleOwner)
+	grantAll(a, *trusted, auth.RoleTrusted)
+	for _, cmd := range []string{"ping", "pos", "health", "help"} {
+		a.Require(cmd, auth.RolePublic)
+	}
+	a.Require("say", auth.RoleTrusted)
+
+	// the "chatbot" config section can retune the rate limit without a restart:
+	//	"chatbot": {"rate_limit": 3, "rate_window": "30s"}
+	c.OnConfigReload(func(cfg *client.Config) {
+		var sec struct {
+			RateLimit  *int             `json:"rate_limit"`
+			RateWindow *client.Duration `json:"rate_window"`
+		}
+		if ok, err := cfg.Section("chatbot", &sec); !ok || err != nil {
+			if err != nil {
+				c.Logger.Println(err)
+			}
+			return
+		}
+		limit, window := a.RateLimit, a.RateWindow
+		if sec.RateLimit != nil {
+			limit = *sec.RateLimit
+		}
+		if sec.RateWindow != nil {
+			window = time.Duration(*sec.RateWindow)
+		}
+		a.SetRateLimit(limit, window)
+	})
+
+	ch.OnPlayerChatFrom(func(uuid [16]byte, sender, message string, isWhisper bool) {
+		cmd, ok := strings.CutPrefix(strings.TrimSpace(message), *prefix)
+		if !ok {
+			return
+		}
+		name, arg, _ := strings.Cut(cmd, " ")
+		name = strings.ToLower(name)
+
+		reply := func(msg string) {
+			if isWhisper {
+				_ = ch.SendCommand("msg " + sender + " " + msg)
+			} else {
+				_ = ch.SendMessage(msg)
+			}
+		}
+
+		switch name {
+		case "ping", "pos", "health", "say":
+		default:
+			name = "help"
+		}
+		if err := a.Authorize(auth.Player(uuid, sender), name, arg); err != nil {
+			// stay quiet when rate limited so spam doesn't make us spam
+			if errors.Is(err, auth.ErrForbidden) {
+				reply(fmt.Sprintf("%s: you can't use %s%s", sender, *prefix, name))
+			}
+			return
+		}
+
+		var msg string
+		switch name {
+		case "ping":
+			msg = "pong"
+		case "pos":
+			x, y, z := s.Position()
+			msg = fmt.Sprintf("I'm at %.1f, %.1f, %.1f", x, y, z)
+		case "health":
+			msg = fmt.Sprintf("health %.1f, food %d", s.Health(), s.Food())
+		case "say":
+			msg = arg
+		default:
+			msg = fmt.Sprintf("commands: %[1]sping, %[1]spos, %[1]shealth, %[1]ssay <text>", *prefix)
+		}
+		if msg != "" {
+			reply(msg)
+		}
+	})
+
+	helpers.Run(c)
+}
+
+// grantAll grants role to each UUID in a comma-separated list.
+func grantAll(a *auth.Authorizer, list string, role auth.Role) {
+	for _, s := range strings.Split(list, ",") {
+		if s = strings.TrimSpace(s); s == "" {
+			continue
+		}
+		uuid, err := ns.UUIDFromString(s)
+		if err != nil {
+			log.Fatalf("invalid UUID %q: %v", s, err)
+		}
+		a.GrantPlayer([16]byte(uuid), role)
+	}
+}
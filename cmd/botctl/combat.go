@@ -9,6 +9,7 @@ import (
 	"github.com/go-mclib/client/pkg/client/modules/inventory"
 	"github.com/go-mclib/client/pkg/client/modules/physics"
 	"github.com/go-mclib/client/pkg/client/modules/self"
+	"github.com/go-mclib/client/pkg/client/modules/sounds"
 	"github.com/go-mclib/client/pkg/helpers"
 	dataEntities "github.com/go-mclib/data/pkg/data/entities"
 	"github.com/go-mclib/data/pkg/packets"
@@ -17,18 +18,30 @@ import (
 
 const baseAttackSpeed = 4.0
 
-func main() {
+// runCombat attacks the nearest attackable entity whenever the cooldown allows.
+func runCombat(args []string) {
+	fs := flag.NewFlagSet("combat", flag.ExitOnError)
 	var f helpers.Flags
-	helpers.RegisterFlags(&f)
-	rotate := flag.Bool("rotate", false, "rotate to face nearest entity before attacking")
-	flag.Parse()
+	helpers.RegisterFlagsOn(fs, &f)
+	rotate := fs.Bool("rotate", false, "rotate to face nearest entity before attacking")
+	fs.Parse(args)
 
 	c := helpers.NewClient(f)
 	c.Register(entities.New())
 	c.Register(combat.New())
 	c.Register(inventory.New())
+	c.Register(sounds.New())
+
+	sounds.From(c).OnSuspectedInvisibleEntity(func(sus sounds.Suspect) {
+		c.Logger.Printf("heard something invisible at %.1f, %.1f, %.1f (%s)", sus.X, sus.Y, sus.Z, sus.Sound)
+	})
 
 	ents := entities.From(c)
+	ents.OnTelegraphedAttack(func(entityID int32, kind entities.TelegraphKind) {
+		if e := ents.GetEntity(entityID); e != nil {
+			c.Logger.Printf("%s is starting a %s attack", e.TypeName, kind)
+		}
+	})
 	com := combat.From(c)
 	inv := inventory.From(c)
 	s := self.From(c)
@@ -98,8 +111,7 @@ func attackCooldownTicks(inv *inventory.Module) float32 {
 // crosshairHits checks whether a ray from the bot's eye along its look direction
 // intersects the entity's AABB within attack range (slab method).
 func crosshairHits(s *self.Module, e *entities.Entity) bool {
-	ox, oy, oz := s.Position()
-	oy += self.EyeHeight
+	ox, oy, oz := s.EyePosition()
 
 	yaw, pitch := s.Rotation()
 	yawRad := float64(yaw) * math.Pi / 180
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+
+	"github.com/go-mclib/client/pkg/client"
+	"github.com/go-mclib/client/pkg/client/modules/entities"
+	"github.com/go-mclib/client/pkg/client/modules/inventory"
+	"github.com/go-mclib/client/pkg/client/modules/pathfinding"
+	"github.com/go-mclib/client/pkg/client/modules/self"
+	"github.com/go-mclib/client/pkg/client/modules/world"
+	"github.com/go-mclib/client/pkg/geom"
+)
+
+// runDump prints a summary of a state dump written with -dump. With
+// -packets it lists the recorded packets too.
+func runDump(args []string) {
+	fs := flag.NewFlagSet("dump", flag.ExitOnError)
+	packets := fs.Bool("packets", false, "list the recorded packets")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: botctl dump [-packets] <file>")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	d, err := client.LoadDump(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("%s on %s at %s (state %v, seed %d)\n", d.Info.Username, d.Info.Address,
+		d.Info.Time.Format("2006-01-02 15:04:05"), d.Info.State, d.Info.Seed)
+	fmt.Printf("modules: %v\n", d.Info.Modules)
+	for _, e := range d.Errors {
+		fmt.Printf("dump failed: %s\n", e)
+	}
+	if len(d.Config) > 0 {
+		names := make([]string, 0, len(d.Config))
+		for name := range d.Config {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		fmt.Printf("config sections: %v\n", names)
+	}
+
+	var s self.StateDump
+	haveSelf := decodeSection(d, self.ModuleName, &s)
+	if haveSelf {
+		fmt.Printf("player: %.2f %.2f %.2f in %s, health %.1f, food %d, pose %v, %d effects\n",
+			s.X, s.Y, s.Z, s.Dimension, s.Health, s.Food, s.Pose, len(s.Effects))
+	}
+	if data, ok := d.Modules[world.ModuleName]; ok {
+		if r, err := world.DecodeDumpRegion(data); err != nil {
+			fmt.Printf("world: %v\n", err)
+		} else {
+			dx, dy, dz := r.Bounds.Size()
+			loaded := 0
+			for _, state := range r.States {
+				if state >= 0 {
+					loaded++
+				}
+			}
+			fmt.Printf("world: %dx%dx%d blocks from %v, %d loaded\n", dx, dy, dz, r.Bounds.Min, loaded)
+			if haveSelf {
+				feet := geom.BlockPos{X: int(math.Floor(s.X)), Y: int(math.Floor(s.Y)) - 1, Z: int(math.Floor(s.Z))}
+				fmt.Printf("  block below player: state %d\n", r.At(feet))
+			}
+		}
+	}
+	var ents []entities.Entity
+	if decodeSection(d, entities.ModuleName, &ents) {
+		fmt.Printf("entities: %d\n", len(ents))
+	}
+	var inv inventory.StateDump
+	if decodeSection(d, inventory.ModuleName, &inv) {
+		fmt.Printf("inventory: %d slots used, hotbar slot %d\n", len(inv.Slots), inv.HeldSlot)
+		if inv.Container != nil {
+			fmt.Printf("  open container %q with %d slots used\n", inv.Container.Title, len(inv.Container.Slots))
+		}
+	}
+	var nav pathfinding.StateDump
+	if decodeSection(d, pathfinding.ModuleName, &nav) {
+		fmt.Printf("pathfinding: navigating %v to %.1f %.1f %.1f, node %d/%d, %d failed searches kept\n",
+			nav.Navigating, nav.Goal.X, nav.Goal.Y, nav.Goal.Z, nav.PathIndex, len(nav.Path), len(nav.FailedSearches))
+		if nav.LastSearch != nil {
+			fmt.Printf("  last search %v -> %v explored %d nodes %s\n", nav.LastSearch.Start, nav.LastSearch.Goal,
+				len(nav.LastSearch.Explored), nav.LastSearch.Err)
+		}
+	}
+
+	fmt.Printf("packets: %d recorded\n", len(d.Packets))
+	if *packets {
+		for _, p := range d.Packets {
+			note := ""
+			if p.Truncated() {
+				note = fmt.Sprintf(" (cut from %d)", p.Length)
+			}
+			fmt.Printf("  %s %v 0x%02x %d bytes%s\n", p.Time.Format("15:04:05.000"), p.State, p.ID, len(p.Data), note)
+		}
+	}
+}
+
+// decodeSection decodes a module's JSON section, reporting whether it was
+// there and valid.
+func decodeSection(d *client.Dump, name string, v any) bool {
+	data, ok := d.Modules[name]
+	if !ok {
+		return false
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		fmt.Printf("%s: %v\n", name, err)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,54 @@
+// Command botctl runs the example bots as subcommands sharing the standard
+// helpers flags:
+//
+//	botctl <subcommand> [flags]
+//	botctl afk -s localhost:25565 -u Bot -online=false
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+)
+
+type subcommand struct {
+	usage string
+	run   func(args []string)
+}
+
+var subcommands = map[string]subcommand{
+	"afk":      {"connect and idle, reconnecting forever", runAfk},
+	"chatbot":  {"answer !ping, !pos, !health and !say in chat", runChatbot},
+	"combat":   {"attack the nearest attackable entity (-rotate to aim)", runCombat},
+	"dump":     {"summarize a state dump written with -dump", runDump},
+	"pathfind": {`walk to players who say "come"`, runPathfind},
+	"sorter":   {`sort items from "filter me" chests into labelled chests`, runSorter},
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+	cmd, ok := subcommands[os.Args[1]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown subcommand %q\n\n", os.Args[1])
+		usage()
+		os.Exit(2)
+	}
+	cmd.run(os.Args[2:])
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: botctl <subcommand> [flags]")
+	fmt.Fprintln(os.Stderr, "\nsubcommands:")
+	names := make([]string, 0, len(subcommands))
+	for name := range subcommands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %-9s %s\n", name, subcommands[name].usage)
+	}
+	fmt.Fprintln(os.Stderr, "\nrun 'botctl <subcommand> -h' for flags")
+}
@@ -4,6 +4,7 @@ import (
 	"flag"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/go-mclib/client/pkg/client"
 	"github.com/go-mclib/client/pkg/client/modules/chat"
@@ -17,10 +18,12 @@ import (
 	jp "github.com/go-mclib/protocol/java_protocol"
 )
 
-func main() {
+// runPathfind walks to players who say "come" in chat.
+func runPathfind(args []string) {
+	fs := flag.NewFlagSet("pathfind", flag.ExitOnError)
 	var f helpers.Flags
-	helpers.RegisterFlags(&f)
-	flag.Parse()
+	helpers.RegisterFlagsOn(fs, &f)
+	fs.Parse(args)
 
 	f.MaxReconnectAttempts = -1
 
@@ -68,7 +71,16 @@ func main() {
 		e := ents.GetEntityByUUID(senderUUID)
 		if e == nil {
 			c.Logger.Printf("%s said 'come' but is not in render distance", senderName)
-			ch.SendMessage(fmt.Sprintf("I can't see you, %s!", senderName))
+			last, ok := ents.LastSeen(senderUUID)
+			if !ok {
+				ch.SendMessage(fmt.Sprintf("I can't see you, %s!", senderName))
+				return
+			}
+			ago := time.Since(last.LastSeen).Round(time.Second)
+			ch.SendMessage(fmt.Sprintf("I can't see you, %s! Heading to where I last saw you %s ago.", senderName, ago))
+			if err := pf.NavigateTo(last.X, last.Y, last.Z); err != nil {
+				c.Logger.Printf("pathfinding error: %v", err)
+			}
 			return
 		}
 
@@ -1,27 +1,34 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"flag"
+	"fmt"
+	"log"
 	"math"
+	"os"
 	"slices"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/go-mclib/client/pkg/behaviors"
 	"github.com/go-mclib/client/pkg/client"
 	"github.com/go-mclib/client/pkg/client/modules/collisions"
+	"github.com/go-mclib/client/pkg/client/modules/combat"
 	"github.com/go-mclib/client/pkg/client/modules/entities"
 	"github.com/go-mclib/client/pkg/client/modules/inventory"
 	"github.com/go-mclib/client/pkg/client/modules/pathfinding"
+	"github.com/go-mclib/client/pkg/client/modules/physics"
 	"github.com/go-mclib/client/pkg/client/modules/self"
 	"github.com/go-mclib/client/pkg/client/modules/world"
+	"github.com/go-mclib/client/pkg/geom"
 	"github.com/go-mclib/client/pkg/helpers"
 	"github.com/go-mclib/data/pkg/data/blocks"
 	dataEntities "github.com/go-mclib/data/pkg/data/entities"
 	"github.com/go-mclib/data/pkg/data/items"
 	ns "github.com/go-mclib/protocol/java_protocol/net_structures"
-	"github.com/go-mclib/protocol/nbt"
 )
 
 var containerBlockIDs = []int32{
@@ -96,16 +103,14 @@ var (
 )
 
 const (
-	signBlockEntityType   = 7
-	hangingSignEntityType = 8
-	blockReach            = 4.5
-	scanRadius            = 128
-	filterSignText        = "filter me"
-	trashSignText         = "trash"
-	filterDebounce        = 3 * time.Second
-	itemPollInterval      = 200 * time.Millisecond
-	rebuildInterval       = 10 * time.Second
-	hungerThreshold       = 18 // food level (0-20) below which the bot pauses to eat
+	blockReach       = 4.5
+	scanRadius       = 128
+	filterSignText   = "filter me"
+	trashSignText    = "trash"
+	filterDebounce   = 3 * time.Second
+	itemPollInterval = 200 * time.Millisecond
+	rebuildInterval  = 10 * time.Second
+	hungerThreshold  = 18 // food level (0-20) below which the bot eats
 )
 
 func init() {
@@ -124,8 +129,6 @@ func init() {
 	}
 }
 
-type blockPos struct{ x, y, z int }
-
 // sorter scans for labeled chests and automatically sorts items into them.
 //
 // Chests labeled with item frames or signs map items to destinations.
@@ -136,7 +139,7 @@ type blockPos struct{ x, y, z int }
 // categoryMatcher pairs a match function with a destination chest.
 type categoryMatcher struct {
 	match func(string) bool
-	pos   blockPos
+	pos   geom.BlockPos
 }
 
 type sorter struct {
@@ -145,14 +148,15 @@ type sorter struct {
 	s    *self.Module
 	w    *world.Module
 	pf   *pathfinding.Module
+	p    *physics.Module
 	col  *collisions.Module
 	ents *entities.Module
 
 	mu               sync.Mutex
-	labelMap         map[int32]blockPos // item ID -> destination chest
-	categoryMatchers []categoryMatcher  // pattern-based categories
-	filterChests     []blockPos         // "filter me" input chests
-	trashChest       *blockPos          // "trash" chest for unlabeled items
+	labelMap         map[int32]geom.BlockPos // item ID -> destination chest
+	categoryMatchers []categoryMatcher       // pattern-based categories
+	filterChests     []geom.BlockPos         // "filter me" input chests
+	trashChest       *geom.BlockPos          // "trash" chest for unlabeled items
 
 	navCh       chan bool     // current navigation result channel
 	containerCh chan struct{} // current container-open signal channel
@@ -169,9 +173,10 @@ func newSorter(c *client.Client) *sorter {
 		s:        self.From(c),
 		w:        world.From(c),
 		pf:       pathfinding.From(c),
+		p:        physics.From(c),
 		col:      collisions.From(c),
 		ents:     entities.From(c),
-		labelMap: make(map[int32]blockPos),
+		labelMap: make(map[int32]geom.BlockPos),
 		trigger:  make(chan struct{}, 1),
 		closeCh:  make(chan struct{}, 1),
 	}
@@ -195,6 +200,15 @@ func (sr *sorter) navigateTo(x, y, z float64, timeout time.Duration) bool {
 		return false
 	}
 
+	// eat on the way instead of stopping for it, but be done before the
+	// caller opens a chest
+	eaten := make(chan struct{})
+	go func() {
+		defer close(eaten)
+		sr.eatIfHungry()
+	}()
+	defer func() { <-eaten }()
+
 	select {
 	case reached := <-ch:
 		return reached
@@ -206,11 +220,11 @@ func (sr *sorter) navigateTo(x, y, z float64, timeout time.Duration) bool {
 }
 
 // openChest navigates to a reachable position and interacts with the chest.
-func (sr *sorter) openChest(pos blockPos) bool {
+func (sr *sorter) openChest(pos geom.BlockPos) bool {
 	sx, sy, sz := sr.s.Position()
-	standX, standY, standZ, found := pathfinding.FindReachablePosition(sr.col, sx, sy, sz, pos.x, pos.y, pos.z, blockReach)
+	standX, standY, standZ, found := pathfinding.FindReachablePosition(sr.col, sx, sy, sz, pos.X, pos.Y, pos.Z, blockReach)
 	if !found {
-		sr.c.Logger.Printf("no reachable position for chest at %d,%d,%d", pos.x, pos.y, pos.z)
+		sr.c.Logger.Printf("no reachable position for chest at %d,%d,%d", pos.X, pos.Y, pos.Z)
 		return false
 	}
 
@@ -230,15 +244,12 @@ func (sr *sorter) openChest(pos blockPos) bool {
 }
 
 // interactChest looks at a chest and opens it (assumes already in range).
-func (sr *sorter) interactChest(pos blockPos) bool {
+func (sr *sorter) interactChest(pos geom.BlockPos) bool {
 	if sr.inv.ContainerOpen() {
 		sr.closeContainer()
 		time.Sleep(100 * time.Millisecond)
 	}
 
-	sr.s.LookAt(float64(pos.x)+0.5, float64(pos.y)+0.5, float64(pos.z)+0.5)
-	time.Sleep(50 * time.Millisecond)
-
 	ch := make(chan struct{}, 1)
 	sr.mu.Lock()
 	sr.containerCh = ch
@@ -249,19 +260,39 @@ func (sr *sorter) interactChest(pos blockPos) bool {
 		sr.mu.Unlock()
 	}()
 
-	if err := sr.c.InteractBlock(pos.x, pos.y, pos.z, 1, 0, 0.5, 0.5, 0.5); err != nil {
-		sr.c.Logger.Printf("interact failed: %v", err)
-		return false
+	// click the face visible from here; fall back to the top center
+	face, cursor := geom.FaceTop, geom.Vec3{X: 0.5, Y: 0.5, Z: 0.5}
+	x, y, z := sr.s.EyePosition()
+	if f, c, ok := pathfinding.FindReachFace(sr.col, x, y, z, pos, blockReach); ok {
+		face, cursor = f, c
 	}
 
-	select {
-	case <-ch:
-		time.Sleep(100 * time.Millisecond)
-		return true
-	case <-time.After(3 * time.Second):
-		sr.c.Logger.Printf("chest open timed out at %d,%d,%d", pos.x, pos.y, pos.z)
+	res := sr.w.Interact(world.Interaction{
+		Pos:     pos,
+		Face:    face,
+		CursorX: float32(cursor.X), CursorY: float32(cursor.Y), CursorZ: float32(cursor.Z),
+		Expect: func(stateID int32) bool {
+			blockID, _ := blocks.StateProperties(int(stateID))
+			return isContainer(blockID)
+		},
+		Prepare: func() {
+			sr.s.LookAt(float64(pos.X)+cursor.X, float64(pos.Y)+cursor.Y, float64(pos.Z)+cursor.Z)
+		},
+		Confirm: func() bool {
+			select {
+			case <-ch:
+				return true
+			case <-time.After(3 * time.Second):
+				return false
+			}
+		},
+	})
+	if res.Err != nil {
+		sr.c.Logger.Printf("chest open failed at %v after %d attempts: %v (last effect: %v)", pos, res.Attempts, res.Err, res.Effect)
 		return false
 	}
+	time.Sleep(100 * time.Millisecond)
+	return true
 }
 
 func (sr *sorter) closeContainer() {
@@ -344,7 +375,7 @@ func (sr *sorter) depositItem(itemID int32) (moved int, full bool) {
 		if item == nil || item.IsEmpty() || item.ID != itemID {
 			continue
 		}
-		if !sr.containerHasSpace(itemID) {
+		if !sr.containerHasSpace(item) {
 			sr.c.Logger.Println("chest is full")
 			return moved, true
 		}
@@ -360,13 +391,11 @@ func (sr *sorter) depositItem(itemID int32) (moved int, full bool) {
 	return moved, false
 }
 
-func (sr *sorter) containerHasSpace(itemID int32) bool {
+// containerHasSpace reports whether at least one of item fits into the open
+// container (an empty slot, or a stack of the same item with the same components).
+func (sr *sorter) containerHasSpace(item *items.ItemStack) bool {
 	for i := range sr.inv.ContainerSlotCount() {
-		cs := sr.inv.ContainerSlot(i)
-		if cs == nil || cs.IsEmpty() {
-			return true
-		}
-		if cs.ID == itemID && cs.Components != nil && cs.Count < cs.Components.MaxStackSize {
+		if inventory.RoomFor(sr.inv.ContainerSlot(i), item) > 0 {
 			return true
 		}
 	}
@@ -459,13 +488,18 @@ func (sr *sorter) requestSort() {
 
 // processFilterChest opens a filter chest, waits for items (camping with the
 // window open), debounces, and takes everything.
-func (sr *sorter) processFilterChest(pos blockPos) {
+func (sr *sorter) processFilterChest(pos geom.BlockPos) {
 	if !sr.openChest(pos) {
 		return
 	}
 
+	// stay put while camping so other players and mobs can't push us out of reach
+	x, y, z := sr.s.Position()
+	sr.p.HoldPosition(geom.Vec3{X: x, Y: y, Z: z}, 0.25)
+	defer sr.p.ReleasePosition()
+
 	if !sr.waitForItems() {
-		sr.c.Logger.Printf("filter chest at %d,%d,%d closed", pos.x, pos.y, pos.z)
+		sr.c.Logger.Printf("filter chest at %d,%d,%d closed", pos.X, pos.Y, pos.Z)
 		return
 	}
 
@@ -475,7 +509,7 @@ func (sr *sorter) processFilterChest(pos blockPos) {
 
 	taken := sr.takeAllFromContainer()
 	if taken > 0 {
-		sr.c.Logger.Printf("collected %d stacks from filter chest at %d,%d,%d", taken, pos.x, pos.y, pos.z)
+		sr.c.Logger.Printf("collected %d stacks from filter chest at %d,%d,%d", taken, pos.X, pos.Y, pos.Z)
 	}
 	sr.closeContainer()
 }
@@ -498,7 +532,7 @@ func (sr *sorter) depositAll() {
 	var overflowIDs []int32
 
 	// collect unique chest positions (exclude trash — handled separately)
-	remaining := make(map[blockPos]bool, len(groups))
+	remaining := make(map[geom.BlockPos]bool, len(groups))
 	for pos := range groups {
 		if trashChest != nil && pos == *trashChest {
 			continue // trash is deposited last
@@ -507,9 +541,9 @@ func (sr *sorter) depositAll() {
 	}
 
 	for len(remaining) > 0 {
-		var targets [][3]int
+		var targets []geom.BlockPos
 		for pos := range remaining {
-			targets = append(targets, [3]int{pos.x, pos.y, pos.z})
+			targets = append(targets, pos)
 		}
 
 		sx, sy, sz := sr.s.Position()
@@ -533,8 +567,7 @@ func (sr *sorter) depositAll() {
 		sr.pf.Stop()
 		time.Sleep(200 * time.Millisecond)
 
-		for _, t := range reachable {
-			pos := blockPos{t[0], t[1], t[2]}
+		for _, pos := range reachable {
 			itemIDs := groups[pos]
 			if len(itemIDs) == 0 {
 				delete(remaining, pos)
@@ -560,8 +593,8 @@ func (sr *sorter) depositAll() {
 			delete(remaining, pos)
 		}
 
-		for _, t := range reachable {
-			delete(remaining, blockPos{t[0], t[1], t[2]})
+		for _, pos := range reachable {
+			delete(remaining, pos)
 		}
 	}
 
@@ -601,11 +634,11 @@ func (sr *sorter) depositAll() {
 // groupSortableItems returns items in the player inventory grouped by their
 // destination chest. Checks exact ID labels first, then category matchers,
 // then falls back to trash.
-func (sr *sorter) groupSortableItems() map[blockPos][]int32 {
+func (sr *sorter) groupSortableItems() map[geom.BlockPos][]int32 {
 	sr.mu.Lock()
 	defer sr.mu.Unlock()
 
-	groups := make(map[blockPos][]int32)
+	groups := make(map[geom.BlockPos][]int32)
 	seen := make(map[int32]bool)
 
 	for i := range 36 {
@@ -649,18 +682,17 @@ func (sr *sorter) groupSortableItems() map[blockPos][]int32 {
 // --- label map ---
 
 // buildLabelMap scans nearby item frames and signs within scanRadius to map
-// items to destination chests. Uses individual GetBlock calls instead of
-// FindBlocks to avoid holding the world RLock for long durations.
+// items to destination chests.
 func (sr *sorter) buildLabelMap() {
 	sx, sy, sz := sr.s.Position()
 	cx := int(math.Floor(sx))
 	cy := int(math.Floor(sy))
 	cz := int(math.Floor(sz))
 
-	labelMap := make(map[int32]blockPos)
+	labelMap := make(map[int32]geom.BlockPos)
 	var matchers []categoryMatcher
-	var filterChests []blockPos
-	var trashChest *blockPos
+	var filterChests []geom.BlockPos
+	var trashChest *geom.BlockPos
 
 	// scan item frames within radius
 	for _, typeID := range []int32{dataEntities.EntityTypeID("minecraft:item_frame"), dataEntities.EntityTypeID("minecraft:glow_item_frame")} {
@@ -679,29 +711,32 @@ func (sr *sorter) buildLabelMap() {
 			}
 			if pos, ok := findContainerNear(sr.w, ex, ey, ez); ok {
 				labelMap[stack.ID] = pos
-				sr.c.Logger.Printf("label: %s -> %d,%d,%d (item frame)", items.ItemName(stack.ID), pos.x, pos.y, pos.z)
+				sr.c.Logger.Printf("label: %s -> %d,%d,%d (item frame)", items.ItemName(stack.ID), pos.X, pos.Y, pos.Z)
 			}
 		}
 	}
 
-	// scan blocks within radius for signs (individual GetBlock calls to
-	// avoid holding the world RLock for the entire chunk set)
-	minY := max(cy-scanRadius, -64)
-	maxY := min(cy+scanRadius, 319)
-	for x := cx - scanRadius; x <= cx+scanRadius; x++ {
-		for z := cz - scanRadius; z <= cz+scanRadius; z++ {
-			for y := minY; y <= maxY; y++ {
-				stateID := sr.w.GetBlock(x, y, z)
-				if stateID == 0 {
-					continue
-				}
-				blockID, _ := blocks.StateProperties(int(stateID))
-				if !wallSignBlockIDs[blockID] {
-					continue
-				}
-				sr.processSignAt(x, y, z, stateID, labelMap, &matchers, &filterChests, &trashChest)
-			}
-		}
+	// scan blocks within radius of the player's chunk section for signs;
+	// the world keeps the scan current, so rebuilding from the same section
+	// doesn't rescan
+	section := geom.BlockPos{X: cx&^15 + 8, Y: cy&^15 + 8, Z: cz&^15 + 8}
+	signs, err := sr.w.Scan(context.Background(), world.ScanJob{
+		Key: "sorter:signs",
+		Match: func(stateID int32) bool {
+			blockID, _ := blocks.StateProperties(int(stateID))
+			return wallSignBlockIDs[blockID]
+		},
+		Region: geom.NewCuboid(
+			section.Offset(-scanRadius, -scanRadius, -scanRadius),
+			section.Offset(scanRadius, scanRadius, scanRadius),
+		),
+	})
+	if err != nil {
+		sr.c.Logger.Printf("sign scan: %v", err)
+		return
+	}
+	for _, hit := range signs {
+		sr.processSignAt(hit.Pos.X, hit.Pos.Y, hit.Pos.Z, hit.State, labelMap, &matchers, &filterChests, &trashChest)
 	}
 
 	sr.mu.Lock()
@@ -714,12 +749,17 @@ func (sr *sorter) buildLabelMap() {
 	sr.c.Logger.Printf("labels: %d items, %d filter chests, trash=%v", len(labelMap), len(filterChests), trashChest != nil)
 }
 
-func (sr *sorter) processSignAt(x, y, z int, stateID int32, labelMap map[int32]blockPos, matchers *[]categoryMatcher, filterChests *[]blockPos, trashChest **blockPos) {
-	be := sr.w.GetBlockEntity(x, y, z)
-	if be == nil || (be.Type != signBlockEntityType && be.Type != hangingSignEntityType) {
+func (sr *sorter) processSignAt(x, y, z int, stateID int32, labelMap map[int32]geom.BlockPos, matchers *[]categoryMatcher, filterChests *[]geom.BlockPos, trashChest **geom.BlockPos) {
+	text, ok := sr.w.SignText(geom.BlockPos{X: x, Y: y, Z: z}, true)
+	if !ok {
 		return
 	}
-	lines := extractSignText(be.Data)
+	var lines []string
+	for _, line := range text {
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
 	if len(lines) == 0 {
 		return
 	}
@@ -734,12 +774,12 @@ func (sr *sorter) processSignAt(x, y, z int, stateID int32, labelMap map[int32]b
 		trimmed := strings.TrimSpace(line)
 		if strings.EqualFold(trimmed, filterSignText) {
 			*filterChests = append(*filterChests, pos)
-			sr.c.Logger.Printf("filter chest at %d,%d,%d", pos.x, pos.y, pos.z)
+			sr.c.Logger.Printf("filter chest at %d,%d,%d", pos.X, pos.Y, pos.Z)
 			return
 		}
 		if strings.EqualFold(trimmed, trashSignText) {
 			*trashChest = &pos
-			sr.c.Logger.Printf("trash chest at %d,%d,%d", pos.x, pos.y, pos.z)
+			sr.c.Logger.Printf("trash chest at %d,%d,%d", pos.X, pos.Y, pos.Z)
 			return
 		}
 	}
@@ -752,14 +792,14 @@ func (sr *sorter) processSignAt(x, y, z int, stateID int32, labelMap map[int32]b
 			cat := strings.ToLower(trimmed[1:])
 			if match, ok := customCategories[cat]; ok {
 				*matchers = append(*matchers, categoryMatcher{match: match, pos: pos})
-				sr.c.Logger.Printf("category: :%s -> %d,%d,%d (sign)", cat, pos.x, pos.y, pos.z)
+				sr.c.Logger.Printf("category: :%s -> %d,%d,%d (sign)", cat, pos.X, pos.Y, pos.Z)
 			}
 			continue
 		}
 
 		for _, itemID := range resolveLabel(trimmed) {
 			labelMap[itemID] = pos
-			sr.c.Logger.Printf("label: %s -> %d,%d,%d (sign)", items.ItemName(itemID), pos.x, pos.y, pos.z)
+			sr.c.Logger.Printf("label: %s -> %d,%d,%d (sign)", items.ItemName(itemID), pos.X, pos.Y, pos.Z)
 		}
 	}
 }
@@ -847,85 +887,46 @@ func isContainer(blockID int32) bool {
 	return slices.Contains(containerBlockIDs, blockID)
 }
 
-func findContainerNear(w *world.Module, x, y, z int) (blockPos, bool) {
+func findContainerNear(w *world.Module, x, y, z int) (geom.BlockPos, bool) {
 	stateID := w.GetBlock(x, y, z)
 	blockID, _ := blocks.StateProperties(int(stateID))
 	if isContainer(blockID) {
-		return blockPos{x, y, z}, true
+		return geom.BlockPos{X: x, Y: y, Z: z}, true
 	}
 	return findAdjacentContainer(w, x, y, z)
 }
 
-func findAdjacentContainer(w *world.Module, x, y, z int) (blockPos, bool) {
-	for _, off := range [][3]int{{1, 0, 0}, {-1, 0, 0}, {0, 0, 1}, {0, 0, -1}, {0, 1, 0}, {0, -1, 0}} {
-		nx, ny, nz := x+off[0], y+off[1], z+off[2]
-		stateID := w.GetBlock(nx, ny, nz)
+func findAdjacentContainer(w *world.Module, x, y, z int) (geom.BlockPos, bool) {
+	pos := geom.BlockPos{X: x, Y: y, Z: z}
+	for _, face := range []geom.Face{geom.FaceEast, geom.FaceWest, geom.FaceSouth, geom.FaceNorth, geom.FaceTop, geom.FaceBottom} {
+		n := pos.Neighbor(face)
+		stateID := w.GetBlock(n.X, n.Y, n.Z)
 		if stateID == 0 {
 			continue
 		}
 		blockID, _ := blocks.StateProperties(int(stateID))
 		if isContainer(blockID) {
-			return blockPos{nx, ny, nz}, true
+			return n, true
 		}
 	}
-	return blockPos{}, false
+	return geom.BlockPos{}, false
 }
 
-func findContainerForSign(w *world.Module, x, y, z int, stateID int32) (blockPos, bool) {
+func findContainerForSign(w *world.Module, x, y, z int, stateID int32) (geom.BlockPos, bool) {
 	blockID, props := blocks.StateProperties(int(stateID))
 	if wallSignBlockIDs[blockID] {
-		dx, dy, dz := wallSignFacingOffset(props["facing"])
-		cx, cy, cz := x+dx, y+dy, z+dz
-		checkBlockID, _ := blocks.StateProperties(int(w.GetBlock(cx, cy, cz)))
-		if isContainer(checkBlockID) {
-			return blockPos{cx, cy, cz}, true
+		// a wall sign hangs on the block behind the way it faces
+		if facing, ok := geom.FaceByName(props["facing"]); ok {
+			c := geom.BlockPos{X: x, Y: y, Z: z}.Neighbor(facing.Opposite())
+			checkBlockID, _ := blocks.StateProperties(int(w.GetBlock(c.X, c.Y, c.Z)))
+			if isContainer(checkBlockID) {
+				return c, true
+			}
 		}
 	}
 	return findAdjacentContainer(w, x, y, z)
 }
 
-func wallSignFacingOffset(facing string) (int, int, int) {
-	switch facing {
-	case "south":
-		return 0, 0, -1
-	case "north":
-		return 0, 0, 1
-	case "east":
-		return -1, 0, 0
-	case "west":
-		return 1, 0, 0
-	default:
-		return 0, 0, 0
-	}
-}
-
-func extractSignText(data nbt.Compound) []string {
-	frontText := data.GetCompound("front_text")
-	if frontText == nil {
-		return nil
-	}
-	messages := frontText.GetList("messages")
-	var lines []string
-	for _, msg := range messages.Elements {
-		var text string
-		switch v := msg.(type) {
-		case nbt.String:
-			text = string(v)
-		case nbt.Compound:
-			text = v.GetString("text")
-		}
-		var tc ns.TextComponent
-		if json.Unmarshal([]byte(text), &tc) == nil {
-			text = tc.String()
-		}
-		text = strings.TrimSpace(text)
-		if text != "" {
-			lines = append(lines, text)
-		}
-	}
-	return lines
-}
-
 // resolveLabel resolves a sign line to item IDs.
 // Lines starting with # are treated as item tags (e.g. "#bundles", "#minecraft:swords").
 // Other lines are treated as individual item names.
@@ -960,10 +961,22 @@ func intAbs(x int) int {
 	return x
 }
 
-func main() {
+// runSorter sorts items from "filter me" chests into sign-labelled chests.
+// With -layout it first writes the labels listed in the file.
+func runSorter(args []string) {
+	fs := flag.NewFlagSet("sorter", flag.ExitOnError)
 	var f helpers.Flags
-	helpers.RegisterFlags(&f)
-	flag.Parse()
+	helpers.RegisterFlagsOn(fs, &f)
+	layoutFile := fs.String("layout", "", `JSON file mapping chest positions to sign lines, e.g. {"10,64,-3": ["#logs"]}, written before sorting`)
+	fs.Parse(args)
+
+	var layout map[geom.BlockPos][]string
+	if *layoutFile != "" {
+		var err error
+		if layout, err = loadLayout(*layoutFile); err != nil {
+			log.Fatalf("layout: %v", err)
+		}
+	}
 
 	c := helpers.NewClient(f)
 	c.MaxReconnectAttempts = -1
@@ -973,7 +986,66 @@ func main() {
 
 	sr := newSorter(c)
 	sr.setup()
-	go sr.run()
+	if layout == nil {
+		go sr.run()
+		helpers.Run(c)
+		return
+	}
+
+	c.Register(combat.New())
+	b, err := behaviors.New(c)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var once sync.Once
+	sr.s.OnSpawn(func() {
+		once.Do(func() {
+			go func() {
+				// give the chunks time to arrive, as buildLabelMap does
+				time.Sleep(5 * time.Second)
+				err := b.LabelChests(context.Background(), layout, func(p behaviors.LabelProgress) {
+					switch {
+					case p.Err != nil:
+						c.Logger.Printf("label %v: %v", p.Chest, p.Err)
+					case p.Unchanged:
+						c.Logger.Printf("label %v: already %q", p.Chest, p.Lines)
+					default:
+						c.Logger.Printf("label %v: wrote %q on sign at %v", p.Chest, p.Lines, p.Sign)
+					}
+				})
+				if err != nil {
+					c.Logger.Printf("some labels weren't written")
+				}
+				sr.buildLabelMap()
+				sr.run()
+			}()
+		})
+	})
 
 	helpers.Run(c)
 }
+
+// loadLayout reads a storage room layout: a JSON object from "x,y,z" chest
+// positions to the lines of their labels.
+func loadLayout(path string) (map[geom.BlockPos][]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var raw map[string][]string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	layout := make(map[geom.BlockPos][]string, len(raw))
+	for key, lines := range raw {
+		var pos geom.BlockPos
+		if _, err := fmt.Sscanf(key, "%d,%d,%d", &pos.X, &pos.Y, &pos.Z); err != nil {
+			return nil, fmt.Errorf("chest position %q: want x,y,z", key)
+		}
+		if len(lines) > 4 {
+			return nil, fmt.Errorf("chest %s: %d lines, signs hold 4", key, len(lines))
+		}
+		layout[pos] = lines
+	}
+	return layout, nil
+}
@@ -6,6 +6,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/go-mclib/client/pkg/client"
 	"github.com/go-mclib/client/pkg/client/modules/collisions"
 	"github.com/go-mclib/client/pkg/client/modules/entities"
 	"github.com/go-mclib/client/pkg/client/modules/inventory"
@@ -160,10 +161,11 @@ func main() {
 			time.Sleep(200 * time.Millisecond)
 		}
 
+		// interact once the physics tick has sent the new rotation
 		s.LookAt(float64(cx)+0.5, float64(cy)+0.5, float64(cz)+0.5)
-		time.Sleep(50 * time.Millisecond)
-
-		if err := c.InteractBlock(cx, cy, cz, 1, 0, 0.5, 0.5, 0.5); err != nil {
+		if err := c.RunOnTick(client.TickAfterSend, func() error {
+			return c.InteractBlock(cx, cy, cz, 1, 0, 0.5, 0.5, 0.5)
+		}); err != nil {
 			c.Logger.Printf("failed to interact with container: %v", err)
 			return
 		}
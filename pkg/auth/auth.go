@@ -0,0 +1,271 @@
+// Package auth is a shared authorization model for everything that lets
+// someone else drive a bot: in-game chat commands (principals are player
+// UUIDs) and HTTP/RPC endpoints (principals are bearer tokens).
+//
+//	a := auth.New()
+//	a.GrantPlayer(ownerUUID, auth.RoleOwner)
+//	a.GrantToken(os.Getenv("BOT_TOKEN"), auth.RoleTrusted)
+//	a.Require("say", auth.RoleTrusted)
+//
+//	if err := a.Authorize(auth.Player(uuid, name), "say", text); err != nil { ... }
+package auth
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	ns "github.com/go-mclib/protocol/java_protocol/net_structures"
+)
+
+// Role is a principal's trust level. Higher roles include the lower ones.
+type Role int
+
+const (
+	RolePublic Role = iota
+	RoleTrusted
+	RoleOwner
+)
+
+func (r Role) String() string {
+	switch r {
+	case RolePublic:
+		return "public"
+	case RoleTrusted:
+		return "trusted"
+	case RoleOwner:
+		return "owner"
+	}
+	return fmt.Sprintf("Role(%d)", int(r))
+}
+
+// RoleByName parses "public", "trusted" or "owner".
+func RoleByName(name string) (Role, bool) {
+	for r := RolePublic; r <= RoleOwner; r++ {
+		if strings.EqualFold(name, r.String()) {
+			return r, true
+		}
+	}
+	return RolePublic, false
+}
+
+var (
+	// ErrForbidden is returned when the principal's role is below the action's requirement.
+	ErrForbidden = errors.New("forbidden")
+	// ErrRateLimited is returned when the principal issued too many actions recently.
+	ErrRateLimited = errors.New("rate limited")
+)
+
+// Principal is whoever issues an action: a player (by UUID) or an API token.
+type Principal struct {
+	uuid  [16]byte
+	token string
+	Name  string // for audit logs
+}
+
+// Player returns the principal for an in-game player.
+func Player(uuid [16]byte, name string) Principal {
+	return Principal{uuid: uuid, Name: name}
+}
+
+// Token returns the principal for an API token. It's named after a hash of
+// the token, so audit logs can tell tokens apart without revealing them.
+func Token(token string) Principal {
+	name := "token"
+	if token != "" {
+		sum := sha256.Sum256([]byte(token))
+		name = "token:" + hex.EncodeToString(sum[:4])
+	}
+	return Principal{token: token, Name: name}
+}
+
+func (p Principal) String() string {
+	if p.token != "" {
+		return p.Name
+	}
+	return fmt.Sprintf("%s (%s)", p.Name, ns.UUID(p.uuid))
+}
+
+// Authorizer binds principals to roles and checks actions against per-action
+// requirements and per-principal rate limits. Every decision is written to
+// the audit log.
+type Authorizer struct {
+	// DefaultRequirement applies to actions without an explicit Require (default: RoleOwner).
+	DefaultRequirement Role
+	// RateLimit is the number of actions a principal may issue per RateWindow
+	// (0 = unlimited). Owners are exempt.
+	RateLimit  int
+	RateWindow time.Duration
+	// Audit receives one line per decision; nil disables auditing.
+	Audit *log.Logger
+
+	mu       sync.Mutex
+	players  map[[16]byte]Role
+	tokens   map[string]Role
+	required map[string]Role
+	recent   map[string][]time.Time
+	swept    time.Time // last time expired recent entries were dropped
+}
+
+// New returns an authorizer where unknown principals are public and actions
+// without an explicit requirement are owner-only.
+func New() *Authorizer {
+	return &Authorizer{
+		DefaultRequirement: RoleOwner,
+		RateLimit:          10,
+		RateWindow:         10 * time.Second,
+		players:            make(map[[16]byte]Role),
+		tokens:             make(map[string]Role),
+		required:           make(map[string]Role),
+		recent:             make(map[string][]time.Time),
+	}
+}
+
+// SetRateLimit changes RateLimit and RateWindow while the authorizer is in use.
+func (a *Authorizer) SetRateLimit(limit int, window time.Duration) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.RateLimit = limit
+	a.RateWindow = window
+}
+
+// GrantPlayer binds a player UUID to a role.
+func (a *Authorizer) GrantPlayer(uuid [16]byte, role Role) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.players[uuid] = role
+}
+
+// GrantToken binds an API token to a role. Empty tokens are ignored.
+func (a *Authorizer) GrantToken(token string, role Role) {
+	if token == "" {
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.tokens[token] = role
+}
+
+// Revoke removes a principal's role binding (it becomes public).
+func (a *Authorizer) Revoke(p Principal) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if p.token != "" {
+		delete(a.tokens, p.token)
+	} else {
+		delete(a.players, p.uuid)
+	}
+}
+
+// Require sets the minimum role for an action (a chat command or API name).
+func (a *Authorizer) Require(action string, role Role) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.required[action] = role
+}
+
+// RoleOf returns the role bound to p, or RolePublic.
+func (a *Authorizer) RoleOf(p Principal) Role {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.roleOf(p)
+}
+
+func (a *Authorizer) roleOf(p Principal) Role {
+	if p.token != "" {
+		return a.tokens[p.token]
+	}
+	return a.players[p.uuid]
+}
+
+func (a *Authorizer) key(p Principal) string {
+	if p.token != "" {
+		return "t:" + p.token
+	}
+	return "p:" + ns.UUID(p.uuid).String()
+}
+
+// Authorize checks whether p may perform action. Every attempt counts
+// against p's rate limit, denied ones too, so a principal spamming an action
+// it may not use is rate limited like any other. detail (e.g. the command's
+// arguments) is only used for the audit log.
+func (a *Authorizer) Authorize(p Principal, action, detail string) error {
+	a.mu.Lock()
+	role := a.roleOf(p)
+	need, ok := a.required[action]
+	if !ok {
+		need = a.DefaultRequirement
+	}
+
+	var err error
+	switch {
+	case role < RoleOwner && !a.allow(a.key(p), time.Now()):
+		err = fmt.Errorf("%w: %s", ErrRateLimited, p.Name)
+	case role < need:
+		err = fmt.Errorf("%w: %s requires %s, %s is %s", ErrForbidden, action, need, p.Name, role)
+	}
+	a.mu.Unlock()
+
+	if a.Audit != nil {
+		outcome := "allowed"
+		if err != nil {
+			outcome = "denied: " + err.Error()
+		}
+		a.Audit.Printf("auth: %s [%s] %s %q: %s", p, role, action, detail, outcome)
+	}
+	return err
+}
+
+// allow records an action for key unless it exceeds the rate limit. Must be called with mu held.
+func (a *Authorizer) allow(key string, now time.Time) bool {
+	if a.RateLimit <= 0 || a.RateWindow <= 0 {
+		return true
+	}
+	if now.Sub(a.swept) >= a.RateWindow {
+		// forget principals with nothing left in the window, so keys seen
+		// once (e.g. random bearer tokens) don't pile up
+		for k, times := range a.recent {
+			if now.Sub(times[len(times)-1]) >= a.RateWindow {
+				delete(a.recent, k)
+			}
+		}
+		a.swept = now
+	}
+	times := a.recent[key]
+	i := 0
+	for i < len(times) && now.Sub(times[i]) >= a.RateWindow {
+		i++
+	}
+	times = times[i:]
+	if len(times) >= a.RateLimit {
+		a.recent[key] = times
+		return false
+	}
+	a.recent[key] = append(times, now)
+	return true
+}
+
+// Middleware guards an HTTP handler with action, authenticating the request
+// by its "Authorization: Bearer <token>" header.
+func (a *Authorizer) Middleware(action string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, _ := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		err := a.Authorize(Token(token), action, r.Method+" "+r.URL.Path)
+		switch {
+		case errors.Is(err, ErrRateLimited):
+			http.Error(w, err.Error(), http.StatusTooManyRequests)
+		case err != nil && token == "":
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+		case err != nil:
+			http.Error(w, err.Error(), http.StatusForbidden)
+		default:
+			next.ServeHTTP(w, r)
+		}
+	})
+}
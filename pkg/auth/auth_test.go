@@ -0,0 +1,100 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAuthorizeRoles(t *testing.T) {
+	a := New()
+	owner, trusted, stranger := [16]byte{1}, [16]byte{2}, [16]byte{3}
+	a.GrantPlayer(owner, RoleOwner)
+	a.GrantPlayer(trusted, RoleTrusted)
+	a.GrantToken("secret", RoleTrusted)
+	a.Require("ping", RolePublic)
+	a.Require("say", RoleTrusted)
+
+	tests := []struct {
+		p      Principal
+		action string
+		want   error
+	}{
+		{Player(stranger, "s"), "ping", nil},
+		{Player(stranger, "s"), "say", ErrForbidden},
+		{Player(trusted, "t"), "say", nil},
+		{Player(trusted, "t"), "shutdown", ErrForbidden}, // default requirement is owner
+		{Player(owner, "o"), "shutdown", nil},
+		{Token("secret"), "say", nil},
+		{Token("wrong"), "say", ErrForbidden},
+	}
+	for _, tt := range tests {
+		if err := a.Authorize(tt.p, tt.action, ""); !errors.Is(err, tt.want) {
+			t.Errorf("Authorize(%v, %q) = %v, want %v", tt.p, tt.action, err, tt.want)
+		}
+	}
+}
+
+func TestAuthorizeRateLimit(t *testing.T) {
+	a := New()
+	a.RateLimit = 2
+	a.RateWindow = time.Hour
+	a.Require("ping", RolePublic)
+	owner := [16]byte{1}
+	a.GrantPlayer(owner, RoleOwner)
+
+	p := Player([16]byte{9}, "spammer")
+	for i := range 2 {
+		if err := a.Authorize(p, "ping", ""); err != nil {
+			t.Fatalf("call %d: %v", i, err)
+		}
+	}
+	if err := a.Authorize(p, "ping", ""); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("third call = %v, want ErrRateLimited", err)
+	}
+	for range 5 {
+		if err := a.Authorize(Player(owner, "o"), "ping", ""); err != nil {
+			t.Errorf("owner should not be rate limited: %v", err)
+		}
+	}
+}
+
+func TestAuthorizeRateLimitsDenied(t *testing.T) {
+	a := New()
+	a.RateLimit = 2
+	a.RateWindow = time.Hour
+	a.Require("say", RoleTrusted)
+
+	p := Player([16]byte{9}, "spammer")
+	for i := range 2 {
+		if err := a.Authorize(p, "say", ""); !errors.Is(err, ErrForbidden) {
+			t.Fatalf("call %d = %v, want ErrForbidden", i, err)
+		}
+	}
+	// replies to forbidden attempts stop once they're rate limited
+	if err := a.Authorize(p, "say", ""); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("third call = %v, want ErrRateLimited", err)
+	}
+}
+
+func TestRateLimitForgetsExpiredPrincipals(t *testing.T) {
+	a := New()
+	a.RateWindow = time.Minute
+	start := time.Now()
+	for i := range 100 {
+		a.allow(fmt.Sprintf("t:random%d", i), start)
+	}
+	a.allow("t:later", start.Add(2*time.Minute))
+	if len(a.recent) != 1 {
+		t.Fatalf("%d principals kept, want 1", len(a.recent))
+	}
+}
+
+func TestTokenNameHidesSecret(t *testing.T) {
+	p := Token("hunter2-secret")
+	if strings.Contains(p.Name, "hunt") || p.Name == Token("other").Name {
+		t.Fatalf("token named %q", p.Name)
+	}
+}
@@ -0,0 +1,218 @@
+package behaviors
+
+import (
+	"context"
+	"errors"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/go-mclib/client/pkg/client"
+	"github.com/go-mclib/client/pkg/client/modules/self"
+	"github.com/go-mclib/client/pkg/client/modules/world"
+	"github.com/go-mclib/client/pkg/geom"
+	"github.com/go-mclib/data/pkg/data/blocks"
+)
+
+// ErrNoAir is returned by Breathe when there's no air in reach.
+var ErrNoAir = errors.New("no air within reach")
+
+const (
+	// airSearchRadius is how far Breathe looks for air.
+	airSearchRadius = 16
+	// airSwimTimeout gives up swimming to an air spot that can't be reached.
+	airSwimTimeout = 10 * time.Second
+)
+
+var (
+	waterBlockID        = blocks.BlockID("minecraft:water")
+	bubbleColumnBlockID = blocks.BlockID("minecraft:bubble_column")
+	// air and the blocks that keep water out of an underwater air pocket
+	airPocketBlockIDs = map[int32]bool{
+		blocks.BlockID("minecraft:air"):      true,
+		blocks.BlockID("minecraft:cave_air"): true,
+	}
+	// blocks that are always full of water
+	waterPlantBlockIDs = map[int32]bool{
+		blocks.BlockID("minecraft:kelp"):          true,
+		blocks.BlockID("minecraft:kelp_plant"):    true,
+		blocks.BlockID("minecraft:seagrass"):      true,
+		blocks.BlockID("minecraft:tall_seagrass"): true,
+	}
+)
+
+func init() {
+	// doors can't be waterlogged, so one placed underwater holds air
+	for _, wood := range []string{
+		"oak", "spruce", "birch", "jungle", "acacia", "dark_oak",
+		"mangrove", "cherry", "bamboo", "crimson", "warped", "pale_oak",
+	} {
+		airPocketBlockIDs[blocks.BlockID("minecraft:"+wood+"_door")] = true
+	}
+	airPocketBlockIDs[blocks.BlockID("minecraft:iron_door")] = true
+}
+
+// holdsWater reports whether a block state is or contains water.
+func holdsWater(stateID int32) bool {
+	blockID, props := blocks.StateProperties(int(stateID))
+	return blockID == waterBlockID || blockID == bubbleColumnBlockID ||
+		waterPlantBlockIDs[blockID] || props["waterlogged"] == "true"
+}
+
+// refillsAir reports whether the eyes regain air in a block with the given
+// state: one without water, or a bubble column (from soul sand or magma).
+func refillsAir(stateID int32) bool {
+	blockID, _ := blocks.StateProperties(int(stateID))
+	return blockID == bubbleColumnBlockID || !holdsWater(stateID)
+}
+
+// breathing reports whether the player's eyes are where air refills.
+func (b *Bot) breathing() bool {
+	x, y, z := b.s.EyePosition()
+	return refillsAir(b.w.GetBlock(int(math.Floor(x)), int(math.Floor(y)), int(math.Floor(z))))
+}
+
+// findAir returns the nearest block the eyes can regain air in, with a
+// swimmable block below for the body and a clear line from the eyes: the
+// surface, an air pocket (e.g. in a door) or a bubble column.
+func (b *Bot) findAir(ctx context.Context) (geom.BlockPos, error) {
+	ex, ey, ez := b.s.EyePosition()
+	eye := geom.Vec3{X: ex, Y: ey, Z: ez}
+	center := geom.BlockPos{X: int(math.Floor(ex)), Y: int(math.Floor(ey)), Z: int(math.Floor(ez))}
+	hits, err := b.w.Scan(ctx, world.ScanJob{
+		Match: func(stateID int32) bool {
+			blockID, _ := blocks.StateProperties(int(stateID))
+			return airPocketBlockIDs[blockID] || blockID == bubbleColumnBlockID
+		},
+		Region:   geom.Sphere{Center: center, Radius: airSearchRadius},
+		Priority: 1, // running out of air beats any other scan
+	})
+	if err != nil {
+		return geom.BlockPos{}, err
+	}
+	slices.SortFunc(hits, func(a, b world.ScanHit) int {
+		da, db := a.Pos.Center().Distance(eye), b.Pos.Center().Distance(eye)
+		switch {
+		case da < db:
+			return -1
+		case da > db:
+			return 1
+		}
+		return 0
+	})
+	for _, hit := range hits {
+		below := hit.Pos.Offset(0, -1, 0)
+		body := b.w.GetBlock(below.X, below.Y, below.Z)
+		bodyID, _ := blocks.StateProperties(int(body))
+		if !holdsWater(body) && !airPocketBlockIDs[bodyID] {
+			continue
+		}
+		target := hit.Pos.Center()
+		blocked, hx, hy, hz := b.col.RaycastBlocks(ex, ey, ez, target.X, target.Y, target.Z)
+		hitBlock := geom.BlockPos{X: int(math.Floor(hx)), Y: int(math.Floor(hy)), Z: int(math.Floor(hz))}
+		if blocked && hitBlock != hit.Pos {
+			continue
+		}
+		return hit.Pos, nil
+	}
+	return geom.BlockPos{}, ErrNoAir
+}
+
+// Breathe swims to the nearest air (see findAir) and stays there until the
+// air supply is full. Swimming is steered directly, so the spot must be in
+// sight; the pathfinder is stopped first.
+func (b *Bot) Breathe(ctx context.Context) error {
+	b.pf.Stop()
+	defer b.phys.SetInput(0, 0, false)
+
+	var target geom.BlockPos
+	var deadline int
+	swimming := false
+	for ticks := 0; b.s.Air() < self.MaxAir; ticks++ {
+		switch {
+		case b.breathing():
+			swimming = false
+			// keep the head above the surface while refilling
+			x, y, z := b.s.Position()
+			b.phys.SetInput(0, 0, holdsWater(b.w.GetBlock(int(math.Floor(x)), int(math.Floor(y)), int(math.Floor(z)))))
+		case !swimming:
+			pos, err := b.findAir(ctx)
+			if err != nil {
+				return err
+			}
+			target, swimming = pos, true
+			deadline = ticks + client.Ticks(airSwimTimeout)
+		case ticks > deadline:
+			return ErrNavigation
+		default:
+			b.swimToward(target.Center())
+		}
+		if err := b.c.WaitTicks(ctx, 1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// swimToward steers one tick toward p: facing it, swimming forward until
+// above or below it, and rising while it's higher than the eyes.
+func (b *Bot) swimToward(p geom.Vec3) {
+	b.s.LookAt(p.X, p.Y, p.Z)
+	x, y, z := b.s.EyePosition()
+	forward := 0.0
+	if p.Sub(geom.Vec3{X: x, Y: p.Y, Z: z}).HorizontalLength() > 0.3 {
+		forward = 1
+	}
+	b.phys.SetInput(forward, 0, p.Y > y)
+}
+
+// WhileBreathing runs task until it returns, surfacing for air in between:
+// when the air supply runs low (see self.OnLowAir) the task's context is
+// cancelled, the bot breathes (see Breathe) and the task is run again, so
+// it should pick up where it left off. Underwater work like mining goes
+// inside task.
+func (b *Bot) WhileBreathing(ctx context.Context, task func(ctx context.Context) error) error {
+	ch := make(chan struct{}, 1)
+	b.mu.Lock()
+	b.lowAirCh = ch
+	b.mu.Unlock()
+	defer func() {
+		b.mu.Lock()
+		b.lowAirCh = nil
+		b.mu.Unlock()
+	}()
+
+	for {
+		if b.s.LowOnAir() {
+			if err := b.Breathe(ctx); err != nil {
+				return err
+			}
+			select {
+			case <-ch: // ran low before breathing
+			default:
+			}
+		}
+
+		taskCtx, cancel := context.WithCancel(ctx)
+		lowAir := false
+		watched := make(chan struct{})
+		go func() {
+			defer close(watched)
+			select {
+			case <-ch:
+				lowAir = true
+				cancel()
+			case <-taskCtx.Done():
+			}
+		}()
+		err := task(taskCtx)
+		cancel()
+		<-watched
+		if !lowAir {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package behaviors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/go-mclib/client/pkg/client/modules/collisions"
+	"github.com/go-mclib/client/pkg/client/modules/combat"
+	"github.com/go-mclib/client/pkg/client/modules/pathfinding"
+	"github.com/go-mclib/client/pkg/geom"
+)
+
+// ErrUnreachable is reported for a Batch target no stand position reaches.
+var ErrUnreachable = errors.New("target unreachable")
+
+// BatchTarget is one interaction for Batch: a block, or an entity, and what
+// to do with it once it's in reach.
+type BatchTarget struct {
+	Block geom.BlockPos
+	// Entity, if not 0, makes the target this entity; Block is ignored.
+	Entity int32
+	// Do performs the interaction. For blocks, spot is the face and cursor
+	// to click from where the bot stands; for entities it's zero.
+	Do func(ctx context.Context, spot pathfinding.ReachSpot) error
+}
+
+// BatchResult is the outcome for one Batch target.
+type BatchResult struct {
+	Stand geom.BlockPos // where the bot stood for it
+	Err   error         // Do's error, ErrUnreachable, or why the bot couldn't get there
+}
+
+// Batch handles many targets with as little walking as it can: it plans
+// the fewest stand positions that cover the targets (reach and line of sight
+// aware, see pathfinding.PlanCover), walks between them in a short order and
+// runs each target's Do from the first stop that covers it. Useful for
+// harvesting, lever rooms and chest rows. A stop that can't be reached fails
+// its targets and the round goes on. Results are in target order.
+func (b *Bot) Batch(ctx context.Context, targets []BatchTarget) ([]BatchResult, error) {
+	results := make([]BatchResult, len(targets))
+	cover := make([]pathfinding.CoverTarget, len(targets))
+	for i, t := range targets {
+		cover[i] = pathfinding.CoverTarget{Block: t.Block, Reach: blockReach}
+		if t.Entity != 0 {
+			e := b.ents.GetEntity(t.Entity)
+			if e == nil {
+				return nil, fmt.Errorf("entity %d not found", t.Entity)
+			}
+			box := collisions.EntityAABB(e.X, e.Y, e.Z, e.Width, e.Height)
+			cover[i] = pathfinding.CoverTarget{Entity: &box, Reach: combat.EntityInteractionRange}
+		}
+	}
+
+	x, y, z := b.s.Position()
+	stops, unreachable := pathfinding.PlanCover(b.col, x, y, z, cover)
+	for _, i := range unreachable {
+		results[i].Err = ErrUnreachable
+	}
+	for _, stop := range stops {
+		stand := geom.Vec3{X: float64(stop.Stand.X) + 0.5, Y: float64(stop.Stand.Y), Z: float64(stop.Stand.Z) + 0.5}
+		x, _, z := b.s.Position()
+		var err error
+		if stand.Sub(geom.Vec3{X: x, Y: stand.Y, Z: z}).HorizontalLength() > 1.0 {
+			err = b.GoTo(ctx, stand)
+		}
+		b.pf.Stop()
+		if ctx.Err() != nil {
+			return results, ctx.Err()
+		}
+		for _, i := range stop.Targets {
+			results[i].Stand = stop.Stand
+			if err != nil {
+				results[i].Err = err
+				continue
+			}
+			results[i].Err = b.runBatchTarget(ctx, targets[i])
+			if ctx.Err() != nil {
+				return results, ctx.Err()
+			}
+		}
+	}
+	return results, nil
+}
+
+// runBatchTarget runs t.Do from where the bot stands, re-checking the reach:
+// the bot stops near the stand spot, not on it, and entities move.
+func (b *Bot) runBatchTarget(ctx context.Context, t BatchTarget) error {
+	ex, ey, ez := b.s.EyePosition()
+	if t.Entity != 0 {
+		e := b.ents.GetEntity(t.Entity)
+		if e == nil {
+			return fmt.Errorf("entity %d gone", t.Entity)
+		}
+		box := collisions.EntityAABB(e.X, e.Y, e.Z, e.Width, e.Height)
+		cx, cy, cz := box.ClosestPoint(ex, ey, ez)
+		if (geom.Vec3{X: ex, Y: ey, Z: ez}).Distance(geom.Vec3{X: cx, Y: cy, Z: cz}) > combat.EntityInteractionRange {
+			return fmt.Errorf("entity %d moved out of reach", t.Entity)
+		}
+		return t.Do(ctx, pathfinding.ReachSpot{})
+	}
+	face, cursor, ok := pathfinding.FindReachFace(b.col, ex, ey, ez, t.Block, blockReach)
+	if !ok {
+		return fmt.Errorf("block at %v out of reach", t.Block)
+	}
+	x, y, z := b.s.Position()
+	stand := geom.BlockPos{X: int(math.Floor(x)), Y: int(math.Floor(y)), Z: int(math.Floor(z))}
+	return t.Do(ctx, pathfinding.ReachSpot{Stand: stand, Target: t.Block, Face: face, Cursor: cursor})
+}
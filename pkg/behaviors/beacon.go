@@ -0,0 +1,79 @@
+package behaviors
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-mclib/client/pkg/client"
+	"github.com/go-mclib/client/pkg/client/modules/inventory"
+	"github.com/go-mclib/client/pkg/geom"
+)
+
+// beaconConfirmWait is how long ConfigureBeacon waits for the beacon's data
+// slots, before and after selecting the effects.
+const beaconConfirmWait = 2 * time.Second
+
+// ConfigureBeacon walks to the beacon at pos, pays it with an item from the
+// inventory and selects primary and secondary (registries.MobEffect ids, -1
+// for none). It returns once the beacon reports the new effects; players in
+// range receive them on the beacon's next 4 second pulse.
+func (b *Bot) ConfigureBeacon(ctx context.Context, pos geom.BlockPos, primary, secondary int32) error {
+	if err := b.OpenContainer(ctx, pos); err != nil {
+		return err
+	}
+	defer func() {
+		if b.inv.ContainerOpen() {
+			_ = b.inv.CloseContainer()
+		}
+	}()
+	if t := b.inv.ContainerMenuType(); t != inventory.MenuBeacon {
+		return fmt.Errorf("block at %v opened menu %d, not a beacon", pos, t)
+	}
+
+	// the data slots follow the open screen packet; SetBeaconEffect checks
+	// the pyramid level against them
+	if err := b.waitFor(ctx, func() bool {
+		_, ok := b.inv.BeaconLevels()
+		return ok
+	}); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("beacon at %v: no levels received", pos)
+	}
+
+	if p, s, ok := b.inv.BeaconEffect(); ok && p == primary && s == secondary {
+		return nil
+	}
+	if err := b.inv.SetBeaconEffect(primary, secondary); err != nil {
+		return err
+	}
+	if err := b.waitFor(ctx, func() bool {
+		p, s, ok := b.inv.BeaconEffect()
+		return ok && p == primary && s == secondary
+	}); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("beacon at %v didn't accept the effects", pos)
+	}
+	return nil
+}
+
+// waitFor checks cond once per tick until it holds, for up to
+// beaconConfirmWait.
+func (b *Bot) waitFor(ctx context.Context, cond func() bool) error {
+	for range client.Ticks(beaconConfirmWait) {
+		if cond() {
+			return nil
+		}
+		if err := b.c.WaitTicks(ctx, 1); err != nil {
+			return err
+		}
+	}
+	if cond() {
+		return nil
+	}
+	return context.DeadlineExceeded
+}
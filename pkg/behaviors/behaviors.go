@@ -0,0 +1,227 @@
+// Package behaviors provides ready-made, context-cancellable bot behaviors
+// (patrol, perimeter guard, courier) built on the client modules.
+//
+//	c := helpers.NewClient(f)
+//	c.Register(entities.New())
+//	c.Register(pathfinding.New())
+//	c.Register(combat.New())
+//	c.Register(inventory.New())
+//	b, err := behaviors.New(c)
+//	...
+//	c.OnPlay(func() { go b.Patrol(ctx, waypoints, true) })
+package behaviors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-mclib/client/pkg/client"
+	"github.com/go-mclib/client/pkg/client/modules/collisions"
+	"github.com/go-mclib/client/pkg/client/modules/combat"
+	"github.com/go-mclib/client/pkg/client/modules/entities"
+	"github.com/go-mclib/client/pkg/client/modules/inventory"
+	"github.com/go-mclib/client/pkg/client/modules/pathfinding"
+	"github.com/go-mclib/client/pkg/client/modules/physics"
+	"github.com/go-mclib/client/pkg/client/modules/self"
+	"github.com/go-mclib/client/pkg/client/modules/world"
+	"github.com/go-mclib/client/pkg/geom"
+)
+
+// ErrNavigation is returned when the bot couldn't reach a destination.
+var ErrNavigation = errors.New("navigation failed")
+
+// blockReach is the vanilla block interaction range.
+const blockReach = 4.5
+
+// Bot drives behaviors for one client. Behaviors share the bot's movement,
+// so run at most one at a time.
+type Bot struct {
+	c    *client.Client
+	s    *self.Module
+	w    *world.Module
+	col  *collisions.Module
+	phys *physics.Module
+	ents *entities.Module
+	pf   *pathfinding.Module
+	com  *combat.Module
+	inv  *inventory.Module
+
+	mu       sync.Mutex
+	navCh    chan bool
+	openCh   chan struct{}
+	lowAirCh chan struct{}
+}
+
+// New wires a Bot to c. The entities, pathfinding, combat and inventory
+// modules must be registered first.
+func New(c *client.Client) (*Bot, error) {
+	b := &Bot{
+		c:    c,
+		s:    self.From(c),
+		w:    world.From(c),
+		col:  collisions.From(c),
+		phys: physics.From(c),
+		ents: entities.From(c),
+		pf:   pathfinding.From(c),
+		com:  combat.From(c),
+		inv:  inventory.From(c),
+	}
+	switch {
+	case b.s == nil || b.w == nil || b.col == nil || b.phys == nil:
+		return nil, errors.New("default modules not registered")
+	case b.ents == nil:
+		return nil, errors.New("entities module not registered")
+	case b.pf == nil:
+		return nil, errors.New("pathfinding module not registered")
+	case b.com == nil:
+		return nil, errors.New("combat module not registered")
+	case b.inv == nil:
+		return nil, errors.New("inventory module not registered")
+	}
+
+	b.pf.OnNavigationComplete(func(reached bool) {
+		b.mu.Lock()
+		ch := b.navCh
+		b.mu.Unlock()
+		if ch != nil {
+			select {
+			case ch <- reached:
+			default:
+			}
+		}
+	})
+	b.inv.OnContainerOpen(func(_ int32, _ inventory.MenuType, _ string) {
+		b.mu.Lock()
+		ch := b.openCh
+		b.mu.Unlock()
+		if ch != nil {
+			select {
+			case ch <- struct{}{}:
+			default:
+			}
+		}
+	})
+	b.s.OnLowAir(func(int32) {
+		b.mu.Lock()
+		ch := b.lowAirCh
+		b.mu.Unlock()
+		if ch != nil {
+			select {
+			case ch <- struct{}{}:
+			default:
+			}
+		}
+	})
+	return b, nil
+}
+
+// GoTo walks to pos and blocks until it's reached, navigation fails or ctx is done.
+func (b *Bot) GoTo(ctx context.Context, pos geom.Vec3) error {
+	ch := make(chan bool, 1)
+	b.mu.Lock()
+	b.navCh = ch
+	b.mu.Unlock()
+	defer func() {
+		b.mu.Lock()
+		b.navCh = nil
+		b.mu.Unlock()
+	}()
+
+	if err := b.pf.NavigateTo(pos.X, pos.Y, pos.Z); err != nil {
+		return fmt.Errorf("%w: %v", ErrNavigation, err)
+	}
+
+	select {
+	case reached := <-ch:
+		if !reached {
+			return ErrNavigation
+		}
+		return nil
+	case <-ctx.Done():
+		b.pf.Stop()
+		return ctx.Err()
+	}
+}
+
+// OpenContainer walks into reach of the container at pos and opens it.
+func (b *Bot) OpenContainer(ctx context.Context, pos geom.BlockPos) error {
+	if b.inv.ContainerOpen() {
+		_ = b.inv.CloseContainer()
+	}
+
+	spot, err := b.approach(ctx, pos)
+	if err != nil {
+		return err
+	}
+
+	ch := make(chan struct{}, 1)
+	b.mu.Lock()
+	b.openCh = ch
+	b.mu.Unlock()
+	defer func() {
+		b.mu.Lock()
+		b.openCh = nil
+		b.mu.Unlock()
+	}()
+
+	point := spot.Point()
+	res := b.w.Interact(world.Interaction{
+		Pos:     pos,
+		Face:    spot.Face,
+		CursorX: float32(spot.Cursor.X), CursorY: float32(spot.Cursor.Y), CursorZ: float32(spot.Cursor.Z),
+		Prepare: func() { b.s.LookAt(point.X, point.Y, point.Z) },
+		Confirm: func() bool {
+			wait, cancel := context.WithCancel(ctx)
+			defer cancel()
+			select {
+			case <-ch:
+				return true
+			case <-b.c.AfterTicks(wait, client.Ticks(3*time.Second)):
+				return false // timed out, or ctx is done
+			}
+		},
+	})
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return res.Err
+}
+
+// approach walks into block reach of pos and returns the face and cursor to
+// interact with it from where the bot stopped.
+func (b *Bot) approach(ctx context.Context, pos geom.BlockPos) (pathfinding.ReachSpot, error) {
+	sx, sy, sz := b.s.Position()
+	spot, found := pathfinding.FindReachSpot(b.col, sx, sy, sz, pos, blockReach)
+	if !found {
+		return spot, fmt.Errorf("no reachable position for block at %v", pos)
+	}
+	stand := geom.Vec3{X: float64(spot.Stand.X) + 0.5, Y: float64(spot.Stand.Y), Z: float64(spot.Stand.Z) + 0.5}
+	if stand.Sub(geom.Vec3{X: sx, Y: stand.Y, Z: sz}).HorizontalLength() > 1.0 {
+		if err := b.GoTo(ctx, stand); err != nil {
+			return spot, err
+		}
+	}
+	b.pf.Stop()
+
+	// the bot stops near the stand spot, not on it; aim from where it is
+	x, y, z := b.s.EyePosition()
+	if face, cursor, ok := pathfinding.FindReachFace(b.col, x, y, z, pos, blockReach); ok {
+		spot.Face, spot.Cursor = face, cursor
+	}
+	return spot, nil
+}
+
+// distanceTo returns the distance from the player's feet to pos.
+func (b *Bot) distanceTo(pos geom.Vec3) float64 {
+	x, y, z := b.s.Position()
+	return geom.Vec3{X: x, Y: y, Z: z}.Distance(pos)
+}
+
+// sleep waits for d, counted in physics ticks (see client.WaitTicks), or
+// until ctx is done.
+func (b *Bot) sleep(ctx context.Context, d time.Duration) error {
+	return b.c.WaitTicks(ctx, client.Ticks(d))
+}
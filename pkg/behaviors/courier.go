@@ -0,0 +1,131 @@
+package behaviors
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-mclib/client/pkg/client/modules/inventory"
+	"github.com/go-mclib/client/pkg/geom"
+	"github.com/go-mclib/data/pkg/data/items"
+)
+
+const (
+	// courierIdle is how long Courier waits before re-checking an empty source.
+	courierIdle = 5 * time.Second
+	// clickDelay spaces out shift-clicks so the server keeps up.
+	clickDelay = 50 * time.Millisecond
+)
+
+// Courier shuttles items matching filter (nil matches all) from the container
+// at from to the container at to, until ctx is done. When the source has
+// nothing to move it waits and checks again.
+func (b *Bot) Courier(ctx context.Context, from, to geom.BlockPos, filter func(*items.ItemStack) bool) error {
+	if filter == nil {
+		filter = func(*items.ItemStack) bool { return true }
+	}
+	defer func() {
+		if b.inv.ContainerOpen() {
+			_ = b.inv.CloseContainer()
+		}
+	}()
+
+	for {
+		if err := b.OpenContainer(ctx, from); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			b.c.Logger.Printf("courier: open source %v: %v", from, err)
+			if err := b.sleep(ctx, courierIdle); err != nil {
+				return err
+			}
+			continue
+		}
+		taken := b.takeMatching(ctx, filter)
+		_ = b.inv.CloseContainer()
+
+		if taken > 0 || b.carrying(filter) {
+			if err := b.OpenContainer(ctx, to); err != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				b.c.Logger.Printf("courier: open destination %v: %v", to, err)
+			} else {
+				stored := b.storeMatching(ctx, filter)
+				_ = b.inv.CloseContainer()
+				b.c.Logger.Printf("courier: moved %d stacks from %v to %v", stored, from, to)
+				if stored > 0 {
+					continue
+				}
+			}
+		}
+
+		if err := b.sleep(ctx, courierIdle); err != nil {
+			return err
+		}
+	}
+}
+
+// takeMatching shift-clicks matching stacks out of the open container until
+// the player inventory stops accepting them. Returns the number of stacks taken.
+func (b *Bot) takeMatching(ctx context.Context, filter func(*items.ItemStack) bool) int {
+	taken := 0
+	for i := range b.inv.ContainerSlotCount() {
+		if !b.inv.ContainerOpen() {
+			break
+		}
+		cs := b.inv.ContainerSlot(i)
+		if cs == nil || cs.IsEmpty() || !filter(cs) {
+			continue
+		}
+		if err := b.inv.ContainerShiftClick(i); err != nil {
+			b.c.Logger.Printf("courier: shift-click failed: %v", err)
+			continue
+		}
+		if b.sleep(ctx, clickDelay) != nil {
+			break
+		}
+		if after := b.inv.ContainerSlot(i); after != nil && !after.IsEmpty() && after.ID == cs.ID {
+			break // inventory full
+		}
+		taken++
+	}
+	return taken
+}
+
+// storeMatching shift-clicks matching stacks from the player inventory into
+// the open container. Returns the number of stacks stored.
+func (b *Bot) storeMatching(ctx context.Context, filter func(*items.ItemStack) bool) int {
+	slotCount := b.inv.ContainerSlotCount()
+	stored := 0
+	for i := range inventory.SlotHotbarEnd - inventory.SlotMainStart {
+		if !b.inv.ContainerOpen() {
+			break
+		}
+		item := b.inv.GetSlot(inventory.SlotMainStart + i)
+		if item == nil || item.IsEmpty() || !filter(item) {
+			continue
+		}
+		if err := b.inv.ContainerShiftClick(slotCount + i); err != nil {
+			b.c.Logger.Printf("courier: shift-click failed: %v", err)
+			continue
+		}
+		if b.sleep(ctx, clickDelay) != nil {
+			break
+		}
+		if after := b.inv.GetSlot(inventory.SlotMainStart + i); after != nil && !after.IsEmpty() && after.ID == item.ID {
+			break // destination full
+		}
+		stored++
+	}
+	return stored
+}
+
+// carrying reports whether the player inventory holds any matching stack.
+func (b *Bot) carrying(filter func(*items.ItemStack) bool) bool {
+	for i := inventory.SlotMainStart; i < inventory.SlotHotbarEnd; i++ {
+		if item := b.inv.GetSlot(i); item != nil && !item.IsEmpty() && filter(item) {
+			return true
+		}
+	}
+	return false
+}
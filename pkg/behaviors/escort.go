@@ -0,0 +1,181 @@
+package behaviors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/go-mclib/client/pkg/client"
+	"github.com/go-mclib/client/pkg/client/modules/playerlist"
+	"github.com/go-mclib/client/pkg/geom"
+)
+
+// ErrTargetLost is returned by Follow when the followed entity is gone.
+var ErrTargetLost = errors.New("escort target lost")
+
+const (
+	// escortSamples is how many ticks of movement the target's heading is
+	// estimated from.
+	escortSamples = 10
+	// escortRepath re-paths once the intercept point moved this far from
+	// the current goal.
+	escortRepath = 2.0
+	// escortStill is the speed, in blocks per tick, below which the target
+	// counts as standing still.
+	escortStill = 0.02
+	// escortRetryTicks waits this long before pathing again after no path
+	// was found, steering straight at the target meanwhile.
+	escortRetryTicks = 20
+)
+
+// Escort configures Follow and EscortPlayer.
+type Escort struct {
+	// Distance is how close to stay to the target (0 = 3).
+	Distance float64
+	// Lead is how far ahead on the target's heading to aim (0 = 1s).
+	Lead time.Duration
+	// SprintDistance is how far behind the bot may fall before it sprints
+	// (0 = 8).
+	SprintDistance float64
+	// LostTimeout gives up once the target has been out of sight this long
+	// (0 = 30s). Meanwhile the bot heads for where it was last seen.
+	LostTimeout time.Duration
+}
+
+// EscortPlayer follows the player with the given name, see Follow. The
+// playerlist module must be registered.
+func (b *Bot) EscortPlayer(ctx context.Context, name string, e Escort) error {
+	pl := playerlist.From(b.c)
+	if pl == nil {
+		return errors.New("playerlist module not registered")
+	}
+	p := pl.GetPlayerByName(name)
+	if p == nil {
+		return fmt.Errorf("%w: no player named %q", ErrTargetLost, name)
+	}
+	return b.Follow(ctx, p.UUID, e)
+}
+
+// Follow keeps up with the entity with the given UUID until ctx is done or
+// the entity is lost. Rather than chasing where the target is, it estimates
+// the target's heading from the last ticks of movement and paths to a point
+// e.Distance behind where it will be e.Lead from now, so the bot moves along
+// with it instead of stopping and starting. It walks while close, sprints
+// when it falls behind, and swims straight for the point in water, where
+// the pathfinder doesn't go.
+func (b *Bot) Follow(ctx context.Context, uuid [16]byte, e Escort) error {
+	distance, lead, sprintDist, lostTimeout := e.Distance, e.Lead, e.SprintDistance, e.LostTimeout
+	if distance <= 0 {
+		distance = 3
+	}
+	if lead <= 0 {
+		lead = time.Second
+	}
+	if sprintDist <= 0 {
+		sprintDist = 8
+	}
+	if lostTimeout <= 0 {
+		lostTimeout = 30 * time.Second
+	}
+
+	wasSprinting := b.s.Sprinting()
+	defer func() {
+		b.pf.Stop()
+		b.pf.SetSprintAllowed(true)
+		b.phys.SetInput(0, 0, false)
+		b.s.SetSprinting(wasSprinting)
+	}()
+
+	var trail []geom.Vec3 // target positions, one per tick
+	var navGoal geom.Vec3
+	var lostSince time.Time
+	navigating, steering := false, false
+	retry := 0 // ticks until pathing is tried again
+	for {
+		var target, goal geom.Vec3
+		moving := false
+		if t := b.ents.GetEntityByUUID(uuid); t != nil {
+			lostSince = time.Time{}
+			target = geom.Vec3{X: t.X, Y: t.Y, Z: t.Z}
+			if len(trail) == escortSamples {
+				trail = trail[1:]
+			}
+			trail = append(trail, target)
+			goal, moving = intercept(trail, distance, client.Ticks(lead))
+		} else {
+			trail = trail[:0]
+			if lostSince.IsZero() {
+				lostSince = b.c.Now()
+			}
+			seen, ok := b.ents.LastSeen(uuid)
+			if !ok || b.c.Now().Sub(lostSince) > lostTimeout {
+				return ErrTargetLost
+			}
+			target = geom.Vec3{X: seen.X, Y: seen.Y, Z: seen.Z}
+			goal = target
+		}
+
+		behind := b.distanceTo(target)
+		x, y, z := b.s.Position()
+		swimming := holdsWater(b.w.GetBlock(int(math.Floor(x)), int(math.Floor(y)), int(math.Floor(z))))
+		if retry > 0 {
+			retry--
+		}
+		switch {
+		case !moving && behind <= distance:
+			// caught up with a target standing still
+			if navigating || steering {
+				b.pf.Stop()
+				b.phys.SetInput(0, 0, false)
+				navigating, steering = false, false
+			}
+			b.s.LookAt(target.X, target.Y+1.5, target.Z)
+		case swimming || retry > 0:
+			if navigating {
+				b.pf.Stop()
+				navigating = false
+			}
+			// sprinting in water swims
+			b.s.SetSprinting(behind > sprintDist)
+			b.swimToward(goal)
+			steering = true
+		default:
+			b.pf.SetSprintAllowed(behind > sprintDist)
+			if !navigating || !b.pf.IsNavigating() || goal.Distance(navGoal) > escortRepath {
+				if err := b.pf.NavigateTo(goal.X, goal.Y, goal.Z); err != nil {
+					// no path, e.g. the target took to the water: head
+					// straight for it for a while
+					retry = escortRetryTicks
+					navigating = false
+					break
+				}
+				navigating, steering, navGoal = true, false, goal
+			}
+		}
+
+		if err := b.c.WaitTicks(ctx, 1); err != nil {
+			return err
+		}
+	}
+}
+
+// intercept returns the point distance behind where the target will be in
+// lead ticks, extrapolating its horizontal movement over trail (oldest
+// first). moving is false, with the target's position, if it's standing
+// still.
+func intercept(trail []geom.Vec3, distance float64, lead int) (goal geom.Vec3, moving bool) {
+	now := trail[len(trail)-1]
+	if len(trail) < 2 {
+		return now, false
+	}
+	v := now.Sub(trail[0]).Scale(1 / float64(len(trail)-1))
+	v.Y = 0
+	speed := v.HorizontalLength()
+	if speed < escortStill {
+		return now, false
+	}
+	ahead := now.Add(v.Scale(float64(lead)))
+	return ahead.Sub(v.Scale(distance / speed)), true
+}
@@ -0,0 +1,116 @@
+package behaviors
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-mclib/client/pkg/client/modules/entities"
+	"github.com/go-mclib/client/pkg/client/modules/world"
+	"github.com/go-mclib/client/pkg/geom"
+	dataEntities "github.com/go-mclib/data/pkg/data/entities"
+)
+
+// FoF is a friend-or-foe policy: it reports whether an entity should be attacked.
+type FoF func(e *entities.Entity) bool
+
+// HostileMobs treats monsters as foes and everything else (players included) as friends.
+func HostileMobs(e *entities.Entity) bool {
+	return dataEntities.EntityCategory(e.TypeName) == "monster"
+}
+
+// AllExcept treats every attackable entity as a foe, except the ones with the given UUIDs.
+func AllExcept(friends ...[16]byte) FoF {
+	return func(e *entities.Entity) bool {
+		for _, uuid := range friends {
+			if e.UUID == uuid {
+				return false
+			}
+		}
+		return dataEntities.IsAttackable(e.TypeName)
+	}
+}
+
+// Guard configures GuardArea.
+type Guard struct {
+	Area  world.Region // columns to defend
+	Post  geom.Vec3    // where to stand when the area is clear
+	IsFoe FoF          // nil means HostileMobs
+
+	// VerticalRange limits intruders to this many blocks above/below Post (0 = 16).
+	VerticalRange float64
+	// ScanInterval is how often the area is scanned (0 = 250ms).
+	ScanInterval time.Duration
+}
+
+// GuardArea defends g.Area until ctx is done: it chases and attacks the
+// nearest foe inside the area, then returns to g.Post once the area is clear.
+func (b *Bot) GuardArea(ctx context.Context, g Guard) error {
+	isFoe := g.IsFoe
+	if isFoe == nil {
+		isFoe = HostileMobs
+	}
+	vertical := g.VerticalRange
+	if vertical <= 0 {
+		vertical = 16
+	}
+	interval := g.ScanInterval
+	if interval <= 0 {
+		interval = 250 * time.Millisecond
+	}
+
+	defer func() {
+		b.com.StopAttacking()
+		b.pf.Stop()
+	}()
+
+	var target int32
+	var chaseGoal geom.Vec3
+	chasing := false
+	for {
+		x, y, z := b.s.Position()
+		foe := b.ents.GetClosestEntity(x, y, z, func(e *entities.Entity) bool {
+			return g.Area.Contains(e.X, e.Z) && e.Y > g.Post.Y-vertical && e.Y < g.Post.Y+vertical && isFoe(e)
+		})
+
+		switch {
+		case foe != nil && b.com.IsWithinReach(foe.ID):
+			if chasing {
+				b.pf.Stop()
+				chasing = false
+			}
+			if target != foe.ID {
+				b.c.Logger.Printf("guard: attacking %s (#%d)", foe.TypeName, foe.ID)
+				target = foe.ID
+				b.com.StartAttacking(foe.ID)
+			}
+		case foe != nil:
+			if target != 0 {
+				b.com.StopAttacking()
+				target = 0
+			}
+			pos := geom.Vec3{X: foe.X, Y: foe.Y, Z: foe.Z}
+			// re-path only when the foe moved away from the current goal
+			if !chasing || !b.pf.IsNavigating() || pos.Distance(chaseGoal) > 2 {
+				if err := b.pf.NavigateTo(pos.X, pos.Y, pos.Z); err == nil {
+					chasing, chaseGoal = true, pos
+				}
+			}
+		default:
+			if target != 0 {
+				b.com.StopAttacking()
+				target = 0
+			}
+			if chasing {
+				b.pf.Stop()
+				chasing = false
+			}
+			if !b.pf.IsNavigating() && b.distanceTo(g.Post) > 1.5 {
+				_ = b.pf.NavigateTo(g.Post.X, g.Post.Y, g.Post.Z)
+			}
+		}
+
+		if err := b.sleep(ctx, interval); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package behaviors
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-mclib/client/pkg/client"
+	"github.com/go-mclib/client/pkg/client/modules/inventory"
+	"github.com/go-mclib/client/pkg/client/modules/world"
+	"github.com/go-mclib/client/pkg/geom"
+	"github.com/go-mclib/data/pkg/data/blocks"
+	"github.com/go-mclib/data/pkg/data/items"
+)
+
+// labelConfirmWait is how long LabelChests waits for the server to send the
+// written sign back.
+const labelConfirmWait = 2 * time.Second
+
+// LabelProgress reports on one chest handled by LabelChests.
+type LabelProgress struct {
+	Chest, Sign geom.BlockPos
+	Lines       []string
+	Placed      bool  // a new sign was placed
+	Unchanged   bool  // the sign already read Lines
+	Err         error // nil once the written text was confirmed
+}
+
+// LabelChests provisions the labels of a sorter-style storage room: layout
+// maps chest positions to up to four sign lines (filter expressions such as
+// "#logs" or ":food"). For each chest it finds the wall sign hanging on it
+// and rewrites the sign if it reads differently, or places a sign from the
+// inventory on a free side and writes it, then waits for the server to send
+// the new text back. Chests are visited nearest first; a chest that can't be
+// labelled doesn't stop the others, and the errors are returned together.
+// onProgress, if not nil, is called once per chest.
+func (b *Bot) LabelChests(ctx context.Context, layout map[geom.BlockPos][]string, onProgress func(LabelProgress)) error {
+	x, y, z := b.s.Position()
+	feet := geom.Vec3{X: x, Y: y, Z: z}
+	chests := make([]geom.BlockPos, 0, len(layout))
+	for pos := range layout {
+		chests = append(chests, pos)
+	}
+	slices.SortFunc(chests, func(a, c geom.BlockPos) int {
+		return cmp.Compare(feet.Distance(a.Center()), feet.Distance(c.Center()))
+	})
+
+	var errs []error
+	for _, chest := range chests {
+		p := b.labelChest(ctx, chest, layout[chest])
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if p.Err != nil {
+			errs = append(errs, fmt.Errorf("label chest at %v: %w", chest, p.Err))
+		}
+		if onProgress != nil {
+			onProgress(p)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (b *Bot) labelChest(ctx context.Context, chest geom.BlockPos, lines []string) LabelProgress {
+	p := LabelProgress{Chest: chest, Lines: lines}
+	if len(lines) > 4 {
+		p.Err = fmt.Errorf("%d lines, signs hold 4", len(lines))
+		return p
+	}
+	var text [4]string
+	copy(text[:], lines)
+	if b.w.GetBlock(chest.X, chest.Y, chest.Z) == 0 {
+		p.Err = errors.New("no block loaded there")
+		return p
+	}
+
+	sign, found := b.chestSign(chest)
+	if found {
+		p.Sign = sign
+		if current, ok := b.w.SignText(sign, true); ok && slices.Equal(current, trimLines(text)) {
+			p.Unchanged = true
+			return p
+		}
+		p.Err = b.rewriteSign(ctx, sign, text)
+		return p
+	}
+
+	face, ok := b.freeSide(chest)
+	if !ok {
+		p.Err = errors.New("no free side for a sign")
+		return p
+	}
+	p.Sign, p.Placed = chest.Neighbor(face), true
+	p.Err = b.placeSign(ctx, chest, face, text)
+	return p
+}
+
+// chestSign returns the wall sign hanging on a side of chest.
+func (b *Bot) chestSign(chest geom.BlockPos) (geom.BlockPos, bool) {
+	for _, f := range geom.HorizontalFaces {
+		n := chest.Neighbor(f)
+		blockID, props := blocks.StateProperties(int(b.w.GetBlock(n.X, n.Y, n.Z)))
+		if strings.HasSuffix(blocks.BlockName(blockID), "_wall_sign") && props["facing"] == f.String() {
+			return n, true
+		}
+	}
+	return geom.BlockPos{}, false
+}
+
+// freeSide returns the side of chest with air in front of it nearest to the
+// player.
+func (b *Bot) freeSide(chest geom.BlockPos) (geom.Face, bool) {
+	x, y, z := b.s.Position()
+	feet := geom.Vec3{X: x, Y: y, Z: z}
+	best, found := geom.Face(0), false
+	for _, f := range geom.HorizontalFaces {
+		n := chest.Neighbor(f)
+		if b.w.GetBlock(n.X, n.Y, n.Z) != 0 {
+			continue
+		}
+		if !found || feet.Distance(n.Center()) < feet.Distance(chest.Neighbor(best).Center()) {
+			best, found = f, true
+		}
+	}
+	return best, found
+}
+
+// rewriteSign opens the editor of an existing sign and writes text to it.
+func (b *Bot) rewriteSign(ctx context.Context, sign geom.BlockPos, text [4]string) error {
+	spot, err := b.approach(ctx, sign)
+	if err != nil {
+		return err
+	}
+	point := spot.Point()
+	res := b.w.EditSign(sign, spot.Face, world.HandMain, func() { b.s.LookAt(point.X, point.Y, point.Z) })
+	if res.Err != nil {
+		return res.Err
+	}
+	return b.writeSign(ctx, sign, text)
+}
+
+// placeSign holds a sign from the inventory, places it on face of chest and
+// writes text to it.
+func (b *Bot) placeSign(ctx context.Context, chest geom.BlockPos, face geom.Face, text [4]string) error {
+	if b.signSlot() < 0 {
+		return errors.New("no sign in the inventory")
+	}
+	if _, err := b.approach(ctx, chest); err != nil {
+		return err
+	}
+	// the sign may have moved while walking
+	slot := b.signSlot()
+	if slot < 0 {
+		return errors.New("no sign in the inventory")
+	}
+	hotbar := 8
+	if slot >= inventory.SlotHotbarStart && slot < inventory.SlotHotbarEnd {
+		hotbar = slot - inventory.SlotHotbarStart
+	} else if err := b.inv.SwapToHotbar(slot, hotbar); err != nil {
+		return fmt.Errorf("swap sign to hotbar: %w", err)
+	}
+	if err := b.inv.SetHeldSlot(hotbar); err != nil {
+		return fmt.Errorf("select sign: %w", err)
+	}
+
+	dx, dy, dz := face.Offset()
+	point := chest.Center().Add(geom.Vec3{X: float64(dx) / 2, Y: float64(dy) / 2, Z: float64(dz) / 2})
+	res := b.w.PlaceSign(chest, face, world.HandMain, func() { b.s.LookAt(point.X, point.Y, point.Z) })
+	if res.Err != nil {
+		return res.Err
+	}
+	return b.writeSign(ctx, chest.Neighbor(face), text)
+}
+
+// writeSign writes text to the sign whose editor is open and waits until
+// the server sends it back.
+func (b *Bot) writeSign(ctx context.Context, sign geom.BlockPos, text [4]string) error {
+	e, _ := b.w.OpenSignEditor()
+	if err := b.w.WriteSign(sign, text); err != nil {
+		return err
+	}
+	want := trimLines(text)
+	for range client.Ticks(labelConfirmWait) {
+		if got, ok := b.w.SignText(sign, e.Front); ok && slices.Equal(got, want) {
+			return nil
+		}
+		if err := b.c.WaitTicks(ctx, 1); err != nil {
+			return err
+		}
+	}
+	got, _ := b.w.SignText(sign, e.Front)
+	return fmt.Errorf("sign at %v reads %q after writing %q", sign, got, want)
+}
+
+// signSlot returns the inventory slot of a sign that hangs on walls, or -1.
+func (b *Bot) signSlot() int {
+	for i := inventory.SlotMainStart; i < inventory.SlotHotbarEnd; i++ {
+		s := b.inv.GetSlot(i)
+		if s.IsEmpty() {
+			continue
+		}
+		name := items.ItemName(s.ID)
+		if strings.HasSuffix(name, "_sign") && !strings.HasSuffix(name, "_hanging_sign") {
+			return i
+		}
+	}
+	return -1
+}
+
+// trimLines returns text as SignText reports it.
+func trimLines(text [4]string) []string {
+	lines := make([]string, len(text))
+	for i, l := range text {
+		lines[i] = strings.TrimSpace(l)
+	}
+	return lines
+}
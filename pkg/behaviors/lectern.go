@@ -0,0 +1,46 @@
+package behaviors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-mclib/client/pkg/client"
+	"github.com/go-mclib/client/pkg/client/modules/inventory"
+	"github.com/go-mclib/client/pkg/geom"
+)
+
+// lecternContentWait is how long ReadLectern waits for the book after the
+// lectern menu opened.
+const lecternContentWait = time.Second
+
+// ReadLectern walks to the lectern at pos, opens it and returns its book. The
+// lectern is closed again before returning.
+func (b *Bot) ReadLectern(ctx context.Context, pos geom.BlockPos) (*inventory.Book, error) {
+	if err := b.OpenContainer(ctx, pos); err != nil {
+		return nil, err
+	}
+	defer func() {
+		if b.inv.ContainerOpen() {
+			_ = b.inv.CloseContainer()
+		}
+	}()
+	if t := b.inv.ContainerMenuType(); t != inventory.MenuLectern {
+		return nil, fmt.Errorf("block at %v opened menu %d, not a lectern", pos, t)
+	}
+
+	// the slot contents follow the open screen packet
+	for ticks := client.Ticks(lecternContentWait); ; ticks-- {
+		book, err := b.inv.LecternBook()
+		if err == nil || (b.inv.ContainerSlotCount() > 0 && !errors.Is(err, inventory.ErrNotABook)) {
+			return book, err
+		}
+		if ticks <= 0 {
+			return nil, fmt.Errorf("lectern at %v: %w", pos, err)
+		}
+		if err := b.c.WaitTicks(ctx, 1); err != nil {
+			return nil, err
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package behaviors
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-mclib/client/pkg/client/modules/entities"
+	"github.com/go-mclib/client/pkg/client/modules/inventory"
+	"github.com/go-mclib/client/pkg/geom"
+	dataEntities "github.com/go-mclib/data/pkg/data/entities"
+	"github.com/go-mclib/data/pkg/data/items"
+)
+
+const mendingEnchantment = "minecraft:mending"
+
+var experienceOrbTypeID = dataEntities.EntityTypeID("minecraft:experience_orb")
+
+// Mending configures MendGear.
+type Mending struct {
+	// Below picks the gear to repair: mending items with less than this
+	// fraction of their durability left (0 = 0.5).
+	Below float64
+	// Until is the fraction of durability an item is repaired to (0 = 1).
+	Until float64
+	// CollectRange walks to experience orbs up to this far from where the
+	// bot started, then back. 0 stays put, for farms that deliver the orbs.
+	CollectRange float64
+	// OnProgress is called when an item being repaired gains durability,
+	// and once more when it's done.
+	OnProgress func(p MendProgress)
+}
+
+// MendProgress is the repair state of one item.
+type MendProgress struct {
+	Item              string
+	Damage, MaxDamage int32
+	Done              bool
+	Queued            int // items still waiting for their turn
+}
+
+// MendGear repairs damaged mending gear with the experience collected at an
+// XP farm. Vanilla mending spends orbs on damaged mending items in the
+// equipment slots, so worn armor repairs in place and the rest takes turns
+// in the off hand, most damaged first. It returns once no item needs repair,
+// with the off hand item from before back in the off hand.
+func (b *Bot) MendGear(ctx context.Context, m Mending) error {
+	below, until := m.Below, m.Until
+	if below <= 0 {
+		below = 0.5
+	}
+	if until <= 0 {
+		until = 1
+	}
+	x, y, z := b.s.Position()
+	home := geom.Vec3{X: x, Y: y, Z: z}
+
+	offhand := b.inv.GetOffhand()
+	defer b.restoreOffhand(offhand)
+
+	// damage last reported per slot, for progress
+	reported := map[int]int32{}
+	var current *items.ItemStack
+	for {
+		queue := b.mendQueue(below)
+		if current != nil {
+			if slot := b.mendingSlot(current, until); slot < 0 {
+				// repaired, or gone (broken or moved away)
+				if m.OnProgress != nil {
+					damage, max := inventory.Durability(b.inv.GetOffhand())
+					m.OnProgress(MendProgress{Item: items.ItemName(current.ID), Damage: damage, MaxDamage: max, Done: true, Queued: len(queue)})
+				}
+				current = nil
+			}
+		}
+		if current == nil {
+			if len(queue) == 0 && !b.armorNeedsMending(until) {
+				return nil
+			}
+			if len(queue) > 0 {
+				if err := b.inv.SwapToOffhand(queue[0]); err != nil {
+					return err
+				}
+				current = b.inv.GetOffhand()
+				queue = queue[1:]
+			}
+		}
+
+		if m.OnProgress != nil {
+			for _, slot := range []int{inventory.SlotOffhand, inventory.SlotArmorHead, inventory.SlotArmorChest, inventory.SlotArmorLegs, inventory.SlotArmorFeet} {
+				s := b.inv.GetSlot(slot)
+				damage, max := inventory.Durability(s)
+				if max == 0 || b.inv.EnchantmentLevel(s, mendingEnchantment) == 0 {
+					continue
+				}
+				if last, ok := reported[slot]; ok && damage < last {
+					m.OnProgress(MendProgress{Item: items.ItemName(s.ID), Damage: damage, MaxDamage: max, Queued: len(queue)})
+				}
+				reported[slot] = damage
+			}
+		}
+
+		if err := b.collectOrb(ctx, home, m.CollectRange); err != nil {
+			return err
+		}
+		if err := b.sleep(ctx, 250*time.Millisecond); err != nil {
+			return err
+		}
+	}
+}
+
+// needsMending reports whether s has mending and less than below of its
+// durability left.
+func (b *Bot) needsMending(s *items.ItemStack, below float64) bool {
+	_, max := inventory.Durability(s)
+	return max > 0 && inventory.DurabilityLeft(s) < below && b.inv.EnchantmentLevel(s, mendingEnchantment) > 0
+}
+
+// mendQueue returns the inventory slots holding gear to repair, most damaged
+// first. Worn armor and the off hand repair in place and aren't listed.
+func (b *Bot) mendQueue(below float64) []int {
+	var queue []int
+	for slot := inventory.SlotMainStart; slot < inventory.SlotHotbarEnd; slot++ {
+		if b.needsMending(b.inv.GetSlot(slot), below) {
+			queue = append(queue, slot)
+		}
+	}
+	left := func(slot int) float64 { return inventory.DurabilityLeft(b.inv.GetSlot(slot)) }
+	for i := 1; i < len(queue); i++ {
+		for j := i; j > 0 && left(queue[j]) < left(queue[j-1]); j-- {
+			queue[j], queue[j-1] = queue[j-1], queue[j]
+		}
+	}
+	return queue
+}
+
+// mendingSlot returns the off hand slot while it still holds item and it's
+// below until, -1 otherwise.
+func (b *Bot) mendingSlot(item *items.ItemStack, until float64) int {
+	s := b.inv.GetOffhand()
+	if s.IsEmpty() || s.ID != item.ID || !b.needsMending(s, until) {
+		return -1
+	}
+	return inventory.SlotOffhand
+}
+
+// armorNeedsMending reports whether worn mending armor is below until.
+func (b *Bot) armorNeedsMending(until float64) bool {
+	head, chest, legs, feet := b.inv.GetArmor()
+	for _, s := range []*items.ItemStack{head, chest, legs, feet} {
+		if b.needsMending(s, until) {
+			return true
+		}
+	}
+	return false
+}
+
+// collectOrb walks to the nearest experience orb within rng of home, then
+// back home.
+func (b *Bot) collectOrb(ctx context.Context, home geom.Vec3, rng float64) error {
+	if rng <= 0 {
+		return nil
+	}
+	orb := b.ents.GetClosestEntity(home.X, home.Y, home.Z, func(e *entities.Entity) bool {
+		return e.TypeID == experienceOrbTypeID && geom.Vec3{X: e.X, Y: e.Y, Z: e.Z}.Distance(home) <= rng
+	})
+	if orb == nil {
+		if b.distanceTo(home) > 1 {
+			return b.GoTo(ctx, home)
+		}
+		return nil
+	}
+	// orbs fly to players within 8 blocks; getting close is enough
+	if err := b.GoTo(ctx, geom.Vec3{X: orb.X, Y: orb.Y, Z: orb.Z}); err != nil && ctx.Err() != nil {
+		return err
+	}
+	return nil
+}
+
+// restoreOffhand swaps the item that was in the off hand before back into it.
+func (b *Bot) restoreOffhand(item *items.ItemStack) {
+	if s := b.inv.GetOffhand(); item.IsEmpty() || !s.IsEmpty() && s.ID == item.ID {
+		return
+	}
+	for slot := inventory.SlotMainStart; slot < inventory.SlotHotbarEnd; slot++ {
+		if s := b.inv.GetSlot(slot); !s.IsEmpty() && s.ID == item.ID {
+			_ = b.inv.SwapToOffhand(slot)
+			return
+		}
+	}
+}
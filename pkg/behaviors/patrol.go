@@ -0,0 +1,42 @@
+package behaviors
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-mclib/client/pkg/geom"
+)
+
+// Patrol walks the waypoints in order, once or (if loop) until ctx is done.
+// Unreachable waypoints are logged and skipped; Patrol only fails when none
+// of the waypoints in a round could be reached.
+func (b *Bot) Patrol(ctx context.Context, waypoints []geom.Vec3, loop bool) error {
+	if len(waypoints) == 0 {
+		return errors.New("no waypoints")
+	}
+	for {
+		reached := 0
+		for i, wp := range waypoints {
+			err := b.GoTo(ctx, wp)
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if err != nil {
+				b.c.Logger.Printf("patrol: waypoint %d %v: %v", i, wp, err)
+				continue
+			}
+			reached++
+		}
+		if reached == 0 {
+			return ErrNavigation
+		}
+		if !loop {
+			return nil
+		}
+		// a tick between rounds, so a round that's done at once (standing
+		// on the only waypoint) doesn't spin
+		if err := b.c.WaitTicks(ctx, 1); err != nil {
+			return err
+		}
+	}
+}
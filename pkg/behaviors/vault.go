@@ -0,0 +1,70 @@
+package behaviors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/go-mclib/client/pkg/client/modules/inventory"
+	"github.com/go-mclib/client/pkg/client/modules/world"
+	"github.com/go-mclib/client/pkg/geom"
+	ns "github.com/go-mclib/protocol/java_protocol/net_structures"
+)
+
+// ErrVaultLocked is returned by UnlockVault when the vault won't take the
+// player's key: it's inactive, or it already rewarded the player.
+var ErrVaultLocked = errors.New("vault can't be unlocked")
+
+// UnlockVault walks to the vault at pos, holds the matching trial key from
+// the inventory and uses it on the vault. It returns once the vault starts
+// ejecting its loot; pick the items up with the entities module.
+func (b *Bot) UnlockVault(ctx context.Context, pos geom.BlockPos) error {
+	v, ok := b.w.GetVault(pos)
+	if !ok {
+		return fmt.Errorf("no vault at %v", pos)
+	}
+	uuid, err := ns.UUIDFromString(b.c.LoginData.UUID)
+	if err != nil {
+		return fmt.Errorf("player uuid: %w", err)
+	}
+	slot := b.inv.FindItem(v.Key)
+	if slot < 0 {
+		return fmt.Errorf("no key for vault at %v", pos)
+	}
+
+	spot, err := b.approach(ctx, pos)
+	if err != nil {
+		return err
+	}
+	// the vault activates once the player is within 4 blocks
+	if err := b.waitFor(ctx, func() bool {
+		v, ok = b.w.GetVault(pos)
+		return ok && v.CanUnlock(uuid)
+	}); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("vault at %v: %w", pos, ErrVaultLocked)
+	}
+
+	// the key may have moved while walking
+	if slot = b.inv.FindItem(v.Key); slot < 0 {
+		return fmt.Errorf("no key for vault at %v", pos)
+	}
+	hotbar := 8
+	if slot >= inventory.SlotHotbarStart && slot < inventory.SlotHotbarEnd {
+		hotbar = slot - inventory.SlotHotbarStart
+	} else if err := b.inv.SwapToHotbar(slot, hotbar); err != nil {
+		return fmt.Errorf("swap key to hotbar: %w", err)
+	}
+	if err := b.inv.SetHeldSlot(hotbar); err != nil {
+		return fmt.Errorf("select key: %w", err)
+	}
+
+	point := spot.Point()
+	res := b.w.UnlockVault(pos, spot.Face, world.HandMain, func() { b.s.LookAt(point.X, point.Y, point.Z) })
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return res.Err
+}
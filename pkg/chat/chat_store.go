@@ -216,3 +216,13 @@ func (c *ChatChainStore) ClearPlayerState(playerUUID ns.UUID) {
 	defer c.mu.Unlock()
 	delete(c.playerStates, playerUUID)
 }
+
+// ResetSession starts a new outbound chain for a new chat session: message
+// indices count from 0 again and messages signed under the old session are
+// forgotten. Inbound state is kept.
+func (c *ChatChainStore) ResetSession() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.messageIndex = 0
+	c.outboundHistory = c.outboundHistory[:0]
+}
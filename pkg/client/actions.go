@@ -1,6 +1,8 @@
 package client
 
 import (
+	"fmt"
+
 	"github.com/go-mclib/data/pkg/packets"
 	ns "github.com/go-mclib/protocol/java_protocol/net_structures"
 )
@@ -36,7 +38,36 @@ func (c *Client) CancelBreakBlock(x, y, z int, face int8) error {
 // PlaceBlock places a block from the given hand at the specified position and face.
 // cursorX, cursorY, cursorZ are positions of the crosshair on the block (0.0 to 1.0).
 func (c *Client) PlaceBlock(x, y, z int, face int8, hand int8, cursorX, cursorY, cursorZ float32) error {
-	return c.WritePacket(&packets.C2SUseItemOn{
+	return c.WritePacket(useItemOn(c, x, y, z, face, hand, cursorX, cursorY, cursorZ))
+}
+
+// PlaceBlockSneaking is PlaceBlock with sneaking held for the interaction
+// (shift-right-click), so placing against chests, furnaces etc. doesn't open
+// their GUI. The previous sneak state is restored afterwards.
+func (c *Client) PlaceBlockSneaking(x, y, z int, face int8, hand int8, cursorX, cursorY, cursorZ float32) error {
+	release, err := c.holdSneak()
+	if err != nil {
+		return err
+	}
+	defer release()
+	// queued (not written directly) so it goes out after the sneak input
+	c.SendPacket(useItemOn(c, x, y, z, face, hand, cursorX, cursorY, cursorZ))
+	return nil
+}
+
+// InteractBlock right-clicks on a block (doors, buttons, levers, etc.).
+func (c *Client) InteractBlock(x, y, z int, face int8, hand int8, cursorX, cursorY, cursorZ float32) error {
+	return c.PlaceBlock(x, y, z, face, hand, cursorX, cursorY, cursorZ)
+}
+
+// InteractBlockSneaking right-clicks on a block while sneaking (see
+// PlaceBlockSneaking).
+func (c *Client) InteractBlockSneaking(x, y, z int, face int8, hand int8, cursorX, cursorY, cursorZ float32) error {
+	return c.PlaceBlockSneaking(x, y, z, face, hand, cursorX, cursorY, cursorZ)
+}
+
+func useItemOn(c *Client, x, y, z int, face int8, hand int8, cursorX, cursorY, cursorZ float32) *packets.C2SUseItemOn {
+	return &packets.C2SUseItemOn{
 		Hand:            ns.VarInt(hand),
 		Location:        ns.Position{X: x, Y: y, Z: z},
 		Face:            ns.VarInt(face),
@@ -46,12 +77,18 @@ func (c *Client) PlaceBlock(x, y, z int, face int8, hand int8, cursorX, cursorY,
 		InsideBlock:     false,
 		WorldBorderHit:  false,
 		Sequence:        ns.VarInt(c.NextBISequence()),
-	})
+	}
 }
 
-// InteractBlock right-clicks on a block (doors, buttons, levers, etc.).
-func (c *Client) InteractBlock(x, y, z int, face int8, hand int8, cursorX, cursorY, cursorZ float32) error {
-	return c.PlaceBlock(x, y, z, face, hand, cursorX, cursorY, cursorZ)
+// holdSneak engages sneaking through the physics module, which owns the
+// input state sent to the server. The returned func restores the previous state.
+func (c *Client) holdSneak() (func(), error) {
+	if m := c.Module("physics"); m != nil {
+		if sh, ok := m.(SneakHolder); ok {
+			return sh.HoldSneak(), nil
+		}
+	}
+	return nil, fmt.Errorf("physics module not registered")
 }
 
 // SwingArm swings the player's arm (animation).
@@ -41,32 +41,60 @@ func (c *Client) initializeAuth(ctx context.Context) error {
 	}
 	c.Username = ld.Username
 
-	cert, err := auth.FetchMojangCertificate(ld.AccessToken)
-	if err != nil {
-		return fmt.Errorf("fetch certificate: %w", err)
+	c.ChatSigner = chat.NewChatSigner()
+	if err := c.installChatKeys(); err != nil {
+		return err
 	}
 
-	c.ChatSigner = chat.NewChatSigner()
-	c.ChatSigner.SetKeys(cert.PrivateKey, cert.PublicKey)
+	c.SessionClient = session_server.NewSessionServerClient()
+	return nil
+}
 
-	playerUUID, err := ns.UUIDFromString(ld.UUID)
+// RenewChatKeys fetches a new chat key pair and Mojang certificate for the
+// chat session and installs them on ChatSigner, logging in again with the
+// refresh token first if the access token expired. The chat module calls it
+// when the key is about to expire (see chat.Module.RenewSession).
+func (c *Client) RenewChatKeys(ctx context.Context) error {
+	if !c.OnlineMode || c.ChatSigner == nil {
+		return fmt.Errorf("no chat signer (offline mode)")
+	}
+	if !c.LoginData.ExpiresAt.IsZero() && time.Now().After(c.LoginData.ExpiresAt) {
+		authClient := auth.NewClient(auth.AuthClientConfig{
+			ClientID: c.ClientID,
+			Username: c.Username,
+		})
+		ld, err := authClient.LoginWithRefreshToken(ctx, c.LoginData.RefreshToken)
+		if err != nil {
+			return fmt.Errorf("refresh login: %w", err)
+		}
+		c.LoginData = ld
+	}
+	return c.installChatKeys()
+}
+
+// installChatKeys fetches the player's chat key pair and certificate and sets
+// them on ChatSigner.
+func (c *Client) installChatKeys() error {
+	cert, err := auth.FetchMojangCertificate(c.LoginData.AccessToken)
+	if err != nil {
+		return fmt.Errorf("fetch certificate: %w", err)
+	}
+	playerUUID, err := ns.UUIDFromString(c.LoginData.UUID)
 	if err != nil {
 		return fmt.Errorf("parse player uuid: %w", err)
 	}
-	c.ChatSigner.PlayerUUID = playerUUID
-	c.ChatSigner.AddPlayerPublicKey(playerUUID, cert.PublicKey)
-
-	c.ChatSigner.X509PublicKey = cert.PublicKeyBytes
-
 	mojangSig, err := base64.StdEncoding.DecodeString(cert.Certificate.PublicKeySignatureV2)
 	if err != nil {
 		return fmt.Errorf("decode mojang signature: %w", err)
 	}
+
+	c.ChatSigner.SetKeys(cert.PrivateKey, cert.PublicKey)
+	c.ChatSigner.PlayerUUID = playerUUID
+	c.ChatSigner.AddPlayerPublicKey(playerUUID, cert.PublicKey)
+	c.ChatSigner.X509PublicKey = cert.PublicKeyBytes
 	c.ChatSigner.SessionKey = mojangSig
 	if expiry, err := time.Parse(time.RFC3339Nano, cert.Certificate.ExpiresAt); err == nil {
 		c.ChatSigner.KeyExpiry = expiry
 	}
-
-	c.SessionClient = session_server.NewSessionServerClient()
 	return nil
 }
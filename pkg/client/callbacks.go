@@ -0,0 +1,48 @@
+package client
+
+import "sync"
+
+// Callbacks is a list of callbacks that can be removed again, for On*
+// registrations that helpers make and drop at runtime (one-shot waits).
+// Adding and removing is safe while the list is being run.
+type Callbacks[F any] struct {
+	mu   sync.Mutex
+	next int
+	list []callback[F]
+}
+
+type callback[F any] struct {
+	id int
+	fn F
+}
+
+// Add appends fn and returns a function that removes it.
+func (cs *Callbacks[F]) Add(fn F) (remove func()) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	id := cs.next
+	cs.next++
+	cs.list = append(cs.list, callback[F]{id: id, fn: fn})
+	return func() {
+		cs.mu.Lock()
+		defer cs.mu.Unlock()
+		for i, cb := range cs.list {
+			if cb.id == id {
+				// copy so a running All snapshot isn't disturbed
+				cs.list = append(cs.list[:i:i], cs.list[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// All returns the callbacks in registration order.
+func (cs *Callbacks[F]) All() []F {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	fns := make([]F, len(cs.list))
+	for i, cb := range cs.list {
+		fns[i] = cb.fn
+	}
+	return fns
+}
@@ -0,0 +1,24 @@
+package client
+
+import "testing"
+
+func TestCallbacksRemove(t *testing.T) {
+	var cs Callbacks[func() int]
+	cs.Add(func() int { return 1 })
+	remove := cs.Add(func() int { return 2 })
+	cs.Add(func() int { return 3 })
+
+	running := cs.All()
+	remove()
+	remove() // removing twice is harmless
+	var got []int
+	for _, fn := range cs.All() {
+		got = append(got, fn())
+	}
+	if len(got) != 2 || got[0] != 1 || got[1] != 3 {
+		t.Fatalf("got %v, want [1 3]", got)
+	}
+	if len(running) != 3 || running[1]() != 2 {
+		t.Fatal("removing changed a running snapshot")
+	}
+}
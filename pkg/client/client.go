@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"sync"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -26,9 +27,24 @@ type Client struct {
 	ClientID   string
 	Brand      string
 
+	// DispatchLagWarning logs a warning when module dispatch falls this far
+	// behind reading packets (0 = never; default: DefaultDispatchLagWarning).
+	DispatchLagWarning time.Duration
+
+	// PacketHistorySize is how many received packets are kept for DumpState
+	// (0 = none; default: DefaultPacketHistorySize).
+	PacketHistorySize int
+	historyMu         sync.Mutex
+	history           []RecordedPacket
+	historyNext       int
+
 	// reconnection
 	MaxReconnectAttempts int
-	shouldReconnect      bool
+	// ReconnectPolicies decides per disconnect class whether and when to
+	// reconnect (default: DefaultReconnectPolicies).
+	ReconnectPolicies map[DisconnectClass]ReconnectPolicy
+	shouldReconnect   bool
+	disconnect        disconnectState
 
 	// TUI
 	Interactive bool
@@ -47,6 +63,9 @@ type Client struct {
 	modules       []Module
 	modulesByName map[string]Module
 	handlers      []Handler
+	toggleMu      sync.Mutex
+	disabled      map[string]bool // replaced, not modified (see toggle.go)
+	onToggle      []func(name string, enabled bool)
 
 	// lifecycle callbacks
 	onConnect    []func()
@@ -58,6 +77,36 @@ type Client struct {
 	resolvedHost string
 	resolvedPort string
 
+	// Watchdog, if set, runs watched module callbacks with a deadline (see Watched).
+	Watchdog *Watchdog
+
+	// per-name counters for Watched callback keys
+	watchMu     sync.Mutex
+	watchCounts map[string]int
+
+	// ConfigPath, if set, is loaded and watched by helpers.Run (see LoadConfig).
+	ConfigPath string
+	config     configState
+
+	// Seed drives all Rand streams; 0 picks a random one on first use (see RandSeed).
+	Seed        uint64
+	randMu      sync.Mutex
+	randStreams map[string]*Rand
+
+	// StorageDir is the root directory of Storage (default: DefaultStorageDir).
+	StorageDir string
+	storageMu  sync.Mutex
+	storage    *Storage
+
+	// WorkBudget is how long sliced jobs may run per tick (default:
+	// DefaultWorkBudget; see Submit).
+	WorkBudget time.Duration
+	work       workState
+
+	// Clock drives the physics tick loop (default: RealClock); a ManualClock
+	// runs the client in lockstep for tests and simulation.
+	Clock Clock
+
 	// block action sequence counter (matches vanilla SequencedPredictiveAction)
 	blockSequence int32
 
@@ -87,6 +136,12 @@ func New(address, username string, onlineMode bool) *Client {
 		OnlineMode:           onlineMode,
 		Brand:                "vanilla",
 		MaxReconnectAttempts: 5,
+		DispatchLagWarning:   DefaultDispatchLagWarning,
+		PacketHistorySize:    DefaultPacketHistorySize,
+		ReconnectPolicies:    DefaultReconnectPolicies(),
+		StorageDir:           DefaultStorageDir,
+		WorkBudget:           DefaultWorkBudget,
+		Clock:                RealClock{},
 		OutgoingPacketQueue:  make(chan jp.Packet, 100),
 		Logger:               log.New(os.Stdout, "", log.LstdFlags),
 		modulesByName:        make(map[string]Module),
@@ -152,6 +207,11 @@ func (c *Client) NextBISequence() int32 {
 	return c.blockSequence
 }
 
+// LastBISequence returns the sequence number of the last block/item action sent.
+func (c *Client) LastBISequence() int32 {
+	return c.blockSequence
+}
+
 // SendChatMessage forwards to the chat module. Satisfies tui.ClientInterface.
 func (c *Client) SendChatMessage(msg string) error {
 	if m := c.Module("chat"); m != nil {
@@ -188,6 +248,9 @@ func (c *Client) EnableInput() {
 
 // Disconnect closes the connection. If force is true, no reconnect is attempted.
 func (c *Client) Disconnect(force bool) error {
+	if force {
+		c.setPendingDisconnect(DisconnectEvent{Class: DisconnectRequested, Text: "disconnected by client"})
+	}
 	c.shouldReconnect = !force
 	return c.TCPClient.Close()
 }
@@ -197,6 +260,8 @@ func (c *Client) Swarm() *Swarm { return c.swarm }
 
 // ConnectAndStart connects, performs auth, and enters the module dispatch loop.
 func (c *Client) ConnectAndStart(ctx context.Context) error {
+	defer c.flushStorage()
+
 	if c.Interactive {
 		tuiProgram, writer := tui.Start(c)
 		c.tuiProgram = tuiProgram
@@ -247,7 +312,9 @@ func (c *Client) runConnectionLoop(ctx context.Context) error {
 
 		c.Logger.Printf("connection error: %v", err)
 
-		if !c.shouldReconnect || maxAttempts == 0 {
+		ev, disconnected := c.finishDisconnect(err)
+		policy := c.reconnectPolicy(ev)
+		if !disconnected || !policy.Reconnect || maxAttempts == 0 {
 			c.Logger.Printf("not reconnecting, exiting...")
 			time.Sleep(500 * time.Millisecond)
 			return err
@@ -260,12 +327,16 @@ func (c *Client) runConnectionLoop(ctx context.Context) error {
 			return err
 		}
 		if maxAttempts == -1 {
-			c.Logger.Printf("reconnecting in 3 seconds... (attempt %d/∞)", attempts)
+			c.Logger.Printf("reconnecting in %s... (attempt %d/∞)", policy.Delay, attempts)
 		} else {
-			c.Logger.Printf("reconnecting in 3 seconds... (attempt %d/%d)", attempts, maxAttempts)
+			c.Logger.Printf("reconnecting in %s... (attempt %d/%d)", policy.Delay, attempts, maxAttempts)
 		}
 
-		time.Sleep(3 * time.Second)
+		select {
+		case <-time.After(policy.Delay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
 
 		if maxAttempts == -1 {
 			c.Logger.Printf("attempting to reconnect indefinitely... (attempt %d)", attempts)
@@ -313,6 +384,10 @@ func (c *Client) connectAndStartOnce(ctx context.Context) error {
 		for {
 			select {
 			case pkt := <-c.OutgoingPacketQueue:
+				if f, ok := pkt.(*flushMarker); ok {
+					close(f.done)
+					continue
+				}
 				if err := c.WritePacket(pkt); err != nil {
 					c.Logger.Println("error writing packet from queue:", err)
 				}
@@ -323,23 +398,14 @@ func (c *Client) connectAndStartOnce(ctx context.Context) error {
 	}()
 
 	// packet loop
-	for {
-		wire, err := c.ReadWirePacket()
-		if err != nil {
-			c.Logger.Println("read packet error:", err)
-			c.shouldReconnect = true
-			if c.queueDone != nil {
-				close(c.queueDone)
-				c.queueDone = nil
-			}
-			c.FireDisconnect()
-			return err
-		}
-		for _, m := range c.modules {
-			m.HandlePacket(wire)
-		}
-		for _, h := range c.handlers {
-			h(c, wire)
-		}
+	err = c.runPacketLoop()
+	c.Logger.Println("read packet error:", err)
+	c.shouldReconnect = true
+	if c.queueDone != nil {
+		close(c.queueDone)
+		c.queueDone = nil
 	}
+	c.FireDisconnect()
+	c.flushStorage()
+	return err
 }
@@ -0,0 +1,162 @@
+package client
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// TickDuration is the length of a game tick (20 ticks per second).
+const TickDuration = 50 * time.Millisecond
+
+// Clock drives the physics tick loop and tells tick-driven modules the time.
+// The default, RealClock, ticks on wall-clock time; a ManualClock only
+// advances when stepped, so tests and simulators run the client in lockstep.
+type Clock interface {
+	// Run calls tick once per tick until ctx is done.
+	Run(ctx context.Context, tick func())
+	// Now returns the current time.
+	Now() time.Time
+}
+
+// RealClock ticks every TickDuration of wall-clock time.
+type RealClock struct{}
+
+func (RealClock) Run(ctx context.Context, tick func()) {
+	ticker := time.NewTicker(TickDuration)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			tick()
+		}
+	}
+}
+
+func (RealClock) Now() time.Time { return time.Now() }
+
+// ManualClock advances only through Step. Its time starts at the given
+// instant and moves TickDuration per step, so timers measured with
+// Client.Now and waits with Client.WaitTicks are deterministic.
+type ManualClock struct {
+	stepMu sync.Mutex // held while stepping; ticks never overlap
+
+	mu    sync.Mutex
+	now   time.Time
+	ticks uint64
+	tick  func() // of the running loop, nil if none
+	run   uint64 // counts Run calls, so a stopping loop doesn't clear its successor
+}
+
+// NewManualClock returns a clock stopped at start.
+func NewManualClock(start time.Time) *ManualClock {
+	return &ManualClock{now: start}
+}
+
+// Run registers tick with the clock until ctx is done. Step calls it.
+func (c *ManualClock) Run(ctx context.Context, tick func()) {
+	c.mu.Lock()
+	c.run++
+	run := c.run
+	c.tick = tick
+	c.mu.Unlock()
+	<-ctx.Done()
+
+	// wait out a step in progress so it doesn't call a stopped loop's tick
+	c.stepMu.Lock()
+	defer c.stepMu.Unlock()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.run == run {
+		c.tick = nil
+	}
+}
+
+// Step advances the clock by n ticks, running the tick loop (if one is
+// running) once per tick on the calling goroutine. Returns the number of
+// ticks the loop ran. Must not be called from a tick.
+func (c *ManualClock) Step(n int) int {
+	c.stepMu.Lock()
+	defer c.stepMu.Unlock()
+	ran := 0
+	for range n {
+		c.mu.Lock()
+		c.now = c.now.Add(TickDuration)
+		c.ticks++
+		tick := c.tick
+		c.mu.Unlock()
+		if tick != nil {
+			tick()
+			ran++
+		}
+	}
+	return ran
+}
+
+// Ticks returns how many ticks the clock was stepped.
+func (c *ManualClock) Ticks() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ticks
+}
+
+func (c *ManualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Now returns the client clock's time. Modules use it for windows and
+// cooldowns tied to the tick loop instead of time.Now.
+func (c *Client) Now() time.Time {
+	if c.Clock == nil {
+		return time.Now()
+	}
+	return c.Clock.Now()
+}
+
+// Ticks converts a duration to whole ticks, at least 1 for a positive d.
+func Ticks(d time.Duration) int {
+	if d <= 0 {
+		return 0
+	}
+	return max(1, int(d/TickDuration))
+}
+
+// WaitTicks waits until n physics ticks have passed, or ctx is done. Under a
+// ManualClock it returns only as the clock is stepped, so waits are counted
+// in game ticks rather than wall-clock time. Without a physics module it
+// sleeps n ticks' worth of wall-clock time. Returns an error if the tick loop
+// stopped.
+func (c *Client) WaitTicks(ctx context.Context, n int) error {
+	ts, ok := c.Module("physics").(TickScheduler)
+	if !ok {
+		select {
+		case <-time.After(time.Duration(n) * TickDuration):
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	for range n {
+		select {
+		case err := <-ts.Schedule(TickStart):
+			if err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// AfterTicks runs WaitTicks in the background; the channel receives its
+// result. Cancel ctx to stop waiting.
+func (c *Client) AfterTicks(ctx context.Context, n int) <-chan error {
+	ch := make(chan error, 1)
+	go func() { ch <- c.WaitTicks(ctx, n) }()
+	return ch
+}
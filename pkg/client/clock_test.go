@@ -0,0 +1,52 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestManualClockLockstep(t *testing.T) {
+	start := time.Unix(1000, 0)
+	clock := NewManualClock(start)
+
+	// stepping without a loop only advances time
+	if ran := clock.Step(2); ran != 0 {
+		t.Errorf("Step ran %d ticks without a loop", ran)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	ticks := 0
+	go func() {
+		clock.Run(ctx, func() { ticks++ })
+		close(stopped)
+	}()
+	for clock.Step(1) == 0 {
+		// wait for Run to register
+	}
+	if ran := clock.Step(3); ran != 3 || ticks != 4 {
+		t.Errorf("Step(3) ran %d, loop saw %d ticks, want 3 and 4", ran, ticks)
+	}
+	if got, want := clock.Now(), start.Add(time.Duration(clock.Ticks())*TickDuration); !got.Equal(want) {
+		t.Errorf("Now() = %v, want %v", got, want)
+	}
+
+	cancel()
+	<-stopped
+	if ran := clock.Step(1); ran != 0 {
+		t.Errorf("Step ran %d ticks after the loop stopped", ran)
+	}
+}
+
+func TestWaitTicksWithoutPhysics(t *testing.T) {
+	c := &Client{modulesByName: map[string]Module{}}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := c.WaitTicks(ctx, 100); err != context.Canceled {
+		t.Errorf("WaitTicks on a cancelled context = %v, want context.Canceled", err)
+	}
+	if err := c.WaitTicks(context.Background(), 1); err != nil {
+		t.Errorf("WaitTicks(1) = %v", err)
+	}
+}
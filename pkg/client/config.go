@@ -0,0 +1,167 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// Config is a JSON config file with one section per module (keyed by module
+// name) plus free-form sections for bots:
+//
+//	{
+//	  "pathfinding": {"max_nodes": 20000},
+//	  "world": {"interact_retries": 4, "interact_ack_timeout": "2s"},
+//	  "chatbot": {"rate_limit": 3}
+//	}
+type Config struct {
+	Path     string
+	ModTime  time.Time
+	Sections map[string]json.RawMessage
+}
+
+// Section decodes the named section into v. Returns false if the section is absent.
+func (cfg *Config) Section(name string, v any) (bool, error) {
+	if cfg == nil {
+		return false, nil
+	}
+	raw, ok := cfg.Sections[name]
+	if !ok {
+		return false, nil
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return true, fmt.Errorf("config section %q: %w", name, err)
+	}
+	return true, nil
+}
+
+// Duration is a time.Duration that decodes from JSON strings like "1.5s".
+type Duration time.Duration
+
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// configState is the loaded config and its reload callbacks.
+type configState struct {
+	mu       sync.Mutex
+	current  *Config
+	onReload []func(cfg *Config)
+}
+
+// Config returns the last successfully applied config, or nil.
+func (c *Client) Config() *Config {
+	c.config.mu.Lock()
+	defer c.config.mu.Unlock()
+	return c.config.current
+}
+
+// OnConfigReload is called after a config was applied to all modules (also on
+// the initial LoadConfig), so bots can re-read their own sections.
+func (c *Client) OnConfigReload(cb func(cfg *Config)) {
+	c.config.onReload = append(c.config.onReload, cb)
+}
+
+// LoadConfig reads the config file at path and applies it to every module
+// implementing Reloadable. Either all sections apply or none do.
+func (c *Client) LoadConfig(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	cfg := &Config{Path: path, ModTime: info.ModTime()}
+	if err := json.Unmarshal(data, &cfg.Sections); err != nil {
+		return fmt.Errorf("config %s: %w", path, err)
+	}
+
+	if err := c.applyConfig(cfg); err != nil {
+		return err
+	}
+	for _, cb := range c.config.onReload {
+		cb(cfg)
+	}
+	return nil
+}
+
+func (c *Client) applyConfig(cfg *Config) error {
+	c.config.mu.Lock()
+	defer c.config.mu.Unlock()
+
+	// validate everything before touching any module
+	var applies []func()
+	for _, m := range c.modules {
+		r, ok := m.(Reloadable)
+		if !ok {
+			continue
+		}
+		raw, ok := cfg.Sections[m.Name()]
+		if !ok {
+			continue
+		}
+		apply, err := r.PrepareConfig(raw)
+		if err != nil {
+			return fmt.Errorf("config section %q: %w", m.Name(), err)
+		}
+		applies = append(applies, apply)
+	}
+	for _, apply := range applies {
+		apply()
+	}
+	c.config.current = cfg
+	return nil
+}
+
+// ReloadConfig re-reads the current config file. A broken file is reported
+// and leaves the previous config in effect.
+func (c *Client) ReloadConfig() error {
+	cfg := c.Config()
+	if cfg == nil {
+		return fmt.Errorf("no config loaded")
+	}
+	return c.LoadConfig(cfg.Path)
+}
+
+// WatchConfig polls the config file every interval and reloads it when it
+// changes, until ctx is done.
+func (c *Client) WatchConfig(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	var failed time.Time // mod time of the last broken version, reported once
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		cfg := c.Config()
+		if cfg == nil {
+			continue
+		}
+		info, err := os.Stat(cfg.Path)
+		if err != nil || info.ModTime().Equal(cfg.ModTime) || info.ModTime().Equal(failed) {
+			continue
+		}
+		if err := c.ReloadConfig(); err != nil {
+			c.Logger.Printf("config reload failed, keeping previous config: %v", err)
+			failed = info.ModTime()
+			continue
+		}
+		c.Logger.Printf("config reloaded from %s", cfg.Path)
+	}
+}
@@ -0,0 +1,285 @@
+package client
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	jp "github.com/go-mclib/protocol/java_protocol"
+	ns "github.com/go-mclib/protocol/java_protocol/net_structures"
+)
+
+// DisconnectClass is the classified cause of a disconnect.
+type DisconnectClass int
+
+const (
+	// DisconnectUnknown: the server gave a reason that didn't match any class.
+	DisconnectUnknown DisconnectClass = iota
+	// DisconnectConnectionLost: the connection dropped without a reason (network error, keepalive timeout).
+	DisconnectConnectionLost
+	// DisconnectRequested: the client disconnected itself with Disconnect(true).
+	DisconnectRequested
+	// DisconnectKicked: a kick without a more specific reason.
+	DisconnectKicked
+	// DisconnectSpam: kicked for sending chat or packets too fast.
+	DisconnectSpam
+	// DisconnectBanned: the account or IP is banned.
+	DisconnectBanned
+	// DisconnectServerRestart: the server is stopping or restarting.
+	DisconnectServerRestart
+	// DisconnectIdleTimeout: kicked for being idle (vanilla player-idle-timeout or AFK plugins).
+	DisconnectIdleTimeout
+	// DisconnectAntiCheat: kicked for illegal movement or by an anti-cheat plugin.
+	DisconnectAntiCheat
+	// DisconnectOutdated: the server doesn't accept this protocol version.
+	DisconnectOutdated
+	// DisconnectTransfer: the server moved the player to another server or back
+	// to configuration, with TreatTransferAsDisconnect set.
+	DisconnectTransfer
+)
+
+var disconnectClassNames = []string{
+	"unknown", "connection lost", "requested", "kicked", "spam", "banned",
+	"server restart", "idle timeout", "anti-cheat", "outdated", "transfer",
+}
+
+func (d DisconnectClass) String() string {
+	if d >= 0 && int(d) < len(disconnectClassNames) {
+		return disconnectClassNames[d]
+	}
+	return fmt.Sprintf("DisconnectClass(%d)", int(d))
+}
+
+// DisconnectEvent describes why a connection ended.
+type DisconnectEvent struct {
+	Class  DisconnectClass
+	State  jp.State         // protocol state the disconnect happened in
+	Reason ns.TextComponent // as sent by the server (zero if none)
+	Text   string           // Reason as plain text, or the connection error
+	// Guessed is set when Class was guessed from keywords in a plugin's
+	// message rather than read from a vanilla translation key.
+	Guessed bool
+	Time    time.Time
+}
+
+// ReconnectPolicy decides whether (and after how long) to reconnect after a
+// disconnect of a given class. MaxReconnectAttempts still caps the total.
+type ReconnectPolicy struct {
+	Reconnect bool
+	Delay     time.Duration
+}
+
+// DefaultReconnectDelay is the delay for classes without a specific policy.
+const DefaultReconnectDelay = 3 * time.Second
+
+// GuessedReconnectDelay is the delay before reconnecting after a disconnect
+// whose policy is not to reconnect, when its class was only guessed from
+// keywords: "Please use /login" or a message about banned items is no
+// reason to stop for good.
+const GuessedReconnectDelay = 10 * time.Minute
+
+// DefaultReconnectPolicies returns the policies installed by New: give up on
+// vanilla bans, version mismatches and local disconnects, back off on spam and
+// anti-cheat kicks, and wait out restarts.
+func DefaultReconnectPolicies() map[DisconnectClass]ReconnectPolicy {
+	return map[DisconnectClass]ReconnectPolicy{
+		DisconnectUnknown:        {Reconnect: true, Delay: DefaultReconnectDelay},
+		DisconnectConnectionLost: {Reconnect: true, Delay: DefaultReconnectDelay},
+		DisconnectRequested:      {Reconnect: false},
+		DisconnectKicked:         {Reconnect: true, Delay: 10 * time.Second},
+		DisconnectSpam:           {Reconnect: true, Delay: time.Minute},
+		DisconnectBanned:         {Reconnect: false},
+		DisconnectServerRestart:  {Reconnect: true, Delay: 30 * time.Second},
+		DisconnectIdleTimeout:    {Reconnect: true, Delay: 5 * time.Second},
+		DisconnectAntiCheat:      {Reconnect: true, Delay: 30 * time.Second},
+		DisconnectOutdated:       {Reconnect: false},
+		DisconnectTransfer:       {Reconnect: true, Delay: DefaultReconnectDelay},
+	}
+}
+
+// vanilla translation keys, matched by prefix
+var disconnectKeys = []struct {
+	prefix string
+	class  DisconnectClass
+}{
+	{"multiplayer.disconnect.banned", DisconnectBanned}, // also banned_ip, banned.reason, ...
+	{"multiplayer.disconnect.idling", DisconnectIdleTimeout},
+	{"multiplayer.disconnect.server_shutdown", DisconnectServerRestart},
+	{"multiplayer.disconnect.outdated_client", DisconnectOutdated},
+	{"multiplayer.disconnect.outdated_server", DisconnectOutdated},
+	{"multiplayer.disconnect.incompatible", DisconnectOutdated},
+	{"multiplayer.disconnect.flying", DisconnectAntiCheat},
+	{"multiplayer.disconnect.invalid_player_movement", DisconnectAntiCheat},
+	{"multiplayer.disconnect.invalid_vehicle_movement", DisconnectAntiCheat},
+	{"multiplayer.disconnect.too_many_pending_chats", DisconnectSpam},
+	{"disconnect.spam", DisconnectSpam},
+	{"disconnect.timeout", DisconnectConnectionLost},
+	{"multiplayer.disconnect.kicked", DisconnectKicked},
+}
+
+// keywords for plugin messages (plain text, lowercased), checked in order;
+// a match is a guess, so it never stops the bot for good (see
+// GuessedReconnectDelay)
+var disconnectKeywords = []struct {
+	words []string
+	class DisconnectClass
+}{
+	{[]string{"banned", "blacklisted"}, DisconnectBanned},
+	{[]string{"outdated", "unsupported version", "please use", "incompatible"}, DisconnectOutdated},
+	{[]string{"restart", "shutting down", "server closed", "server is stopping"}, DisconnectServerRestart},
+	{[]string{"idle", "afk", "inactiv"}, DisconnectIdleTimeout},
+	{[]string{"spam", "too fast", "flood", "slow down"}, DisconnectSpam},
+	{[]string{"cheat", "hack", "flying", "unfair advantage", "illegal", "invalid move"}, DisconnectAntiCheat},
+	{[]string{"kicked"}, DisconnectKicked},
+}
+
+// ClassifyDisconnect classifies a disconnect reason by its vanilla translation
+// key if it has one, otherwise by keywords in its plain text.
+func ClassifyDisconnect(reason ns.TextComponent) DisconnectClass {
+	class, _ := classifyReason(reason)
+	return class
+}
+
+// classifyReason is ClassifyDisconnect, also reporting whether the class was
+// guessed from keywords.
+func classifyReason(reason ns.TextComponent) (class DisconnectClass, guessed bool) {
+	if class, ok := classifyKey(reason); ok {
+		return class, false
+	}
+	text := strings.ToLower(reason.String())
+	for _, k := range disconnectKeywords {
+		for _, w := range k.words {
+			if strings.Contains(text, w) {
+				return k.class, true
+			}
+		}
+	}
+	return DisconnectUnknown, false
+}
+
+func classifyKey(tc ns.TextComponent) (DisconnectClass, bool) {
+	if tc.Translate != "" {
+		for _, k := range disconnectKeys {
+			if strings.HasPrefix(tc.Translate, k.prefix) {
+				return k.class, true
+			}
+		}
+	}
+	for _, children := range [][]ns.TextComponent{tc.With, tc.Extra} {
+		for _, child := range children {
+			if class, ok := classifyKey(child); ok {
+				return class, true
+			}
+		}
+	}
+	return DisconnectUnknown, false
+}
+
+// disconnectState is the pending disconnect of the current connection plus
+// per-class counters across reconnects.
+type disconnectState struct {
+	mu       sync.Mutex
+	pending  *DisconnectEvent
+	last     *DisconnectEvent
+	counts   map[DisconnectClass]int
+	onReason []func(ev DisconnectEvent)
+}
+
+// OnDisconnectReason is called once per ended connection with its classified
+// cause, before the reconnect policy is applied.
+func (c *Client) OnDisconnectReason(cb func(ev DisconnectEvent)) {
+	c.disconnect.onReason = append(c.disconnect.onReason, cb)
+}
+
+// DisconnectWithReason records why the server ended the connection (for
+// OnDisconnectReason and the reconnect policy) and closes it. Used by the
+// protocol module on disconnect packets.
+func (c *Client) DisconnectWithReason(class DisconnectClass, reason ns.TextComponent) error {
+	c.setPendingDisconnect(reasonEvent(class, reason))
+	return c.Disconnect(false)
+}
+
+func reasonEvent(class DisconnectClass, reason ns.TextComponent) DisconnectEvent {
+	ev := DisconnectEvent{Class: class, Reason: reason, Text: reason.String()}
+	if c, guessed := classifyReason(reason); guessed && c == class {
+		ev.Guessed = true
+	}
+	return ev
+}
+
+// LastDisconnect returns the most recent disconnect, if any.
+func (c *Client) LastDisconnect() (DisconnectEvent, bool) {
+	c.disconnect.mu.Lock()
+	defer c.disconnect.mu.Unlock()
+	if c.disconnect.last == nil {
+		return DisconnectEvent{}, false
+	}
+	return *c.disconnect.last, true
+}
+
+// DisconnectCounts returns how many disconnects of each class happened since the client was created.
+func (c *Client) DisconnectCounts() map[DisconnectClass]int {
+	c.disconnect.mu.Lock()
+	defer c.disconnect.mu.Unlock()
+	counts := make(map[DisconnectClass]int, len(c.disconnect.counts))
+	for class, n := range c.disconnect.counts {
+		counts[class] = n
+	}
+	return counts
+}
+
+// setPendingDisconnect records the cause of the current connection ending.
+// The first cause wins (a kick packet is followed by a read error).
+func (c *Client) setPendingDisconnect(ev DisconnectEvent) {
+	c.disconnect.mu.Lock()
+	defer c.disconnect.mu.Unlock()
+	if c.disconnect.pending != nil {
+		return
+	}
+	ev.State = c.State()
+	ev.Time = time.Now()
+	c.disconnect.pending = &ev
+}
+
+// finishDisconnect takes the pending disconnect (or derives one from err),
+// counts it and fires OnDisconnectReason. Returns false if the connection
+// never got far enough to be disconnected (e.g. connect or auth failed).
+func (c *Client) finishDisconnect(err error) (DisconnectEvent, bool) {
+	c.disconnect.mu.Lock()
+	ev := c.disconnect.pending
+	c.disconnect.pending = nil
+	if ev == nil && c.shouldReconnect {
+		ev = &DisconnectEvent{Class: DisconnectConnectionLost, State: c.State(), Text: err.Error(), Time: time.Now()}
+	}
+	if ev == nil {
+		c.disconnect.mu.Unlock()
+		return DisconnectEvent{}, false
+	}
+	if c.disconnect.counts == nil {
+		c.disconnect.counts = make(map[DisconnectClass]int)
+	}
+	c.disconnect.counts[ev.Class]++
+	c.disconnect.last = ev
+	c.disconnect.mu.Unlock()
+
+	c.Logger.Printf("disconnected (%s): %s", ev.Class, ev.Text)
+	for _, cb := range c.disconnect.onReason {
+		cb(*ev)
+	}
+	return *ev, true
+}
+
+// reconnectPolicy returns the policy for the disconnect's class, falling back
+// to reconnecting after DefaultReconnectDelay. A guessed class never stops
+// the bot for good (see GuessedReconnectDelay).
+func (c *Client) reconnectPolicy(ev DisconnectEvent) ReconnectPolicy {
+	p, ok := c.ReconnectPolicies[ev.Class]
+	if !ok {
+		return ReconnectPolicy{Reconnect: true, Delay: DefaultReconnectDelay}
+	}
+	if !p.Reconnect && ev.Guessed {
+		return ReconnectPolicy{Reconnect: true, Delay: max(p.Delay, GuessedReconnectDelay)}
+	}
+	return p
+}
@@ -0,0 +1,68 @@
+package client
+
+import (
+	"testing"
+
+	ns "github.com/go-mclib/protocol/java_protocol/net_structures"
+)
+
+func TestClassifyDisconnect(t *testing.T) {
+	tests := []struct {
+		name   string
+		reason ns.TextComponent
+		want   DisconnectClass
+	}{
+		{"vanilla ban", ns.TextComponent{Translate: "multiplayer.disconnect.banned.reason", With: []ns.TextComponent{{Text: "griefing"}}}, DisconnectBanned},
+		{"vanilla ip ban", ns.TextComponent{Translate: "multiplayer.disconnect.banned_ip.reason"}, DisconnectBanned},
+		{"vanilla idle", ns.TextComponent{Translate: "multiplayer.disconnect.idling"}, DisconnectIdleTimeout},
+		{"vanilla shutdown", ns.TextComponent{Translate: "multiplayer.disconnect.server_shutdown"}, DisconnectServerRestart},
+		{"vanilla outdated", ns.TextComponent{Translate: "multiplayer.disconnect.outdated_client", With: []ns.TextComponent{{Text: "1.21"}}}, DisconnectOutdated},
+		{"vanilla spam", ns.TextComponent{Translate: "disconnect.spam"}, DisconnectSpam},
+		{"vanilla flying", ns.TextComponent{Translate: "multiplayer.disconnect.flying"}, DisconnectAntiCheat},
+		{"vanilla kick", ns.TextComponent{Translate: "multiplayer.disconnect.kicked"}, DisconnectKicked},
+		{"nested key", ns.TextComponent{Text: "", Extra: []ns.TextComponent{{Translate: "multiplayer.disconnect.idling"}}}, DisconnectIdleTimeout},
+		{"plugin ban", ns.TextComponent{Text: "You are permanently Banned from this server!"}, DisconnectBanned},
+		{"plugin restart", ns.TextComponent{Text: "Server is restarting, be right back"}, DisconnectServerRestart},
+		{"plugin afk", ns.TextComponent{Text: "You have been kicked for being AFK"}, DisconnectIdleTimeout},
+		{"plugin anticheat", ns.TextComponent{Text: "Unfair Advantage"}, DisconnectAntiCheat},
+		{"plugin kick", ns.TextComponent{Text: "Kicked by an operator."}, DisconnectKicked},
+		{"unknown", ns.TextComponent{Text: "Goodbye"}, DisconnectUnknown},
+	}
+
+	for _, tt := range tests {
+		if got := ClassifyDisconnect(tt.reason); got != tt.want {
+			t.Errorf("%s: ClassifyDisconnect(%q) = %s, want %s", tt.name, tt.reason.String(), got, tt.want)
+		}
+	}
+}
+
+func TestReconnectPolicy(t *testing.T) {
+	c := New("localhost:25565", "Bot", false)
+	tests := []struct {
+		name      string
+		class     DisconnectClass
+		reason    ns.TextComponent
+		reconnect bool
+	}{
+		{"vanilla ban", DisconnectBanned, ns.TextComponent{Translate: "multiplayer.disconnect.banned"}, false},
+		{"vanilla outdated", DisconnectOutdated, ns.TextComponent{Translate: "multiplayer.disconnect.outdated_client"}, false},
+		// keyword false positives retry, after a long delay
+		{"login plugin", DisconnectOutdated, ns.TextComponent{Text: "Please use /login <password>"}, true},
+		{"banned items", DisconnectBanned, ns.TextComponent{Text: "Banned items are not allowed in this world"}, true},
+		{"incompatible mod", DisconnectOutdated, ns.TextComponent{Text: "Incompatible client mods detected"}, true},
+		{"transfer", DisconnectTransfer, ns.TextComponent{Text: "server transfer"}, true},
+	}
+	for _, tt := range tests {
+		if got := ClassifyDisconnect(tt.reason); tt.class != DisconnectTransfer && got != tt.class {
+			t.Fatalf("%s: classified as %s, want %s", tt.name, got, tt.class)
+		}
+		ev := reasonEvent(tt.class, tt.reason)
+		p := c.reconnectPolicy(ev)
+		if p.Reconnect != tt.reconnect {
+			t.Errorf("%s: reconnect = %v, want %v", tt.name, p.Reconnect, tt.reconnect)
+		}
+		if ev.Guessed && p.Delay < GuessedReconnectDelay {
+			t.Errorf("%s: guessed class retried after %v", tt.name, p.Delay)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package client
+
+import (
+	"time"
+
+	jp "github.com/go-mclib/protocol/java_protocol"
+)
+
+const (
+	// DefaultDispatchLagWarning is how far module dispatch may fall behind the
+	// connection before a warning is logged.
+	DefaultDispatchLagWarning = 2 * time.Second
+
+	// dispatchQueueSize bounds how many packets the reader can get ahead of
+	// dispatch. When it's full the reader blocks and keepalives go unanswered.
+	dispatchQueueSize = 4096
+	// lagWarningInterval rate limits dispatch lag warnings.
+	lagWarningInterval = 5 * time.Second
+)
+
+type queuedPacket struct {
+	wire *jp.WirePacket
+	read time.Time
+	done chan struct{} // closed after dispatch if the reader waits for it
+}
+
+// runPacketLoop reads packets on a separate goroutine and dispatches them to
+// modules and handlers on this one, so slow callbacks (e.g. a chat handler
+// waiting on an HTTP API) delay dispatch but not the connection: urgent
+// packets like keepalives are answered by the reader (see UrgentHandler).
+// Returns the read error that ended the connection, after dispatching every
+// packet read before it.
+func (c *Client) runPacketLoop() error {
+	var urgent []UrgentHandler
+	for _, m := range c.modules {
+		if u, ok := m.(UrgentHandler); ok {
+			urgent = append(urgent, u)
+		}
+	}
+
+	queue := make(chan queuedPacket, dispatchQueueSize)
+	var readErr error
+	go func() {
+		defer close(queue)
+		for {
+			wire, err := c.ReadWirePacket()
+			if err != nil {
+				readErr = err
+				return
+			}
+			// without urgent handlers nobody can tell which packets are safe
+			// to read past, so stay in lockstep
+			barrier := len(urgent) == 0
+			for _, u := range urgent {
+				if u.HandleUrgent(wire) {
+					barrier = true
+				}
+			}
+			q := queuedPacket{wire: wire, read: time.Now()}
+			if barrier {
+				q.done = make(chan struct{})
+			}
+			select {
+			case queue <- q:
+			default:
+				c.Logger.Printf("dispatch queue full (%d packets), reading blocks until it drains", dispatchQueueSize)
+				queue <- q
+			}
+			if q.done != nil {
+				<-q.done
+			}
+		}
+	}()
+
+	var lastWarning time.Time
+	for q := range queue {
+		if lag := time.Since(q.read); lag > c.DispatchLagWarning && c.DispatchLagWarning > 0 &&
+			time.Since(lastWarning) > lagWarningInterval {
+			c.Logger.Printf("module dispatch is %s behind the connection (%d packets queued); a callback is probably blocking", lag.Round(time.Millisecond), len(queue))
+			lastWarning = time.Now()
+		}
+		c.dispatch(q.wire)
+		if q.done != nil {
+			close(q.done)
+		}
+	}
+	return readErr
+}
+
+// dispatch hands a packet to every enabled module and every handler.
+func (c *Client) dispatch(wire *jp.WirePacket) {
+	c.recordPacket(wire)
+	disabled := c.disabledModules()
+	for _, m := range c.modules {
+		if !disabled[m.Name()] {
+			m.HandlePacket(wire)
+		}
+	}
+	for _, h := range c.handlers {
+		h(c, wire)
+	}
+}
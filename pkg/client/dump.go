@@ -0,0 +1,280 @@
+package client
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	jp "github.com/go-mclib/protocol/java_protocol"
+)
+
+// DefaultPacketHistorySize is how many received packets are kept for DumpState.
+const DefaultPacketHistorySize = 256
+
+// maxRecordedPayload cuts the payloads kept in the packet history, so a few
+// chunk packets don't make up the whole dump.
+const maxRecordedPayload = 16 << 10
+
+// RecordedPacket is a received packet kept in the packet history.
+type RecordedPacket struct {
+	Time  time.Time
+	State jp.State
+	ID    int32
+	// Data is the payload, cut to 16 KiB; Length is its full size.
+	Data   []byte
+	Length int
+}
+
+// Truncated reports whether Data is shorter than the packet was.
+func (p RecordedPacket) Truncated() bool { return len(p.Data) < p.Length }
+
+// StateDumper is optionally implemented by modules that add their state to
+// DumpState: the world around the player, entities, the inventory and so on.
+// The section's format is up to the module (JSON unless documented
+// otherwise), which also provides the decoder for it.
+type StateDumper interface {
+	DumpState() ([]byte, error)
+}
+
+// DumpInfo describes the client a dump was taken from.
+type DumpInfo struct {
+	Time     time.Time
+	Address  string
+	Username string
+	State    jp.State
+	Seed     uint64
+	Modules  []string
+}
+
+// Dump is a state dump read back by LoadDump.
+type Dump struct {
+	Info    DumpInfo
+	Packets []RecordedPacket           // oldest first
+	Config  map[string]json.RawMessage // config sections in effect, if any
+	Modules map[string][]byte          // module name -> its DumpState section
+	Errors  []string                   // modules that failed to dump
+}
+
+// PacketHistory returns the recently received packets, oldest first.
+func (c *Client) PacketHistory() []RecordedPacket {
+	c.historyMu.Lock()
+	defer c.historyMu.Unlock()
+	if len(c.history) < cap(c.history) {
+		return append([]RecordedPacket(nil), c.history...)
+	}
+	return append(append([]RecordedPacket(nil), c.history[c.historyNext:]...), c.history[:c.historyNext]...)
+}
+
+// recordPacket adds a received packet to the history.
+func (c *Client) recordPacket(wire *jp.WirePacket) {
+	if c.PacketHistorySize <= 0 {
+		return
+	}
+	p := RecordedPacket{
+		Time:   time.Now(),
+		State:  c.State(),
+		ID:     int32(wire.PacketID),
+		Data:   bytes.Clone(wire.Data[:min(len(wire.Data), maxRecordedPayload)]),
+		Length: len(wire.Data),
+	}
+
+	c.historyMu.Lock()
+	defer c.historyMu.Unlock()
+	if cap(c.history) != c.PacketHistorySize {
+		c.history = make([]RecordedPacket, 0, c.PacketHistorySize)
+		c.historyNext = 0
+	}
+	if len(c.history) < cap(c.history) {
+		c.history = append(c.history, p)
+		return
+	}
+	c.history[c.historyNext] = p
+	c.historyNext = (c.historyNext + 1) % len(c.history)
+}
+
+// DumpState writes a compressed snapshot of the client to path for bug
+// reports: the recent packet history, the config in effect and the section
+// of every module implementing StateDumper. Read it back with LoadDump. A
+// module failing to dump is noted in the file and doesn't stop the others.
+func (c *Client) DumpState(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	zw := zip.NewWriter(f)
+	werr := c.writeDump(zw)
+	if err := zw.Close(); werr == nil {
+		werr = err
+	}
+	if err := f.Close(); werr == nil {
+		werr = err
+	}
+	return werr
+}
+
+func (c *Client) writeDump(zw *zip.Writer) error {
+	info := DumpInfo{
+		Time:     time.Now(),
+		Address:  c.Address,
+		Username: c.Username,
+		State:    c.State(),
+		Seed:     c.Seed,
+	}
+	for _, m := range c.modules {
+		info.Modules = append(info.Modules, m.Name())
+	}
+	if err := writeDumpJSON(zw, "info.json", info); err != nil {
+		return err
+	}
+	if cfg := c.Config(); cfg != nil {
+		if err := writeDumpJSON(zw, "config.json", cfg.Sections); err != nil {
+			return err
+		}
+	}
+
+	w, err := zw.Create("packets.bin")
+	if err != nil {
+		return err
+	}
+	if err := writePackets(w, c.PacketHistory()); err != nil {
+		return err
+	}
+
+	var failed []string
+	for _, m := range c.modules {
+		d, ok := m.(StateDumper)
+		if !ok {
+			continue
+		}
+		data, err := d.DumpState()
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", m.Name(), err))
+			continue
+		}
+		w, err := zw.Create("modules/" + m.Name())
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(data); err != nil {
+			return err
+		}
+	}
+	if len(failed) > 0 {
+		w, err := zw.Create("errors.txt")
+		if err != nil {
+			return err
+		}
+		_, err = io.WriteString(w, strings.Join(failed, "\n")+"\n")
+		return err
+	}
+	return nil
+}
+
+func writeDumpJSON(zw *zip.Writer, name string, v any) error {
+	w, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	return json.NewEncoder(w).Encode(v)
+}
+
+// writePackets encodes packets as records of state (byte), ID, time (unix
+// nanoseconds), full length and payload, all but the first varints.
+func writePackets(w io.Writer, packets []RecordedPacket) error {
+	var buf []byte
+	for _, p := range packets {
+		buf = append(buf[:0], byte(p.State))
+		buf = binary.AppendUvarint(buf, uint64(p.ID))
+		buf = binary.AppendVarint(buf, p.Time.UnixNano())
+		buf = binary.AppendUvarint(buf, uint64(p.Length))
+		buf = binary.AppendUvarint(buf, uint64(len(p.Data)))
+		buf = append(buf, p.Data...)
+		if _, err := w.Write(buf); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readPackets(data []byte) ([]RecordedPacket, error) {
+	errShort := errors.New("truncated packet record")
+	var packets []RecordedPacket
+	for len(data) > 0 {
+		p := RecordedPacket{State: jp.State(data[0])}
+		data = data[1:]
+		var fields [4]uint64
+		for i := range fields {
+			var n int
+			if i == 1 {
+				var v int64
+				v, n = binary.Varint(data)
+				fields[i] = uint64(v)
+			} else {
+				fields[i], n = binary.Uvarint(data)
+			}
+			if n <= 0 {
+				return packets, errShort
+			}
+			data = data[n:]
+		}
+		p.ID = int32(fields[0])
+		p.Time = time.Unix(0, int64(fields[1]))
+		p.Length = int(fields[2])
+		if uint64(len(data)) < fields[3] {
+			return packets, errShort
+		}
+		p.Data, data = data[:fields[3]], data[fields[3]:]
+		packets = append(packets, p)
+	}
+	return packets, nil
+}
+
+// LoadDump reads a file written by DumpState. Module sections are returned
+// raw; decode them with the module's own types.
+func LoadDump(path string) (*Dump, error) {
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+
+	d := &Dump{Modules: make(map[string][]byte)}
+	for _, f := range zr.File {
+		data, err := readZipFile(f)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f.Name, err)
+		}
+		switch name := f.Name; {
+		case name == "info.json":
+			err = json.Unmarshal(data, &d.Info)
+		case name == "config.json":
+			err = json.Unmarshal(data, &d.Config)
+		case name == "packets.bin":
+			d.Packets, err = readPackets(data)
+		case name == "errors.txt":
+			d.Errors = strings.Split(strings.TrimSpace(string(data)), "\n")
+		case strings.HasPrefix(name, "modules/"):
+			d.Modules[strings.TrimPrefix(name, "modules/")] = data
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f.Name, err)
+		}
+	}
+	return d, nil
+}
+
+func readZipFile(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
@@ -0,0 +1,55 @@
+package client
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	jp "github.com/go-mclib/protocol/java_protocol"
+	ns "github.com/go-mclib/protocol/java_protocol/net_structures"
+)
+
+type dumpModule struct{}
+
+func (dumpModule) Name() string                { return "dump" }
+func (dumpModule) Init(*Client)                {}
+func (dumpModule) HandlePacket(*jp.WirePacket) {}
+func (dumpModule) Reset()                      {}
+func (dumpModule) DumpState() ([]byte, error)  { return []byte{1, 2, 3}, nil }
+
+func TestDumpRoundTrip(t *testing.T) {
+	c := New("localhost:25565", "Bot", false)
+	c.PacketHistorySize = 4
+	c.Register(dumpModule{})
+	for i := range 6 {
+		c.recordPacket(&jp.WirePacket{PacketID: ns.VarInt(i), Data: make([]byte, i)})
+	}
+	c.recordPacket(&jp.WirePacket{PacketID: 6, Data: make([]byte, maxRecordedPayload+1)})
+
+	path := filepath.Join(t.TempDir(), "state.zip")
+	if err := c.DumpState(path); err != nil {
+		t.Fatal(err)
+	}
+	d, err := LoadDump(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if d.Info.Username != "Bot" || d.Info.Address != "localhost:25565" {
+		t.Errorf("info = %+v", d.Info)
+	}
+	if len(d.Packets) != 4 {
+		t.Fatalf("got %d packets, want the last 4", len(d.Packets))
+	}
+	for i, p := range d.Packets {
+		if want := int32(i + 3); p.ID != want {
+			t.Errorf("packet %d has ID %d, want %d", i, p.ID, want)
+		}
+	}
+	if last := d.Packets[3]; !last.Truncated() || len(last.Data) != maxRecordedPayload {
+		t.Errorf("large packet kept %d of %d bytes", len(last.Data), last.Length)
+	}
+	if got := d.Modules["dump"]; !bytes.Equal(got, []byte{1, 2, 3}) {
+		t.Errorf("module section = %v", got)
+	}
+}
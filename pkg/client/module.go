@@ -1,6 +1,10 @@
 package client
 
-import jp "github.com/go-mclib/protocol/java_protocol"
+import (
+	"encoding/json"
+
+	jp "github.com/go-mclib/protocol/java_protocol"
+)
 
 // Module is a pluggable game-state component.
 type Module interface {
@@ -28,5 +32,44 @@ type ChatMessageSender interface {
 	SendCommand(cmd string) error
 }
 
+// SneakHolder is optionally implemented by the physics module.
+// HoldSneak engages sneaking and sends the input change right away (ahead of
+// any packet queued after it); the returned func restores the previous state.
+type SneakHolder interface {
+	HoldSneak() (release func())
+}
+
+// TickScheduler is optionally implemented by the physics module.
+// Schedule runs steps in order at the given phase of the next tick; the
+// returned channel receives the first step error (or nil) once they ran.
+type TickScheduler interface {
+	Schedule(phase TickPhase, steps ...func() error) <-chan error
+}
+
+// Reloadable is optionally implemented by modules with options that can
+// change at runtime (see Client.LoadConfig). PrepareConfig validates the
+// module's config section and returns a func applying it; nothing is applied
+// unless every module's section is valid.
+type Reloadable interface {
+	PrepareConfig(raw json.RawMessage) (apply func(), err error)
+}
+
+// Toggler is optionally implemented by modules that need to act when they're
+// disabled or re-enabled at runtime (see Client.SetModuleEnabled), e.g. to
+// release input they hold or to resync state that went stale meanwhile.
+type Toggler interface {
+	SetEnabled(enabled bool)
+}
+
+// UrgentHandler is optionally implemented by modules that must see packets
+// as soon as they are read, ahead of module dispatch (which can lag behind
+// slow callbacks). HandleUrgent runs on the read goroutine and must not block.
+// Returning true makes the reader wait until the packet was dispatched before
+// reading the next one, for packets that change how the following ones are
+// read (state, compression, encryption).
+type UrgentHandler interface {
+	HandleUrgent(pkt *jp.WirePacket) (barrier bool)
+}
+
 // Handler is a lightweight packet callback for one-off matching.
 type Handler func(c *Client, pkt *jp.WirePacket)
@@ -5,6 +5,7 @@ import (
 	"encoding/binary"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/go-mclib/client/pkg/client"
@@ -19,20 +20,37 @@ const ModuleName = "chat"
 type Module struct {
 	client *client.Client
 
-	onPlayerChat    []func(sender, message string, isWhisper bool)
-	onSystemChat    []func(message string, isOverlay bool)
-	onDisguisedChat []func(sender, message string, isWhisper bool)
+	// RenewBefore is how long before the chat key expires the session is
+	// renewed (see RenewSession).
+	RenewBefore time.Duration
+
+	sendMu sync.Mutex // orders signing, sending and session changes
+	mu     sync.Mutex
+	// session renewal state, guarded by mu
+	expiry      time.Time
+	renewing    bool
+	lastAttempt time.Time
+
+	onSessionRenewed []func(expiresAt time.Time)
+	onPlayerChat     []func(sender, message string, isWhisper bool)
+	onPlayerChatFrom []func(senderUUID [16]byte, sender, message string, isWhisper bool)
+	onSystemChat     client.Callbacks[func(message string, isOverlay bool)]
+	onDisguisedChat  []func(sender, message string, isWhisper bool)
 }
 
 func New() *Module {
-	return &Module{}
+	return &Module{RenewBefore: DefaultRenewBefore}
 }
 
 func (m *Module) Name() string { return ModuleName }
 
 func (m *Module) Init(c *client.Client) { m.client = c }
 
-func (m *Module) Reset() {}
+func (m *Module) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.expiry, m.lastAttempt = time.Time{}, time.Time{}
+}
 
 // From retrieves the chat module from a client.
 func From(c *client.Client) *Module {
@@ -48,8 +66,16 @@ func From(c *client.Client) *Module {
 func (m *Module) OnPlayerChat(cb func(sender, message string, isWhisper bool)) {
 	m.onPlayerChat = append(m.onPlayerChat, cb)
 }
-func (m *Module) OnSystemChat(cb func(message string, isOverlay bool)) {
-	m.onSystemChat = append(m.onSystemChat, cb)
+
+// OnPlayerChatFrom is OnPlayerChat with the sender's UUID, for callers that
+// need to identify the sender reliably (names can be spoofed in decorations).
+func (m *Module) OnPlayerChatFrom(cb func(senderUUID [16]byte, sender, message string, isWhisper bool)) {
+	m.onPlayerChatFrom = append(m.onPlayerChatFrom, cb)
+}
+
+// OnSystemChat is called for system messages; remove unregisters cb.
+func (m *Module) OnSystemChat(cb func(message string, isOverlay bool)) (remove func()) {
+	return m.onSystemChat.Add(cb)
 }
 func (m *Module) OnDisguisedChat(cb func(sender, message string, isWhisper bool)) {
 	m.onDisguisedChat = append(m.onDisguisedChat, cb)
@@ -59,6 +85,7 @@ func (m *Module) HandlePacket(pkt *jp.WirePacket) {
 	if m.client.State() != jp.StatePlay {
 		return
 	}
+	m.renew(false) // once the key is due to expire
 	switch pkt.PacketID {
 	case packet_ids.S2CPlayerChatID:
 		m.handlePlayerChat(pkt)
@@ -85,6 +112,9 @@ func (m *Module) handlePlayerChat(pkt *jp.WirePacket) {
 	for _, cb := range m.onPlayerChat {
 		cb(sender, msg, isWhisper)
 	}
+	for _, cb := range m.onPlayerChatFrom {
+		cb([16]byte(d.Sender), sender, msg, isWhisper)
+	}
 }
 
 func (m *Module) handleSystemChat(pkt *jp.WirePacket) {
@@ -92,13 +122,16 @@ func (m *Module) handleSystemChat(pkt *jp.WirePacket) {
 	if err := pkt.ReadInto(&d); err != nil {
 		return
 	}
+	if isSessionRejected(d.Content) {
+		m.renew(true)
+	}
 	txt := d.Content.String()
 	if d.Overlay {
 		m.client.Logger.Printf("[SYSTEM-ACTION] %s", txt)
 	} else {
 		m.client.Logger.Printf("[SYSTEM] %s", txt)
 	}
-	for _, cb := range m.onSystemChat {
+	for _, cb := range m.onSystemChat.All() {
 		cb(txt, bool(d.Overlay))
 	}
 }
@@ -129,6 +162,8 @@ func (m *Module) SendMessage(message string) error {
 	c := m.client
 
 	if c.ChatSigner != nil {
+		m.sendMu.Lock()
+		defer m.sendMu.Unlock()
 		saltBytes := make([]byte, 8)
 		rand.Read(saltBytes)
 		salt := int64(binary.BigEndian.Uint64(saltBytes))
@@ -168,23 +203,35 @@ func (m *Module) SendCommand(command string) error {
 	})
 }
 
-// SendChatSessionData sends the chat session UUID, key, and expiry.
+// SendChatSessionData starts a new chat session: it sends the session UUID,
+// key, and expiry and restarts the chain of signed messages.
 // Implements client.ChatSessionSender.
 func (m *Module) SendChatSessionData() error {
+	m.sendMu.Lock()
+	defer m.sendMu.Unlock()
+	return m.sendSessionData()
+}
+
+func (m *Module) sendSessionData() error {
 	c := m.client
 	if c.ChatSigner == nil {
 		return fmt.Errorf("no chat signer")
 	}
 
-	var sessionID ns.UUID
-	rand.Read(sessionID[:])
-	c.ChatSigner.SessionUUID = sessionID
-
 	pub := c.ChatSigner.X509PublicKey
 	if len(pub) == 0 {
 		return fmt.Errorf("no public key")
 	}
 
+	var sessionID ns.UUID
+	rand.Read(sessionID[:])
+	c.ChatSigner.SessionUUID = sessionID
+	c.ChatSigner.ResetSession()
+
+	m.mu.Lock()
+	m.expiry = c.ChatSigner.KeyExpiry
+	m.mu.Unlock()
+
 	return c.WritePacket(&packets.C2SChatSessionUpdate{
 		SessionId:    sessionID,
 		ExpiresAt:    ns.Int64(c.ChatSigner.KeyExpiry.UnixMilli()),
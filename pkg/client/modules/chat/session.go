@@ -0,0 +1,99 @@
+package chat
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	ns "github.com/go-mclib/protocol/java_protocol/net_structures"
+)
+
+// DefaultRenewBefore renews the chat session well ahead of the key expiry,
+// leaving time to retry if Mojang's API is down.
+const DefaultRenewBefore = 10 * time.Minute
+
+const (
+	renewRetry   = time.Minute
+	renewTimeout = 30 * time.Second
+)
+
+// translation keys of the system messages the server answers chat with when
+// it has no valid session for us (the session was removed or the key expired)
+var sessionRejectedKeys = []string{
+	"chat.disabled.expiredProfileKey",
+	"chat.disabled.missingProfileKey",
+	"chat.disabled.invalid_signature",
+}
+
+// OnChatSessionRenewed registers a callback for when the chat session was
+// renewed with a new key, expiring at expiresAt.
+func (m *Module) OnChatSessionRenewed(cb func(expiresAt time.Time)) {
+	m.onSessionRenewed = append(m.onSessionRenewed, cb)
+}
+
+// RenewSession re-keys the chat session: it fetches a new key and
+// certificate (see client.RenewChatKeys), sends them as a new session and
+// restarts the chain of signed messages. Messages sent meanwhile wait for it,
+// so none is signed with the old key.
+//
+// The module does this by itself RenewBefore the key expires, and when the
+// server rejects chat for a missing or expired key.
+func (m *Module) RenewSession(ctx context.Context) error {
+	c := m.client
+	m.sendMu.Lock()
+	if err := c.RenewChatKeys(ctx); err != nil {
+		m.sendMu.Unlock()
+		return err
+	}
+	err := m.sendSessionData()
+	expiry := c.ChatSigner.KeyExpiry
+	m.sendMu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	c.Logger.Printf("chat session renewed, key expires %s", expiry.Format(time.RFC3339))
+	for _, cb := range m.onSessionRenewed {
+		cb(expiry)
+	}
+	return nil
+}
+
+// renew starts RenewSession in the background if the key is due to expire,
+// or regardless if force, unless a renewal is running or one failed within
+// renewRetry.
+func (m *Module) renew(force bool) {
+	m.mu.Lock()
+	now := time.Now()
+	due := !m.expiry.IsZero() && !m.renewing && now.Sub(m.lastAttempt) >= renewRetry &&
+		(force || m.expiry.Sub(now) < m.RenewBefore)
+	if due {
+		m.renewing, m.lastAttempt = true, now
+	}
+	m.mu.Unlock()
+	if !due {
+		return
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), renewTimeout)
+		defer cancel()
+		if err := m.RenewSession(ctx); err != nil {
+			m.client.Logger.Printf("renew chat session: %v", err)
+		}
+		m.mu.Lock()
+		m.renewing = false
+		m.mu.Unlock()
+	}()
+}
+
+// isSessionRejected reports whether a system message says the server has no
+// valid chat session for us.
+func isSessionRejected(tc ns.TextComponent) bool {
+	for _, key := range sessionRejectedKeys {
+		if strings.HasPrefix(tc.Translate, key) {
+			return true
+		}
+	}
+	return false
+}
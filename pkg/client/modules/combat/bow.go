@@ -0,0 +1,144 @@
+package combat
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/go-mclib/client/pkg/client"
+	"github.com/go-mclib/client/pkg/client/modules/entities"
+	"github.com/go-mclib/client/pkg/client/modules/self"
+)
+
+// BowDrawTicks is how long a bow takes to draw fully.
+const BowDrawTicks = 20
+
+// arrow flight, as in vanilla AbstractArrow: a fully drawn arrow leaves at 3
+// blocks per tick from just below the eyes, then slows by 1% and falls 0.05
+// blocks per tick faster every tick
+const (
+	arrowSpeed     = 3.0
+	arrowDrag      = 0.99
+	arrowGravity   = 0.05
+	arrowEyeOffset = 0.1
+	arrowMaxTicks  = 200
+)
+
+// ErrOutOfRange is returned when a fully drawn arrow can't reach the target.
+var ErrOutOfRange = errors.New("target out of bow range")
+
+// AimBow returns the point to look at so that a fully drawn arrow released
+// now hits the entity. It leads the target by the arrow's flight time plus
+// the time the release takes to reach the server, from the entity's motion
+// over the last ticks (tracked while attacking or shooting at it).
+func (m *Module) AimBow(entityID int32) (x, y, z float64, err error) {
+	s := self.From(m.client)
+	ents := entities.From(m.client)
+	if s == nil || ents == nil {
+		return 0, 0, 0, errors.New("self and entities modules must be registered")
+	}
+	e := ents.GetEntity(entityID)
+	if e == nil {
+		return 0, 0, 0, fmt.Errorf("entity %d not found", entityID)
+	}
+
+	ex, ey, ez := s.EyePosition()
+	ey -= arrowEyeOffset
+	delay, _ := m.compensation()
+	var flight, pitch, dx, dz, dist float64
+	// the flight time depends on where the target will be, and the other
+	// way round; a few rounds settle it
+	for range 3 {
+		px, py, pz := m.predict(e, delay+flight)
+		dx, dz = px-ex, pz-ez
+		dist = math.Hypot(dx, dz)
+		var ok bool
+		if pitch, flight, ok = solvePitch(dist, py+e.Height/2-ey); !ok {
+			return 0, 0, 0, ErrOutOfRange
+		}
+	}
+	if dist == 0 {
+		// straight up or down
+		return ex, ey + math.Copysign(1, math.Sin(pitch)), ez, nil
+	}
+	// a far point along the shot, so the look is the same from the eyes
+	const far = 64.0
+	h := far * math.Cos(pitch)
+	return ex + dx/dist*h, ey + arrowEyeOffset + far*math.Sin(pitch), ez + dz/dist*h, nil
+}
+
+// ShootBow draws the bow in hand, keeps aiming at the entity with AimBow
+// while drawing and releases it at full draw. The draw is held a little
+// longer when the latency varies, since the server times it from when the
+// use reached it. Cancelling ctx releases the bow early.
+func (m *Module) ShootBow(ctx context.Context, entityID int32, hand int8) error {
+	s := self.From(m.client)
+	if s == nil {
+		return errors.New("self module not registered")
+	}
+	m.track(entityID)
+	x, y, z, err := m.AimBow(entityID)
+	if err != nil {
+		return err
+	}
+	s.LookAt(x, y, z)
+	if err := s.StartUsingItem(hand); err != nil {
+		return err
+	}
+
+	_, jitter := m.compensation()
+	for range BowDrawTicks + int(math.Ceil(jitter)) {
+		if err := m.client.WaitTicks(ctx, 1); err != nil {
+			_ = s.ReleaseUsingItem()
+			return err
+		}
+		if x, y, z, err = m.AimBow(entityID); err != nil {
+			_ = s.ReleaseUsingItem()
+			return err
+		}
+		s.LookAt(x, y, z)
+	}
+	// release once the last rotation went out
+	return m.client.RunOnTick(client.TickAfterSend, s.ReleaseUsingItem)
+}
+
+// solvePitch returns the pitch (radians, up positive) at which a fully drawn
+// arrow passes dy above the start dist blocks away, on the low arc, and how
+// many ticks it takes to get there. ok is false if it can't reach.
+func solvePitch(dist, dy float64) (pitch, ticks float64, ok bool) {
+	lo, hi := -math.Pi/2+0.01, math.Pi/4
+	if h, _, reached := arrowHeight(hi, dist); !reached || h < dy {
+		return 0, 0, false
+	}
+	for range 40 {
+		mid := (lo + hi) / 2
+		if h, _, reached := arrowHeight(mid, dist); !reached || h < dy {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	_, ticks, _ = arrowHeight(hi, dist)
+	return hi, ticks, true
+}
+
+// arrowHeight simulates an arrow shot at pitch and returns its height when
+// it's dist blocks away horizontally, and after how many ticks.
+func arrowHeight(pitch, dist float64) (height, ticks float64, reached bool) {
+	vh, vy := arrowSpeed*math.Cos(pitch), arrowSpeed*math.Sin(pitch)
+	var x, y float64
+	for t := range arrowMaxTicks {
+		if x+vh >= dist {
+			f := 0.0
+			if vh > 0 {
+				f = (dist - x) / vh
+			}
+			return y + vy*f, float64(t) + f, true
+		}
+		x, y = x+vh, y+vy
+		vh *= arrowDrag
+		vy = vy*arrowDrag - arrowGravity
+	}
+	return 0, 0, false
+}
@@ -3,6 +3,7 @@ package combat
 import (
 	"fmt"
 	"math"
+	"sync"
 
 	"github.com/go-mclib/client/pkg/client"
 	"github.com/go-mclib/client/pkg/client/modules/collisions"
@@ -29,10 +30,17 @@ type Module struct {
 	attacking            bool
 	ticksSinceLastAttack int
 
+	// LatencyCompensation scales the measured latency (see
+	// protocol.Module.Latency, which needs its PingInterval set) that attacks
+	// and bow shots are timed and aimed ahead for (0 = none). See latency.go.
+	LatencyCompensation float64
+	mu                  sync.Mutex
+	motion              motion
+
 	onAttack []func(entityID int32)
 }
 
-func New() *Module { return &Module{} }
+func New() *Module { return &Module{LatencyCompensation: DefaultLatencyCompensation} }
 
 func (m *Module) Name() string { return ModuleName }
 
@@ -44,7 +52,11 @@ func (m *Module) Init(c *client.Client) {
 	p := physics.From(c)
 	if p != nil {
 		p.OnTick(func() {
+			if !c.ModuleEnabled(ModuleName) {
+				return
+			}
 			m.ticksSinceLastAttack++
+			m.updateMotion()
 			if m.attacking {
 				m.tryAttack()
 			}
@@ -58,6 +70,9 @@ func (m *Module) Reset() {
 	m.targetID = 0
 	m.attacking = false
 	m.ticksSinceLastAttack = 0
+	m.mu.Lock()
+	m.motion = motion{}
+	m.mu.Unlock()
 }
 
 func From(c *client.Client) *Module {
@@ -89,7 +104,7 @@ func (m *Module) Attack(entityID int32) error {
 		return fmt.Errorf("entity %d out of reach", entityID)
 	}
 
-	if m.GetAttackCooldown() < 0.9 {
+	if !m.cooldownReady() {
 		return fmt.Errorf("attack on cooldown")
 	}
 
@@ -97,10 +112,13 @@ func (m *Module) Attack(entityID int32) error {
 }
 
 // StartAttacking begins continuous attacking on the given entity.
-// Attacks are executed each physics tick when cooldown is ready.
+// Attacks are executed each physics tick when cooldown is ready, allowing
+// for the latency: the target must be in reach where it will be when the
+// attack reaches the server.
 func (m *Module) StartAttacking(entityID int32) {
 	m.targetID = entityID
 	m.attacking = true
+	m.track(entityID)
 }
 
 // StopAttacking stops continuous attacking.
@@ -132,7 +150,7 @@ func (m *Module) GetAttackCooldown() float32 {
 }
 
 func (m *Module) tryAttack() {
-	if m.GetAttackCooldown() < 0.9 {
+	if !m.cooldownReady() {
 		return
 	}
 	ents := entities.From(m.client)
@@ -175,19 +193,20 @@ func (m *Module) performAttack(e *entities.Entity) error {
 	return nil
 }
 
-// isWithinReach checks distance from player eye position to the closest point on entity AABB.
+// isWithinReach checks distance from player eye position to the closest point
+// on entity AABB, where the entity will be once an attack sent now reaches the
+// server.
 func (m *Module) isWithinReach(e *entities.Entity) bool {
 	s := self.From(m.client)
 	if s == nil {
 		return false
 	}
 
-	sx, sy, sz := s.Position()
-	eyeX := sx
-	eyeY := sy + self.EyeHeight
-	eyeZ := sz
+	eyeX, eyeY, eyeZ := s.EyePosition()
 
-	aabb := collisions.EntityAABB(e.X, e.Y, e.Z, e.Width, e.Height)
+	delay, _ := m.compensation()
+	x, y, z := m.predict(e, delay)
+	aabb := collisions.EntityAABB(x, y, z, e.Width, e.Height)
 	cx, cy, cz := aabb.ClosestPoint(eyeX, eyeY, eyeZ)
 
 	dx := eyeX - cx
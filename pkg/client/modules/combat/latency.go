@@ -0,0 +1,123 @@
+package combat
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/go-mclib/client/pkg/client"
+	"github.com/go-mclib/client/pkg/client/modules/entities"
+	"github.com/go-mclib/client/pkg/client/modules/protocol"
+)
+
+// DefaultLatencyCompensation scales how far ahead attacks and shots are
+// timed and aimed for the measured latency.
+const DefaultLatencyCompensation = 1.0
+
+// motion is the estimated velocity of an entity the module aims at, from its
+// position over the last ticks: most mobs' movement reaches the client as
+// position updates only.
+type motion struct {
+	id         int32
+	x, y, z    float64
+	vx, vy, vz float64 // blocks per tick
+	samples    int
+}
+
+// PrepareConfig implements client.Reloadable for the "combat" section.
+func (m *Module) PrepareConfig(raw json.RawMessage) (func(), error) {
+	var cfg struct {
+		LatencyCompensation *float64 `json:"latency_compensation"`
+	}
+	if err := json.Unmarshal(raw, &cfg); err != nil {
+		return nil, err
+	}
+	if cfg.LatencyCompensation != nil && *cfg.LatencyCompensation < 0 {
+		return nil, fmt.Errorf("latency_compensation must not be negative, got %g", *cfg.LatencyCompensation)
+	}
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		if cfg.LatencyCompensation != nil {
+			m.LatencyCompensation = *cfg.LatencyCompensation
+		}
+	}, nil
+}
+
+// compensation returns, in ticks, how long a packet takes to reach the
+// server and how much that varies, both scaled by LatencyCompensation. Both
+// are 0 until the protocol module measured the latency.
+func (m *Module) compensation() (delay, jitter float64) {
+	p := protocol.From(m.client)
+	if p == nil {
+		return 0, 0
+	}
+	rtt, dev, ok := p.Latency()
+	if !ok {
+		return 0, 0
+	}
+	m.mu.Lock()
+	f := m.LatencyCompensation
+	m.mu.Unlock()
+	tick := float64(client.TickDuration)
+	return f * float64(rtt) / 2 / tick, f * float64(dev) / tick
+}
+
+// track starts estimating the motion of entityID, replacing the one tracked
+// before.
+func (m *Module) track(entityID int32) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.motion.id != entityID {
+		m.motion = motion{id: entityID}
+	}
+}
+
+// updateMotion samples the tracked entity's position, once per tick.
+func (m *Module) updateMotion() {
+	m.mu.Lock()
+	id := m.motion.id
+	m.mu.Unlock()
+	ents := entities.From(m.client)
+	if id == 0 || ents == nil {
+		return
+	}
+	e := ents.GetEntity(id)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	mo := &m.motion
+	if e == nil || mo.id != id {
+		return
+	}
+	if mo.samples > 0 {
+		// halve the weight of older ticks, so turns show within a few ticks
+		mo.vx += (e.X - mo.x - mo.vx) / 2
+		mo.vy += (e.Y - mo.y - mo.vy) / 2
+		mo.vz += (e.Z - mo.z - mo.vz) / 2
+	}
+	mo.x, mo.y, mo.z = e.X, e.Y, e.Z
+	mo.samples++
+}
+
+// predict returns where e will be in ticks from now, extrapolating its
+// horizontal motion if it's tracked. Vertical motion is left out: jumps and
+// falls don't keep their speed.
+func (m *Module) predict(e *entities.Entity, ticks float64) (x, y, z float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.motion.id != e.ID || m.motion.samples < 2 {
+		return e.X, e.Y, e.Z
+	}
+	return e.X + m.motion.vx*ticks, e.Y, e.Z + m.motion.vz*ticks
+}
+
+// cooldownReady reports whether an attack sent now lands at full strength.
+// The server counts the cooldown from when it received the previous attack,
+// so a steady latency cancels out, but if that one was held up and this one
+// isn't, the server sees a shorter gap: wait out the jitter too.
+func (m *Module) cooldownReady() bool {
+	_, jitter := m.compensation()
+	ticks := float64(m.ticksSinceLastAttack+1) - math.Ceil(jitter)
+	return ticks/float64(DefaultCooldownTicks) >= 0.9
+}
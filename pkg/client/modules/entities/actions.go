@@ -1,10 +1,13 @@
 package entities
 
 import (
+	"fmt"
 	"math"
 
 	"github.com/go-mclib/client/pkg/client/modules/collisions"
 	"github.com/go-mclib/client/pkg/client/modules/self"
+	"github.com/go-mclib/data/pkg/packets"
+	ns "github.com/go-mclib/protocol/java_protocol/net_structures"
 )
 
 // GetEntity returns an entity by ID, or nil if not found.
@@ -143,10 +146,40 @@ func (m *Module) CanSee(entityID int32) bool {
 		return false
 	}
 
-	sx, sy, sz := s.Position()
-	eyeX := sx
-	eyeY := sy + self.EyeHeight
-	eyeZ := sz
+	eyeX, eyeY, eyeZ := s.EyePosition()
 	hit, _, _, _ := col.RaycastBlocks(eyeX, eyeY, eyeZ, e.X, e.Y+e.EyeHeight, e.Z)
 	return !hit
 }
+
+// EntityInteractionRange is how far from the eyes an entity can be used
+// (Player.ENTITY_INTERACTION_RANGE).
+const EntityInteractionRange = 3.0
+
+// Interact uses the item in hand (0 main, 1 offhand) on an entity, like a
+// right click: feeding, trading, giving an allay its item, leashing. The
+// player looks at the entity first.
+func (m *Module) Interact(entityID int32, hand int32) error {
+	s := self.From(m.client)
+	if s == nil {
+		return fmt.Errorf("self module not registered")
+	}
+	e := m.GetEntity(entityID)
+	if e == nil {
+		return fmt.Errorf("entity %d not found", entityID)
+	}
+	eyeX, eyeY, eyeZ := s.EyePosition()
+	cx, cy, cz := collisions.EntityAABB(e.X, e.Y, e.Z, e.Width, e.Height).ClosestPoint(eyeX, eyeY, eyeZ)
+	if d := math.Sqrt((cx-eyeX)*(cx-eyeX) + (cy-eyeY)*(cy-eyeY) + (cz-eyeZ)*(cz-eyeZ)); d > EntityInteractionRange {
+		return fmt.Errorf("entity %d out of reach (%.1f blocks)", entityID, d)
+	}
+
+	s.LookAt(e.X, e.Y+e.EyeHeight, e.Z)
+	m.client.SendPacket(&packets.C2SInteract{
+		EntityId:        ns.VarInt(entityID),
+		Type:            0, // interact
+		Hand:            ns.VarInt(hand),
+		SneakKeyPressed: ns.Boolean(s.Sneaking()),
+	})
+	m.client.SendPacket(&packets.C2SSwing{Hand: ns.VarInt(hand)})
+	return nil
+}
@@ -0,0 +1,114 @@
+package entities
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/go-mclib/data/pkg/data/entities"
+	"github.com/go-mclib/data/pkg/data/items"
+	ns "github.com/go-mclib/protocol/java_protocol/net_structures"
+)
+
+// allay metadata (Allay, after the Mob flags)
+const (
+	allayIndexDancing      = 16
+	allayIndexCanDuplicate = 17
+)
+
+// allayDeliveryRange is how far from an allay an item it throws appears:
+// allays drop items from their body, which is 0.6 tall.
+const allayDeliveryRange = 1.5
+
+// IsAllay reports whether the entity is an allay.
+func (e *Entity) IsAllay() bool { return e.TypeName == "minecraft:allay" }
+
+// Dancing reports whether an allay dances to a playing jukebox. A dancing
+// allay can be duplicated with an amethyst shard.
+func (e *Entity) Dancing() bool {
+	d := e.Metadata.Get(allayIndexDancing)
+	return e.IsAllay() && len(d) > 0 && d[0] != 0
+}
+
+// CanDuplicate reports whether an allay is off its duplication cooldown.
+func (e *Entity) CanDuplicate() bool {
+	d := e.Metadata.Get(allayIndexCanDuplicate)
+	return e.IsAllay() && len(d) > 0 && d[0] != 0
+}
+
+// Item returns the stack of an item entity, or nil if it isn't one or the
+// server hasn't sent it yet.
+func (e *Entity) Item() *items.ItemStack {
+	if e.TypeName != "minecraft:item" {
+		return nil
+	}
+	d := e.Metadata.Get(entities.ItemEntityIndexItem)
+	if len(d) == 0 {
+		return nil
+	}
+	raw, err := ns.NewReader(d).ReadSlot(items.Decoder())
+	if err != nil {
+		return nil
+	}
+	stack, err := items.FromSlot(raw)
+	if err != nil || stack.IsEmpty() {
+		return nil
+	}
+	return stack
+}
+
+// Allays returns the allays in range, and the item each collects (its
+// filter: the item in its hand, 0 for none).
+func (m *Module) Allays() map[int32]int32 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	allays := make(map[int32]int32)
+	for id, e := range m.entities {
+		if e.IsAllay() {
+			allays[id] = e.MainHand
+		}
+	}
+	return allays
+}
+
+// GiveAllayItem hands the allay the item held in the main hand, which sets
+// what it collects and makes the player the one it delivers to. One item of
+// the stack is taken.
+func (m *Module) GiveAllayItem(entityID int32) error {
+	if e := m.GetEntity(entityID); e == nil || !e.IsAllay() {
+		return fmt.Errorf("entity %d is not an allay", entityID)
+	}
+	return m.Interact(entityID, 0)
+}
+
+// TakeAllayItem takes back the allay's item, clearing its filter. The main
+// hand must be empty; the server drops the item if there's no room.
+func (m *Module) TakeAllayItem(entityID int32) error {
+	return m.GiveAllayItem(entityID)
+}
+
+// OnAllayDelivery is called when an item entity appears at an allay that
+// collects that item: the allay threw it to the player (or its note block).
+// itemEntityID is the dropped item, ready to be picked up.
+func (m *Module) OnAllayDelivery(cb func(allayID, itemEntityID int32, item *items.ItemStack)) {
+	m.onAllayDelivery = append(m.onAllayDelivery, cb)
+}
+
+// allayDelivery returns the allay that dropped the item entity e, or -1, and
+// the item. Must be called with mu held, right after e's item was first set.
+func (m *Module) allayDelivery(e *Entity) (int32, *items.ItemStack) {
+	item := e.Item()
+	if item == nil {
+		return -1, nil
+	}
+	best, bestDist := int32(-1), allayDeliveryRange
+	for id, a := range m.entities {
+		if !a.IsAllay() || a.MainHand != item.ID {
+			continue
+		}
+		dx, dy, dz := e.X-a.X, e.Y-a.Y, e.Z-a.Z
+		if d := math.Sqrt(dx*dx + dy*dy + dz*dz); d <= bestDist {
+			best, bestDist = id, d
+		}
+	}
+	return best, item
+}
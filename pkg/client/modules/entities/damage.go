@@ -0,0 +1,163 @@
+package entities
+
+import (
+	"fmt"
+)
+
+// damageTypeRegistry is the synced registry S2CDamageEvent source types index.
+const damageTypeRegistry = "minecraft:damage_type"
+
+// DamageKind groups damage types by the response they call for.
+type DamageKind int
+
+const (
+	DamageOther      DamageKind = iota // magic, wither, starvation, cramming, ...
+	DamageAttack                       // melee hit by a mob or player
+	DamageProjectile                   // arrows, tridents, spit, shulker bullets, ...
+	DamageFall                         // falling, ender pearls, stalagmites
+	DamageFire                         // fire, lava, magma blocks, fireballs
+	DamageVoid                         // below the world or outside the border
+	DamageDrowning
+	DamageExplosion
+	DamageFreezing // powder snow
+	DamageLightning
+)
+
+func (k DamageKind) String() string {
+	switch k {
+	case DamageOther:
+		return "other"
+	case DamageAttack:
+		return "attack"
+	case DamageProjectile:
+		return "projectile"
+	case DamageFall:
+		return "fall"
+	case DamageFire:
+		return "fire"
+	case DamageVoid:
+		return "void"
+	case DamageDrowning:
+		return "drowning"
+	case DamageExplosion:
+		return "explosion"
+	case DamageFreezing:
+		return "freezing"
+	case DamageLightning:
+		return "lightning"
+	}
+	return fmt.Sprintf("DamageKind(%d)", int(k))
+}
+
+// damageKindTags are the vanilla damage type tags each kind is read from, in
+// order of precedence (a fireball is both fire and a projectile).
+var damageKindTags = []struct {
+	tag  string
+	kind DamageKind
+}{
+	{"minecraft:is_drowning", DamageDrowning},
+	{"minecraft:is_fall", DamageFall},
+	{"minecraft:is_fire", DamageFire},
+	{"minecraft:is_explosion", DamageExplosion},
+	{"minecraft:is_freezing", DamageFreezing},
+	{"minecraft:is_lightning", DamageLightning},
+	{"minecraft:is_projectile", DamageProjectile},
+}
+
+// damageKindNames classifies the vanilla damage types when the server didn't
+// send the tags.
+var damageKindNames = map[string]DamageKind{
+	"minecraft:mob_attack":            DamageAttack,
+	"minecraft:mob_attack_no_aggro":   DamageAttack,
+	"minecraft:player_attack":         DamageAttack,
+	"minecraft:sting":                 DamageAttack,
+	"minecraft:arrow":                 DamageProjectile,
+	"minecraft:trident":               DamageProjectile,
+	"minecraft:mob_projectile":        DamageProjectile,
+	"minecraft:spit":                  DamageProjectile,
+	"minecraft:thrown":                DamageProjectile,
+	"minecraft:wither_skull":          DamageProjectile,
+	"minecraft:wind_charge":           DamageProjectile,
+	"minecraft:fall":                  DamageFall,
+	"minecraft:ender_pearl":           DamageFall,
+	"minecraft:stalagmite":            DamageFall,
+	"minecraft:in_fire":               DamageFire,
+	"minecraft:campfire":              DamageFire,
+	"minecraft:on_fire":               DamageFire,
+	"minecraft:lava":                  DamageFire,
+	"minecraft:hot_floor":             DamageFire,
+	"minecraft:fireball":              DamageFire,
+	"minecraft:unattributed_fireball": DamageFire,
+	"minecraft:out_of_world":          DamageVoid,
+	"minecraft:outside_border":        DamageVoid,
+	"minecraft:drown":                 DamageDrowning,
+	"minecraft:explosion":             DamageExplosion,
+	"minecraft:player_explosion":      DamageExplosion,
+	"minecraft:bad_respawn_point":     DamageExplosion,
+	"minecraft:fireworks":             DamageExplosion,
+	"minecraft:freeze":                DamageFreezing,
+	"minecraft:lightning_bolt":        DamageLightning,
+}
+
+// DamageSource is the source of an S2CDamageEvent, resolved against the
+// damage_type registry synced in configuration.
+type DamageSource struct {
+	TypeID int32
+	// Type is the damage type, e.g. "minecraft:fall", or "damage#<id>" if the
+	// registry wasn't received.
+	Type string
+	Kind DamageKind
+	// CauseID is the entity responsible (the shooter of an arrow), DirectID
+	// the one that dealt the damage (the arrow). -1 for none.
+	CauseID, DirectID int32
+}
+
+// ByEntity reports whether an entity caused the damage. Only that damage
+// comes with knockback.
+func (s DamageSource) ByEntity() bool {
+	return s.CauseID >= 0 || s.DirectID >= 0
+}
+
+// registryLookup is implemented by the protocol module, looked up by
+// interface so entities doesn't depend on it.
+type registryLookup interface {
+	RegistryEntryName(registry string, id int32) string
+	TagContains(registry, tag string, id int32) bool
+}
+
+// OnDamage is called when an entity, including the player, takes damage.
+func (m *Module) OnDamage(cb func(entityID int32, src DamageSource)) {
+	m.onDamage = append(m.onDamage, cb)
+}
+
+// resolveDamage names the damage type and classifies it, by the server's
+// damage type tags, falling back to the vanilla type names. causeID and
+// directID are the packet's entity IDs plus one.
+func (m *Module) resolveDamage(typeID, causeID, directID int32) DamageSource {
+	src := DamageSource{
+		TypeID:   typeID,
+		Type:     fmt.Sprintf("damage#%d", typeID),
+		Kind:     DamageOther,
+		CauseID:  causeID - 1,
+		DirectID: directID - 1,
+	}
+	r, ok := m.client.Module("protocol").(registryLookup)
+	if !ok {
+		return src
+	}
+	if name := r.RegistryEntryName(damageTypeRegistry, typeID); name != "" {
+		src.Type = name
+	}
+	for _, t := range damageKindTags {
+		if r.TagContains(damageTypeRegistry, t.tag, typeID) {
+			src.Kind = t.kind
+			return src
+		}
+	}
+	if kind, ok := damageKindNames[src.Type]; ok {
+		src.Kind = kind
+	} else if src.ByEntity() {
+		src.Kind = DamageAttack
+	}
+	return src
+}
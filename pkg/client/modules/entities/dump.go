@@ -0,0 +1,20 @@
+package entities
+
+import (
+	"encoding/json"
+	"slices"
+)
+
+// DumpState implements client.StateDumper. The section is the JSON list of
+// tracked entities, the player included, ordered by ID; decode it into a
+// []Entity.
+func (m *Module) DumpState() ([]byte, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	list := make([]Entity, 0, len(m.entities))
+	for _, e := range m.entities {
+		list = append(list, *e)
+	}
+	slices.SortFunc(list, func(a, b Entity) int { return int(a.ID - b.ID) })
+	return json.Marshal(list)
+}
@@ -1,13 +1,18 @@
 package entities
 
 import (
+	"encoding/json"
+	"fmt"
 	"math"
+	"slices"
 	"sync"
+	"time"
 
 	"github.com/go-mclib/client/pkg/client"
 	"github.com/go-mclib/client/pkg/client/modules/self"
 	"github.com/go-mclib/data/pkg/data/entities"
 	entity_hitboxes "github.com/go-mclib/data/pkg/data/hitboxes/entities"
+	"github.com/go-mclib/data/pkg/data/items"
 	"github.com/go-mclib/data/pkg/data/packet_ids"
 	"github.com/go-mclib/data/pkg/packets"
 	jp "github.com/go-mclib/protocol/java_protocol"
@@ -22,35 +27,113 @@ type Entity struct {
 	TypeID   int32
 	TypeName string
 
-	X, Y, Z          float64
-	Yaw, Pitch       float32
-	HeadYaw          float32
-	VelX, VelY, VelZ float64
-	OnGround         bool
-	Width, Height    float64
-	EyeHeight        float64
-	SpawnData        int32 // extra data from S2CAddEntity (e.g. block state for falling blocks)
-	Metadata         entities.Metadata
+	X, Y, Z           float64
+	Yaw, Pitch        float32
+	HeadYaw           float32
+	VelX, VelY, VelZ  float64
+	OnGround          bool
+	Width, Height     float64
+	EyeHeight         float64
+	SpawnData         int32 // extra data from S2CAddEntity (e.g. block state for falling blocks)
+	MainHand, OffHand int32 // held item IDs from S2CSetEquipment, 0 if empty or not received
+	Head              int32 // item ID worn on the head, as MainHand
+	Metadata          entities.Metadata
 }
 
+// shared entity flags (metadata index 0)
+const (
+	FlagOnFire     = 0x01
+	FlagSneaking   = 0x02
+	FlagSprinting  = 0x08
+	FlagSwimming   = 0x10
+	FlagInvisible  = 0x20
+	FlagGlowing    = 0x40
+	FlagFallFlying = 0x80
+)
+
+// animations of S2CAnimate (OnEntityAnimation)
+const (
+	AnimationSwingMainHand    = 0
+	AnimationWakeUp           = 2
+	AnimationSwingOffHand     = 3
+	AnimationCriticalHit      = 4
+	AnimationMagicCriticalHit = 5
+)
+
+// Flags returns the entity's shared flags byte (see Flag*), 0 if not received yet.
+func (e *Entity) Flags() byte {
+	if d := e.Metadata.Get(entities.EntityIndexFlags); len(d) > 0 {
+		return d[0]
+	}
+	return 0
+}
+
+// Invisible reports whether the entity has the invisible flag (potion or /effect).
+func (e *Entity) Invisible() bool { return e.Flags()&FlagInvisible != 0 }
+
 type Module struct {
 	client *client.Client
 
 	mu       sync.RWMutex
 	entities map[int32]*Entity
 
+	// MaxEntities caps the tracked entities so a server spawning masses of
+	// them can't run the bot out of memory: past it, the entities farthest
+	// from the player are dropped, as if removed (0 = no cap). Dropped
+	// entities aren't tracked again until the server re-adds them.
+	MaxEntities int
+	dropped     int
+
+	// last-seen history of removed entities (see history.go)
+	HistorySize   int
+	HistoryExpiry time.Duration
+	history       map[[16]byte]*Sighting
+
+	// RangeHysteresis is how far past a watched radius an entity must move to
+	// exit it (default: DefaultRangeHysteresis), so entities pacing along the
+	// edge don't fire enter/exit every tick. See ranges.go.
+	RangeHysteresis float64
+	ranges          rangeWatches
+
+	// TelegraphRange is how far away attack telegraphs are reported
+	// (default: DefaultTelegraphRange). See telegraph.go.
+	TelegraphRange float64
+	lastSwing      map[int32]time.Time
+	aggressors     map[int32]time.Time
+
+	// traders and patrol captains already reported, and the raid the player
+	// is in (see raids.go)
+	announced map[[16]byte]bool
+	raid      *Raid
+
 	onEntitySpawn     []func(e *Entity)
 	onEntityRemove    []func(entityID int32)
 	onEntityMove      []func(e *Entity)
 	onEntityVelocity  []func(e *Entity)
 	onEntityDamage    []func(entityID, sourceTypeID, sourceCauseID, sourceDirectID int32)
+	onDamage          []func(entityID int32, src DamageSource)
 	onEntityAnimation []func(entityID int32, animation uint8)
+	onAllayDelivery   []func(allayID, itemEntityID int32, item *items.ItemStack)
 	onHurtAnimation   []func(entityID int32, yaw float32)
+	onTelegraph       []func(entityID int32, kind TelegraphKind)
+	onWanderingTrader []func(e *Entity)
+	onPatrol          []func(p Patrol)
+	onRaidStart       []func(r Raid)
+	onRaidEnd         []func(r Raid, outcome RaidOutcome)
 }
 
 func New() *Module {
 	return &Module{
-		entities: make(map[int32]*Entity),
+		entities:      make(map[int32]*Entity),
+		HistorySize:   DefaultHistorySize,
+		HistoryExpiry: DefaultHistoryExpiry,
+		history:       make(map[[16]byte]*Sighting),
+
+		RangeHysteresis: DefaultRangeHysteresis,
+		TelegraphRange:  DefaultTelegraphRange,
+		lastSwing:       make(map[int32]time.Time),
+		aggressors:      make(map[int32]time.Time),
+		announced:       make(map[[16]byte]bool),
 	}
 }
 
@@ -60,10 +143,63 @@ func (m *Module) Init(c *client.Client) {
 	c.OnTransfer(m.Reset)
 }
 
+// PrepareConfig implements client.Reloadable for the "entities" section.
+func (m *Module) PrepareConfig(raw json.RawMessage) (func(), error) {
+	var cfg struct {
+		HistorySize     *int             `json:"history_size"`
+		HistoryExpiry   *client.Duration `json:"history_expiry"`
+		RangeHysteresis *float64         `json:"range_hysteresis"`
+		TelegraphRange  *float64         `json:"telegraph_range"`
+		MaxEntities     *int             `json:"max_entities"`
+	}
+	if err := json.Unmarshal(raw, &cfg); err != nil {
+		return nil, err
+	}
+	if cfg.HistorySize != nil && *cfg.HistorySize < 0 {
+		return nil, fmt.Errorf("history_size must not be negative, got %d", *cfg.HistorySize)
+	}
+	if cfg.HistoryExpiry != nil && *cfg.HistoryExpiry < 0 {
+		return nil, fmt.Errorf("history_expiry must not be negative")
+	}
+	if cfg.RangeHysteresis != nil && *cfg.RangeHysteresis < 0 {
+		return nil, fmt.Errorf("range_hysteresis must not be negative, got %g", *cfg.RangeHysteresis)
+	}
+	if cfg.TelegraphRange != nil && *cfg.TelegraphRange < 0 {
+		return nil, fmt.Errorf("telegraph_range must not be negative, got %g", *cfg.TelegraphRange)
+	}
+	if cfg.MaxEntities != nil && *cfg.MaxEntities < 0 {
+		return nil, fmt.Errorf("max_entities must not be negative, got %d", *cfg.MaxEntities)
+	}
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		if cfg.HistorySize != nil {
+			m.HistorySize = *cfg.HistorySize
+		}
+		if cfg.HistoryExpiry != nil {
+			m.HistoryExpiry = time.Duration(*cfg.HistoryExpiry)
+		}
+		if cfg.RangeHysteresis != nil {
+			m.RangeHysteresis = *cfg.RangeHysteresis
+		}
+		if cfg.TelegraphRange != nil {
+			m.TelegraphRange = *cfg.TelegraphRange
+		}
+		if cfg.MaxEntities != nil {
+			m.MaxEntities = *cfg.MaxEntities
+		}
+	}, nil
+}
+
 func (m *Module) Reset() {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	m.entities = make(map[int32]*Entity)
+	m.history = make(map[[16]byte]*Sighting)
+	m.lastSwing = make(map[int32]time.Time)
+	m.aggressors = make(map[int32]time.Time)
+	m.announced = make(map[[16]byte]bool)
+	m.raid = nil
 }
 
 func From(c *client.Client) *Module {
@@ -123,6 +259,12 @@ func (m *Module) HandlePacket(pkt *jp.WirePacket) {
 		m.handleAnimate(pkt)
 	case packet_ids.S2CHurtAnimationID:
 		m.handleHurtAnimation(pkt)
+	case packet_ids.S2CSetEquipmentID:
+		m.handleSetEquipment(pkt)
+	case packet_ids.S2CRotateHeadID:
+		m.handleRotateHead(pkt)
+	case packet_ids.S2CBossEventID:
+		m.handleBossEvent(pkt)
 	}
 }
 
@@ -155,13 +297,54 @@ func (m *Module) handleAddEntity(pkt *jp.WirePacket) {
 		SpawnData: int32(d.Data),
 	}
 
+	_, pos, _ := m.playerPos()
 	m.mu.Lock()
 	m.entities[e.ID] = e
+	delete(m.history, e.UUID)
+	dropped := m.dropFarthest(pos)
 	m.mu.Unlock()
 
-	for _, cb := range m.onEntitySpawn {
-		cb(e)
+	if !slices.Contains(dropped, e.ID) {
+		for _, cb := range m.onEntitySpawn {
+			cb(e)
+		}
+		m.announceTrader(e)
+	}
+	for _, id := range dropped {
+		if id == e.ID {
+			continue
+		}
+		for _, cb := range m.onEntityRemove {
+			cb(id)
+		}
+	}
+}
+
+// dropFarthest drops the entities farthest from pos over MaxEntities and
+// returns their IDs. Must be called with mu held.
+func (m *Module) dropFarthest(pos [3]float64) []int32 {
+	var dropped []int32
+	for m.MaxEntities > 0 && len(m.entities) > m.MaxEntities {
+		var farthest *Entity
+		for _, e := range m.entities {
+			if farthest == nil || distSqTo(e, pos[0], pos[1], pos[2]) > distSqTo(farthest, pos[0], pos[1], pos[2]) {
+				farthest = e
+			}
+		}
+		m.remember(farthest)
+		delete(m.entities, farthest.ID)
+		dropped = append(dropped, farthest.ID)
+		m.dropped++
 	}
+	return dropped
+}
+
+// DroppedEntityCount returns how many entities were dropped for being over
+// MaxEntities.
+func (m *Module) DroppedEntityCount() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.dropped
 }
 
 func (m *Module) handleRemoveEntities(pkt *jp.WirePacket) {
@@ -184,7 +367,10 @@ func (m *Module) handleRemoveEntities(pkt *jp.WirePacket) {
 
 	m.mu.Lock()
 	for _, id := range ids {
-		delete(m.entities, id)
+		if e, ok := m.entities[id]; ok {
+			m.remember(e)
+			delete(m.entities, id)
+		}
 	}
 	m.mu.Unlock()
 
@@ -209,6 +395,7 @@ func (m *Module) handleForgetLevelChunk(pkt *jp.WirePacket) {
 		ecx := int32(math.Floor(e.X / 16))
 		ecz := int32(math.Floor(e.Z / 16))
 		if ecx == cx && ecz == cz {
+			m.remember(e)
 			delete(m.entities, id)
 			removed = append(removed, id)
 		}
@@ -342,16 +529,36 @@ func (m *Module) handleSetEntityData(pkt *jp.WirePacket) {
 		return
 	}
 
+	ownID, pos, hasSelf := m.playerPos()
+
+	var telegraphs []TelegraphKind
+	deliveredBy, delivered := int32(-1), (*items.ItemStack)(nil)
 	m.mu.Lock()
 	e := m.entities[int32(d.EntityId)]
 	if e != nil {
+		before := e.telegraphState()
+		hadItem := len(e.Metadata.Get(entities.ItemEntityIndexItem)) > 0
 		// merge entries instead of replacing — S2CSetEntityData only sends
 		// dirty entries, so replacing would lose previously set values
 		for _, entry := range d.Metadata {
 			e.Metadata.Set(entry.Index, entry.Serializer, entry.Data)
 		}
+		if hasSelf && e.ID != ownID {
+			telegraphs = m.metadataTelegraphs(e, before, e.telegraphState(), pos)
+		}
+		// an item entity's stack arrives right after it spawns
+		if !hadItem && e.TypeName == "minecraft:item" {
+			deliveredBy, delivered = m.allayDelivery(e)
+		}
 	}
 	m.mu.Unlock()
+
+	m.fireTelegraphs(int32(d.EntityId), telegraphs...)
+	if deliveredBy >= 0 {
+		for _, cb := range m.onAllayDelivery {
+			cb(deliveredBy, int32(d.EntityId), delivered)
+		}
+	}
 }
 
 func (m *Module) handleDamageEvent(pkt *jp.WirePacket) {
@@ -363,6 +570,12 @@ func (m *Module) handleDamageEvent(pkt *jp.WirePacket) {
 	for _, cb := range m.onEntityDamage {
 		cb(int32(d.EntityId), int32(d.SourceTypeId), int32(d.SourceCauseId), int32(d.SourceDirectId))
 	}
+	if len(m.onDamage) > 0 {
+		src := m.resolveDamage(int32(d.SourceTypeId), int32(d.SourceCauseId), int32(d.SourceDirectId))
+		for _, cb := range m.onDamage {
+			cb(int32(d.EntityId), src)
+		}
+	}
 }
 
 func (m *Module) handleAnimate(pkt *jp.WirePacket) {
@@ -374,6 +587,11 @@ func (m *Module) handleAnimate(pkt *jp.WirePacket) {
 	for _, cb := range m.onEntityAnimation {
 		cb(int32(d.EntityId), uint8(d.Animation))
 	}
+	if d.Animation == AnimationSwingMainHand || d.Animation == AnimationSwingOffHand {
+		if m.swingTelegraph(int32(d.EntityId)) {
+			m.fireTelegraphs(int32(d.EntityId), TelegraphMelee)
+		}
+	}
 }
 
 func (m *Module) handleHurtAnimation(pkt *jp.WirePacket) {
@@ -382,6 +600,9 @@ func (m *Module) handleHurtAnimation(pkt *jp.WirePacket) {
 		return
 	}
 
+	if int32(d.EntityId) == m.ownEntityID() {
+		m.attributeHurt()
+	}
 	for _, cb := range m.onHurtAnimation {
 		cb(int32(d.EntityId), float32(d.Yaw))
 	}
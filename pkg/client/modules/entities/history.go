@@ -0,0 +1,115 @@
+package entities
+
+import (
+	"slices"
+	"time"
+)
+
+const (
+	// DefaultHistorySize is the default number of despawned entities remembered.
+	DefaultHistorySize = 256
+	// DefaultHistoryExpiry is the default time after which a sighting is forgotten.
+	DefaultHistoryExpiry = 10 * time.Minute
+)
+
+// Sighting is the last known state of an entity that left render distance
+// (or was removed by the server).
+type Sighting struct {
+	ID       int32
+	UUID     [16]byte
+	TypeID   int32
+	TypeName string
+	X, Y, Z  float64
+	LastSeen time.Time
+}
+
+// LastSeen returns where the entity with the given UUID was last seen. For
+// entities that are still tracked this is their current position and the
+// current time.
+func (m *Module) LastSeen(uuid [16]byte) (Sighting, bool) {
+	if e := m.GetEntityByUUID(uuid); e != nil {
+		return sightingOf(e, m.client.Now()), true
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.pruneHistory(m.client.Now())
+	s, ok := m.history[uuid]
+	if !ok {
+		return Sighting{}, false
+	}
+	return *s, true
+}
+
+// History returns all remembered sightings matching filter (nil matches all),
+// most recent first. Entities that are currently tracked are not included.
+func (m *Module) History(filter func(*Sighting) bool) []Sighting {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.pruneHistory(m.client.Now())
+
+	result := make([]Sighting, 0, len(m.history))
+	for _, s := range m.history {
+		if filter == nil || filter(s) {
+			result = append(result, *s)
+		}
+	}
+	slices.SortFunc(result, func(a, b Sighting) int { return b.LastSeen.Compare(a.LastSeen) })
+	return result
+}
+
+// ClearHistory forgets all sightings.
+func (m *Module) ClearHistory() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.history = make(map[[16]byte]*Sighting)
+}
+
+func sightingOf(e *Entity, t time.Time) Sighting {
+	return Sighting{
+		ID:       e.ID,
+		UUID:     e.UUID,
+		TypeID:   e.TypeID,
+		TypeName: e.TypeName,
+		X:        e.X,
+		Y:        e.Y,
+		Z:        e.Z,
+		LastSeen: t,
+	}
+}
+
+// remember records a removed entity. Must be called with mu held.
+func (m *Module) remember(e *Entity) {
+	if m.HistorySize <= 0 {
+		return
+	}
+	now := m.client.Now()
+	s := sightingOf(e, now)
+	m.history[e.UUID] = &s
+	m.pruneHistory(now)
+
+	// evict the oldest sightings when over capacity
+	for len(m.history) > m.HistorySize {
+		var oldest [16]byte
+		var oldestTime time.Time
+		first := true
+		for uuid, s := range m.history {
+			if first || s.LastSeen.Before(oldestTime) {
+				oldest, oldestTime, first = uuid, s.LastSeen, false
+			}
+		}
+		delete(m.history, oldest)
+	}
+}
+
+// pruneHistory drops expired sightings. Must be called with mu held.
+func (m *Module) pruneHistory(now time.Time) {
+	if m.HistoryExpiry <= 0 {
+		return
+	}
+	for uuid, s := range m.history {
+		if now.Sub(s.LastSeen) > m.HistoryExpiry {
+			delete(m.history, uuid)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package entities
+
+import (
+	"github.com/go-mclib/client/pkg/client/modules/self"
+	"github.com/go-mclib/client/pkg/client/modules/world"
+	"github.com/go-mclib/data/pkg/data/entities"
+)
+
+// MapMarkers returns markers for world.RenderTopDown: the tracked entities
+// within region, colored by kind (players, monsters, others), and the player
+// itself last so it is drawn on top.
+func (m *Module) MapMarkers(region world.Region) []world.Marker {
+	var markers []world.Marker
+	for _, e := range m.GetAllEntities() {
+		if !region.Contains(e.X, e.Z) {
+			continue
+		}
+		c := world.MarkerOther
+		switch {
+		case e.TypeName == "minecraft:player":
+			c = world.MarkerPlayer
+		case entities.EntityCategory(e.TypeName) == "monster":
+			c = world.MarkerHostile
+		}
+		markers = append(markers, world.Marker{X: e.X, Z: e.Z, Color: c})
+	}
+
+	if s := self.From(m.client); s != nil {
+		x, _, z := s.Position()
+		if region.Contains(x, z) {
+			markers = append(markers, world.Marker{X: x, Z: z, Color: world.MarkerSelf})
+		}
+	}
+	return markers
+}
@@ -0,0 +1,223 @@
+package entities
+
+import (
+	"math"
+	"strings"
+
+	"github.com/go-mclib/data/pkg/data/items"
+	"github.com/go-mclib/data/pkg/packets"
+	jp "github.com/go-mclib/protocol/java_protocol"
+	ns "github.com/go-mclib/protocol/java_protocol/net_structures"
+)
+
+// patrolRange is how far from its captain a patrol member may be: patrols
+// spawn within a few blocks of each other and follow the captain closely.
+const patrolRange = 16.0
+
+// boss bar title keys of raids
+const (
+	raidTitle       = "event.minecraft.raid"
+	raidVictoryText = "event.minecraft.raid.victory"
+	raidDefeatText  = "event.minecraft.raid.defeat"
+)
+
+// illagers that join patrols and raids
+var illagerTypes = map[string]bool{
+	"minecraft:pillager":   true,
+	"minecraft:vindicator": true,
+	"minecraft:evoker":     true,
+	"minecraft:illusioner": true,
+}
+
+// Patrol is a group of illagers led by a captain, who wears an ominous banner.
+type Patrol struct {
+	Captain int32
+	Members []int32 // other illagers near the captain when it was seen, captain excluded
+}
+
+// Raid is a raid the player takes part in, as shown by its boss bar.
+type Raid struct {
+	Bar      [16]byte // boss bar UUID
+	Progress float32  // bar fill, 0-1: spawn progress between waves, raiders left during one
+}
+
+// RaidOutcome is how a raid the player was in ended.
+type RaidOutcome int
+
+const (
+	RaidLeft    RaidOutcome = iota // the bar went away: the player left the village or the raid was stopped
+	RaidVictory                    // all waves defeated
+	RaidDefeat                     // the raiders won
+)
+
+func (o RaidOutcome) String() string {
+	switch o {
+	case RaidVictory:
+		return "victory"
+	case RaidDefeat:
+		return "defeat"
+	}
+	return "left"
+}
+
+// IsWanderingTrader reports whether the entity is a wandering trader.
+func (e *Entity) IsWanderingTrader() bool { return e.TypeName == "minecraft:wandering_trader" }
+
+// IsCaptain reports whether the entity is an illager wearing a banner on its
+// head, which marks the leader of a patrol or raid.
+func (e *Entity) IsCaptain() bool { return illagerTypes[e.TypeName] && e.Head == items.WhiteBanner }
+
+// OnWanderingTrader is called the first time a wandering trader comes into
+// view, with the trader's position in e. A trader that leaves view and comes
+// back isn't reported again.
+func (m *Module) OnWanderingTrader(cb func(e *Entity)) {
+	m.onWanderingTrader = append(m.onWanderingTrader, cb)
+}
+
+// OnPatrol is called the first time the captain of an illager patrol comes
+// into view outside of a raid. Members are the illagers around the captain
+// at that point; stragglers may arrive later.
+func (m *Module) OnPatrol(cb func(p Patrol)) {
+	m.onPatrol = append(m.onPatrol, cb)
+}
+
+// OnRaidStart is called when the server shows the player a raid bar, when a
+// raid starts or the player walks into one.
+func (m *Module) OnRaidStart(cb func(r Raid)) {
+	m.onRaidStart = append(m.onRaidStart, cb)
+}
+
+// OnRaidEnd is called when the raid bar shows the raid was won or lost, or
+// when it goes away (RaidLeft) before that.
+func (m *Module) OnRaidEnd(cb func(r Raid, outcome RaidOutcome)) {
+	m.onRaidEnd = append(m.onRaidEnd, cb)
+}
+
+// CurrentRaid returns the raid the player is in, if the server shows one.
+func (m *Module) CurrentRaid() (Raid, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.raid == nil {
+		return Raid{}, false
+	}
+	return *m.raid, true
+}
+
+// announceTrader fires OnWanderingTrader for a trader not seen before.
+func (m *Module) announceTrader(e *Entity) {
+	if !e.IsWanderingTrader() {
+		return
+	}
+	m.mu.Lock()
+	seen := m.announced[e.UUID]
+	m.announced[e.UUID] = true
+	m.mu.Unlock()
+	if seen {
+		return
+	}
+	for _, cb := range m.onWanderingTrader {
+		cb(e)
+	}
+}
+
+// announcePatrol fires OnPatrol for a captain not seen before, once its
+// banner arrived. Raid captains wear the same banner, so captains don't count
+// while a raid is on.
+func (m *Module) announcePatrol(entityID int32) {
+	m.mu.Lock()
+	e := m.entities[entityID]
+	if e == nil || !e.IsCaptain() || m.raid != nil || m.announced[e.UUID] {
+		m.mu.Unlock()
+		return
+	}
+	m.announced[e.UUID] = true
+	p := Patrol{Captain: e.ID}
+	for id, o := range m.entities {
+		if id == e.ID || !illagerTypes[o.TypeName] {
+			continue
+		}
+		if math.Sqrt(distSqTo(o, e.X, e.Y, e.Z)) <= patrolRange {
+			p.Members = append(p.Members, id)
+		}
+	}
+	m.mu.Unlock()
+
+	for _, cb := range m.onPatrol {
+		cb(p)
+	}
+}
+
+func (m *Module) handleBossEvent(pkt *jp.WirePacket) {
+	var d packets.S2CBossEvent
+	if err := pkt.ReadInto(&d); err != nil {
+		return
+	}
+	bar := [16]byte(d.Uuid)
+
+	m.mu.Lock()
+	var start, end *Raid
+	outcome := RaidLeft
+	switch d.Action {
+	case packets.BossEventActionAdd:
+		add, err := d.DataActionAdd()
+		if err != nil || add.Title.Translate != raidTitle || m.raid != nil {
+			break
+		}
+		m.raid = &Raid{Bar: bar, Progress: float32(add.Health)}
+		r := *m.raid
+		start = &r
+	case packets.BossEventActionUpdateHealth:
+		h, err := d.DataActionUpdateHealth()
+		if err == nil && m.raid != nil && m.raid.Bar == bar {
+			m.raid.Progress = float32(h.Health)
+		}
+	case packets.BossEventActionUpdateTitle:
+		t, err := d.DataActionUpdateTitle()
+		if err != nil || m.raid == nil || m.raid.Bar != bar {
+			break
+		}
+		switch {
+		case hasTranslation(t.Title, raidVictoryText):
+			outcome = RaidVictory
+		case hasTranslation(t.Title, raidDefeatText):
+			outcome = RaidDefeat
+		}
+		if outcome != RaidLeft {
+			r := *m.raid
+			end = &r
+			m.raid = nil
+		}
+	case packets.BossEventActionRemove:
+		if m.raid != nil && m.raid.Bar == bar {
+			r := *m.raid
+			end = &r
+			m.raid = nil
+		}
+	}
+	m.mu.Unlock()
+
+	if start != nil {
+		for _, cb := range m.onRaidStart {
+			cb(*start)
+		}
+	}
+	if end != nil {
+		for _, cb := range m.onRaidEnd {
+			cb(*end, outcome)
+		}
+	}
+}
+
+// hasTranslation reports whether tc or one of its children is a translation
+// whose key starts with prefix.
+func hasTranslation(tc ns.TextComponent, prefix string) bool {
+	if strings.HasPrefix(tc.Translate, prefix) {
+		return true
+	}
+	for _, c := range tc.Extra {
+		if hasTranslation(c, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,136 @@
+package entities
+
+import (
+	"sync"
+
+	"github.com/go-mclib/client/pkg/client/modules/self"
+)
+
+// DefaultRangeHysteresis is the default extra distance an entity must move
+// past a watched radius before it counts as having left it.
+const DefaultRangeHysteresis = 1.0
+
+// rangeWatch tracks which entities are within one radius of the player.
+type rangeWatch struct {
+	radius  float64
+	inside  map[int32]*Entity
+	onEnter []func(e *Entity)
+	onExit  []func(e *Entity)
+}
+
+type rangeWatches struct {
+	mu      sync.Mutex
+	watches []*rangeWatch
+}
+
+// watch returns the watch for radius, creating it if needed.
+func (r *rangeWatches) watch(radius float64) *rangeWatch {
+	for _, w := range r.watches {
+		if w.radius == radius {
+			return w
+		}
+	}
+	w := &rangeWatch{radius: radius, inside: make(map[int32]*Entity)}
+	r.watches = append(r.watches, w)
+	return w
+}
+
+// OnEntityEnterRange is called when a tracked entity comes within radius
+// blocks of the player, whether it moved, the player moved or it spawned
+// there. Ranges are evaluated once per tick by the physics module, so guard
+// and follow behaviors get one event per crossing instead of polling.
+func (m *Module) OnEntityEnterRange(radius float64, cb func(e *Entity)) {
+	m.ranges.mu.Lock()
+	defer m.ranges.mu.Unlock()
+	w := m.ranges.watch(radius)
+	w.onEnter = append(w.onEnter, cb)
+}
+
+// OnEntityExitRange is called when an entity that entered radius moves more
+// than RangeHysteresis beyond it, or stops being tracked (removed, dead or
+// its chunk unloaded). e holds the last known state.
+func (m *Module) OnEntityExitRange(radius float64, cb func(e *Entity)) {
+	m.ranges.mu.Lock()
+	defer m.ranges.mu.Unlock()
+	w := m.ranges.watch(radius)
+	w.onExit = append(w.onExit, cb)
+}
+
+// EntitiesInRange returns the entities currently inside a watched radius (as
+// of the last tick), or nil if nothing watches it.
+func (m *Module) EntitiesInRange(radius float64) []*Entity {
+	m.ranges.mu.Lock()
+	defer m.ranges.mu.Unlock()
+	for _, w := range m.ranges.watches {
+		if w.radius != radius {
+			continue
+		}
+		result := make([]*Entity, 0, len(w.inside))
+		for _, e := range w.inside {
+			result = append(result, e)
+		}
+		return result
+	}
+	return nil
+}
+
+type rangeEvent struct {
+	cbs []func(e *Entity)
+	e   *Entity
+}
+
+// TickRanges updates the watched ranges and fires enter/exit callbacks.
+// Called once per tick by the physics module.
+func (m *Module) TickRanges() {
+	s := self.From(m.client)
+	if s == nil {
+		return
+	}
+	px, py, pz := s.Position()
+	ownID := m.ownEntityID()
+
+	m.ranges.mu.Lock()
+	if len(m.ranges.watches) == 0 {
+		m.ranges.mu.Unlock()
+		return
+	}
+	var events []rangeEvent
+	m.mu.RLock()
+	hysteresis := m.RangeHysteresis
+	for _, w := range m.ranges.watches {
+		enterSq := w.radius * w.radius
+		exitSq := (w.radius + hysteresis) * (w.radius + hysteresis)
+		for id, e := range w.inside {
+			cur, ok := m.entities[id]
+			if !ok || cur != e {
+				delete(w.inside, id)
+				events = append(events, rangeEvent{w.onExit, e})
+			} else if distSqTo(e, px, py, pz) > exitSq {
+				delete(w.inside, id)
+				events = append(events, rangeEvent{w.onExit, e})
+			}
+		}
+		for id, e := range m.entities {
+			if id == ownID {
+				continue
+			}
+			if _, in := w.inside[id]; !in && distSqTo(e, px, py, pz) <= enterSq {
+				w.inside[id] = e
+				events = append(events, rangeEvent{w.onEnter, e})
+			}
+		}
+	}
+	m.mu.RUnlock()
+	m.ranges.mu.Unlock()
+
+	for _, ev := range events {
+		for _, cb := range ev.cbs {
+			cb(ev.e)
+		}
+	}
+}
+
+func distSqTo(e *Entity, x, y, z float64) float64 {
+	dx, dy, dz := e.X-x, e.Y-y, e.Z-z
+	return dx*dx + dy*dy + dz*dz
+}
@@ -0,0 +1,337 @@
+package entities
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/go-mclib/client/pkg/client/modules/self"
+	"github.com/go-mclib/data/pkg/data/entities"
+	"github.com/go-mclib/data/pkg/data/items"
+	"github.com/go-mclib/data/pkg/packets"
+	jp "github.com/go-mclib/protocol/java_protocol"
+	ns "github.com/go-mclib/protocol/java_protocol/net_structures"
+)
+
+// TelegraphKind is the kind of attack an entity is starting.
+type TelegraphKind int
+
+const (
+	TelegraphMelee      TelegraphKind = iota // arm swing in melee range, facing the player
+	TelegraphRanged                          // started drawing a bow or crossbow, or raising a trident
+	TelegraphAggressive                      // mob switched to attacking (zombies raise their arms)
+	TelegraphExplosion                       // creeper started to swell or was ignited
+	TelegraphFireball                        // ghast or blaze charging a fireball
+	TelegraphRiptide                         // riptide spin attack
+)
+
+func (k TelegraphKind) String() string {
+	switch k {
+	case TelegraphMelee:
+		return "melee"
+	case TelegraphRanged:
+		return "ranged"
+	case TelegraphAggressive:
+		return "aggressive"
+	case TelegraphExplosion:
+		return "explosion"
+	case TelegraphFireball:
+		return "fireball"
+	case TelegraphRiptide:
+		return "riptide"
+	}
+	return fmt.Sprintf("TelegraphKind(%d)", int(k))
+}
+
+// DefaultTelegraphRange is the default distance within which attack
+// telegraphs are reported (skeletons shoot from up to 15 blocks).
+const DefaultTelegraphRange = 16.0
+
+const (
+	// meleeTelegraphRange is the player entity interaction range plus a
+	// margin for latency.
+	meleeTelegraphRange = 4.5
+	// facingTolerance is how far (degrees) an entity may look away from the
+	// player and still be considered aiming at it.
+	facingTolerance = 45.0
+	// aggressorWindow is how long after a swing the player's hurt animation
+	// is attributed to the swinger.
+	aggressorWindow = 500 * time.Millisecond
+	// aggressorMemory is how long an entity that hit the player counts as an
+	// aggressor, whose telegraphs are reported whichever way it faces.
+	aggressorMemory = 30 * time.Second
+)
+
+// living entity flags (LivingEntity metadata index 8)
+const (
+	livingUsingItem  = 0x01
+	livingSpinAttack = 0x04
+)
+
+// mob flags (Mob metadata index 15)
+const mobAggressive = 0x04
+
+// blaze flags (Blaze metadata index 16)
+const blazeCharged = 0x01
+
+// rangedMobs use a ranged weapon when their equipment isn't known.
+var rangedMobs = map[string]bool{
+	"minecraft:skeleton": true,
+	"minecraft:stray":    true,
+	"minecraft:bogged":   true,
+	"minecraft:pillager": true,
+}
+
+// nonMobLiving are living entities without Mob metadata.
+var nonMobLiving = map[string]bool{
+	"minecraft:player":      true,
+	"minecraft:armor_stand": true,
+	"minecraft:mannequin":   true,
+}
+
+// OnTelegraphedAttack is called when an entity within TelegraphRange starts
+// an attack on the player: swings at it, draws a bow at it, a creeper starts
+// to swell. It runs on the packet that shows it, so defensive behaviors
+// (raise a shield, strafe, retreat) can react within the tick.
+func (m *Module) OnTelegraphedAttack(cb func(entityID int32, kind TelegraphKind)) {
+	m.onTelegraph = append(m.onTelegraph, cb)
+}
+
+// IsAggressor reports whether the entity recently hit the player (a swing
+// followed by the player's hurt animation).
+func (m *Module) IsAggressor(entityID int32) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.client.Now().Sub(m.aggressors[entityID]) < aggressorMemory
+}
+
+// telegraphState is the metadata that telegraphs an attack.
+type telegraphState struct {
+	usingItem  bool
+	spinAttack bool
+	aggressive bool
+	swelling   bool
+	charging   bool
+}
+
+func (e *Entity) telegraphState() telegraphState {
+	var st telegraphState
+	if !entities.IsAttackable(e.TypeName) {
+		return st
+	}
+	if d := e.Metadata.Get(entities.LivingEntityIndexLivingFlags); len(d) > 0 {
+		st.usingItem = d[0]&livingUsingItem != 0
+		st.spinAttack = d[0]&livingSpinAttack != 0
+	}
+	if nonMobLiving[e.TypeName] {
+		return st
+	}
+	if d := e.Metadata.Get(entities.MobIndexMobFlags); len(d) > 0 {
+		st.aggressive = d[0]&mobAggressive != 0
+	}
+	switch e.TypeName {
+	case "minecraft:creeper":
+		if d := e.Metadata.Get(entities.CreeperIndexSwellDir); len(d) > 0 {
+			dir, _ := ns.NewReader(d).ReadVarInt()
+			st.swelling = dir > 0
+		}
+		if d := e.Metadata.Get(entities.CreeperIndexIsIgnited); len(d) > 0 && d[0] != 0 {
+			st.swelling = true
+		}
+	case "minecraft:ghast":
+		if d := e.Metadata.Get(entities.GhastIndexIsCharging); len(d) > 0 {
+			st.charging = d[0] != 0
+		}
+	case "minecraft:blaze":
+		if d := e.Metadata.Get(entities.BlazeIndexBlazeFlags); len(d) > 0 {
+			st.charging = d[0]&blazeCharged != 0
+		}
+	}
+	return st
+}
+
+// metadataTelegraphs compares an entity's state before and after a metadata
+// update, with the player at pos. Must be called with mu held.
+func (m *Module) metadataTelegraphs(e *Entity, before, after telegraphState, pos [3]float64) []TelegraphKind {
+	var kinds []TelegraphKind
+	if after.usingItem && !before.usingItem && e.holdsRangedWeapon() {
+		kinds = append(kinds, TelegraphRanged)
+	}
+	if after.spinAttack && !before.spinAttack {
+		kinds = append(kinds, TelegraphRiptide)
+	}
+	if after.aggressive && !before.aggressive && !rangedMobs[e.TypeName] {
+		// skeletons turn aggressive when they draw, which is reported as ranged
+		kinds = append(kinds, TelegraphAggressive)
+	}
+	if after.swelling && !before.swelling {
+		kinds = append(kinds, TelegraphExplosion)
+	}
+	if after.charging && !before.charging {
+		kinds = append(kinds, TelegraphFireball)
+	}
+	if len(kinds) == 0 {
+		return nil
+	}
+
+	dist, facing := aimAt(e, pos)
+	if dist > m.TelegraphRange {
+		return nil
+	}
+	aggressor := m.client.Now().Sub(m.aggressors[e.ID]) < aggressorMemory
+	filtered := kinds[:0]
+	for _, k := range kinds {
+		// creepers and blazes don't need to face the player; aimed
+		// weapons do, unless the entity already hit it
+		if k == TelegraphRanged || k == TelegraphRiptide {
+			if !facing && !aggressor {
+				continue
+			}
+		}
+		filtered = append(filtered, k)
+	}
+	return filtered
+}
+
+func (e *Entity) holdsRangedWeapon() bool {
+	for _, id := range [2]int32{e.MainHand, e.OffHand} {
+		switch id {
+		case items.Bow, items.Crossbow, items.Trident:
+			return true
+		}
+	}
+	return e.MainHand == 0 && e.OffHand == 0 && rangedMobs[e.TypeName]
+}
+
+// playerPos returns the player's entity ID and position.
+func (m *Module) playerPos() (id int32, pos [3]float64, ok bool) {
+	s := self.From(m.client)
+	if s == nil {
+		return -1, pos, false
+	}
+	pos[0], pos[1], pos[2] = s.Position()
+	return s.EntityID(), pos, true
+}
+
+// aimAt returns the entity's distance to the player at pos and whether it
+// looks toward the player.
+func aimAt(e *Entity, pos [3]float64) (dist float64, facing bool) {
+	dx, dy, dz := pos[0]-e.X, pos[1]-e.Y, pos[2]-e.Z
+	dist = math.Sqrt(dx*dx + dy*dy + dz*dz)
+
+	// yaw 0 looks toward +Z, 90 toward -X
+	toPlayer := math.Atan2(-dx, dz) * 180 / math.Pi
+	diff := math.Mod(toPlayer-float64(e.HeadYaw)+540, 360) - 180
+	return dist, math.Abs(diff) <= facingTolerance
+}
+
+// swingTelegraph records a swing and reports it if it's a melee attack on
+// the player.
+func (m *Module) swingTelegraph(entityID int32) bool {
+	ownID, pos, ok := m.playerPos()
+	if !ok || entityID == ownID {
+		return false
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e := m.entities[entityID]
+	if e == nil {
+		return false
+	}
+	dist, facing := aimAt(e, pos)
+	if dist > meleeTelegraphRange {
+		return false
+	}
+	m.lastSwing[entityID] = m.client.Now()
+	return facing || m.client.Now().Sub(m.aggressors[entityID]) < aggressorMemory
+}
+
+// attributeHurt marks entities that swung just before the player was hurt
+// as aggressors.
+func (m *Module) attributeHurt() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.client.Now()
+	for id, at := range m.lastSwing {
+		if now.Sub(at) <= aggressorWindow {
+			m.aggressors[id] = now
+		}
+		delete(m.lastSwing, id)
+	}
+	for id, at := range m.aggressors {
+		if now.Sub(at) >= aggressorMemory {
+			delete(m.aggressors, id)
+		}
+	}
+}
+
+func (m *Module) fireTelegraphs(entityID int32, kinds ...TelegraphKind) {
+	for _, k := range kinds {
+		for _, cb := range m.onTelegraph {
+			cb(entityID, k)
+		}
+	}
+}
+
+// handleSetEquipment tracks held items, which tell a bow draw from eating,
+// and the head slot, where patrol captains wear their banner.
+func (m *Module) handleSetEquipment(pkt *jp.WirePacket) {
+	// parse manually: the packet struct reads the entries as a byte array
+	buf := ns.NewReader(pkt.Data)
+	id, err := buf.ReadVarInt()
+	if err != nil {
+		return
+	}
+	held := map[byte]int32{}
+	for {
+		b, err := buf.ReadByte()
+		if err != nil {
+			return
+		}
+		slot, err := buf.ReadSlot(items.Decoder())
+		if err != nil {
+			return
+		}
+		if s := b & 0x7F; s <= 1 || s == equipmentHead { // main hand, offhand, head
+			held[s] = 0
+			if slot.Count > 0 {
+				held[s] = int32(slot.ItemID)
+			}
+		}
+		if b&0x80 == 0 {
+			break
+		}
+	}
+
+	m.mu.Lock()
+	if e := m.entities[int32(id)]; e != nil {
+		if item, ok := held[0]; ok {
+			e.MainHand = item
+		}
+		if item, ok := held[1]; ok {
+			e.OffHand = item
+		}
+		if item, ok := held[equipmentHead]; ok {
+			e.Head = item
+		}
+	}
+	m.mu.Unlock()
+	if _, ok := held[equipmentHead]; ok {
+		m.announcePatrol(int32(id))
+	}
+}
+
+// equipmentHead is the head slot in S2CSetEquipment.
+const equipmentHead = 5
+
+func (m *Module) handleRotateHead(pkt *jp.WirePacket) {
+	var d packets.S2CRotateHead
+	if err := pkt.ReadInto(&d); err != nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if e := m.entities[int32(d.EntityId)]; e != nil {
+		e.HeadYaw = float32(d.HeadYaw.Degrees())
+	}
+}
@@ -145,23 +145,36 @@ func (m *Module) SetHeldSlot(slot int) error {
 // SwapToHotbar swaps an item from any container slot into a hotbar slot (0-8).
 // Uses the SWAP click mode.
 func (m *Module) SwapToHotbar(containerSlot, hotbarIndex int) error {
-	if containerSlot < 0 || containerSlot >= TotalSlots {
-		return fmt.Errorf("invalid container slot %d", containerSlot)
-	}
 	if hotbarIndex < 0 || hotbarIndex > 8 {
 		return fmt.Errorf("invalid hotbar index %d", hotbarIndex)
 	}
+	return m.swap(containerSlot, SlotHotbarStart+hotbarIndex, int8(hotbarIndex))
+}
 
-	hotbarSlot := SlotHotbarStart + hotbarIndex
+// SwapToOffhand swaps an item from any container slot into the off hand,
+// like pressing the swap key over it.
+func (m *Module) SwapToOffhand(containerSlot int) error {
+	return m.swap(containerSlot, SlotOffhand, swapButtonOffhand)
+}
+
+// swapButtonOffhand is the SWAP click button for the off hand.
+const swapButtonOffhand = 40
+
+// swap exchanges containerSlot with target, the hotbar or off hand slot the
+// SWAP click button stands for.
+func (m *Module) swap(containerSlot, target int, button int8) error {
+	if containerSlot < 0 || containerSlot >= TotalSlots {
+		return fmt.Errorf("invalid container slot %d", containerSlot)
+	}
 
 	m.mu.Lock()
 	stateID := m.stateID
 
 	// predict the swap
 	srcEntry := m.slots[containerSlot]
-	dstEntry := m.slots[hotbarSlot]
+	dstEntry := m.slots[target]
 	m.slots[containerSlot] = dstEntry
-	m.slots[hotbarSlot] = srcEntry
+	m.slots[target] = srcEntry
 
 	cursorHashed := slotToHashed(m.cursor.raw)
 	m.mu.Unlock()
@@ -170,11 +183,11 @@ func (m *Module) SwapToHotbar(containerSlot, hotbarIndex int) error {
 		WindowId: 0,
 		StateId:  ns.VarInt(stateID),
 		Slot:     ns.Int16(containerSlot),
-		Button:   ns.Int8(hotbarIndex),
+		Button:   ns.Int8(button),
 		Mode:     2, // SWAP
 		ChangedSlots: []packets.ChangedSlot{
 			{SlotNum: ns.Int16(containerSlot), Item: slotToHashed(dstEntry.raw)},
-			{SlotNum: ns.Int16(hotbarSlot), Item: slotToHashed(srcEntry.raw)},
+			{SlotNum: ns.Int16(target), Item: slotToHashed(srcEntry.raw)},
 		},
 		CarriedItem: cursorHashed,
 	})
@@ -182,14 +195,21 @@ func (m *Module) SwapToHotbar(containerSlot, hotbarIndex int) error {
 		// revert prediction on send failure
 		m.mu.Lock()
 		m.slots[containerSlot] = srcEntry
-		m.slots[hotbarSlot] = dstEntry
+		m.slots[target] = dstEntry
 		m.mu.Unlock()
 		return err
 	}
 
+	m.mu.Lock()
+	m.pendingCause = CauseInventoryMove
+	m.pendingUntil = m.client.Now().Add(causeWindow)
+	m.recordChange(containerSlot, srcEntry.item, dstEntry.item)
+	m.recordChange(target, dstEntry.item, srcEntry.item)
+	m.mu.Unlock()
+
 	for _, cb := range m.onSlotUpdate {
 		cb(containerSlot, dstEntry.item)
-		cb(hotbarSlot, srcEntry.item)
+		cb(target, srcEntry.item)
 	}
 	return nil
 }
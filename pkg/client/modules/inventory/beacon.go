@@ -0,0 +1,148 @@
+package inventory
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/go-mclib/data/pkg/data/items"
+	"github.com/go-mclib/data/pkg/data/registries"
+	"github.com/go-mclib/data/pkg/packets"
+	ns "github.com/go-mclib/protocol/java_protocol/net_structures"
+)
+
+// beacon data slots (BeaconMenu)
+const (
+	beaconDataLevels    = 0
+	beaconDataPrimary   = 1
+	beaconDataSecondary = 2
+)
+
+// beaconPaymentSlot is the only container slot of the beacon menu.
+const beaconPaymentSlot = 0
+
+// BeaconPaymentItems are the items accepted as beacon payment.
+var BeaconPaymentItems = []int32{items.IronIngot, items.GoldIngot, items.Emerald, items.Diamond, items.NetheriteIngot}
+
+// BeaconEffects lists the effects unlocked by each pyramid level (index 0 is
+// level 1), like BeaconBlockEntity.BEACON_EFFECTS. At level 4 the primary
+// effect can also be picked as secondary to raise it to level II.
+var BeaconEffects = [][]int32{
+	{registries.MobEffect.Get("minecraft:speed"), registries.MobEffect.Get("minecraft:haste")},
+	{registries.MobEffect.Get("minecraft:resistance"), registries.MobEffect.Get("minecraft:jump_boost")},
+	{registries.MobEffect.Get("minecraft:strength")},
+	{registries.MobEffect.Get("minecraft:regeneration")},
+}
+
+// beaconEffectLevel returns the pyramid level that unlocks effect, or 0.
+func beaconEffectLevel(effect int32) int {
+	for i, effects := range BeaconEffects {
+		if slices.Contains(effects, effect) {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+func (m *Module) beaconOpen() error {
+	if t := m.ContainerMenuType(); t != MenuBeacon {
+		return fmt.Errorf("no beacon open")
+	}
+	return nil
+}
+
+// BeaconLevels returns the pyramid levels (0-4) of the open beacon, or false
+// if no beacon is open or the server hasn't sent them yet. 0 means the beacon
+// is inactive (no pyramid or the beam is blocked).
+func (m *Module) BeaconLevels() (int, bool) {
+	if m.beaconOpen() != nil {
+		return 0, false
+	}
+	levels, ok := m.ContainerProperty(beaconDataLevels)
+	return int(levels), ok
+}
+
+// BeaconEffect returns the primary and secondary effect of the open beacon as
+// registries.MobEffect ids, -1 if unset.
+func (m *Module) BeaconEffect() (primary, secondary int32, ok bool) {
+	if m.beaconOpen() != nil {
+		return -1, -1, false
+	}
+	p, okP := m.ContainerProperty(beaconDataPrimary)
+	s, okS := m.ContainerProperty(beaconDataSecondary)
+	// encoded as registry id + 1, 0 for none
+	return int32(p) - 1, int32(s) - 1, okP && okS
+}
+
+// SetBeaconEffect selects the effects of the open beacon (registries.MobEffect
+// ids, -1 for none). If the payment slot is empty, one payment item from the
+// player inventory is moved into it first. The server confirms by updating the
+// beacon's data slots (see BeaconEffect); the payment item is consumed.
+func (m *Module) SetBeaconEffect(primary, secondary int32) error {
+	if err := m.beaconOpen(); err != nil {
+		return err
+	}
+	if primary < 0 && secondary < 0 {
+		return fmt.Errorf("no effect selected")
+	}
+	levels, known := m.BeaconLevels()
+	if known && levels == 0 {
+		return fmt.Errorf("beacon is inactive")
+	}
+	for _, effect := range []int32{primary, secondary} {
+		if effect < 0 {
+			continue
+		}
+		need := beaconEffectLevel(effect)
+		if need == 0 {
+			return fmt.Errorf("%s is not a beacon effect", registries.MobEffect.ByID(effect))
+		}
+		if known && need > levels {
+			return fmt.Errorf("%s needs a level %d pyramid, beacon has %d", registries.MobEffect.ByID(effect), need, levels)
+		}
+	}
+	// vanilla only allows a secondary effect at level 4: regeneration, or the
+	// primary effect again
+	if secondary >= 0 {
+		if known && levels < 4 {
+			return fmt.Errorf("secondary effect needs a level 4 pyramid, beacon has %d", levels)
+		}
+		if secondary != primary && beaconEffectLevel(secondary) != 4 {
+			return fmt.Errorf("secondary effect must be regeneration or the primary effect")
+		}
+	}
+
+	if err := m.payBeacon(); err != nil {
+		return err
+	}
+
+	pkt := &packets.C2SSetBeacon{
+		PrimaryEffect:   ns.None[ns.VarInt](),
+		SecondaryEffect: ns.None[ns.VarInt](),
+	}
+	if primary >= 0 {
+		pkt.PrimaryEffect = ns.Some(ns.VarInt(primary))
+	}
+	if secondary >= 0 {
+		pkt.SecondaryEffect = ns.Some(ns.VarInt(secondary))
+	}
+	return m.client.WritePacket(pkt)
+}
+
+// payBeacon shift-clicks a payment item into the empty payment slot. The
+// slot holds a single item, so BeaconMenu.quickMoveStack moves just one.
+func (m *Module) payBeacon() error {
+	if s := m.ContainerSlot(beaconPaymentSlot); !s.IsEmpty() {
+		if !slices.Contains(BeaconPaymentItems, s.ID) {
+			return fmt.Errorf("payment slot holds %s", items.ItemName(s.ID))
+		}
+		return nil
+	}
+	for i := SlotMainStart; i < SlotHotbarEnd; i++ {
+		item := m.GetSlot(i)
+		if item.IsEmpty() || !slices.Contains(BeaconPaymentItems, item.ID) {
+			continue
+		}
+		return m.ContainerShiftClick(m.ContainerSlotCount() + i - SlotMainStart)
+	}
+	return fmt.Errorf("no beacon payment item (iron, gold, emerald, diamond or netherite) in inventory")
+}
@@ -0,0 +1,211 @@
+package inventory
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/go-mclib/data/pkg/data/items"
+	"github.com/go-mclib/data/pkg/packets"
+	ns "github.com/go-mclib/protocol/java_protocol/net_structures"
+)
+
+// book limits enforced by the server (WritableBookContent, WrittenBookContent)
+const (
+	MaxBookPages      = 100
+	MaxBookPageLength = 1024
+	MaxBookTitle      = 32
+)
+
+// ErrNotABook is returned when a slot holds no book contents.
+var ErrNotABook = errors.New("not a book")
+
+// Book is the contents of a written book or book and quill.
+type Book struct {
+	Title  string // empty for unsigned books
+	Author string
+	// Generation is 0 for originals, 1 for a copy, 2 for a copy of a copy and
+	// 3 for tattered books.
+	Generation int32
+	Signed     bool // written_book (pages are text components) vs writable_book
+	Pages      []ns.TextComponent
+}
+
+// PageText returns page i as plain text, or "" if out of range.
+func (b *Book) PageText(i int) string {
+	if i < 0 || i >= len(b.Pages) {
+		return ""
+	}
+	return b.Pages[i].String()
+}
+
+// Text returns every page as plain text, separated by newlines.
+func (b *Book) Text() string {
+	pages := make([]string, len(b.Pages))
+	for i := range b.Pages {
+		pages[i] = b.Pages[i].String()
+	}
+	return strings.Join(pages, "\n")
+}
+
+// BookFromSlot reads the book contents of a protocol slot. The book components
+// aren't decoded into items.ItemComponents, so they are parsed from the raw
+// component data. Returns ErrNotABook if the slot has no book contents (e.g.
+// an empty book and quill).
+func BookFromSlot(s ns.Slot) (*Book, error) {
+	for _, comp := range s.Components.Add {
+		switch comp.ID {
+		case items.ComponentWrittenBookContent:
+			return readWrittenBook(comp.Data)
+		case items.ComponentWritableBookContent:
+			return readWritableBook(comp.Data)
+		}
+	}
+	return nil, ErrNotABook
+}
+
+// Book returns the contents of the book in a player inventory slot (0-45).
+func (m *Module) Book(index int) (*Book, error) {
+	if index < 0 || index >= TotalSlots {
+		return nil, fmt.Errorf("invalid slot %d", index)
+	}
+	m.mu.RLock()
+	raw := m.slots[index].raw
+	m.mu.RUnlock()
+	return BookFromSlot(raw)
+}
+
+// ContainerBook returns the contents of the book in a container slot (0-based,
+// container slots only).
+func (m *Module) ContainerBook(index int) (*Book, error) {
+	m.mu.RLock()
+	if m.container == nil || index < 0 || index >= len(m.container.slots) {
+		m.mu.RUnlock()
+		return nil, fmt.Errorf("invalid container slot %d", index)
+	}
+	raw := m.container.slots[index].raw
+	m.mu.RUnlock()
+	return BookFromSlot(raw)
+}
+
+// filtered variants (chat filtering) are skipped, the raw text is kept
+func skipOptionalString(buf *ns.PacketBuffer) error {
+	present, err := buf.ReadBool()
+	if err != nil || !present {
+		return err
+	}
+	_, err = buf.ReadString(0)
+	return err
+}
+
+func readWritableBook(data []byte) (*Book, error) {
+	buf := ns.NewReader(data)
+	n, err := buf.ReadVarInt()
+	if err != nil {
+		return nil, err
+	}
+	b := &Book{Pages: make([]ns.TextComponent, 0, n)}
+	for range n {
+		page, err := buf.ReadString(0)
+		if err != nil {
+			return nil, err
+		}
+		if err := skipOptionalString(buf); err != nil {
+			return nil, err
+		}
+		b.Pages = append(b.Pages, ns.TextComponent{Text: string(page)})
+	}
+	return b, nil
+}
+
+func readWrittenBook(data []byte) (*Book, error) {
+	buf := ns.NewReader(data)
+	b := &Book{Signed: true}
+	title, err := buf.ReadString(0)
+	if err != nil {
+		return nil, err
+	}
+	if err := skipOptionalString(buf); err != nil {
+		return nil, err
+	}
+	author, err := buf.ReadString(0)
+	if err != nil {
+		return nil, err
+	}
+	generation, err := buf.ReadVarInt()
+	if err != nil {
+		return nil, err
+	}
+	b.Title, b.Author, b.Generation = string(title), string(author), int32(generation)
+
+	n, err := buf.ReadVarInt()
+	if err != nil {
+		return nil, err
+	}
+	b.Pages = make([]ns.TextComponent, n)
+	for i := range b.Pages {
+		if err := b.Pages[i].Decode(buf); err != nil {
+			return nil, fmt.Errorf("page %d: %w", i, err)
+		}
+		filtered, err := buf.ReadBool()
+		if err != nil {
+			return nil, err
+		}
+		if filtered {
+			var skip ns.TextComponent
+			if err := skip.Decode(buf); err != nil {
+				return nil, fmt.Errorf("page %d: %w", i, err)
+			}
+		}
+	}
+	return b, nil
+}
+
+// WriteBook replaces the pages of the book and quill in a hotbar slot (0-8)
+// without signing it. The server sends the updated slot back.
+func (m *Module) WriteBook(hotbarSlot int, pages []string) error {
+	return m.editBook(hotbarSlot, pages, nil)
+}
+
+// SignBook writes pages to the book and quill in a hotbar slot (0-8) and signs
+// it with title, turning it into a written book.
+func (m *Module) SignBook(hotbarSlot int, pages []string, title string) error {
+	title = strings.TrimSpace(title)
+	if title == "" {
+		return errors.New("book title is empty")
+	}
+	if utf8.RuneCountInString(title) > MaxBookTitle {
+		return fmt.Errorf("book title longer than %d characters", MaxBookTitle)
+	}
+	return m.editBook(hotbarSlot, pages, &title)
+}
+
+func (m *Module) editBook(hotbarSlot int, pages []string, title *string) error {
+	if hotbarSlot < 0 || hotbarSlot > 8 {
+		return fmt.Errorf("invalid hotbar slot %d", hotbarSlot)
+	}
+	if len(pages) > MaxBookPages {
+		return fmt.Errorf("%d pages, books hold at most %d", len(pages), MaxBookPages)
+	}
+	entries := make([]ns.String, len(pages))
+	for i, p := range pages {
+		if utf8.RuneCountInString(p) > MaxBookPageLength {
+			return fmt.Errorf("page %d longer than %d characters", i, MaxBookPageLength)
+		}
+		entries[i] = ns.String(p)
+	}
+	if item := m.GetSlot(SlotHotbarStart + hotbarSlot); item.IsEmpty() || item.ID != items.WritableBook {
+		return fmt.Errorf("no book and quill in hotbar slot %d", hotbarSlot)
+	}
+
+	pkt := &packets.C2SEditBook{
+		Slot:    ns.VarInt(hotbarSlot), // Inventory index, the hotbar is 0-8
+		Entries: entries,
+		Title:   ns.None[ns.String](),
+	}
+	if title != nil {
+		pkt.Title = ns.Some(ns.String(*title))
+	}
+	return m.client.WritePacket(pkt)
+}
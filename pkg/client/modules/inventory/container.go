@@ -16,7 +16,12 @@ const (
 	MenuBeacon     MenuType = 9
 	MenuFurnace    MenuType = 14
 	MenuHopper     MenuType = 16
+	MenuLectern    MenuType = 17
 	MenuShulkerBox MenuType = 20
+
+	// MenuMount is a horse, donkey, mule, llama or camel inventory. It's
+	// opened by S2CMountScreenOpen and isn't in the menu registry (see mount.go).
+	MenuMount MenuType = -2
 )
 
 type containerState struct {
@@ -24,7 +29,25 @@ type containerState struct {
 	menuType MenuType
 	title    string
 	stateID  int32
-	slots    []slotEntry // container-only slots (excludes the 36 player inv slots)
+	slots    []slotEntry   // container-only slots (excludes the 36 player inv slots)
+	data     map[int]int16 // data slots from S2CContainerSetData (lectern page, furnace progress, ...)
+
+	// set for MenuMount
+	mountEntity  int32
+	mountColumns int
+
+	// set when a MenuHandler claimed the menu (see menus.go)
+	handler *MenuHandler
+	view    *MenuView
+}
+
+// claimed returns the handler that claimed the open menu and its view, or
+// nil. Must be called with mu held.
+func (m *Module) claimed() (*MenuHandler, *MenuView) {
+	if m.container == nil {
+		return nil, nil
+	}
+	return m.container.handler, m.container.view
 }
 
 // containerViewSlot returns the slotEntry at the given absolute container view index.
@@ -51,6 +74,7 @@ func (m *Module) setContainerViewSlot(idx int, entry slotEntry) {
 	}
 	playerIdx := SlotMainStart + (idx - containerSlotCount)
 	if playerIdx >= SlotMainStart && playerIdx < TotalSlots {
+		m.recordChange(playerIdx, m.slots[playerIdx].item, entry.item)
 		m.slots[playerIdx] = entry
 	}
 }
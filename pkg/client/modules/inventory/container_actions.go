@@ -2,6 +2,7 @@ package inventory
 
 import (
 	"fmt"
+	"slices"
 
 	"github.com/go-mclib/data/pkg/data/items"
 	"github.com/go-mclib/data/pkg/packets"
@@ -60,6 +61,36 @@ func (m *Module) ContainerSlots() []*items.ItemStack {
 	return result
 }
 
+// ContainerProperty returns a data slot of the open container (e.g. the
+// lectern page or furnace progress), or false if the server hasn't sent it.
+func (m *Module) ContainerProperty(property int) (int16, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.container == nil {
+		return 0, false
+	}
+	v, ok := m.container.data[property]
+	return v, ok
+}
+
+// ContainerButton clicks a menu button of the open container (lectern page
+// turns, enchantment options, stonecutter recipes, ...). The server answers
+// with data slot and slot updates.
+func (m *Module) ContainerButton(buttonID int) error {
+	m.mu.RLock()
+	if m.container == nil {
+		m.mu.RUnlock()
+		return fmt.Errorf("no container open")
+	}
+	windowID := m.container.windowID
+	m.mu.RUnlock()
+
+	return m.client.WritePacket(&packets.C2SContainerButtonClick{
+		WindowId: ns.VarInt(windowID),
+		ButtonId: ns.VarInt(buttonID),
+	})
+}
+
 // ContainerClick performs a left-click (Mode 0, Button 0) on a slot in the open container view.
 // viewIndex is the absolute index in the container view (0..totalSlots-1).
 func (m *Module) ContainerClick(viewIndex int) error {
@@ -89,8 +120,13 @@ func (m *Module) ContainerClick(viewIndex int) error {
 		// place stack
 		newClicked = cursorEntry
 		newCursor = slotEntry{}
+	} else if Stackable(cursorEntry.item, clickedEntry.item) {
+		// merge as much as fits; only stacks with identical components merge
+		n := min(cursorEntry.item.Count, RoomFor(clickedEntry.item, cursorEntry.item))
+		newClicked = withCount(clickedEntry, clickedEntry.item.Count+n)
+		newCursor = withCount(cursorEntry, cursorEntry.item.Count-n)
 	} else {
-		// swap (simplified — accurate merge for same-item stacks is complex)
+		// swap
 		newClicked = cursorEntry
 		newCursor = clickedEntry
 	}
@@ -166,21 +202,32 @@ func (m *Module) ContainerShiftClick(viewIndex int) error {
 		return nil
 	}
 
-	// shift-click prediction is complex (depends on destination space);
-	// send minimal prediction and rely on server re-sync
+	// predict the move for menus we mirror; otherwise send a minimal
+	// prediction and rely on server re-sync
+	changedSlots := []packets.ChangedSlot{
+		{SlotNum: ns.Int16(viewIndex), Item: ns.EmptyHashedSlot()},
+	}
+	if changed, ok := m.predictQuickMove(viewIndex); ok {
+		slices.Sort(changed)
+		changedSlots = nil
+		for _, i := range slices.Compact(changed) {
+			changedSlots = append(changedSlots, packets.ChangedSlot{
+				SlotNum: ns.Int16(i),
+				Item:    slotToHashed(m.containerViewSlot(i).raw),
+			})
+		}
+	}
 	cursorHashed := slotToHashed(m.cursor.raw)
 	m.mu.Unlock()
 
 	return m.client.WritePacket(&packets.C2SContainerClick{
-		WindowId: ns.VarInt(c.windowID),
-		StateId:  ns.VarInt(stateID),
-		Slot:     ns.Int16(viewIndex),
-		Button:   0,
-		Mode:     1, // QUICK_MOVE
-		ChangedSlots: []packets.ChangedSlot{
-			{SlotNum: ns.Int16(viewIndex), Item: ns.EmptyHashedSlot()},
-		},
-		CarriedItem: cursorHashed,
+		WindowId:     ns.VarInt(c.windowID),
+		StateId:      ns.VarInt(stateID),
+		Slot:         ns.Int16(viewIndex),
+		Button:       0,
+		Mode:         1, // QUICK_MOVE
+		ChangedSlots: changedSlots,
+		CarriedItem:  cursorHashed,
 	})
 }
 
@@ -192,11 +239,18 @@ func (m *Module) CloseContainer() error {
 		return fmt.Errorf("no container open")
 	}
 	windowID := m.container.windowID
+	handler, view := m.claimed()
 	m.container = nil
 	m.mu.Unlock()
 
-	for _, cb := range m.onContainerClose {
-		cb()
+	if handler != nil {
+		if handler.OnClose != nil {
+			handler.OnClose(view)
+		}
+	} else {
+		for _, cb := range m.onContainerClose {
+			cb()
+		}
 	}
 
 	return m.client.WritePacket(&packets.C2SContainerClose{
@@ -0,0 +1,75 @@
+package inventory
+
+import (
+	"encoding/json"
+
+	"github.com/go-mclib/data/pkg/data/items"
+)
+
+// StateDump is the inventory's section of a client state dump (see
+// client.DumpState), encoded as JSON. Only non-empty slots are listed.
+type StateDump struct {
+	HeldSlot  int
+	StateID   int32
+	Slots     []DumpedSlot
+	Cursor    *DumpedSlot      `json:",omitempty"`
+	Container *DumpedContainer `json:",omitempty"`
+}
+
+// DumpedContainer is the open container of a StateDump.
+type DumpedContainer struct {
+	WindowID int32
+	MenuType MenuType
+	Title    string
+	StateID  int32
+	Slots    []DumpedSlot // container slots, not including the player's
+}
+
+// DumpedSlot is a non-empty slot of a StateDump. Components are left out.
+type DumpedSlot struct {
+	Index int
+	ID    int32
+	Name  string
+	Count int32
+}
+
+func dumpSlots(entries []slotEntry) []DumpedSlot {
+	var result []DumpedSlot
+	for i, e := range entries {
+		if d, ok := dumpSlot(i, e); ok {
+			result = append(result, d)
+		}
+	}
+	return result
+}
+
+func dumpSlot(index int, e slotEntry) (DumpedSlot, bool) {
+	if e.item == nil || e.item.Count <= 0 {
+		return DumpedSlot{}, false
+	}
+	return DumpedSlot{Index: index, ID: e.item.ID, Name: items.ItemName(e.item.ID), Count: e.item.Count}, true
+}
+
+// DumpState implements client.StateDumper.
+func (m *Module) DumpState() ([]byte, error) {
+	m.mu.RLock()
+	d := StateDump{
+		HeldSlot: m.heldSlot,
+		StateID:  m.stateID,
+		Slots:    dumpSlots(m.slots[:]),
+	}
+	if s, ok := dumpSlot(-1, m.cursor); ok {
+		d.Cursor = &s
+	}
+	if c := m.container; c != nil {
+		d.Container = &DumpedContainer{
+			WindowID: c.windowID,
+			MenuType: c.menuType,
+			Title:    c.title,
+			StateID:  c.stateID,
+			Slots:    dumpSlots(c.slots),
+		}
+	}
+	m.mu.RUnlock()
+	return json.Marshal(d)
+}
@@ -0,0 +1,69 @@
+package inventory
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/go-mclib/data/pkg/data/items"
+)
+
+// enchantmentRegistry is the synced registry enchantment components index.
+const enchantmentRegistry = "minecraft:enchantment"
+
+// registryNamer is implemented by the protocol module, looked up by interface
+// so inventory doesn't depend on it.
+type registryNamer interface {
+	RegistryEntryName(registry string, id int32) string
+}
+
+// Durability returns how much damage an item has taken and can take; max is
+// 0 for items without durability.
+func Durability(s *items.ItemStack) (damage, max int32) {
+	if s.IsEmpty() || s.Components == nil || s.Components.Unbreakable {
+		return 0, 0
+	}
+	return s.Components.Damage, s.Components.MaxDamage
+}
+
+// DurabilityLeft returns the fraction of an item's durability left, 1 for
+// items without durability.
+func DurabilityLeft(s *items.ItemStack) float64 {
+	damage, max := Durability(s)
+	if max <= 0 {
+		return 1
+	}
+	return float64(max-damage) / float64(max)
+}
+
+// Enchantments returns an item's enchantments by name, e.g.
+// "minecraft:mending", resolved against the enchantment registry synced in
+// configuration ("enchantment#<id>" if it wasn't received).
+func (m *Module) Enchantments(s *items.ItemStack) map[string]int32 {
+	if s.IsEmpty() || s.Components == nil || len(s.Components.Enchantments) == 0 {
+		return nil
+	}
+	r, _ := m.client.Module("protocol").(registryNamer)
+	result := make(map[string]int32, len(s.Components.Enchantments))
+	for key, level := range s.Components.Enchantments {
+		// components keep the registry ID as "id:<n>"
+		name := key
+		if n, ok := strings.CutPrefix(key, "id:"); ok {
+			if id, err := strconv.Atoi(n); err == nil {
+				name = "enchantment#" + n
+				if r != nil {
+					if entry := r.RegistryEntryName(enchantmentRegistry, int32(id)); entry != "" {
+						name = entry
+					}
+				}
+			}
+		}
+		result[name] = level
+	}
+	return result
+}
+
+// EnchantmentLevel returns the level of the named enchantment on an item,
+// 0 if it doesn't have it.
+func (m *Module) EnchantmentLevel(s *items.ItemStack, name string) int32 {
+	return m.Enchantments(s)[name]
+}
@@ -2,6 +2,7 @@ package inventory
 
 import (
 	"sync"
+	"time"
 
 	"github.com/go-mclib/client/pkg/client"
 	"github.com/go-mclib/data/pkg/data/items"
@@ -20,6 +21,9 @@ type Module struct {
 	client *client.Client
 	mu     sync.RWMutex
 
+	// JournalSize caps the number of slot changes kept in the journal (default: 256).
+	JournalSize int
+
 	slots    [TotalSlots]slotEntry
 	heldSlot int
 	stateID  int32
@@ -27,13 +31,25 @@ type Module struct {
 
 	container *containerState // nil when no container is open
 
+	menuMu       sync.Mutex
+	menuHandlers []*MenuHandler
+
+	journal      []SlotChange
+	pendingCause ChangeCause
+	pendingUntil time.Time
+
 	onSlotUpdate     []func(index int, item *items.ItemStack)
 	onHeldSlotChange []func(slot int)
 	onContainerOpen  []func(windowID int32, menuType MenuType, title string)
 	onContainerClose []func()
+	onContainerData  []func(property int, value int16)
 }
 
-func New() *Module { return &Module{} }
+func New() *Module {
+	return &Module{
+		JournalSize: DefaultJournalSize,
+	}
+}
 
 func (m *Module) Name() string { return ModuleName }
 
@@ -49,6 +65,8 @@ func (m *Module) Reset() {
 	m.stateID = 0
 	m.cursor = slotEntry{}
 	m.container = nil
+	m.journal = nil
+	m.pendingCause = CauseUnknown
 	m.mu.Unlock()
 }
 
@@ -62,22 +80,34 @@ func From(c *client.Client) *Module {
 
 // events
 
+// OnSlotUpdate runs under the client watchdog, if one is set.
 func (m *Module) OnSlotUpdate(cb func(index int, item *items.ItemStack)) {
-	m.onSlotUpdate = append(m.onSlotUpdate, cb)
+	run := m.client.Watched("inventory.OnSlotUpdate")
+	m.onSlotUpdate = append(m.onSlotUpdate, func(index int, item *items.ItemStack) {
+		run(func() { cb(index, item) })
+	})
 }
 
 func (m *Module) OnHeldSlotChange(cb func(slot int)) {
 	m.onHeldSlotChange = append(m.onHeldSlotChange, cb)
 }
 
+// OnContainerOpen is not called for menus claimed by a MenuHandler.
 func (m *Module) OnContainerOpen(cb func(windowID int32, menuType MenuType, title string)) {
 	m.onContainerOpen = append(m.onContainerOpen, cb)
 }
 
+// OnContainerClose is not called for menus claimed by a MenuHandler.
 func (m *Module) OnContainerClose(cb func()) {
 	m.onContainerClose = append(m.onContainerClose, cb)
 }
 
+// OnContainerData is called when the server updates a data slot of the open
+// container (see ContainerProperty).
+func (m *Module) OnContainerData(cb func(property int, value int16)) {
+	m.onContainerData = append(m.onContainerData, cb)
+}
+
 func (m *Module) HandlePacket(pkt *jp.WirePacket) {
 	if m.client.State() != jp.StatePlay {
 		return
@@ -85,16 +115,22 @@ func (m *Module) HandlePacket(pkt *jp.WirePacket) {
 	switch pkt.PacketID {
 	case packet_ids.S2COpenScreenID:
 		m.handleOpenScreen(pkt)
+	case packet_ids.S2CMountScreenOpenID:
+		m.handleMountScreenOpen(pkt)
 	case packet_ids.S2CContainerSetContentID:
 		m.handleContainerSetContent(pkt)
 	case packet_ids.S2CContainerSetSlotID:
 		m.handleContainerSetSlot(pkt)
+	case packet_ids.S2CContainerSetDataID:
+		m.handleContainerSetData(pkt)
 	case packet_ids.S2CContainerCloseID:
 		m.handleContainerClose(pkt)
 	case packet_ids.S2CSetHeldSlotID:
 		m.handleSetHeldSlot(pkt)
 	case packet_ids.S2CSetPlayerInventoryID:
 		m.handleSetPlayerInventory(pkt)
+	case packet_ids.S2CTakeItemEntityID:
+		m.handleTakeItemEntity(pkt)
 	}
 }
 
@@ -109,17 +145,42 @@ func (m *Module) handleOpenScreen(pkt *jp.WirePacket) {
 	if d.WindowTitle.Translate != "" {
 		title = d.WindowTitle.Translate
 	}
-
-	m.mu.Lock()
-	m.container = &containerState{
+	m.openContainer(&containerState{
 		windowID: int32(d.WindowId),
 		menuType: MenuType(d.WindowType),
 		title:    title,
+	}, d.WindowTitle.String())
+}
+
+// openContainer makes c the open container and tells its handler or the
+// OnContainerOpen callbacks.
+func (m *Module) openContainer(c *containerState, plainTitle string) {
+	windowID, menuType, title := c.windowID, c.menuType, c.title
+	handler := m.claimMenu(menuType, plainTitle)
+
+	m.mu.Lock()
+	// a new screen replaces a claimed menu without a close packet
+	replacedHandler, replaced := m.claimed()
+	m.container = c
+	var view *MenuView
+	if handler != nil {
+		view = &MenuView{m: m, WindowID: windowID, Type: menuType, Title: plainTitle}
+		m.container.handler, m.container.view = handler, view
 	}
 	m.mu.Unlock()
 
+	if replacedHandler != nil && replacedHandler.OnClose != nil {
+		replacedHandler.OnClose(replaced)
+	}
+	if handler != nil {
+		m.client.Debugf("inventory: menu %q (type %d) claimed by %s", plainTitle, menuType, handler.Name)
+		if handler.OnOpen != nil {
+			handler.OnOpen(view)
+		}
+		return
+	}
 	for _, cb := range m.onContainerOpen {
-		cb(int32(d.WindowId), MenuType(d.WindowType), title)
+		cb(windowID, menuType, title)
 	}
 }
 
@@ -151,11 +212,18 @@ func (m *Module) handleContainerSetContent(pkt *jp.WirePacket) {
 
 	// update player inventory from the trailing 36 slots
 	for i := range min(PlayerInvSlots, len(d.Slots)-containerSlotCount) {
-		m.slots[SlotMainStart+i] = decodeSlotEntry(d.Slots[containerSlotCount+i])
+		entry := decodeSlotEntry(d.Slots[containerSlotCount+i])
+		m.recordChange(SlotMainStart+i, m.slots[SlotMainStart+i].item, entry.item)
+		m.slots[SlotMainStart+i] = entry
 	}
 
 	m.cursor = decodeSlotEntry(d.CarriedItem)
+	handler, view := m.claimed()
 	m.mu.Unlock()
+
+	if handler != nil && handler.OnUpdate != nil {
+		handler.OnUpdate(view)
+	}
 }
 
 func (m *Module) handlePlayerInvSetContent(d packets.S2CContainerSetContent) {
@@ -163,9 +231,12 @@ func (m *Module) handlePlayerInvSetContent(d packets.S2CContainerSetContent) {
 	m.stateID = int32(d.StateId)
 	count := min(len(d.Slots), TotalSlots)
 	for i := range count {
-		m.slots[i] = decodeSlotEntry(d.Slots[i])
+		entry := decodeSlotEntry(d.Slots[i])
+		m.recordChange(i, m.slots[i].item, entry.item)
+		m.slots[i] = entry
 	}
 	for i := count; i < TotalSlots; i++ {
+		m.recordChange(i, m.slots[i].item, nil)
 		m.slots[i] = slotEntry{}
 	}
 	m.cursor = decodeSlotEntry(d.CarriedItem)
@@ -203,6 +274,7 @@ func (m *Module) handleContainerSetSlot(pkt *jp.WirePacket) {
 		entry := decodeSlotEntry(d.SlotData)
 		m.mu.Lock()
 		m.stateID = int32(d.StateId)
+		m.recordChange(idx, m.slots[idx].item, entry.item)
 		m.slots[idx] = entry
 		m.mu.Unlock()
 		for _, cb := range m.onSlotUpdate {
@@ -222,11 +294,41 @@ func (m *Module) handleContainerSetSlot(pkt *jp.WirePacket) {
 		} else if idx >= containerSlotCount {
 			playerIdx := SlotMainStart + (idx - containerSlotCount)
 			if playerIdx >= SlotMainStart && playerIdx < TotalSlots {
-				m.slots[playerIdx] = decodeSlotEntry(d.SlotData)
+				entry := decodeSlotEntry(d.SlotData)
+				m.recordChange(playerIdx, m.slots[playerIdx].item, entry.item)
+				m.slots[playerIdx] = entry
 			}
 		}
 	}
+	handler, view := m.claimed()
 	m.mu.Unlock()
+
+	if handler != nil && handler.OnUpdate != nil {
+		handler.OnUpdate(view)
+	}
+}
+
+func (m *Module) handleContainerSetData(pkt *jp.WirePacket) {
+	var d packets.S2CContainerSetData
+	if err := pkt.ReadInto(&d); err != nil {
+		m.client.Logger.Println("inventory: failed to parse container set data:", err)
+		return
+	}
+
+	m.mu.Lock()
+	if m.container == nil || m.container.windowID != int32(d.WindowId) {
+		m.mu.Unlock()
+		return
+	}
+	if m.container.data == nil {
+		m.container.data = make(map[int]int16)
+	}
+	m.container.data[int(d.Property)] = int16(d.Value)
+	m.mu.Unlock()
+
+	for _, cb := range m.onContainerData {
+		cb(int(d.Property), int16(d.Value))
+	}
 }
 
 func (m *Module) handleContainerClose(pkt *jp.WirePacket) {
@@ -236,11 +338,20 @@ func (m *Module) handleContainerClose(pkt *jp.WirePacket) {
 	}
 
 	m.mu.Lock()
+	var handler *MenuHandler
+	var view *MenuView
 	if m.container != nil && m.container.windowID == int32(d.WindowId) {
+		handler, view = m.claimed()
 		m.container = nil
 	}
 	m.mu.Unlock()
 
+	if handler != nil {
+		if handler.OnClose != nil {
+			handler.OnClose(view)
+		}
+		return
+	}
 	for _, cb := range m.onContainerClose {
 		cb()
 	}
@@ -281,6 +392,7 @@ func (m *Module) handleSetPlayerInventory(pkt *jp.WirePacket) {
 	entry := decodeSlotEntry(d.SlotData)
 
 	m.mu.Lock()
+	m.recordChange(containerIdx, m.slots[containerIdx].item, entry.item)
 	m.slots[containerIdx] = entry
 	m.mu.Unlock()
 
@@ -289,6 +401,21 @@ func (m *Module) handleSetPlayerInventory(pkt *jp.WirePacket) {
 	}
 }
 
+func (m *Module) handleTakeItemEntity(pkt *jp.WirePacket) {
+	var d packets.S2CTakeItemEntity
+	if err := pkt.ReadInto(&d); err != nil {
+		return
+	}
+
+	// the self module imports inventory, so look it up by interface
+	type entityIDer interface{ EntityID() int32 }
+	s, ok := m.client.Module("self").(entityIDer)
+	if !ok || int32(d.CollectorEntityId) != s.EntityID() {
+		return
+	}
+	m.ExpectCause(CausePickup)
+}
+
 func decodeSlotEntry(raw ns.Slot) slotEntry {
 	stack, err := items.FromSlot(raw)
 	if err != nil {
@@ -0,0 +1,176 @@
+package inventory
+
+import (
+	"slices"
+	"time"
+
+	"github.com/go-mclib/data/pkg/data/items"
+)
+
+// DefaultJournalSize is the default number of slot changes kept in the journal.
+const DefaultJournalSize = 256
+
+// causeWindow is how long an expected cause applies to incoming slot changes.
+const causeWindow = time.Second
+
+// ChangeCause describes why a slot changed, when known.
+type ChangeCause uint8
+
+const (
+	CauseUnknown       ChangeCause = iota
+	CausePickup                    // an item entity was collected (S2CTakeItemEntity)
+	CauseContainerMove             // moved to/from an open container
+	CauseInventoryMove             // rearranged within the player inventory
+	CauseConsumed                  // eaten or otherwise used up
+)
+
+func (c ChangeCause) String() string {
+	switch c {
+	case CausePickup:
+		return "pickup"
+	case CauseContainerMove:
+		return "container_move"
+	case CauseInventoryMove:
+		return "inventory_move"
+	case CauseConsumed:
+		return "consumed"
+	default:
+		return "unknown"
+	}
+}
+
+// SlotChange is a single journal entry: the contents of a player inventory
+// slot (0-45) before and after a change.
+type SlotChange struct {
+	Time   time.Time
+	Slot   int
+	Before items.ItemStack // zero value = empty
+	After  items.ItemStack
+	Cause  ChangeCause
+}
+
+// Snapshot is a point-in-time copy of the player inventory, including the cursor.
+type Snapshot struct {
+	Time   time.Time
+	Slots  [TotalSlots]items.ItemStack // zero value = empty
+	Cursor items.ItemStack
+}
+
+// ItemDelta is the change in total count of one item between two snapshots.
+// Count is positive for items gained and negative for items lost.
+type ItemDelta struct {
+	ItemID int32
+	Count  int32
+}
+
+// Counts returns the total count per item ID across all slots and the cursor.
+func (s Snapshot) Counts() map[int32]int32 {
+	counts := make(map[int32]int32)
+	for _, it := range s.Slots {
+		if !it.IsEmpty() {
+			counts[it.ID] += it.Count
+		}
+	}
+	if !s.Cursor.IsEmpty() {
+		counts[s.Cursor.ID] += s.Cursor.Count
+	}
+	return counts
+}
+
+// Diff returns the per-item count changes from s to after, sorted by item ID.
+// Items whose total count is unchanged are omitted.
+func (s Snapshot) Diff(after Snapshot) []ItemDelta {
+	before, now := s.Counts(), after.Counts()
+	var deltas []ItemDelta
+	for id, n := range now {
+		if d := n - before[id]; d != 0 {
+			deltas = append(deltas, ItemDelta{ItemID: id, Count: d})
+		}
+	}
+	for id, n := range before {
+		if _, ok := now[id]; !ok {
+			deltas = append(deltas, ItemDelta{ItemID: id, Count: -n})
+		}
+	}
+	slices.SortFunc(deltas, func(a, b ItemDelta) int { return int(a.ItemID - b.ItemID) })
+	return deltas
+}
+
+// Snapshot copies the current player inventory and cursor.
+func (m *Module) Snapshot() Snapshot {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	snap := Snapshot{Time: m.client.Now(), Cursor: stackValue(m.cursor.item)}
+	for i := range TotalSlots {
+		snap.Slots[i] = stackValue(m.slots[i].item)
+	}
+	return snap
+}
+
+// Journal returns a copy of the recorded slot changes, oldest first.
+func (m *Module) Journal() []SlotChange {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return slices.Clone(m.journal)
+}
+
+// JournalSince returns the recorded slot changes at or after t, oldest first.
+func (m *Module) JournalSince(t time.Time) []SlotChange {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	i, _ := slices.BinarySearchFunc(m.journal, t, func(c SlotChange, t time.Time) int {
+		return c.Time.Compare(t)
+	})
+	return slices.Clone(m.journal[i:])
+}
+
+// ClearJournal removes all recorded slot changes.
+func (m *Module) ClearJournal() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.journal = nil
+}
+
+// ExpectCause attributes slot changes arriving within the next second to cause.
+// Use it before an action whose effect the server confirms later (e.g. eating).
+func (m *Module) ExpectCause(cause ChangeCause) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.pendingCause = cause
+	m.pendingUntil = m.client.Now().Add(causeWindow)
+}
+
+// recordChange appends a journal entry if the slot contents actually changed.
+// Must be called under m.mu lock.
+func (m *Module) recordChange(slot int, before, after *items.ItemStack) {
+	b, a := stackValue(before), stackValue(after)
+	if b.ID == a.ID && b.Count == a.Count {
+		return
+	}
+
+	now := m.client.Now()
+	cause := CauseUnknown
+	switch {
+	case m.pendingCause != CauseUnknown && now.Before(m.pendingUntil):
+		cause = m.pendingCause
+	case m.container != nil:
+		cause = CauseContainerMove
+	}
+
+	size := m.JournalSize
+	if size <= 0 {
+		size = DefaultJournalSize
+	}
+	if len(m.journal) >= size {
+		m.journal = slices.Delete(m.journal, 0, len(m.journal)-size+1)
+	}
+	m.journal = append(m.journal, SlotChange{Time: now, Slot: slot, Before: b, After: a, Cause: cause})
+}
+
+// stackValue copies an item stack, normalizing empty stacks to the zero value.
+func stackValue(s *items.ItemStack) items.ItemStack {
+	if s.IsEmpty() {
+		return items.ItemStack{}
+	}
+	return *s
+}
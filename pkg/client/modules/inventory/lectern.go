@@ -0,0 +1,78 @@
+package inventory
+
+import "fmt"
+
+// lectern menu buttons (LecternMenu.clickMenuButton)
+const (
+	lecternButtonPrevious = 1
+	lecternButtonNext     = 2
+	lecternButtonTake     = 3
+	lecternButtonPageBase = 100 // 100+n jumps to page n
+)
+
+// lecternDataPage is the data slot holding the current page.
+const lecternDataPage = 0
+
+// lecternOpen returns an error unless a lectern menu is open.
+func (m *Module) lecternOpen() error {
+	if t := m.ContainerMenuType(); t != MenuLectern {
+		return fmt.Errorf("no lectern open")
+	}
+	return nil
+}
+
+// LecternBook returns the book on the open lectern.
+func (m *Module) LecternBook() (*Book, error) {
+	if err := m.lecternOpen(); err != nil {
+		return nil, err
+	}
+	return m.ContainerBook(0)
+}
+
+// LecternPage returns the page the open lectern shows (0-based), or false if
+// no lectern is open or the server hasn't sent it yet.
+func (m *Module) LecternPage() (int, bool) {
+	if m.lecternOpen() != nil {
+		return 0, false
+	}
+	page, ok := m.ContainerProperty(lecternDataPage)
+	return int(page), ok
+}
+
+// LecternNextPage turns the open lectern to the next page. The page is shared
+// by everyone reading the lectern and powers comparators.
+func (m *Module) LecternNextPage() error {
+	if err := m.lecternOpen(); err != nil {
+		return err
+	}
+	return m.ContainerButton(lecternButtonNext)
+}
+
+// LecternPreviousPage turns the open lectern to the previous page.
+func (m *Module) LecternPreviousPage() error {
+	if err := m.lecternOpen(); err != nil {
+		return err
+	}
+	return m.ContainerButton(lecternButtonPrevious)
+}
+
+// LecternSetPage turns the open lectern to page (0-based). The server clamps
+// it to the book's page count.
+func (m *Module) LecternSetPage(page int) error {
+	if page < 0 {
+		return fmt.Errorf("invalid page %d", page)
+	}
+	if err := m.lecternOpen(); err != nil {
+		return err
+	}
+	return m.ContainerButton(lecternButtonPageBase + page)
+}
+
+// LecternTakeBook takes the book off the open lectern into the player
+// inventory. Requires build permission at the lectern.
+func (m *Module) LecternTakeBook() error {
+	if err := m.lecternOpen(); err != nil {
+		return err
+	}
+	return m.ContainerButton(lecternButtonTake)
+}
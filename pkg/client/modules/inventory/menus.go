@@ -0,0 +1,186 @@
+package inventory
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/go-mclib/data/pkg/data/items"
+)
+
+// MenuHandler claims opened menus for server-GUI automation (shop menus,
+// voting menus, kit selectors, ...). A claimed menu is delivered only to its
+// handler: OnContainerOpen and OnContainerClose don't fire for it, so chest
+// logic waiting for a container doesn't mistake a GUI for one.
+type MenuHandler struct {
+	Name string // for logging and ClaimedMenu
+
+	// Types are the menu types to claim; empty claims any type.
+	Types []MenuType
+	// Title is matched against the plain-text title; nil matches any title.
+	Title *regexp.Regexp
+
+	OnOpen func(v *MenuView)
+	// OnUpdate is called when the menu's slots change, including the
+	// initial contents that follow OnOpen.
+	OnUpdate func(v *MenuView)
+	// OnClose is called when the server or CloseContainer closes the menu.
+	OnClose func(v *MenuView)
+}
+
+func (h *MenuHandler) matches(menuType MenuType, title string) bool {
+	if len(h.Types) > 0 && !slices.Contains(h.Types, menuType) {
+		return false
+	}
+	return h.Title == nil || h.Title.MatchString(title)
+}
+
+// HandleMenu registers h. When a menu opens, the first registered handler
+// that matches it claims it. Returns a function that unregisters h; a menu it
+// already claimed stays claimed until closed.
+func (m *Module) HandleMenu(h *MenuHandler) (remove func()) {
+	m.menuMu.Lock()
+	m.menuHandlers = append(m.menuHandlers, h)
+	m.menuMu.Unlock()
+	return func() {
+		m.menuMu.Lock()
+		defer m.menuMu.Unlock()
+		m.menuHandlers = slices.DeleteFunc(m.menuHandlers, func(o *MenuHandler) bool { return o == h })
+	}
+}
+
+// ClaimedMenu returns the name of the handler that claimed the open menu, or
+// "" if none did (or no menu is open).
+func (m *Module) ClaimedMenu() string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.container == nil || m.container.handler == nil {
+		return ""
+	}
+	return m.container.handler.Name
+}
+
+// claimMenu finds the handler for a newly opened menu.
+func (m *Module) claimMenu(menuType MenuType, title string) *MenuHandler {
+	m.menuMu.Lock()
+	defer m.menuMu.Unlock()
+	for _, h := range m.menuHandlers {
+		if h.matches(menuType, title) {
+			return h
+		}
+	}
+	return nil
+}
+
+// MenuView is a claimed menu as seen by its handler. Its methods fail once
+// the menu is no longer the open one.
+type MenuView struct {
+	m        *Module
+	WindowID int32
+	Type     MenuType
+	Title    string // plain text, formatting stripped
+}
+
+// Open reports whether this menu is still the open one.
+func (v *MenuView) Open() bool {
+	v.m.mu.RLock()
+	defer v.m.mu.RUnlock()
+	return v.m.container != nil && v.m.container.windowID == v.WindowID
+}
+
+func (v *MenuView) check() error {
+	if !v.Open() {
+		return fmt.Errorf("menu %q (window %d) is closed", v.Title, v.WindowID)
+	}
+	return nil
+}
+
+// Slots returns the menu's own slots (without the player inventory).
+func (v *MenuView) Slots() []*items.ItemStack {
+	if !v.Open() {
+		return nil
+	}
+	return v.m.ContainerSlots()
+}
+
+// Slot returns the item at a menu slot, or nil.
+func (v *MenuView) Slot(i int) *items.ItemStack {
+	if !v.Open() {
+		return nil
+	}
+	return v.m.ContainerSlot(i)
+}
+
+// Find returns the first menu slot whose item satisfies pred, or -1.
+func (v *MenuView) Find(pred func(s *items.ItemStack) bool) int {
+	for i, s := range v.Slots() {
+		if !s.IsEmpty() && pred(s) {
+			return i
+		}
+	}
+	return -1
+}
+
+// FindByName returns the first menu slot whose display name contains name
+// (case-insensitive), or -1. GUI plugins label their buttons with custom names.
+func (v *MenuView) FindByName(name string) int {
+	name = strings.ToLower(name)
+	return v.Find(func(s *items.ItemStack) bool {
+		return strings.Contains(strings.ToLower(DisplayName(s)), name)
+	})
+}
+
+// Click left-clicks a menu slot.
+func (v *MenuView) Click(i int) error {
+	if err := v.check(); err != nil {
+		return err
+	}
+	return v.m.ContainerClick(i)
+}
+
+// RightClick right-clicks a menu slot.
+func (v *MenuView) RightClick(i int) error {
+	if err := v.check(); err != nil {
+		return err
+	}
+	return v.m.ContainerRightClick(i)
+}
+
+// ShiftClick shift-clicks a menu slot.
+func (v *MenuView) ShiftClick(i int) error {
+	if err := v.check(); err != nil {
+		return err
+	}
+	return v.m.ContainerShiftClick(i)
+}
+
+// Close closes the menu.
+func (v *MenuView) Close() error {
+	if err := v.check(); err != nil {
+		return err
+	}
+	return v.m.CloseContainer()
+}
+
+// DisplayName returns the name an item shows in its tooltip: the custom name,
+// else the item name component, else the item id.
+func DisplayName(s *items.ItemStack) string {
+	if s.IsEmpty() {
+		return ""
+	}
+	if c := s.Components; c != nil {
+		for _, n := range []*items.ItemNameComponent{c.CustomName, c.ItemName} {
+			if n == nil {
+				continue
+			}
+			if n.Text != "" {
+				return n.Text
+			}
+			if n.Translate != "" {
+				return n.Translate
+			}
+		}
+	}
+	return items.ItemName(s.ID)
+}
@@ -0,0 +1,84 @@
+package inventory
+
+import (
+	"fmt"
+
+	"github.com/go-mclib/data/pkg/data/items"
+	"github.com/go-mclib/data/pkg/packets"
+	jp "github.com/go-mclib/protocol/java_protocol"
+	ns "github.com/go-mclib/protocol/java_protocol/net_structures"
+)
+
+// mount menu slots (AbstractMountInventoryMenu): the equipment slots come
+// first, then the chest of a donkey, mule or llama, 3 rows of columns each
+const (
+	MountSlotSaddle     = 0
+	MountSlotBodyArmor  = 1 // horse armor, llama carpet
+	MountSlotChestStart = 2
+)
+
+// playerCommandOpenInventory is ServerboundPlayerCommandPacket.Action
+// OPEN_INVENTORY, which opens the inventory of the ridden animal.
+const playerCommandOpenInventory = 5
+
+// MountInventory returns the animal whose inventory is open and the number of
+// its chest slots (0 without a chest), or false if no mount menu is open.
+func (m *Module) MountInventory() (entityID int32, chestSlots int, ok bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.container == nil || m.container.menuType != MenuMount {
+		return 0, 0, false
+	}
+	return m.container.mountEntity, m.container.mountColumns * 3, true
+}
+
+// MountChest returns the chest slots of the open mount menu. Index i is view
+// index MountSlotChestStart+i for ContainerClick and friends.
+func (m *Module) MountChest() []*items.ItemStack {
+	_, n, ok := m.MountInventory()
+	if !ok {
+		return nil
+	}
+	slots := m.ContainerSlots()
+	if len(slots) < MountSlotChestStart+n {
+		return nil // contents not received yet
+	}
+	return slots[MountSlotChestStart : MountSlotChestStart+n]
+}
+
+// OpenVehicleInventory asks to open the inventory of the animal the player
+// rides, as vanilla does when the inventory key is pressed while mounted. The
+// server answers with a mount menu (see OnContainerOpen, MenuMount). Pack
+// animals that aren't ridden open theirs when used while sneaking.
+func (m *Module) OpenVehicleInventory() error {
+	// the self module imports inventory, so look it up by interface
+	type rider interface {
+		EntityID() int32
+		Vehicle() int32
+	}
+	s, ok := m.client.Module("self").(rider)
+	if !ok {
+		return fmt.Errorf("opening a vehicle inventory needs the self module")
+	}
+	if s.Vehicle() < 0 {
+		return fmt.Errorf("not riding")
+	}
+	return m.client.WritePacket(&packets.C2SPlayerCommand{
+		EntityId: ns.VarInt(s.EntityID()),
+		ActionId: playerCommandOpenInventory,
+	})
+}
+
+func (m *Module) handleMountScreenOpen(pkt *jp.WirePacket) {
+	var d packets.S2CMountScreenOpen
+	if err := pkt.ReadInto(&d); err != nil {
+		m.client.Logger.Println("inventory: failed to parse mount screen:", err)
+		return
+	}
+	m.openContainer(&containerState{
+		windowID:     int32(d.WindowId),
+		menuType:     MenuMount,
+		mountEntity:  int32(d.EntityId),
+		mountColumns: int(d.InventoryColumnsCount),
+	}, "")
+}
@@ -0,0 +1,172 @@
+package inventory
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+
+	"github.com/go-mclib/data/pkg/data/items"
+	ns "github.com/go-mclib/protocol/java_protocol/net_structures"
+)
+
+// defaultMaxStackSize is used when an item's components don't say otherwise.
+const defaultMaxStackSize = 64
+
+// Stackable reports whether a and b can merge into one stack: same item and
+// identical components (enchantments, custom name, damage, ...), like vanilla
+// ItemStack.isSameItemSameComponents. Counts are ignored; empty stacks never
+// stack.
+func Stackable(a, b *items.ItemStack) bool {
+	if a.IsEmpty() || b.IsEmpty() || a.ID != b.ID {
+		return false
+	}
+	if a.Components == b.Components {
+		return true
+	}
+	// compare the patches against the item defaults, which only list what differs
+	pa, errA := a.ToSlot()
+	pb, errB := b.ToSlot()
+	if errA != nil || errB != nil {
+		return false
+	}
+	return samePatch(pa, pb)
+}
+
+func samePatch(a, b ns.Slot) bool {
+	if !slices.Equal(a.Components.Remove, b.Components.Remove) ||
+		len(a.Components.Add) != len(b.Components.Add) {
+		return false
+	}
+	for i, ca := range a.Components.Add {
+		cb := b.Components.Add[i]
+		if ca.ID != cb.ID || !bytes.Equal(ca.Data, cb.Data) {
+			return false
+		}
+	}
+	return true
+}
+
+// MaxStackSize returns how many of s fit in one slot (the max_stack_size
+// component: 1 for tools and enchanted gear, 16 for ender pearls, ...).
+func MaxStackSize(s *items.ItemStack) int32 {
+	if s.IsEmpty() {
+		return defaultMaxStackSize
+	}
+	if s.Components != nil && s.Components.MaxStackSize > 0 {
+		return s.Components.MaxStackSize
+	}
+	if d := items.DefaultComponents(s.ID); d != nil && d.MaxStackSize > 0 {
+		return d.MaxStackSize
+	}
+	return defaultMaxStackSize
+}
+
+// RoomFor returns how many items of s can be added to dst: a full stack if
+// dst is empty, the remaining space if they stack, otherwise 0.
+func RoomFor(dst, s *items.ItemStack) int32 {
+	if s.IsEmpty() {
+		return 0
+	}
+	if dst.IsEmpty() {
+		return MaxStackSize(s)
+	}
+	if !Stackable(dst, s) {
+		return 0
+	}
+	return max(0, MaxStackSize(dst)-dst.Count)
+}
+
+// withCount returns a copy of e holding n items (empty if n <= 0).
+func withCount(e slotEntry, n int32) slotEntry {
+	if n <= 0 || e.item.IsEmpty() {
+		return slotEntry{}
+	}
+	item := *e.item
+	item.Count = n
+	raw := e.raw
+	raw.Count = ns.VarInt(n)
+	return slotEntry{raw: raw, item: &item}
+}
+
+// quickMoveRanges returns the view ranges a shift-click on viewIndex moves
+// items into for menus whose quickMoveStack we mirror (the container and
+// player inventory sections swap contents, like ChestMenu), or ok=false.
+func quickMoveRanges(menuType MenuType, containerSlots, viewIndex int) (start, end int, reverse, ok bool) {
+	switch menuType {
+	case MenuGeneric9x1, MenuGeneric9x2, MenuGeneric9x3, MenuGeneric9x4, MenuGeneric9x5, MenuGeneric9x6,
+		MenuGeneric3x3, MenuHopper, MenuShulkerBox:
+	default:
+		return 0, 0, false, false
+	}
+	if viewIndex < containerSlots {
+		return containerSlots, containerSlots + PlayerInvSlots, true, true
+	}
+	return 0, containerSlots, false, true
+}
+
+// predictQuickMove mirrors AbstractContainerMenu.moveItemStackTo for a
+// shift-click on viewIndex: first top up matching stacks, then fill the
+// first empty slot. Returns the changed view indices (none if nothing fits),
+// or ok=false if the move can't be predicted. Must be called with mu held.
+func (m *Module) predictQuickMove(viewIndex int) (changed []int, ok bool) {
+	c := m.container
+	start, end, reverse, ok := quickMoveRanges(c.menuType, len(c.slots), viewIndex)
+	src := m.containerViewSlot(viewIndex)
+	if !ok || src.item.IsEmpty() {
+		return nil, false
+	}
+	if c.menuType == MenuShulkerBox && viewIndex >= len(c.slots) &&
+		strings.HasSuffix(items.ItemName(src.item.ID), "shulker_box") {
+		return nil, false // shulker box slots reject shulker boxes
+	}
+
+	order := make([]int, 0, end-start)
+	for i := start; i < end; i++ {
+		order = append(order, i)
+	}
+	if reverse {
+		slices.Reverse(order)
+	}
+
+	remaining := src.item.Count
+	// doClick repeats quickMoveStack while it makes progress
+	for progress := true; progress && remaining > 0; {
+		progress = false
+		// pass 1: merge into stacks of the same item with the same components
+		if MaxStackSize(src.item) > 1 {
+			for _, i := range order {
+				if remaining == 0 {
+					break
+				}
+				dst := m.containerViewSlot(i)
+				n := min(remaining, RoomFor(dst.item, src.item))
+				if dst.item.IsEmpty() || n == 0 {
+					continue
+				}
+				m.setContainerViewSlot(i, withCount(dst, dst.item.Count+n))
+				remaining -= n
+				changed = append(changed, i)
+				progress = true
+			}
+		}
+		// pass 2: the first empty slot takes the rest (up to a full stack)
+		if remaining > 0 {
+			for _, i := range order {
+				if !m.containerViewSlot(i).item.IsEmpty() {
+					continue
+				}
+				n := min(remaining, MaxStackSize(src.item))
+				m.setContainerViewSlot(i, withCount(src, n))
+				remaining -= n
+				changed = append(changed, i)
+				progress = true
+				break
+			}
+		}
+	}
+	if len(changed) == 0 {
+		return nil, true
+	}
+	m.setContainerViewSlot(viewIndex, withCount(src, remaining))
+	return append(changed, viewIndex), true
+}
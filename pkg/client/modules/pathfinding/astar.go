@@ -8,6 +8,7 @@ import (
 	"github.com/go-mclib/client/pkg/client/modules/collisions"
 	"github.com/go-mclib/client/pkg/client/modules/entities"
 	"github.com/go-mclib/client/pkg/client/modules/world"
+	"github.com/go-mclib/client/pkg/geom"
 )
 
 const DefaultMaxNodes = 10000
@@ -24,8 +25,34 @@ var diagonalOffsets = [4][2]int{
 
 func findPath(w *world.Module, col *collisions.Module, ents *entities.Module,
 	startX, startY, startZ, goalX, goalY, goalZ, maxNodes int,
-	jumpPower, effectiveSpeed float64,
+	jumpPower, effectiveSpeed float64, trace *Search,
 ) ([]PathNode, error) {
+	a := newAStar(w, col, ents, startX, startY, startZ, goalX, goalY, goalZ, maxNodes, jumpPower, effectiveSpeed, trace)
+	_, path, err := a.step(math.MaxInt)
+	return path, err
+}
+
+// aStar is an A* search that can be run in slices (see step).
+type aStar struct {
+	w                   *world.Module
+	col                 *collisions.Module
+	ents                *entities.Module
+	goalX, goalY, goalZ int
+	maxNodes            int
+	jumpPower           float64
+	effectiveSpeed      float64
+	trace               *Search
+
+	openSet *nodeHeap
+	// best known g-cost to each position for proper A* deduplication
+	gScore   map[geom.BlockPos]float64
+	explored int
+}
+
+func newAStar(w *world.Module, col *collisions.Module, ents *entities.Module,
+	startX, startY, startZ, goalX, goalY, goalZ, maxNodes int,
+	jumpPower, effectiveSpeed float64, trace *Search,
+) *aStar {
 	start := &PathNode{X: startX, Y: startY, Z: startZ}
 	start.H = heuristic(startX, startY, startZ, goalX, goalY, goalZ)
 	start.F = start.H
@@ -33,45 +60,59 @@ func findPath(w *world.Module, col *collisions.Module, ents *entities.Module,
 	openSet := &nodeHeap{start}
 	heap.Init(openSet)
 
-	// track best known g-cost to each position for proper A* deduplication
-	gScore := map[[3]int]float64{
-		{startX, startY, startZ}: 0,
+	return &aStar{
+		w: w, col: col, ents: ents,
+		goalX: goalX, goalY: goalY, goalZ: goalZ,
+		maxNodes:  maxNodes,
+		jumpPower: jumpPower, effectiveSpeed: effectiveSpeed,
+		trace:   trace,
+		openSet: openSet,
+		gScore: map[geom.BlockPos]float64{
+			{X: startX, Y: startY, Z: startZ}: 0,
+		},
 	}
+}
 
-	explored := 0
-
-	for openSet.Len() > 0 {
-		current := heap.Pop(openSet).(*PathNode)
+// step expands up to n nodes; done reports whether the search finished,
+// with the path or why there's none.
+func (a *aStar) step(n int) (done bool, path []PathNode, err error) {
+	for ; n > 0 && a.openSet.Len() > 0; n-- {
+		current := heap.Pop(a.openSet).(*PathNode)
 		cx, cy, cz := current.X, current.Y, current.Z
 
-		if cx == goalX && cy == goalY && cz == goalZ {
-			return reconstructPath(current), nil
+		if cx == a.goalX && cy == a.goalY && cz == a.goalZ {
+			return true, reconstructPath(current), nil
 		}
 
-		explored++
-		if explored >= maxNodes {
-			return nil, fmt.Errorf("pathfinding: max nodes (%d) reached", maxNodes)
+		a.explored++
+		if a.trace != nil {
+			a.trace.Explored = append(a.trace.Explored, ExploredNode{X: cx, Y: cy, Z: cz, G: current.G, H: current.H})
+		}
+		if a.explored >= a.maxNodes {
+			return true, nil, fmt.Errorf("pathfinding: max nodes (%d) reached", a.maxNodes)
 		}
 
 		// skip if this node has been superseded by a cheaper path
-		key := [3]int{cx, cy, cz}
-		if best, ok := gScore[key]; ok && current.G > best {
+		key := geom.BlockPos{X: cx, Y: cy, Z: cz}
+		if best, ok := a.gScore[key]; ok && current.G > best {
 			continue
 		}
 
 		// generate all movement types
-		tryCardinalMoves(w, col, ents, current, goalX, goalY, goalZ, gScore, openSet)
-		tryDiagonalMoves(w, col, ents, current, goalX, goalY, goalZ, gScore, openSet)
-		tryParkourMoves(w, col, current, goalX, goalY, goalZ, gScore, openSet, jumpPower, effectiveSpeed)
+		tryCardinalMoves(a.w, a.col, a.ents, current, a.goalX, a.goalY, a.goalZ, a.gScore, a.openSet)
+		tryDiagonalMoves(a.w, a.col, a.ents, current, a.goalX, a.goalY, a.goalZ, a.gScore, a.openSet)
+		tryParkourMoves(a.w, a.col, current, a.goalX, a.goalY, a.goalZ, a.gScore, a.openSet, a.jumpPower, a.effectiveSpeed)
 	}
-
-	return nil, fmt.Errorf("pathfinding: no path found")
+	if a.openSet.Len() > 0 {
+		return false, nil, nil
+	}
+	return true, nil, fmt.Errorf("pathfinding: no path found")
 }
 
 // tryCardinalMoves generates walk, step-up, descend, fall, and door moves in 4 cardinal directions.
 func tryCardinalMoves(w *world.Module, col *collisions.Module, ents *entities.Module,
 	current *PathNode, goalX, goalY, goalZ int,
-	gScore map[[3]int]float64, openSet *nodeHeap,
+	gScore map[geom.BlockPos]float64, openSet *nodeHeap,
 ) {
 	cx, cy, cz := current.X, current.Y, current.Z
 
@@ -132,6 +173,12 @@ func tryCardinalMoves(w *world.Module, col *collisions.Module, ents *entities.Mo
 				}
 			}
 
+			fc := flowCost(w, col, cx, cy, cz, nx, ny, nz)
+			if fc < 0 && !isGoal {
+				break
+			}
+			cost += max(fc, 0)
+
 			// calculate edge cost
 			var edgeCost float64
 			switch {
@@ -142,7 +189,7 @@ func tryCardinalMoves(w *world.Module, col *collisions.Module, ents *entities.Mo
 			}
 
 			tentativeG := current.G + edgeCost
-			nKey := [3]int{nx, ny, nz}
+			nKey := geom.BlockPos{X: nx, Y: ny, Z: nz}
 			if best, ok := gScore[nKey]; ok && tentativeG >= best {
 				continue
 			}
@@ -171,7 +218,7 @@ func tryCardinalMoves(w *world.Module, col *collisions.Module, ents *entities.Mo
 func tryMove(w *world.Module, col *collisions.Module, ents *entities.Module,
 	current *PathNode, nx, ny, nz, dy int,
 	goalX, goalY, goalZ int,
-	gScore map[[3]int]float64, openSet *nodeHeap,
+	gScore map[geom.BlockPos]float64, openSet *nodeHeap,
 ) {
 	cx, cz := current.X, current.Z
 	isGoal := nx == goalX && ny == goalY && nz == goalZ
@@ -195,13 +242,19 @@ func tryMove(w *world.Module, col *collisions.Module, ents *entities.Module,
 		}
 	}
 
+	fc := flowCost(w, col, cx, current.Y, cz, nx, ny, nz)
+	if fc < 0 && !isGoal {
+		return
+	}
+	cost += max(fc, 0)
+
 	edgeCost := cost
 	if dy == 1 {
 		edgeCost += JumpOneBlockCost
 	}
 
 	tentativeG := current.G + edgeCost
-	nKey := [3]int{nx, ny, nz}
+	nKey := geom.BlockPos{X: nx, Y: ny, Z: nz}
 	if best, ok := gScore[nKey]; ok && tentativeG >= best {
 		return
 	}
@@ -221,7 +274,7 @@ func tryMove(w *world.Module, col *collisions.Module, ents *entities.Module,
 func tryDoorMove(w *world.Module, _ *collisions.Module, _ *entities.Module,
 	current *PathNode, nx, ny, nz int,
 	goalX, goalY, goalZ int,
-	gScore map[[3]int]float64, openSet *nodeHeap,
+	gScore map[geom.BlockPos]float64, openSet *nodeHeap,
 ) {
 	doorX, doorY, doorZ, found := findClosedWoodenDoor(w, nx, ny, nz)
 	if !found {
@@ -233,7 +286,7 @@ func tryDoorMove(w *world.Module, _ *collisions.Module, _ *entities.Module,
 	cost := SprintOneBlockCost + DoorInteractCost
 
 	tentativeG := current.G + cost
-	nKey := [3]int{nx, ny, nz}
+	nKey := geom.BlockPos{X: nx, Y: ny, Z: nz}
 	if best, ok := gScore[nKey]; ok && tentativeG >= best {
 		return
 	}
@@ -255,7 +308,7 @@ func tryDoorMove(w *world.Module, _ *collisions.Module, _ *entities.Module,
 // tryDiagonalMoves generates diagonal movement neighbors.
 func tryDiagonalMoves(w *world.Module, col *collisions.Module, ents *entities.Module,
 	current *PathNode, goalX, goalY, goalZ int,
-	gScore map[[3]int]float64, openSet *nodeHeap,
+	gScore map[geom.BlockPos]float64, openSet *nodeHeap,
 ) {
 	cx, cy, cz := current.X, current.Y, current.Z
 
@@ -296,13 +349,18 @@ func tryDiagonalMoves(w *world.Module, col *collisions.Module, ents *entities.Mo
 				}
 			}
 
-			edgeCost := cost * math.Sqrt2
+			fc := flowCost(w, col, cx, cy, cz, nx, ny, nz)
+			if fc < 0 && !isGoal {
+				continue
+			}
+
+			edgeCost := cost*math.Sqrt2 + max(fc, 0)
 			if dy == -1 {
 				edgeCost += descendCost()
 			}
 
 			tentativeG := current.G + edgeCost
-			nKey := [3]int{nx, ny, nz}
+			nKey := geom.BlockPos{X: nx, Y: ny, Z: nz}
 			if best, ok := gScore[nKey]; ok && tentativeG >= best {
 				continue
 			}
@@ -323,7 +381,7 @@ func tryDiagonalMoves(w *world.Module, col *collisions.Module, ents *entities.Mo
 // tryParkourMoves generates sprint-jump moves using physics simulation.
 func tryParkourMoves(w *world.Module, col *collisions.Module,
 	current *PathNode, goalX, goalY, goalZ int,
-	gScore map[[3]int]float64, openSet *nodeHeap,
+	gScore map[geom.BlockPos]float64, openSet *nodeHeap,
 	jumpPower, effectiveSpeed float64,
 ) {
 	cx, cy, cz := current.X, current.Y, current.Z
@@ -375,7 +433,7 @@ func tryParkourMoves(w *world.Module, col *collisions.Module,
 		edgeCost := float64(landing.Ticks) + 1.0 // +1 for the jump action penalty
 
 		tentativeG := current.G + edgeCost
-		nKey := [3]int{nx, ny, nz}
+		nKey := geom.BlockPos{X: nx, Y: ny, Z: nz}
 		if best, ok := gScore[nKey]; ok && tentativeG >= best {
 			continue
 		}
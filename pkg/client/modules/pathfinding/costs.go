@@ -15,6 +15,17 @@ const (
 
 	DoorInteractCost = 10.0 // ticks to stop, open/close, resume
 
+	// SuspectBlockCost is added for each suspect block (world.MarkSuspect) a
+	// move stands in or on, so paths go around blocks that may be ghosts.
+	SuspectBlockCost = 100.0
+
+	// FlowUpstreamCost is added for a block moved straight against a water
+	// or lava current, scaled by how much the move opposes it;
+	// FlowCrossCost for a block moved across one, which drifts the player
+	// off the path.
+	FlowUpstreamCost = 15.0
+	FlowCrossCost    = 3.0
+
 	CostInf = 1_000_000.0
 
 	playerWidth          = 0.6
@@ -90,6 +101,11 @@ var dangerCosts = map[string]float64{
 	"minecraft:powder_snow":      20,
 	"minecraft:soul_sand":        SoulSandWalkCost - WalkOneBlockCost,
 	"minecraft:water":            2,
+	"minecraft:seagrass":         2,
+	"minecraft:tall_seagrass":    2,
+	"minecraft:kelp":             2,
+	"minecraft:kelp_plant":       2,
+	"minecraft:bubble_column":    20,
 	"minecraft:campfire":         50,
 	"minecraft:soul_campfire":    75,
 	"minecraft:fire":             100,
@@ -0,0 +1,191 @@
+package pathfinding
+
+import (
+	"math"
+	"slices"
+
+	"github.com/go-mclib/client/pkg/client/modules/collisions"
+	"github.com/go-mclib/client/pkg/geom"
+)
+
+// CoverTarget is a target for PlanCover: a block to click, or an entity to
+// hit or use.
+type CoverTarget struct {
+	Block geom.BlockPos
+	// Entity, if set, is the entity's bounding box and the target is the
+	// entity rather than Block.
+	Entity *collisions.AABB
+	Reach  float64
+}
+
+// CoverStop is a stand position and the targets (indices into the targets
+// given to PlanCover) to handle from it.
+type CoverStop struct {
+	Stand   geom.BlockPos
+	Targets []int
+}
+
+// reachableFrom reports whether the target can be interacted with from eye.
+// Blocks need a face in reach with a clear line of sight (see
+// FindReachFace); entities need their box in reach and its center in sight.
+func (t CoverTarget) reachableFrom(col *collisions.Module, eye geom.Vec3) bool {
+	if t.Entity == nil {
+		_, _, ok := FindReachFace(col, eye.X, eye.Y, eye.Z, t.Block, t.Reach)
+		return ok
+	}
+	b := *t.Entity
+	cx, cy, cz := b.ClosestPoint(eye.X, eye.Y, eye.Z)
+	if eye.Distance(geom.Vec3{X: cx, Y: cy, Z: cz}) > t.Reach {
+		return false
+	}
+	if col == nil {
+		return true
+	}
+	hit, _, _, _ := col.RaycastBlocks(eye.X, eye.Y, eye.Z, (b.MinX+b.MaxX)/2, (b.MinY+b.MaxY)/2, (b.MinZ+b.MaxZ)/2)
+	return !hit
+}
+
+// center returns the block or entity box center.
+func (t CoverTarget) center() geom.Vec3 {
+	if t.Entity == nil {
+		return t.Block.Center()
+	}
+	b := *t.Entity
+	return geom.Vec3{X: (b.MinX + b.MaxX) / 2, Y: (b.MinY + b.MaxY) / 2, Z: (b.MinZ + b.MaxZ) / 2}
+}
+
+// PlanCover plans a round that handles every target from as few stand
+// positions as it can: it picks the position covering the most targets not
+// yet covered, ties going to the one nearest the start, until all reachable
+// targets are covered (a greedy set cover, within a log factor of the
+// fewest). The stops are then ordered into a short walk from (fromX, fromY,
+// fromZ), nearest first and improved by swapping legs (2-opt); distances are
+// straight lines, not paths. unreachable lists the targets no standable
+// position reaches.
+//
+// It generalizes FindBestReachPosition, which picks one position at a time.
+func PlanCover(col *collisions.Module, fromX, fromY, fromZ float64, targets []CoverTarget) (stops []CoverStop, unreachable []int) {
+	from := geom.Vec3{X: fromX, Y: fromY, Z: fromZ}
+
+	// candidate stand positions and what each covers
+	covers := make(map[geom.BlockPos][]int)
+	checked := make(map[geom.BlockPos]bool)
+	for _, t := range targets {
+		r := int(math.Ceil(t.Reach)) + 1
+		c := t.center()
+		base := geom.BlockPos{X: int(math.Floor(c.X)), Y: int(math.Floor(c.Y)), Z: int(math.Floor(c.Z))}
+		for dx := -r; dx <= r; dx++ {
+			for dz := -r; dz <= r; dz++ {
+				for dy := -r; dy <= r; dy++ {
+					pos := base.Offset(dx, dy, dz)
+					if checked[pos] {
+						continue
+					}
+					checked[pos] = true
+					if !canStandAtHeight(col, pos.X, pos.Y, pos.Z, playerHeight) {
+						continue
+					}
+					eye := standEye(pos)
+					for i, o := range targets {
+						// cheap bound before the raycasts
+						if eye.Distance(o.center()) > o.Reach+1 {
+							continue
+						}
+						if o.reachableFrom(col, eye) {
+							covers[pos] = append(covers[pos], i)
+						}
+					}
+				}
+			}
+		}
+	}
+
+	covered := make([]bool, len(targets))
+	for {
+		var best geom.BlockPos
+		var bestTargets []int
+		bestDist := math.MaxFloat64
+		for pos, ts := range covers {
+			var left []int
+			for _, i := range ts {
+				if !covered[i] {
+					left = append(left, i)
+				}
+			}
+			if len(left) == 0 {
+				continue
+			}
+			d := from.Distance(standEye(pos))
+			if len(left) > len(bestTargets) || (len(left) == len(bestTargets) && d < bestDist) {
+				best, bestTargets, bestDist = pos, left, d
+			}
+		}
+		if bestTargets == nil {
+			break
+		}
+		for _, i := range bestTargets {
+			covered[i] = true
+		}
+		stops = append(stops, CoverStop{Stand: best, Targets: bestTargets})
+	}
+	for i, c := range covered {
+		if !c {
+			unreachable = append(unreachable, i)
+		}
+	}
+	return orderStops(from, stops), unreachable
+}
+
+// orderStops orders stops into a short open walk starting at from: nearest
+// neighbor first, then 2-opt until no reversal of a stretch shortens it.
+func orderStops(from geom.Vec3, stops []CoverStop) []CoverStop {
+	if len(stops) < 2 {
+		return stops
+	}
+	left := slices.Clone(stops)
+	order := make([]CoverStop, 0, len(stops))
+	at := from
+	for len(left) > 0 {
+		next := 0
+		for i, s := range left {
+			if at.Distance(s.Stand.Center()) < at.Distance(left[next].Stand.Center()) {
+				next = i
+			}
+		}
+		order = append(order, left[next])
+		at = left[next].Stand.Center()
+		left = slices.Delete(left, next, next+1)
+	}
+
+	point := func(i int) geom.Vec3 {
+		if i < 0 {
+			return from
+		}
+		return order[i].Stand.Center()
+	}
+	for improved := true; improved; {
+		improved = false
+		for i := 0; i < len(order)-1; i++ {
+			for j := i + 1; j < len(order); j++ {
+				// reverse order[i..j]: legs (i-1, i) and (j, j+1) become
+				// (i-1, j) and (i, j+1); the walk is open at the end
+				before := point(i - 1).Distance(point(i))
+				after := point(i - 1).Distance(point(j))
+				if j+1 < len(order) {
+					before += point(j).Distance(point(j + 1))
+					after += point(i).Distance(point(j + 1))
+				}
+				if after < before-1e-9 {
+					slices.Reverse(order[i : j+1])
+					improved = true
+				}
+			}
+		}
+	}
+	return order
+}
+
+// standEye returns the eye position of a player standing in pos.
+func standEye(pos geom.BlockPos) geom.Vec3 {
+	return geom.Vec3{X: float64(pos.X) + 0.5, Y: float64(pos.Y) + eyeHeight, Z: float64(pos.Z) + 0.5}
+}
@@ -0,0 +1,62 @@
+package pathfinding
+
+import (
+	"encoding/json"
+
+	"github.com/go-mclib/client/pkg/geom"
+)
+
+// StateDump is the pathfinder's section of a client state dump (see
+// client.DumpState), encoded as JSON. Path nodes come without their Parent.
+type StateDump struct {
+	Navigating bool
+	Goal       geom.Vec3
+	Path       []PathNode
+	PathIndex  int
+	StuckTicks int
+	// the recorded searches, if RecordSearches or KeepFailedSearches is set
+	LastSearch     *Search   `json:",omitempty"`
+	FailedSearches []*Search `json:",omitempty"`
+}
+
+// flatPath copies path without the Parent links, which would repeat the
+// path up to each node.
+func flatPath(path []PathNode) []PathNode {
+	if path == nil {
+		return nil
+	}
+	flat := make([]PathNode, len(path))
+	for i, n := range path {
+		n.Parent = nil
+		flat[i] = n
+	}
+	return flat
+}
+
+func flatSearch(s *Search) *Search {
+	if s == nil {
+		return nil
+	}
+	flat := *s
+	flat.Path = flatPath(s.Path)
+	return &flat
+}
+
+// DumpState implements client.StateDumper.
+func (m *Module) DumpState() ([]byte, error) {
+	m.mu.Lock()
+	d := StateDump{
+		Navigating: m.navigating,
+		Goal:       geom.Vec3{X: m.goalX, Y: m.goalY, Z: m.goalZ},
+		Path:       flatPath(m.path),
+		PathIndex:  m.pathIndex,
+		StuckTicks: m.stuckTicks,
+	}
+	m.mu.Unlock()
+
+	d.LastSearch = flatSearch(m.LastSearch())
+	for _, s := range m.FailedSearches() {
+		d.FailedSearches = append(d.FailedSearches, flatSearch(s))
+	}
+	return json.Marshal(d)
+}
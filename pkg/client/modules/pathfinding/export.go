@@ -0,0 +1,180 @@
+package pathfinding
+
+import (
+	"encoding/json"
+	"io"
+	"math"
+	"time"
+
+	"github.com/go-mclib/client/pkg/client/modules/collisions"
+	"github.com/go-mclib/client/pkg/client/modules/entities"
+	"github.com/go-mclib/client/pkg/client/modules/world"
+	"github.com/go-mclib/client/pkg/geom"
+)
+
+// Search is a recorded A* search: its endpoints, the resulting path (nil if
+// it failed), and every node expanded along the way.
+type Search struct {
+	Time     time.Time
+	Start    geom.BlockPos
+	Goal     geom.BlockPos
+	Path     []PathNode
+	Explored []ExploredNode
+	Err      string // empty on success
+}
+
+// ExploredNode is a node expanded by the search, with its costs.
+type ExploredNode struct {
+	X, Y, Z int
+	G, H    float64
+}
+
+// LastSearch returns the latest search, if RecordSearches is enabled.
+func (m *Module) LastSearch() *Search {
+	m.searchMu.Lock()
+	defer m.searchMu.Unlock()
+	return m.lastSearch
+}
+
+// FailedSearches returns the kept failed searches, oldest first.
+func (m *Module) FailedSearches() []*Search {
+	m.searchMu.Lock()
+	defer m.searchMu.Unlock()
+	return append([]*Search(nil), m.failedSearches...)
+}
+
+// search runs findPath, recording it when RecordSearches or KeepFailedSearches is set.
+func (m *Module) search(w *world.Module, col *collisions.Module, ents *entities.Module,
+	startX, startY, startZ, goalX, goalY, goalZ, maxNodes int,
+	jumpPower, effectiveSpeed float64,
+) ([]PathNode, error) {
+	a := m.newSearch(w, col, ents, startX, startY, startZ, goalX, goalY, goalZ, maxNodes, jumpPower, effectiveSpeed)
+	_, path, err := a.step(math.MaxInt)
+	m.recordSearch(a, path, err)
+	return path, err
+}
+
+// newSearch starts a search, traced when RecordSearches or
+// KeepFailedSearches is set; pass its outcome to recordSearch.
+func (m *Module) newSearch(w *world.Module, col *collisions.Module, ents *entities.Module,
+	startX, startY, startZ, goalX, goalY, goalZ, maxNodes int,
+	jumpPower, effectiveSpeed float64,
+) *aStar {
+	var trace *Search
+	if m.RecordSearches || m.KeepFailedSearches > 0 {
+		trace = &Search{
+			Time:  time.Now(),
+			Start: geom.BlockPos{X: startX, Y: startY, Z: startZ},
+			Goal:  geom.BlockPos{X: goalX, Y: goalY, Z: goalZ},
+		}
+	}
+	return newAStar(w, col, ents, startX, startY, startZ, goalX, goalY, goalZ, maxNodes, jumpPower, effectiveSpeed, trace)
+}
+
+// recordSearch keeps a finished traced search.
+func (m *Module) recordSearch(a *aStar, path []PathNode, err error) {
+	trace := a.trace
+	if trace == nil {
+		return
+	}
+	trace.Path = path
+	if err != nil {
+		trace.Err = err.Error()
+	}
+
+	m.searchMu.Lock()
+	if m.RecordSearches {
+		m.lastSearch = trace
+	}
+	if err != nil && m.KeepFailedSearches > 0 {
+		m.failedSearches = append(m.failedSearches, trace)
+		if over := len(m.failedSearches) - m.KeepFailedSearches; over > 0 {
+			m.failedSearches = m.failedSearches[over:]
+		}
+	}
+	m.searchMu.Unlock()
+}
+
+// geoJSON-like export: coordinates are [x, z, y] of block centers so the
+// horizontal plane maps onto a top-down world map (see world.RenderTopDown).
+
+type featureCollection struct {
+	Type       string         `json:"type"`
+	Properties map[string]any `json:"properties"`
+	Features   []feature      `json:"features"`
+}
+
+type feature struct {
+	Type       string         `json:"type"`
+	Geometry   geometry       `json:"geometry"`
+	Properties map[string]any `json:"properties"`
+}
+
+type geometry struct {
+	Type        string `json:"type"`
+	Coordinates any    `json:"coordinates"`
+}
+
+func coord(x, y, z int) [3]float64 {
+	return [3]float64{float64(x) + 0.5, float64(z) + 0.5, float64(y)}
+}
+
+// Export writes the search as a GeoJSON-like FeatureCollection: a LineString
+// for the path (if any), a Point per path node with its costs and move flags,
+// and a Point per explored node with kind "explored".
+func (s *Search) Export(w io.Writer) error {
+	fc := featureCollection{
+		Type: "FeatureCollection",
+		Properties: map[string]any{
+			"time":     s.Time,
+			"start":    [3]int{s.Start.X, s.Start.Y, s.Start.Z},
+			"goal":     [3]int{s.Goal.X, s.Goal.Y, s.Goal.Z},
+			"explored": len(s.Explored),
+		},
+	}
+	if s.Err != "" {
+		fc.Properties["error"] = s.Err
+	}
+
+	if len(s.Path) > 0 {
+		line := make([][3]float64, len(s.Path))
+		for i, n := range s.Path {
+			line[i] = coord(n.X, n.Y, n.Z)
+		}
+		fc.Features = append(fc.Features, feature{
+			Type:       "Feature",
+			Geometry:   geometry{Type: "LineString", Coordinates: line},
+			Properties: map[string]any{"kind": "path", "cost": s.Path[len(s.Path)-1].G},
+		})
+		for i, n := range s.Path {
+			props := map[string]any{"kind": "path_node", "index": i, "g": n.G, "h": n.H, "f": n.F}
+			if n.Sneaking {
+				props["sneaking"] = true
+			}
+			if n.Jump {
+				props["jump"] = true
+				props["jump_yaw"] = n.JumpYaw
+			}
+			if n.InteractDoor {
+				props["door"] = [3]int{n.DoorX, n.DoorY, n.DoorZ}
+			}
+			fc.Features = append(fc.Features, feature{
+				Type:       "Feature",
+				Geometry:   geometry{Type: "Point", Coordinates: coord(n.X, n.Y, n.Z)},
+				Properties: props,
+			})
+		}
+	}
+
+	for _, n := range s.Explored {
+		fc.Features = append(fc.Features, feature{
+			Type:       "Feature",
+			Geometry:   geometry{Type: "Point", Coordinates: coord(n.X, n.Y, n.Z)},
+			Properties: map[string]any{"kind": "explored", "g": n.G, "h": n.H},
+		})
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(fc)
+}
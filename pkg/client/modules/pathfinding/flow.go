@@ -0,0 +1,89 @@
+package pathfinding
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/go-mclib/client/pkg/client/modules/collisions"
+	"github.com/go-mclib/client/pkg/client/modules/physics"
+	"github.com/go-mclib/client/pkg/client/modules/world"
+	"github.com/go-mclib/data/pkg/data/blocks"
+)
+
+// flowHazardStrength is how strong (horizontally, of the normalized flow) a
+// current must be to carry the player off the next block.
+const flowHazardStrength = 0.5
+
+// flowCost returns the extra cost of moving from (cx, cy, cz) to (nx, ny, nz)
+// through flowing water or lava: currents push the player about 0.014 blocks
+// per tick, which slows moves against them and drifts moves across them. It
+// returns -1 if the current at the destination pushes into a hazard (a
+// dangerous block or a drop deeper than safeFallDistance), where the player
+// can't hold its position.
+func flowCost(w *world.Module, col *collisions.Module, cx, cy, cz, nx, ny, nz int) float64 {
+	sx, _, sz := physics.FluidFlow(w, cx, cy, cz)
+	dx, _, dz := physics.FluidFlow(w, nx, ny, nz)
+	if sx == 0 && sz == 0 && dx == 0 && dz == 0 {
+		return 0
+	}
+	if flowIntoHazard(w, col, nx, ny, nz, dx, dz) {
+		return -1
+	}
+
+	// the current along the move, averaged over both ends
+	fx, fz := (sx+dx)/2, (sz+dz)/2
+	mx, mz := float64(nx-cx), float64(nz-cz)
+	length := math.Hypot(mx, mz)
+	if length == 0 {
+		return 0
+	}
+	mx, mz = mx/length, mz/length
+	along := fx*mx + fz*mz
+	across := math.Abs(fx*mz - fz*mx)
+
+	cost := across * FlowCrossCost * length
+	if along < 0 {
+		cost += -along * FlowUpstreamCost * length
+	}
+	return cost
+}
+
+// flowIntoHazard reports whether a strong current at (x, y, z) flowing in
+// (fx, fz) carries the player into a dangerous block or off a deep drop.
+func flowIntoHazard(w *world.Module, col *collisions.Module, x, y, z int, fx, fz float64) bool {
+	if math.Hypot(fx, fz) < flowHazardStrength {
+		return false
+	}
+	// the neighbor the current mostly points at
+	tx, tz := x, z
+	if math.Abs(fx) >= math.Abs(fz) {
+		tx += int(math.Copysign(1, fx))
+	} else {
+		tz += int(math.Copysign(1, fz))
+	}
+
+	for dy := 0; dy >= -safeFallDistance; dy-- {
+		state := w.GetBlock(tx, y+dy, tz)
+		if blockDangerCost(state) >= 50 {
+			return true
+		}
+		if dy == 0 && !col.CanFitAt(float64(tx)+0.5, float64(y), float64(tz)+0.5, playerWidth, playerHeight) {
+			return false // pushed against a wall
+		}
+		if physics.IsWater(state) && !isFallingFluid(state) {
+			return false // stays afloat; falling water carries it down
+		}
+		if canStandAt(w, col, tx, y+dy, tz) {
+			return blockDangerCost(w.GetBlock(tx, y+dy-1, tz)) >= 50
+		}
+	}
+	return true
+}
+
+// isFallingFluid reports whether a fluid block is falling (level 8 and up),
+// as in a waterfall.
+func isFallingFluid(state int32) bool {
+	_, props := blocks.StateProperties(int(state))
+	level, _ := strconv.Atoi(props["level"])
+	return level >= 8
+}
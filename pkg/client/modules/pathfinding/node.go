@@ -6,6 +6,7 @@ import (
 	"github.com/go-mclib/client/pkg/client/modules/collisions"
 	"github.com/go-mclib/client/pkg/client/modules/entities"
 	"github.com/go-mclib/client/pkg/client/modules/world"
+	"github.com/go-mclib/client/pkg/geom"
 	"github.com/go-mclib/data/pkg/data/blocks"
 	block_shapes "github.com/go-mclib/data/pkg/data/hitboxes/blocks"
 )
@@ -78,6 +79,13 @@ func moveCostInner(w *world.Module, ents *entities.Module, x, y, z int, sneaking
 	belowState := w.GetBlock(x, y-1, z)
 	cost += blockDangerCost(belowState)
 
+	// blocks the server may not agree with
+	for _, dy := range [3]int{-1, 0, 1} {
+		if w.Suspect(geom.BlockPos{X: x, Y: y + dy, Z: z}) {
+			cost += SuspectBlockCost
+		}
+	}
+
 	// adjacent lava
 	for _, offset := range [4][2]int{{1, 0}, {-1, 0}, {0, 1}, {0, -1}} {
 		adjState := w.GetBlock(x+offset[0], y, z+offset[1])
@@ -174,13 +182,14 @@ func canDiagonalTraverse(w *world.Module, col *collisions.Module, cx, cy, cz, ox
 }
 
 // FindReachablePosition finds the standable position closest to (fromX, fromY, fromZ)
-// that has line-of-sight to (bx, by, bz) within reach distance.
+// from which a face of (bx, by, bz) can be clicked. See FindReachSpot, which also
+// returns the face and cursor to click with.
 func FindReachablePosition(col *collisions.Module, fromX, fromY, fromZ float64, bx, by, bz int, reach float64) (int, int, int, bool) {
-	standX, standY, standZ, _, found := FindBestReachPosition(col, fromX, fromY, fromZ, [][3]int{{bx, by, bz}}, reach)
+	spot, found := FindReachSpot(col, fromX, fromY, fromZ, geom.BlockPos{X: bx, Y: by, Z: bz}, reach)
 	if !found {
 		return 0, 0, 0, false
 	}
-	return standX, standY, standZ, true
+	return spot.Stand.X, spot.Stand.Y, spot.Stand.Z, true
 }
 
 // FindBestReachPosition finds the standable position from which the most targets
@@ -189,9 +198,9 @@ func FindReachablePosition(col *collisions.Module, fromX, fromY, fromZ float64,
 // Returns the stand position and the subset of targets reachable from it.
 func FindBestReachPosition(col *collisions.Module,
 	fromX, fromY, fromZ float64,
-	targets [][3]int,
+	targets []geom.BlockPos,
 	reach float64,
-) (standX, standY, standZ int, reachable [][3]int, found bool) {
+) (standX, standY, standZ int, reachable []geom.BlockPos, found bool) {
 	if len(targets) == 0 {
 		return 0, 0, 0, nil, false
 	}
@@ -199,16 +208,16 @@ func FindBestReachPosition(col *collisions.Module,
 	r := int(math.Ceil(reach))
 
 	// collect unique candidate standable positions around all targets
-	candidates := make(map[[3]int]bool)
+	candidates := make(map[geom.BlockPos]bool)
 	for _, t := range targets {
 		for dx := -r; dx <= r; dx++ {
 			for dz := -r; dz <= r; dz++ {
 				for dy := -r; dy <= r; dy++ {
-					pos := [3]int{t[0] + dx, t[1] + dy, t[2] + dz}
+					pos := t.Offset(dx, dy, dz)
 					if candidates[pos] {
 						continue
 					}
-					if canStandAtHeight(col, pos[0], pos[1], pos[2], playerHeight) {
+					if canStandAtHeight(col, pos.X, pos.Y, pos.Z, playerHeight) {
 						candidates[pos] = true
 					}
 				}
@@ -220,14 +229,14 @@ func FindBestReachPosition(col *collisions.Module,
 	bestFromDist := math.MaxFloat64
 
 	for pos := range candidates {
-		eyeX := float64(pos[0]) + 0.5
-		eyeY := float64(pos[1]) + eyeHeight
-		eyeZ := float64(pos[2]) + 0.5
+		eyeX := float64(pos.X) + 0.5
+		eyeY := float64(pos.Y) + eyeHeight
+		eyeZ := float64(pos.Z) + 0.5
 
 		// count reachable targets from this position
 		count := 0
 		for _, t := range targets {
-			if canReachBlock(col, eyeX, eyeY, eyeZ, t[0], t[1], t[2], reach) {
+			if canReachBlock(col, eyeX, eyeY, eyeZ, t.X, t.Y, t.Z, reach) {
 				count++
 			}
 		}
@@ -241,7 +250,7 @@ func FindBestReachPosition(col *collisions.Module,
 		if count > bestCount || (count == bestCount && fromDist < bestFromDist) {
 			bestCount = count
 			bestFromDist = fromDist
-			standX, standY, standZ = pos[0], pos[1], pos[2]
+			standX, standY, standZ = pos.X, pos.Y, pos.Z
 			found = true
 		}
 	}
@@ -255,7 +264,7 @@ func FindBestReachPosition(col *collisions.Module,
 	eyeY := float64(standY) + eyeHeight
 	eyeZ := float64(standZ) + 0.5
 	for _, t := range targets {
-		if canReachBlock(col, eyeX, eyeY, eyeZ, t[0], t[1], t[2], reach) {
+		if canReachBlock(col, eyeX, eyeY, eyeZ, t.X, t.Y, t.Z, reach) {
 			reachable = append(reachable, t)
 		}
 	}
@@ -263,33 +272,24 @@ func FindBestReachPosition(col *collisions.Module,
 }
 
 // canReachBlock checks if a position (eye coords) can interact with a block
-// at (bx,by,bz) — within reach distance and with clear line of sight.
+// at (bx,by,bz) — some face within reach distance and with clear line of sight.
 func canReachBlock(col *collisions.Module, eyeX, eyeY, eyeZ float64, bx, by, bz int, reach float64) bool {
-	tx := float64(bx) + 0.5
-	ty := float64(by) + 0.5
-	tz := float64(bz) + 0.5
-	dx, dy, dz := eyeX-tx, eyeY-ty, eyeZ-tz
-	if dx*dx+dy*dy+dz*dz > reach*reach {
-		return false
-	}
-	if col != nil {
-		hit, _, _, _ := col.RaycastBlocks(eyeX, eyeY, eyeZ, tx, ty, tz)
-		if hit {
-			return false
-		}
-	}
-	return true
+	_, _, ok := FindReachFace(col, eyeX, eyeY, eyeZ, geom.BlockPos{X: bx, Y: by, Z: bz}, reach)
+	return ok
 }
 
 func blockDangerCost(stateID int32) float64 {
 	if stateID == 0 {
 		return 0
 	}
-	blockID, _ := blocks.StateProperties(int(stateID))
+	blockID, props := blocks.StateProperties(int(stateID))
 	name := blocks.BlockName(blockID)
 	if c, ok := dangerCosts[name]; ok {
 		return c
 	}
+	if props["waterlogged"] == "true" {
+		return dangerCosts["minecraft:water"]
+	}
 	return 0
 }
 
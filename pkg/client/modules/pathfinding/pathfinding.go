@@ -1,6 +1,8 @@
 package pathfinding
 
 import (
+	"encoding/json"
+	"fmt"
 	"math"
 	"sync"
 
@@ -15,11 +17,24 @@ import (
 
 const ModuleName = "pathfinding"
 
+// repathSliceNodes is how many nodes a re-path expands per slice of tick
+// time (see client.Submit).
+const repathSliceNodes = 64
+
+// repathJoinNodes is how far into a finished re-path to look for the node
+// nearest the player, who kept walking while it was searched.
+const repathJoinNodes = 8
+
 type Module struct {
 	client *client.Client
 
 	MaxNodes int // maximum A* nodes to explore (default: 10000)
 
+	// RecordSearches keeps the explored node set of the latest search (see LastSearch).
+	RecordSearches bool
+	// KeepFailedSearches keeps the last N failed searches for post-mortem analysis (0 = none).
+	KeepFailedSearches int
+
 	mu            sync.Mutex
 	navigating    bool
 	path          []PathNode
@@ -33,6 +48,10 @@ type Module struct {
 	goalY         float64
 	goalZ         float64
 
+	// re-path in progress, run in slices on the tick thread (see tryRepath)
+	repath       *aStar
+	cancelRepath func()
+
 	// door interaction state
 	doorWaitTicks int  // countdown while waiting for door to open
 	doorOpened    bool // whether we already sent the interact packet
@@ -40,6 +59,12 @@ type Module struct {
 	// saved sprint/sneak state to restore after navigation
 	savedSprinting bool
 	savedSneaking  bool
+	noSprint       bool // see SetSprintAllowed
+
+	// search recording (export.go)
+	searchMu       sync.Mutex
+	lastSearch     *Search
+	failedSearches []*Search
 
 	onPathFound          []func(path []PathNode)
 	onNavigationComplete []func(reached bool)
@@ -61,9 +86,71 @@ func (m *Module) Init(c *client.Client) {
 	p := physics.From(c)
 	if p != nil {
 		p.OnTick(func() {
-			m.navigationTick()
+			if c.ModuleEnabled(ModuleName) {
+				m.navigationTick()
+			}
 		})
 	}
+	if s := self.From(c); s != nil {
+		s.OnPositionChange(m.onPositionChange)
+	}
+}
+
+// onPositionChange keeps following the path after a rubber-band, re-plans
+// from where a teleport left the player, and gives up after a respawn or
+// dimension change (the goal is in a world that's no longer loaded).
+func (m *Module) onPositionChange(_, _, _ float64, cause self.PositionCause) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.navigating {
+		return
+	}
+	switch cause {
+	case self.PositionCorrection, self.PositionDismount:
+		m.stuckTicks = 0
+	case self.PositionTeleport:
+		// the old path starts somewhere else now: wait for the new one
+		m.path = nil
+		if p := physics.From(m.client); p != nil {
+			p.SetInput(0, 0, false)
+		}
+		if !m.tryRepath() {
+			m.completeNavigation(false)
+		}
+	default:
+		m.completeNavigation(false)
+	}
+}
+
+// PrepareConfig implements client.Reloadable for the "pathfinding" section.
+func (m *Module) PrepareConfig(raw json.RawMessage) (func(), error) {
+	var cfg struct {
+		MaxNodes           *int  `json:"max_nodes"`
+		RecordSearches     *bool `json:"record_searches"`
+		KeepFailedSearches *int  `json:"keep_failed_searches"`
+	}
+	if err := json.Unmarshal(raw, &cfg); err != nil {
+		return nil, err
+	}
+	if cfg.MaxNodes != nil && *cfg.MaxNodes <= 0 {
+		return nil, fmt.Errorf("max_nodes must be positive, got %d", *cfg.MaxNodes)
+	}
+	if cfg.KeepFailedSearches != nil && *cfg.KeepFailedSearches < 0 {
+		return nil, fmt.Errorf("keep_failed_searches must not be negative, got %d", *cfg.KeepFailedSearches)
+	}
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		if cfg.MaxNodes != nil {
+			m.MaxNodes = *cfg.MaxNodes
+		}
+		if cfg.RecordSearches != nil {
+			m.RecordSearches = *cfg.RecordSearches
+		}
+		if cfg.KeepFailedSearches != nil {
+			m.KeepFailedSearches = *cfg.KeepFailedSearches
+		}
+	}, nil
 }
 
 func (m *Module) Reset() {
@@ -77,6 +164,7 @@ func (m *Module) Reset() {
 	m.retreatCycles = 0
 	m.doorWaitTicks = 0
 	m.doorOpened = false
+	m.stopRepath()
 }
 
 func From(c *client.Client) *Module {
@@ -129,7 +217,7 @@ func (m *Module) FindPath(goalX, goalY, goalZ float64) ([]PathNode, error) {
 		effectiveSpeed = p.GetEffectiveSpeed()
 	}
 
-	path, err := findPath(w, col, ents, startX, startY, startZ, gx, gy, gz, maxNodes, jumpPower, effectiveSpeed)
+	path, err := m.search(w, col, ents, startX, startY, startZ, gx, gy, gz, maxNodes, jumpPower, effectiveSpeed)
 	if err != nil {
 		return nil, err
 	}
@@ -151,6 +239,7 @@ func (m *Module) NavigateTo(goalX, goalY, goalZ float64) error {
 	s := self.From(m.client)
 
 	m.mu.Lock()
+	m.stopRepath()
 	m.path = path
 	m.pathIndex = 0
 	m.navigating = true
@@ -179,6 +268,7 @@ func (m *Module) Stop() {
 	if m.navigating {
 		m.navigating = false
 		m.path = nil
+		m.stopRepath()
 
 		p := physics.From(m.client)
 		if p != nil {
@@ -192,6 +282,35 @@ func (m *Module) Stop() {
 	}
 }
 
+// SetEnabled implements client.Toggler. Navigation pauses while the module
+// is disabled, without the input it set, and resumes from wherever the
+// player is then.
+func (m *Module) SetEnabled(enabled bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.navigating {
+		return
+	}
+	if !enabled {
+		if p := physics.From(m.client); p != nil {
+			p.SetInput(0, 0, false)
+		}
+		return
+	}
+	if !m.tryRepath() {
+		m.completeNavigation(false)
+	}
+}
+
+// SetSprintAllowed lets navigation sprint on straight stretches (the
+// default) or keeps it walking, e.g. to keep pace with someone. Sprint
+// jumps over gaps sprint either way.
+func (m *Module) SetSprintAllowed(allowed bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.noSprint = !allowed
+}
+
 // IsNavigating returns true if the bot is currently navigating.
 func (m *Module) IsNavigating() bool {
 	m.mu.Lock()
@@ -231,6 +350,9 @@ func (m *Module) navigationTick() {
 			}
 			cost, _ := moveCost(w, col, nil, node.X, node.Y, node.Z)
 			if cost < 0 {
+				if m.repath != nil {
+					break // keep going until the new path is ready
+				}
 				if m.tryRepath() {
 					return
 				}
@@ -348,6 +470,16 @@ func (m *Module) navigationTick() {
 	}
 	s.LookAt(lookX, wpY+playerHeight, lookZ)
 
+	// using an item (eating on the way) slows walking to a fifth, see
+	// physics.RestrictUsingItem: keep following the path, but wait with
+	// jumps, which were planned at full speed
+	_, usingItem := s.UsingItem()
+	if usingItem && wp.Jump {
+		p.SetInput(0, 0, false)
+		m.stuckTicks = 0
+		return
+	}
+
 	// movement input
 	sneaking := s.Sneaking() || wp.Sneaking
 	var jumping, sprinting bool
@@ -363,7 +495,7 @@ func (m *Module) navigationTick() {
 		jumping = false
 
 		// sprint when moving straight and far enough ahead
-		if !sneaking && horizDist > 2.0 {
+		if !sneaking && !usingItem && !m.noSprint && horizDist > 2.0 {
 			sprinting = shouldSprint(m.path, m.pathIndex, x, z)
 		}
 	}
@@ -384,7 +516,7 @@ func (m *Module) navigationTick() {
 	m.lastNavX = x
 	m.lastNavZ = z
 
-	if m.stuckTicks > 40 || m.retreatCycles > 3 {
+	if (m.stuckTicks > 40 || m.retreatCycles > 3) && m.repath == nil {
 		if m.tryRepath() {
 			return
 		}
@@ -392,7 +524,12 @@ func (m *Module) navigationTick() {
 	}
 }
 
-// tryRepath attempts to recompute a path to the current goal.
+// tryRepath starts re-planning the path to the current goal from where the
+// player is. The search runs in slices on the tick thread (see
+// client.Submit) so a long one doesn't stall movement; navigation keeps
+// following the old path until it's done, then joins the new one, and gives
+// up if it fails. Returns false if it can't start. Must be called with mu
+// held.
 func (m *Module) tryRepath() bool {
 	s := self.From(m.client)
 	w := world.From(m.client)
@@ -423,24 +560,55 @@ func (m *Module) tryRepath() bool {
 		effectiveSpeed = p.GetEffectiveSpeed()
 	}
 
-	path, err := findPath(w, col, ents, startX, startY, startZ, gx, gy, gz, maxNodes, jumpPower, effectiveSpeed)
-	if err != nil {
-		return false
-	}
+	m.stopRepath()
+	a := m.newSearch(w, col, ents, startX, startY, startZ, gx, gy, gz, maxNodes, jumpPower, effectiveSpeed)
+	m.repath = a
+	m.cancelRepath = m.client.Submit("pathfinding.repath", func() bool {
+		done, path, err := a.step(repathSliceNodes)
+		if !done {
+			return false
+		}
+		m.recordSearch(a, path, err)
 
-	m.path = path
-	m.pathIndex = 0
-	m.stuckTicks = 0
-	m.retreatTicks = 0
-	m.retreatCycles = 0
-	m.doorWaitTicks = 0
-	m.doorOpened = false
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		if m.repath != a {
+			// stopped or superseded meanwhile
+			return true
+		}
+		m.repath, m.cancelRepath = nil, nil
+		if err != nil {
+			m.completeNavigation(false)
+			return true
+		}
+		m.path = path
+		m.pathIndex = 0
+		if s := self.From(m.client); s != nil {
+			x, y, z := s.Position()
+			m.pathIndex = nearestNode(path, repathJoinNodes, x, y, z)
+		}
+		m.stuckTicks = 0
+		m.retreatTicks = 0
+		m.retreatCycles = 0
+		m.doorWaitTicks = 0
+		m.doorOpened = false
+		return true
+	})
 	return true
 }
 
+// stopRepath drops a re-path in progress. Must be called with mu held.
+func (m *Module) stopRepath() {
+	if m.cancelRepath != nil {
+		m.cancelRepath()
+	}
+	m.repath, m.cancelRepath = nil, nil
+}
+
 func (m *Module) completeNavigation(reached bool) {
 	m.navigating = false
 	m.path = nil
+	m.stopRepath()
 
 	p := physics.From(m.client)
 	if p != nil {
@@ -459,6 +627,21 @@ func (m *Module) completeNavigation(reached bool) {
 
 // distToBlockEdge returns the distance from (x,z) to the block edge in the
 // direction of (dx,dz). Used for timing parkour edge-jumps.
+// nearestNode returns the index of the node among the first n of path
+// closest to a position.
+func nearestNode(path []PathNode, n int, x, y, z float64) int {
+	best, bestDist := 0, math.Inf(1)
+	for i := range min(n, len(path)) {
+		dx := float64(path[i].X) + 0.5 - x
+		dy := float64(path[i].Y) - y
+		dz := float64(path[i].Z) + 0.5 - z
+		if d := dx*dx + dy*dy + dz*dz; d < bestDist {
+			best, bestDist = i, d
+		}
+	}
+	return best
+}
+
 func distToBlockEdge(x, z, dx, dz float64) float64 {
 	// determine primary movement axis
 	if math.Abs(dx) > math.Abs(dz) {
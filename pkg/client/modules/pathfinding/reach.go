@@ -0,0 +1,179 @@
+package pathfinding
+
+import (
+	"math"
+	"sort"
+
+	"github.com/go-mclib/client/pkg/client/modules/collisions"
+	"github.com/go-mclib/client/pkg/geom"
+)
+
+// ReachSpot is a position to stand at to click a block, and where to click it.
+type ReachSpot struct {
+	Stand  geom.BlockPos // block the player's feet are in
+	Target geom.BlockPos
+	Face   geom.Face
+	Cursor geom.Vec3 // clicked point relative to Target, each axis in [0, 1]
+}
+
+// Point returns the clicked point in world coordinates, the point to look at.
+func (s ReachSpot) Point() geom.Vec3 {
+	return geom.Vec3{
+		X: float64(s.Target.X) + s.Cursor.X,
+		Y: float64(s.Target.Y) + s.Cursor.Y,
+		Z: float64(s.Target.Z) + s.Cursor.Z,
+	}
+}
+
+// faceSamples are the points tried on a face, as fractions of its extent:
+// the center first, then inset toward the corners.
+var faceSamples = [5][2]float64{{0.5, 0.5}, {0.2, 0.2}, {0.8, 0.2}, {0.2, 0.8}, {0.8, 0.8}}
+
+// faceInset moves raycast end points just inside the target, so the ray's
+// last block is the target (RaycastBlocks doesn't test it).
+const faceInset = 1e-3
+
+// FindReachSpot finds the standable position closest to (fromX, fromY, fromZ)
+// from which a face of target can be clicked: a point on the face within reach
+// of the eye, with a raycast to it unobstructed. Unlike testing the block
+// center, this rejects spots where only the occluded side of the block is in
+// reach and returns the face and cursor to send with the interaction.
+func FindReachSpot(col *collisions.Module, fromX, fromY, fromZ float64, target geom.BlockPos, reach float64) (ReachSpot, bool) {
+	r := int(math.Ceil(reach))
+	var best ReachSpot
+	bestDist := math.MaxFloat64
+	found := false
+
+	for dx := -r; dx <= r; dx++ {
+		for dz := -r; dz <= r; dz++ {
+			for dy := -r; dy <= r; dy++ {
+				pos := target.Offset(dx, dy, dz)
+				eyeX := float64(pos.X) + 0.5
+				eyeY := float64(pos.Y) + eyeHeight
+				eyeZ := float64(pos.Z) + 0.5
+				fdx, fdy, fdz := fromX-eyeX, fromY-eyeY, fromZ-eyeZ
+				fromDist := fdx*fdx + fdy*fdy + fdz*fdz
+				if fromDist >= bestDist {
+					continue
+				}
+				if !canStandAtHeight(col, pos.X, pos.Y, pos.Z, playerHeight) {
+					continue
+				}
+				face, cursor, ok := FindReachFace(col, eyeX, eyeY, eyeZ, target, reach)
+				if !ok {
+					continue
+				}
+				best = ReachSpot{Stand: pos, Target: target, Face: face, Cursor: cursor}
+				bestDist = fromDist
+				found = true
+			}
+		}
+	}
+	return best, found
+}
+
+// FindReachFace picks the face of target to click from an eye position. Faces
+// turned toward the eye are tried most head-on first, each at a few sample
+// points; the first point within reach with a clear raycast wins. cursor is
+// that point relative to target.
+func FindReachFace(col *collisions.Module, eyeX, eyeY, eyeZ float64, target geom.BlockPos, reach float64) (face geom.Face, cursor geom.Vec3, ok bool) {
+	box := targetBox(col, target)
+	eye := [3]float64{eyeX, eyeY, eyeZ}
+	lo := [3]float64{box.MinX, box.MinY, box.MinZ}
+	hi := [3]float64{box.MaxX, box.MaxY, box.MaxZ}
+
+	type candidate struct {
+		face   geom.Face
+		facing float64 // eye distance in front of the face plane
+	}
+	var faces []candidate
+	for _, f := range geom.Faces {
+		axis, dir := faceAxis(f)
+		var facing float64
+		if dir > 0 {
+			facing = eye[axis] - hi[axis]
+		} else {
+			facing = lo[axis] - eye[axis]
+		}
+		if facing > 0 {
+			faces = append(faces, candidate{f, facing})
+		}
+	}
+	sort.Slice(faces, func(i, j int) bool { return faces[i].facing > faces[j].facing })
+
+	origin := [3]float64{float64(target.X), float64(target.Y), float64(target.Z)}
+	for _, c := range faces {
+		axis, dir := faceAxis(c.face)
+		u, v := (axis+1)%3, (axis+2)%3
+		for _, s := range faceSamples {
+			var p [3]float64
+			if dir > 0 {
+				p[axis] = hi[axis]
+			} else {
+				p[axis] = lo[axis]
+			}
+			p[u] = lo[u] + (hi[u]-lo[u])*s[0]
+			p[v] = lo[v] + (hi[v]-lo[v])*s[1]
+
+			dx, dy, dz := p[0]-eyeX, p[1]-eyeY, p[2]-eyeZ
+			if dx*dx+dy*dy+dz*dz > reach*reach {
+				continue
+			}
+			if col != nil {
+				end := p
+				end[axis] -= float64(dir) * faceInset
+				if hit, _, _, _ := col.RaycastBlocks(eyeX, eyeY, eyeZ, end[0], end[1], end[2]); hit {
+					continue
+				}
+			}
+			cursor = geom.Vec3{X: p[0] - origin[0], Y: p[1] - origin[1], Z: p[2] - origin[2]}
+			return c.face, cursor, true
+		}
+	}
+	return 0, geom.Vec3{}, false
+}
+
+// targetBox returns the bounds of target's collision shape, or the full block
+// if it has none (buttons, levers, plants are still clickable).
+func targetBox(col *collisions.Module, target geom.BlockPos) collisions.AABB {
+	full := collisions.NewAABB(
+		float64(target.X), float64(target.Y), float64(target.Z),
+		float64(target.X+1), float64(target.Y+1), float64(target.Z+1),
+	)
+	if col == nil {
+		return full
+	}
+	// shrink the query so only the target's own cell is scanned
+	shapes := col.GetBlockCollisions(full.Inflate(-faceInset, -faceInset, -faceInset))
+	if len(shapes) == 0 {
+		return full
+	}
+	box := collisions.AABB{
+		MinX: math.Inf(1), MinY: math.Inf(1), MinZ: math.Inf(1),
+		MaxX: math.Inf(-1), MaxY: math.Inf(-1), MaxZ: math.Inf(-1),
+	}
+	for _, s := range shapes {
+		box.MinX, box.MinY, box.MinZ = min(box.MinX, s.MinX), min(box.MinY, s.MinY), min(box.MinZ, s.MinZ)
+		box.MaxX, box.MaxY, box.MaxZ = max(box.MaxX, s.MaxX), max(box.MaxY, s.MaxY), max(box.MaxZ, s.MaxZ)
+	}
+	// fence and wall shapes are 1.5 tall; the click must land in the block
+	box.MinX, box.MinY, box.MinZ = max(box.MinX, full.MinX), max(box.MinY, full.MinY), max(box.MinZ, full.MinZ)
+	box.MaxX, box.MaxY, box.MaxZ = min(box.MaxX, full.MaxX), min(box.MaxY, full.MaxY), min(box.MaxZ, full.MaxZ)
+	if box.MinX >= box.MaxX || box.MinY >= box.MaxY || box.MinZ >= box.MaxZ {
+		return full
+	}
+	return box
+}
+
+// faceAxis returns the axis (0 = X, 1 = Y, 2 = Z) a face is perpendicular to
+// and the sign of its outward normal.
+func faceAxis(f geom.Face) (axis, dir int) {
+	dx, dy, dz := f.Offset()
+	switch {
+	case dx != 0:
+		return 0, dx
+	case dy != 0:
+		return 1, dy
+	}
+	return 2, dz
+}
@@ -1,8 +1,7 @@
 package physics
 
 import (
-	"time"
-
+	"github.com/go-mclib/client/pkg/client"
 	"github.com/go-mclib/data/pkg/data/registries"
 )
 
@@ -68,7 +67,7 @@ const (
 	PositionThresholdSq = 4e-8 // (2e-4)²
 	PositionReminderMax = 20
 	TicksPerSecond      = 20
-	TickDuration        = 50 * time.Millisecond
+	TickDuration        = client.TickDuration
 
 	// double: literals in travelInAir / getEffectiveGravity
 	SlowFallingGravity   = 0.01
@@ -80,4 +79,5 @@ const (
 var (
 	effectLevitation  = registries.MobEffect.Get("minecraft:levitation")
 	effectSlowFalling = registries.MobEffect.Get("minecraft:slow_falling")
+	effectBlindness   = registries.MobEffect.Get("minecraft:blindness")
 )
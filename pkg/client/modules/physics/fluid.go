@@ -4,6 +4,7 @@ import (
 	"math"
 	"strconv"
 
+	"github.com/go-mclib/client/pkg/client/modules/self"
 	"github.com/go-mclib/client/pkg/client/modules/world"
 	"github.com/go-mclib/data/pkg/data/blocks"
 	block_shapes "github.com/go-mclib/data/pkg/data/hitboxes/blocks"
@@ -16,14 +17,14 @@ const (
 
 // applyFluidPushing applies flow forces from water/lava currents.
 // Called before travel() in the tick, matching Entity.baseTick() in vanilla.
-func (m *Module) applyFluidPushing(x, y, z float64, w *world.Module) {
+func (m *Module) applyFluidPushing(x, y, z, height float64, w *world.Module) {
 	hw := PlayerWidth / 2
 	// deflate AABB by 0.001 as MC does
 	minX := x - hw + 0.001
 	minY := y + 0.001
 	minZ := z - hw + 0.001
 	maxX := x + hw - 0.001
-	maxY := y + PlayerHeight - 0.001
+	maxY := y + height - 0.001
 	maxZ := z + hw - 0.001
 
 	var totalX, totalY, totalZ float64
@@ -100,6 +101,16 @@ func (m *Module) applyFluidPushing(x, y, z float64, w *world.Module) {
 	m.velZ += totalZ
 }
 
+// FluidFlow returns the direction the water or lava in the block at (x, y,
+// z) pushes entities, normalized; zero for still fluid or no fluid.
+func FluidFlow(w *world.Module, x, y, z int) (fx, fy, fz float64) {
+	blockID, _ := blocks.StateProperties(int(w.GetBlock(x, y, z)))
+	if blockID != waterBlockID && blockID != lavaBlockID {
+		return 0, 0, 0
+	}
+	return getFluidFlow(w, blockID, x, y, z)
+}
+
 // getFluidFlow computes the flow direction at a fluid block.
 // Matches FlowingFluid.getFlow() from vanilla.
 func getFluidFlow(w *world.Module, fluidBlockID int32, bx, by, bz int) (flowX, flowY, flowZ float64) {
@@ -193,3 +204,20 @@ func parseLevel(s string) int {
 	}
 	return n
 }
+
+// updateSwimming starts swimming when sprinting with the eyes in water and
+// keeps it while sprinting in water (vanilla Entity.updateSwimming).
+func updateSwimming(s *self.Module, x, y, z float64, w *world.Module) {
+	if s.Vehicle() >= 0 {
+		s.SetSwimming(false)
+		return
+	}
+	bx, bz := int(math.Floor(x)), int(math.Floor(z))
+	inWater := IsWater(w.GetBlock(bx, int(math.Floor(y)), bz))
+	if s.Swimming() {
+		s.SetSwimming(s.Sprinting() && inWater)
+		return
+	}
+	eyeInWater := IsWater(w.GetBlock(bx, int(math.Floor(y+s.CurrentEyeHeight())), bz))
+	s.SetSwimming(s.Sprinting() && inWater && eyeInWater)
+}
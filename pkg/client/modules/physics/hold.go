@@ -0,0 +1,167 @@
+package physics
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/go-mclib/client/pkg/client/modules/self"
+	"github.com/go-mclib/client/pkg/geom"
+)
+
+// DefaultHoldReleaseDistance is how far the player can be displaced from a
+// held position before HoldPosition gives up (explosions, rubber-bands, ...).
+const DefaultHoldReleaseDistance = 3.0
+
+// holdLookahead is how many ticks of current velocity are projected forward
+// when correcting, so the player brakes before reaching the target instead of
+// oscillating around it.
+const holdLookahead = 3.0
+
+type hold struct {
+	active    bool
+	pos       geom.Vec3
+	tolerance float64
+}
+
+// HoldPosition keeps the player within tolerance blocks (horizontally) of pos
+// by generating walking input each tick, counteracting entity pushing and
+// minor knockback. It overrides input set by OnTick callbacks (e.g.
+// navigation) until ReleasePosition is called, until the server teleports or
+// respawns the player, or until the player is displaced farther than
+// HoldReleaseDistance (knockback, a large rubber-band).
+func (m *Module) HoldPosition(pos geom.Vec3, tolerance float64) {
+	m.mu.Lock()
+	m.hold = hold{active: true, pos: pos, tolerance: max(tolerance, 0.05)}
+	m.mu.Unlock()
+}
+
+// ReleasePosition stops holding position and clears the corrective input.
+func (m *Module) ReleasePosition() {
+	m.mu.Lock()
+	wasActive := m.hold.active
+	m.hold = hold{}
+	if wasActive {
+		m.forwardImpulse = 0
+		m.strafeImpulse = 0
+	}
+	m.mu.Unlock()
+}
+
+// HeldPosition returns the position being held, if any.
+func (m *Module) HeldPosition() (geom.Vec3, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.hold.pos, m.hold.active
+}
+
+// PrepareConfig implements client.Reloadable for the "physics" section.
+func (m *Module) PrepareConfig(raw json.RawMessage) (func(), error) {
+	var cfg struct {
+		HoldReleaseDistance *float64 `json:"hold_release_distance"`
+	}
+	if err := json.Unmarshal(raw, &cfg); err != nil {
+		return nil, err
+	}
+	if cfg.HoldReleaseDistance != nil && *cfg.HoldReleaseDistance <= 0 {
+		return nil, fmt.Errorf("hold_release_distance must be positive, got %g", *cfg.HoldReleaseDistance)
+	}
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		if cfg.HoldReleaseDistance != nil {
+			m.HoldReleaseDistance = *cfg.HoldReleaseDistance
+		}
+	}, nil
+}
+
+// OnHoldRelease is called when HoldPosition gives up because the player was
+// displaced too far (not on ReleasePosition).
+func (m *Module) OnHoldRelease(cb func()) {
+	m.onHoldRelease = append(m.onHoldRelease, cb)
+}
+
+// releaseIfDisplaced drops the hold when the player is too far from it.
+// Returns true if the hold was released.
+func (m *Module) releaseIfDisplaced(x, y, z float64) bool {
+	m.mu.Lock()
+	h := m.hold
+	limit := m.HoldReleaseDistance
+	if limit <= 0 {
+		limit = DefaultHoldReleaseDistance
+	}
+	if !h.active || h.pos.Distance(geom.Vec3{X: x, Y: y, Z: z}) <= limit {
+		m.mu.Unlock()
+		return false
+	}
+	m.mu.Unlock()
+	return m.releaseHold(x, y, z)
+}
+
+// releaseHold drops the hold after the player was moved to x, y, z and
+// notifies OnHoldRelease. Returns false if nothing was held.
+func (m *Module) releaseHold(x, y, z float64) bool {
+	m.mu.Lock()
+	h := m.hold
+	if !h.active {
+		m.mu.Unlock()
+		return false
+	}
+	m.hold = hold{}
+	m.forwardImpulse = 0
+	m.strafeImpulse = 0
+	m.mu.Unlock()
+
+	m.client.Logger.Printf("physics: released held position %v (displaced to %.2f, %.2f, %.2f)", h.pos, x, y, z)
+	for _, cb := range m.onHoldRelease {
+		cb()
+	}
+	return true
+}
+
+// applyHold replaces the movement input with keys that steer back toward the
+// held position. Like a player, it only presses keys: each impulse is -1, 0 or 1.
+func (m *Module) applyHold(s *self.Module) {
+	m.mu.RLock()
+	h := m.hold
+	m.mu.RUnlock()
+	if !h.active {
+		return
+	}
+
+	x, y, z := s.Position()
+	if m.releaseIfDisplaced(x, y, z) {
+		return
+	}
+
+	dx := h.pos.X - (x + m.velX*holdLookahead)
+	dz := h.pos.Z - (z + m.velZ*holdLookahead)
+	var forward, strafe float64
+	if math.Hypot(dx, dz) > h.tolerance/2 {
+		// inverse of moveRelative: world direction -> (forward, strafe) for the current yaw
+		yaw, _ := s.Rotation()
+		sinYaw := math.Sin(float64(yaw) * math.Pi / 180.0)
+		cosYaw := math.Cos(float64(yaw) * math.Pi / 180.0)
+		f := -dx*sinYaw + dz*cosYaw
+		st := dx*cosYaw + dz*sinYaw
+		forward, strafe = holdKey(f, st), holdKey(st, f)
+	}
+
+	m.mu.Lock()
+	m.forwardImpulse = forward
+	m.strafeImpulse = strafe
+	m.jumping = false
+	m.mu.Unlock()
+}
+
+// holdKey presses a key along an axis when that axis carries a meaningful
+// share of the correction (so diagonal corrections press two keys).
+func holdKey(axis, other float64) float64 {
+	if math.Abs(axis) < 0.4*math.Abs(other) || math.Abs(axis) < 1e-3 {
+		return 0
+	}
+	if axis > 0 {
+		return 1
+	}
+	return -1
+}
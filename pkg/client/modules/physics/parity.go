@@ -0,0 +1,220 @@
+package physics
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/go-mclib/client/pkg/client/modules/self"
+	"github.com/go-mclib/data/pkg/packets"
+	jp "github.com/go-mclib/protocol/java_protocol"
+	ns "github.com/go-mclib/protocol/java_protocol/net_structures"
+)
+
+// sendRidingRotation sends the per-tick rotation packet of a passenger
+// (vanilla LocalPlayer.tick sends MovePlayer.Rot instead of sendPosition).
+func (m *Module) sendRidingRotation(s *self.Module) {
+	yaw, pitch := s.Rotation()
+	var flags ns.Int8
+	if m.onGround {
+		flags = 0x01
+	}
+	if m.horizontalCollision {
+		flags |= 0x02
+	}
+	m.send(&packets.C2SMovePlayerRot{
+		Yaw: ns.Float32(yaw), Pitch: ns.Float32(pitch),
+		Flags: flags,
+	})
+	m.lastSentYaw, m.lastSentPitch = yaw, pitch
+}
+
+// TickRecord is what the tick loop sent during one tick, with the player
+// state the parity checklist depends on.
+type TickRecord struct {
+	Packets []string // packet type names, e.g. "C2SMovePlayerPos"
+	Loaded  bool
+	Riding  bool
+	Dead    bool
+	Camera  bool // attached to another entity (see RestrictCamera)
+}
+
+// TickTrace is a recording of consecutive ticks. It covers the play phase
+// only: the tick loop stops when the server moves the client back to the
+// configuration phase (a transfer), where the protocol module only answers
+// the server's keep-alives, pings and pack requests, and starts again on the
+// next login. Traffic in the configuration phase isn't recorded or checked.
+type TickTrace []TickRecord
+
+// StartTrace starts recording the packets the tick loop sends, one record per
+// tick, replacing any previous recording.
+func (m *Module) StartTrace() {
+	m.traceMu.Lock()
+	defer m.traceMu.Unlock()
+	m.tracing = true
+	m.trace = nil
+	m.traceTick = nil
+}
+
+// StopTrace stops recording and returns the recorded ticks.
+func (m *Module) StopTrace() TickTrace {
+	m.traceMu.Lock()
+	defer m.traceMu.Unlock()
+	m.tracing = false
+	t := m.trace
+	m.trace, m.traceTick = nil, nil
+	return t
+}
+
+// send queues a tick-loop packet, recording it when a trace is running.
+func (m *Module) send(pkt jp.Packet) {
+	m.traceMu.Lock()
+	if m.tracing {
+		m.traceTick = append(m.traceTick, packetName(pkt))
+	}
+	m.traceMu.Unlock()
+	m.client.SendPacket(pkt)
+}
+
+// endTraceTick closes the current tick's record.
+func (m *Module) endTraceTick(s *self.Module) {
+	m.traceMu.Lock()
+	defer m.traceMu.Unlock()
+	if !m.tracing {
+		return
+	}
+	riding := s.Vehicle() >= 0
+	_, camera := s.SpectatingEntity()
+	m.trace = append(m.trace, TickRecord{
+		Packets: m.traceTick,
+		Loaded:  s.Loaded(),
+		Riding:  riding,
+		Dead:    s.Health() <= 0,
+		Camera:  camera,
+	})
+	m.traceTick = nil
+}
+
+func packetName(pkt jp.Packet) string {
+	name := fmt.Sprintf("%T", pkt)
+	return name[strings.LastIndex(name, ".")+1:]
+}
+
+// Check validates the trace against the vanilla idle cadence checklist and
+// returns one message per violation:
+//   - every tick ends with exactly one C2SClientTickEnd
+//   - C2SPlayerInput is sent before any movement packet of the tick
+//   - at most one movement packet per tick
+//   - before the player has loaded in, only C2SClientTickEnd is sent
+//   - on the death screen, no input or movement is sent
+//   - while the camera is attached to another entity, input changes are
+//     sent but no movement
+//   - a passenger sends C2SMovePlayerRot every tick and never its position
+//   - otherwise a position is sent at least every PositionReminderMax ticks
+func (t TickTrace) Check() []string {
+	var issues []string
+	sincePos := 0
+	for i, rec := range t {
+		fail := func(format string, args ...any) {
+			issues = append(issues, fmt.Sprintf("tick %d: ", i)+fmt.Sprintf(format, args...))
+		}
+
+		n := len(rec.Packets)
+		if n == 0 || rec.Packets[n-1] != "C2SClientTickEnd" {
+			fail("does not end with C2SClientTickEnd")
+		}
+		if c := count(rec.Packets, "C2SClientTickEnd"); c > 1 {
+			fail("%d C2SClientTickEnd packets", c)
+		}
+
+		moves := 0
+		sentPos := false
+		for j, p := range rec.Packets {
+			if !isMovePacket(p) {
+				continue
+			}
+			moves++
+			if p == "C2SMovePlayerPos" || p == "C2SMovePlayerPosRot" {
+				sentPos = true
+			}
+			if k := slices.Index(rec.Packets, "C2SPlayerInput"); k > j {
+				fail("C2SPlayerInput after %s", p)
+			}
+		}
+		if moves > 1 {
+			fail("%d movement packets", moves)
+		}
+
+		switch {
+		case !rec.Loaded:
+			if n > 1 {
+				fail("sent %v before loading in", rec.Packets[:n-1])
+			}
+			sincePos = 0
+		case rec.Dead:
+			if moves > 0 || count(rec.Packets, "C2SPlayerInput") > 0 {
+				fail("sent movement while dead")
+			}
+			sincePos = 0
+		case rec.Camera:
+			if moves > 0 {
+				fail("spectating camera sent movement")
+			}
+			sincePos = 0
+		case rec.Riding:
+			if count(rec.Packets, "C2SMovePlayerRot") != 1 {
+				fail("passenger did not send C2SMovePlayerRot")
+			}
+			if sentPos {
+				fail("passenger sent its position")
+			}
+			sincePos = 0
+		default:
+			if sentPos {
+				sincePos = 0
+			} else if sincePos++; sincePos >= PositionReminderMax {
+				fail("no position for %d ticks", sincePos)
+				sincePos = 0
+			}
+		}
+	}
+	return issues
+}
+
+// DiffTraces compares the packets of a recorded trace with a reference one
+// (e.g. captured from a vanilla client through a proxy, using the same packet
+// names) tick by tick, and returns one message per differing tick.
+func DiffTraces(got, want TickTrace) []string {
+	var diffs []string
+	for i := range max(len(got), len(want)) {
+		var g, w []string
+		if i < len(got) {
+			g = got[i].Packets
+		}
+		if i < len(want) {
+			w = want[i].Packets
+		}
+		if !slices.Equal(g, w) {
+			diffs = append(diffs, fmt.Sprintf("tick %d: got %v, want %v", i, g, w))
+		}
+	}
+	return diffs
+}
+
+func isMovePacket(name string) bool {
+	switch name {
+	case "C2SMovePlayerPos", "C2SMovePlayerPosRot", "C2SMovePlayerRot", "C2SMovePlayerStatusOnly":
+		return true
+	}
+	return false
+}
+
+func count(names []string, name string) int {
+	n := 0
+	for _, s := range names {
+		if s == name {
+			n++
+		}
+	}
+	return n
+}
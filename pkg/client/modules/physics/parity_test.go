@@ -0,0 +1,44 @@
+package physics
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTickTraceCheck(t *testing.T) {
+	idle := TickRecord{Packets: []string{"C2SMovePlayerStatusOnly", "C2SClientTickEnd"}, Loaded: true}
+	pos := TickRecord{Packets: []string{"C2SMovePlayerPos", "C2SClientTickEnd"}, Loaded: true}
+	quiet := []string{"C2SClientTickEnd"}
+
+	// dying, respawning a minute later and standing on the death screen
+	// meanwhile sends no position, which isn't a missing reminder
+	trace := TickTrace{pos}
+	for range 3 * PositionReminderMax {
+		trace = append(trace, TickRecord{Packets: quiet, Loaded: true, Dead: true})
+	}
+	trace = append(trace, pos, idle)
+	if issues := trace.Check(); len(issues) != 0 {
+		t.Fatalf("death ticks flagged: %v", issues)
+	}
+
+	camera := TickTrace{pos}
+	for range 2 * PositionReminderMax {
+		camera = append(camera, TickRecord{Packets: []string{"C2SPlayerInput", "C2SClientTickEnd"}, Loaded: true, Camera: true})
+	}
+	if issues := camera.Check(); len(issues) != 0 {
+		t.Fatalf("camera ticks flagged: %v", issues)
+	}
+
+	moving := TickTrace{{Packets: []string{"C2SPlayerInput", "C2SMovePlayerPos", "C2SClientTickEnd"}, Loaded: true, Dead: true}}
+	if issues := moving.Check(); len(issues) != 1 || !strings.Contains(issues[0], "dead") {
+		t.Fatalf("got %v, want movement while dead", issues)
+	}
+
+	var silent TickTrace
+	for range PositionReminderMax {
+		silent = append(silent, TickRecord{Packets: quiet, Loaded: true})
+	}
+	if issues := silent.Check(); len(issues) != 1 || !strings.Contains(issues[0], "no position") {
+		t.Fatalf("got %v, want a missing reminder", issues)
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"math"
 	"sync"
-	"time"
 
 	"github.com/go-mclib/client/pkg/client"
 	"github.com/go-mclib/client/pkg/client/modules/collisions"
@@ -47,16 +46,43 @@ type Module struct {
 	lastSentInputFlags              uint8
 	positionReminder                int
 
+	// serializes input/position packets between the tick loop and HoldSneak
+	sendMu sync.Mutex
+
+	// vehicle the player rides (riding.go); a passenger doesn't move itself
+	ride         ride
+	onRideChange []func(state RideState, vehicle int32)
+
+	// packet recording for parity checks (StartTrace/StopTrace)
+	traceMu   sync.Mutex
+	tracing   bool
+	trace     TickTrace
+	traceTick []string
+
 	cancel context.CancelFunc
 
 	// damage tracking for knockback filtering
 	hasPendingDamage      bool
 	lastDamageEntityCause bool // true if the last damage had an entity source
 
+	// movement restrictions (restrictions.go)
+	restrictions        Restriction
+	portalCooldown      int32
+	onRestrictionChange []func(r Restriction)
+
+	// held position (HoldPosition)
+	HoldReleaseDistance float64
+	hold                hold
+	onHoldRelease       []func()
+
 	onTick []func()
+
+	// steps queued via Schedule, indexed by client.TickPhase
+	schedMu   sync.Mutex
+	scheduled [tickPhases][]scheduledSteps
 }
 
-func New() *Module { return &Module{} }
+func New() *Module { return &Module{HoldReleaseDistance: DefaultHoldReleaseDistance} }
 
 func (m *Module) Name() string { return ModuleName }
 
@@ -68,12 +94,17 @@ func (m *Module) Init(c *client.Client) {
 	if s != nil {
 		// start tick loop when player spawns
 		s.OnSpawn(func() {
+			m.setPortalCooldown(s.PortalCooldown())
 			m.startTickLoop()
 		})
+		s.OnRespawn(func() {
+			m.setPortalCooldown(s.PortalCooldown())
+		})
+		s.OnVehicleChange(m.handleVehicleChange)
 
 		// sync last-sent tracking after server teleport so sendPosition
 		// doesn't re-send a flying packet for the same position
-		s.OnPosition(func(x, y, z float64) {
+		s.OnPositionChange(func(x, y, z float64, cause self.PositionCause) {
 			m.lastSentX = x
 			m.lastSentY = y
 			m.lastSentZ = z
@@ -82,10 +113,32 @@ func (m *Module) Init(c *client.Client) {
 			m.lastSentPitch = pitch
 			m.lastSentOnGround = m.onGround
 			m.positionReminder = 0
+			m.placedAfterDismount()
+			switch cause {
+			case self.PositionCorrection, self.PositionDismount:
+				// a short hop: keep holding if still close
+				m.releaseIfDisplaced(x, y, z)
+			default:
+				// moved on purpose (or into another world): the held spot is stale
+				m.releaseHold(x, y, z)
+			}
 		})
 	}
 }
 
+// SetEnabled implements client.Toggler. While physics is disabled the
+// player isn't moved, but the tick loop keeps running: OnTick callbacks,
+// scheduled steps, position reminders and ClientTickEnd. Teleports still
+// reach the player, but knockback doesn't, so it starts again at rest.
+func (m *Module) SetEnabled(enabled bool) {
+	if !enabled {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.velX, m.velY, m.velZ = 0, 0, 0
+}
+
 func (m *Module) Reset() {
 	if m.cancel != nil {
 		m.cancel()
@@ -102,8 +155,13 @@ func (m *Module) Reset() {
 	m.forwardImpulse = 0
 	m.strafeImpulse = 0
 	m.jumping = false
+	m.ride = ride{}
+	m.hold = hold{}
+	m.restrictions = 0
+	m.portalCooldown = 0
 	m.mu.Unlock()
 	m.positionReminder = 0
+	m.failScheduled()
 }
 
 func From(c *client.Client) *Module {
@@ -151,9 +209,34 @@ func (m *Module) Input() (forward, strafe float64, jumping bool) {
 	return m.forwardImpulse, m.strafeImpulse, m.jumping
 }
 
+// HoldSneak engages sneaking and queues the input change immediately instead
+// of waiting for the next tick, so a packet queued right after it is handled
+// as a shift-click. The returned func restores the previous sneak state.
+// Implements client.SneakHolder.
+func (m *Module) HoldSneak() func() {
+	s := self.From(m.client)
+	if s == nil || s.Sneaking() {
+		return func() {}
+	}
+	m.setSneakingNow(s, true)
+	return func() { m.setSneakingNow(s, false) }
+}
+
+func (m *Module) setSneakingNow(s *self.Module, sneaking bool) {
+	m.sendMu.Lock()
+	defer m.sendMu.Unlock()
+	s.SetSneaking(sneaking)
+	m.sendInput(s)
+	m.sendSneakingIfNeeded(s)
+}
+
 // events
 
-func (m *Module) OnTick(cb func()) { m.onTick = append(m.onTick, cb) }
+// OnTick runs under the client watchdog, if one is set.
+func (m *Module) OnTick(cb func()) {
+	run := m.client.Watched("physics.OnTick")
+	m.onTick = append(m.onTick, func() { run(cb) })
+}
 
 // actions
 
@@ -177,6 +260,8 @@ func (m *Module) HandlePacket(pkt *jp.WirePacket) {
 		m.handleEntityMotion(pkt)
 	case packet_ids.S2CPlayerPositionID:
 		m.handleTeleport(pkt)
+	case packet_ids.S2CMoveVehicleID:
+		m.handleMoveVehicle(pkt)
 	}
 }
 
@@ -275,19 +360,11 @@ func (m *Module) startTickLoop() {
 	m.lastSentOnGround = m.onGround
 	m.positionReminder = 0
 
-	go func() {
-		ticker := time.NewTicker(TickDuration)
-		defer ticker.Stop()
-
-		for {
-			select {
-			case <-ctx.Done():
-				return
-			case <-ticker.C:
-				m.tick()
-			}
-		}
-	}()
+	clock := m.client.Clock
+	if clock == nil {
+		clock = client.RealClock{}
+	}
+	go clock.Run(ctx, m.tick)
 }
 
 func (m *Module) tick() {
@@ -297,9 +374,29 @@ func (m *Module) tick() {
 	if s == nil || w == nil || col == nil {
 		return
 	}
+	// sliced heavy work (see client.Submit) gets what's left of the tick
+	defer m.client.RunWork()
 
-	// an external controller handles movement and position — skip physics
+	scheduled := m.takeScheduled()
+
+	// an external controller handles movement and position — skip physics,
+	// but still run scheduled steps in phase order
 	if s.SuppressPositionEcho() {
+		for _, batches := range scheduled {
+			m.runScheduled(batches)
+		}
+		return
+	}
+
+	r := m.updateRestrictions(s)
+
+	// not loaded in yet after login/respawn: vanilla skips the player tick
+	// but Minecraft.tick still ends the tick
+	if r&RestrictNotLoaded != 0 {
+		for _, batches := range scheduled {
+			m.runScheduled(batches)
+		}
+		m.endTick(s)
 		return
 	}
 
@@ -311,21 +408,91 @@ func (m *Module) tick() {
 	for _, cb := range m.onTick {
 		cb()
 	}
+	m.runScheduled(scheduled[client.TickStart])
+
+	// disabled (see client.SetModuleEnabled): the player holds still, but
+	// the tick still ends as vanilla's does, with position reminders
+	if !m.client.ModuleEnabled(ModuleName) {
+		m.runScheduled(scheduled[client.TickBeforeSend])
+		if r&RestrictDead == 0 {
+			m.sendMu.Lock()
+			m.sendInput(s)
+			m.sendPosition(s)
+			m.sendMu.Unlock()
+		}
+		m.runScheduled(scheduled[client.TickAfterSend])
+		m.endTick(s)
+		return
+	}
+
+	m.applyHold(s)
+
+	// dead, frozen or spectating players don't move (see Restriction)
+	m.applyRestrictions(s, r)
+
+	// spectators hold still; while the camera is attached to another entity
+	// vanilla sends input but no position, which the server would ignore
+	if r&(RestrictSpectator|RestrictCamera) != 0 {
+		m.mu.Lock()
+		m.velX, m.velY, m.velZ = 0, 0, 0
+		m.mu.Unlock()
+		m.runScheduled(scheduled[client.TickBeforeSend])
+		m.sendMu.Lock()
+		m.sendInput(s)
+		if r&RestrictCamera == 0 {
+			m.sendPosition(s)
+		}
+		m.sendMu.Unlock()
+		m.runScheduled(scheduled[client.TickAfterSend])
+		m.endTick(s)
+		return
+	}
+
+	// passengers are moved by their vehicle: vanilla LocalPlayer.tick sends
+	// input and a rotation packet instead of sendPosition, and the driver of
+	// a boat moves the boat and reports it with C2SMoveVehicle (riding.go)
+	switch state := m.RideState(); state {
+	case RidePassenger, RideControlling:
+		if state == RideControlling {
+			m.steerBoat(s, w, col)
+		}
+		m.followVehicle(s)
+		m.runScheduled(scheduled[client.TickBeforeSend])
+		m.sendMu.Lock()
+		m.sendInput(s)
+		m.sendRidingRotation(s)
+		if state == RideControlling {
+			m.sendVehicleMove()
+		}
+		m.sendMu.Unlock()
+		m.runScheduled(scheduled[client.TickAfterSend])
+		m.endTick(s)
+		return
+	case RideDismounting:
+		// the server places the player next to the vehicle; moving before
+		// that would be checked from where the player sat
+		m.waitDismount()
+		m.runScheduled(scheduled[client.TickBeforeSend])
+		m.sendMu.Lock()
+		m.sendInput(s)
+		m.sendMu.Unlock()
+		m.runScheduled(scheduled[client.TickAfterSend])
+		m.endTick(s)
+		return
+	}
 
 	x, y, z := s.Position()
 	yaw, _ := s.Rotation()
 
+	// hitbox height of the pose picked last tick (1.5 crouching, 0.6 swimming)
+	playerHeight := s.Height()
+
 	// apply fluid flow pushing (Entity.baseTick in vanilla, before aiStep)
-	m.applyFluidPushing(x, y, z, w)
+	m.applyFluidPushing(x, y, z, playerHeight, w)
+	updateSwimming(s, x, y, z, w)
 
-	// process inputs (LocalPlayer.modifyInput: 0.98 friction + sneaking + square normalization)
-	forwardImpulse, strafeImpulse := modifyInput(m.forwardImpulse, m.strafeImpulse, s.Sneaking())
-
-	// effective player height (1.5 when sneaking, 1.8 otherwise)
-	playerHeight := PlayerHeight
-	if s.Sneaking() {
-		playerHeight = PlayerSneakingHeight
-	}
+	// process inputs (LocalPlayer.modifyInput: 0.98 friction + item use + sneaking + square normalization)
+	forwardImpulse, strafeImpulse := modifyInput(m.forwardImpulse, m.strafeImpulse, r&RestrictUsingItem != 0, s.Sneaking())
 
 	// movement threshold zeroing (LivingEntity.aiStep lines 2917-2940)
 	// for players: zero horizontal velocity if magnitude² < 9e-6
@@ -407,14 +574,37 @@ func (m *Module) tick() {
 	// entity pushing
 	m.applyEntityPushing(newX, newY, newZ, playerHeight)
 
-	// send input state (vanilla: LocalPlayer.tick sends C2SPlayerInput before sendPosition)
-	m.sendInput(s)
+	// pick the pose for the next tick (Player.updatePlayerPose, end of tick)
+	s.UpdatePose(func(p self.Pose) bool {
+		h, _ := p.Dimensions()
+		return col.CanFitAt(newX, newY, newZ, PlayerWidth, h)
+	})
 
-	// send position (calls sendIsSprintingIfNeeded equivalent first, matching vanilla)
-	m.sendPosition(s)
+	m.runScheduled(scheduled[client.TickBeforeSend])
 
-	// tick end (vanilla: Minecraft.tick sends ClientTickEnd after all tick logic)
-	m.client.SendPacket(&packets.C2SClientTickEnd{})
+	// on the death screen the server expects no movement; don't fight it
+	if r&RestrictDead == 0 {
+		m.sendMu.Lock()
+		// send input state (vanilla: LocalPlayer.tick sends C2SPlayerInput before sendPosition)
+		m.sendInput(s)
+
+		// send position (calls sendIsSprintingIfNeeded equivalent first, matching vanilla)
+		m.sendPosition(s)
+		m.sendMu.Unlock()
+	}
+
+	m.runScheduled(scheduled[client.TickAfterSend])
+
+	m.endTick(s)
+}
+
+// endTick sends ClientTickEnd (vanilla: Minecraft.tick sends it after all tick logic).
+func (m *Module) endTick(s *self.Module) {
+	m.send(&packets.C2SClientTickEnd{})
+	m.endTraceTick(s)
+	if ents := entities.From(m.client); ents != nil && m.client.ModuleEnabled(entities.ModuleName) {
+		ents.TickRanges()
+	}
 }
 
 // applyAirInputScaled adds movement input to velocity (pre-collision) with pre-scaled impulses.
@@ -569,9 +759,10 @@ func notEqual(a, b float64) bool {
 
 // modifyInput processes raw movement input matching vanilla LocalPlayer.modifyInput:
 // 1. scale by InputFriction (0.98)
-// 2. scale by SneakingSpeedFactor if sneaking
-// 3. normalize diagonal to unit square distance (modifyInputSpeedForSquareMovement)
-func modifyInput(forward, strafe float64, sneaking bool) (float64, float64) {
+// 2. scale by self.UseItemSpeedFactor if using an item
+// 3. scale by SneakingSpeedFactor if sneaking
+// 4. normalize diagonal to unit square distance (modifyInputSpeedForSquareMovement)
+func modifyInput(forward, strafe float64, usingItem, sneaking bool) (float64, float64) {
 	if forward == 0 && strafe == 0 {
 		return 0, 0
 	}
@@ -579,6 +770,11 @@ func modifyInput(forward, strafe float64, sneaking bool) (float64, float64) {
 	forward *= InputFriction
 	strafe *= InputFriction
 
+	if usingItem {
+		forward *= float64(float32(self.UseItemSpeedFactor))
+		strafe *= float64(float32(self.UseItemSpeedFactor))
+	}
+
 	if sneaking {
 		forward *= SneakingSpeedFactor
 		strafe *= SneakingSpeedFactor
@@ -708,12 +904,28 @@ func (m *Module) sendInput(s *self.Module) {
 
 	if flags != m.lastSentInputFlags {
 		m.lastSentInputFlags = flags
-		m.client.SendPacket(&packets.C2SPlayerInput{
+		m.send(&packets.C2SPlayerInput{
 			Flags: ns.Uint8(flags),
 		})
 	}
 }
 
+// sendSneakingIfNeeded sends a sneaking state change.
+func (m *Module) sendSneakingIfNeeded(s *self.Module) {
+	sneaking := s.Sneaking()
+	if sneaking != m.lastSentSneaking {
+		m.lastSentSneaking = sneaking
+		actionID := ns.VarInt(1) // stop sneaking
+		if sneaking {
+			actionID = 0 // start sneaking
+		}
+		m.send(&packets.C2SPlayerCommand{
+			EntityId: ns.VarInt(s.EntityID()),
+			ActionId: actionID,
+		})
+	}
+}
+
 // sendPosition sends position/rotation packets following MC's LocalPlayer.sendPosition logic.
 // Sends sprint/sneak commands first (vanilla: sendIsSprintingIfNeeded is called inside sendPosition).
 func (m *Module) sendPosition(s *self.Module) {
@@ -725,25 +937,13 @@ func (m *Module) sendPosition(s *self.Module) {
 		if sprinting {
 			actionID = 3 // start sprinting
 		}
-		m.client.SendPacket(&packets.C2SPlayerCommand{
+		m.send(&packets.C2SPlayerCommand{
 			EntityId: ns.VarInt(s.EntityID()),
 			ActionId: actionID,
 		})
 	}
 
-	// send sneaking state change
-	sneaking := s.Sneaking()
-	if sneaking != m.lastSentSneaking {
-		m.lastSentSneaking = sneaking
-		actionID := ns.VarInt(1) // stop sneaking
-		if sneaking {
-			actionID = 0 // start sneaking
-		}
-		m.client.SendPacket(&packets.C2SPlayerCommand{
-			EntityId: ns.VarInt(s.EntityID()),
-			ActionId: actionID,
-		})
-	}
+	m.sendSneakingIfNeeded(s)
 
 	m.positionReminder++
 
@@ -765,23 +965,23 @@ func (m *Module) sendPosition(s *self.Module) {
 	}
 
 	if moved && rotated {
-		m.client.SendPacket(&packets.C2SMovePlayerPosRot{
+		m.send(&packets.C2SMovePlayerPosRot{
 			X: ns.Float64(x), FeetY: ns.Float64(y), Z: ns.Float64(z),
 			Yaw: ns.Float32(yaw), Pitch: ns.Float32(pitch),
 			Flags: flags,
 		})
 	} else if moved {
-		m.client.SendPacket(&packets.C2SMovePlayerPos{
+		m.send(&packets.C2SMovePlayerPos{
 			X: ns.Float64(x), FeetY: ns.Float64(y), Z: ns.Float64(z),
 			Flags: flags,
 		})
 	} else if rotated {
-		m.client.SendPacket(&packets.C2SMovePlayerRot{
+		m.send(&packets.C2SMovePlayerRot{
 			Yaw: ns.Float32(yaw), Pitch: ns.Float32(pitch),
 			Flags: flags,
 		})
 	} else if m.onGround != m.lastSentOnGround || m.horizontalCollision != m.lastSentHorizontalCollision {
-		m.client.SendPacket(&packets.C2SMovePlayerStatusOnly{
+		m.send(&packets.C2SMovePlayerStatusOnly{
 			Flags: flags,
 		})
 	}
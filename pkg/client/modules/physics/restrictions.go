@@ -0,0 +1,136 @@
+package physics
+
+import (
+	"strings"
+
+	"github.com/go-mclib/client/pkg/client/modules/self"
+)
+
+// Restriction is a set of states in which the server expects the player not
+// to move (or not to move freely). The physics tick honors them instead of
+// sending movement the server would reject or correct.
+type Restriction uint8
+
+const (
+	// RestrictNotLoaded: after login/respawn, until the client reports it has
+	// loaded the world. The player isn't ticked at all.
+	RestrictNotLoaded Restriction = 1 << iota
+	// RestrictDead: on the death screen, waiting for respawn. Input is zeroed
+	// and no input or position packets are sent.
+	RestrictDead
+	// RestrictFrozen: movement speed reduced to zero (e.g. slowness 255 as
+	// used by freeze plugins). Input and jumping are zeroed.
+	RestrictFrozen
+	// RestrictBlind: blindness; vanilla doesn't allow sprinting, so sprinting is stopped.
+	RestrictBlind
+	// RestrictPortalCooldown: just crossed a portal; the server ignores portal
+	// contact until the cooldown expires. Informational, movement is unaffected.
+	RestrictPortalCooldown
+	// RestrictSpectator: spectator mode. Spectators fly through blocks, which
+	// isn't simulated, so the player holds still where it is.
+	RestrictSpectator
+	// RestrictCamera: the camera is attached to another entity (S2CSetCamera).
+	// The server moves the player with it; like vanilla, which only moves the
+	// controlled camera, input is zeroed and no position is sent.
+	RestrictCamera
+	// RestrictUsingItem: eating, drinking, drawing a bow or blocking. Like
+	// vanilla, walking input is scaled by self.UseItemSpeedFactor and
+	// sprinting stops, except while swimming or riding.
+	RestrictUsingItem
+)
+
+var restrictionNames = []string{"not loaded", "dead", "frozen", "blind", "portal cooldown", "spectator", "camera", "using item"}
+
+func (r Restriction) String() string {
+	if r == 0 {
+		return "none"
+	}
+	var names []string
+	for i, name := range restrictionNames {
+		if r&(1<<i) != 0 {
+			names = append(names, name)
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+// Restrictions returns the movement restrictions in effect as of the last tick.
+func (m *Module) Restrictions() Restriction {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.restrictions
+}
+
+// OnRestrictionChange is called from the tick loop when the set of restrictions changes.
+func (m *Module) OnRestrictionChange(cb func(r Restriction)) {
+	m.onRestrictionChange = append(m.onRestrictionChange, cb)
+}
+
+func (m *Module) setPortalCooldown(ticks int32) {
+	m.mu.Lock()
+	m.portalCooldown = ticks
+	m.mu.Unlock()
+}
+
+// updateRestrictions recomputes the restrictions for this tick.
+func (m *Module) updateRestrictions(s *self.Module) Restriction {
+	var r Restriction
+	if !s.Loaded() {
+		r |= RestrictNotLoaded
+	}
+	if s.Health() <= 0 {
+		r |= RestrictDead
+	}
+	if m.getEffectiveSpeed(s) <= 0 {
+		r |= RestrictFrozen
+	}
+	if s.HasEffect(effectBlindness) {
+		r |= RestrictBlind
+	}
+	if s.Spectator() {
+		r |= RestrictSpectator
+	}
+	if _, ok := s.SpectatingEntity(); ok {
+		r |= RestrictCamera
+	}
+	if _, ok := s.UsingItem(); ok {
+		r |= RestrictUsingItem
+	}
+
+	m.mu.Lock()
+	if m.portalCooldown > 0 {
+		r |= RestrictPortalCooldown
+		if s.Loaded() {
+			m.portalCooldown--
+		}
+	}
+	changed := r != m.restrictions
+	m.restrictions = r
+	m.mu.Unlock()
+
+	if changed {
+		for _, cb := range m.onRestrictionChange {
+			cb(r)
+		}
+	}
+	return r
+}
+
+// applyRestrictions adjusts input for the restrictions in effect.
+func (m *Module) applyRestrictions(s *self.Module, r Restriction) {
+	if r&(RestrictDead|RestrictFrozen|RestrictSpectator|RestrictCamera) != 0 {
+		// LivingEntity.aiStep: isImmobile zeroes input; zero speed leaves nothing to walk with
+		m.mu.Lock()
+		m.forwardImpulse = 0
+		m.strafeImpulse = 0
+		m.jumping = false
+		m.mu.Unlock()
+	}
+	if r&(RestrictBlind|RestrictFrozen) != 0 && s.Sprinting() {
+		s.SetSprinting(false)
+	}
+	// LocalPlayer.shouldStopRunSprinting
+	if r&RestrictUsingItem != 0 && s.Sprinting() && !s.Swimming() && s.Vehicle() < 0 {
+		s.SetSprinting(false)
+	}
+}
@@ -0,0 +1,404 @@
+package physics
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/go-mclib/client/pkg/client/modules/collisions"
+	"github.com/go-mclib/client/pkg/client/modules/entities"
+	"github.com/go-mclib/client/pkg/client/modules/self"
+	"github.com/go-mclib/client/pkg/client/modules/world"
+	"github.com/go-mclib/data/pkg/data/blocks"
+	"github.com/go-mclib/data/pkg/packets"
+	jp "github.com/go-mclib/protocol/java_protocol"
+	ns "github.com/go-mclib/protocol/java_protocol/net_structures"
+)
+
+// RideState is how the player moves while riding (see Module.RideState).
+type RideState int
+
+const (
+	RideNone        RideState = iota // not riding: physics moves the player
+	RidePassenger                    // moved by a vehicle the server simulates (minecarts, a boat's back seat)
+	RideControlling                  // driving a boat: the client moves it and reports it with C2SMoveVehicle
+	RideDismounting                  // left the vehicle, waiting for the server to place the player
+)
+
+func (r RideState) String() string {
+	switch r {
+	case RideNone:
+		return "none"
+	case RidePassenger:
+		return "passenger"
+	case RideControlling:
+		return "controlling"
+	case RideDismounting:
+		return "dismounting"
+	}
+	return fmt.Sprintf("RideState(%d)", int(r))
+}
+
+// boat physics, as in vanilla AbstractBoat
+const (
+	BoatWidth  = 1.375
+	BoatHeight = 0.5625
+
+	boatGravity       = 0.04
+	boatForward       = 0.04
+	boatBackward      = 0.005
+	boatTurnBoost     = 0.005 // turning on the spot still moves a bit
+	boatTurnSpeed     = 1.0   // degrees per tick, accumulated
+	boatWaterFriction = 0.9
+	boatUnderFriction = 0.45
+	boatBuoyancy      = 0.06153846
+	boatBuoyancyDrag  = 0.75
+)
+
+// playerVehicleAttachment is how far above the player's feet it sits on a
+// vehicle (vanilla Player.DEFAULT_VEHICLE_ATTACHMENT).
+const playerVehicleAttachment = 0.6
+
+// dismountTimeout is how many ticks to wait for the server to place the
+// player after dismounting before moving on its own again.
+const dismountTimeout = 10
+
+// ride is the riding state machine: the self module's vehicle tracking (see
+// self.OnVehicleChange) moves it between none, passenger or controlling and
+// dismounting, and the position sync after a dismount (or dismountTimeout)
+// back to none.
+type ride struct {
+	state   RideState
+	vehicle int32
+	seat    int    // index among the vehicle's passengers, 0 drives
+	seats   int    // number of passengers
+	kind    string // vehicle type name
+	wait    int    // ticks left in RideDismounting
+
+	// the driven boat (RideControlling)
+	x, y, z          float64
+	yaw              float64
+	velX, velY, velZ float64
+	deltaRot         float64
+	onGround         bool
+	paddleL, paddleR bool
+}
+
+// RideState returns the riding state.
+func (m *Module) RideState() RideState {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.ride.state
+}
+
+// VehiclePosition returns the position of the boat the player drives, as
+// simulated by the client. ok is false unless RideState is RideControlling.
+func (m *Module) VehiclePosition() (x, y, z float64, ok bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	r := &m.ride
+	return r.x, r.y, r.z, r.state == RideControlling
+}
+
+// OnRideStateChange registers a callback for riding state changes, with the
+// vehicle involved.
+func (m *Module) OnRideStateChange(cb func(state RideState, vehicle int32)) {
+	m.onRideChange = append(m.onRideChange, cb)
+}
+
+// setRideState moves the state machine and fires the callbacks. Must be
+// called without mu held.
+func (m *Module) setRideState(update func(r *ride)) {
+	m.mu.Lock()
+	before := m.ride.state
+	update(&m.ride)
+	state, vehicle := m.ride.state, m.ride.vehicle
+	m.mu.Unlock()
+	if state == before {
+		return
+	}
+	for _, cb := range m.onRideChange {
+		cb(state, vehicle)
+	}
+}
+
+// handleVehicleChange moves the state machine as the player mounts, changes
+// seats or leaves a vehicle (see self.OnVehicleChange).
+func (m *Module) handleVehicleChange(vehicleID int32, seat, seats int) {
+	var vehicle *entities.Entity
+	if ents := entities.From(m.client); ents != nil {
+		vehicle = ents.GetEntity(vehicleID)
+	}
+
+	m.setRideState(func(r *ride) {
+		switch {
+		case seat >= 0:
+			mounted := r.state != RidePassenger && r.state != RideControlling || r.vehicle != vehicleID
+			r.vehicle, r.seat, r.seats = vehicleID, seat, seats
+			r.state, r.kind = RidePassenger, ""
+			if vehicle != nil {
+				r.kind = vehicle.TypeName
+				if isBoat(r.kind) && seat == 0 {
+					r.state = RideControlling
+				}
+			}
+			if r.state == RideControlling && mounted {
+				r.x, r.y, r.z, r.yaw = vehicle.X, vehicle.Y, vehicle.Z, float64(vehicle.Yaw)
+				r.velX, r.velY, r.velZ, r.deltaRot = 0, 0, 0, 0
+			}
+		case (r.state == RidePassenger || r.state == RideControlling) && r.vehicle == vehicleID:
+			r.state, r.wait = RideDismounting, dismountTimeout
+		}
+	})
+}
+
+// handleMoveVehicle applies the server's correction of the driven boat.
+func (m *Module) handleMoveVehicle(pkt *jp.WirePacket) {
+	var d packets.S2CMoveVehicle
+	if err := pkt.ReadInto(&d); err != nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	r := &m.ride
+	if r.state != RideControlling {
+		return
+	}
+	r.x, r.y, r.z, r.yaw = float64(d.X), float64(d.Y), float64(d.Z), float64(d.Yaw)
+	r.velX, r.velY, r.velZ = 0, 0, 0
+}
+
+// placedAfterDismount ends RideDismounting on the server's position sync.
+func (m *Module) placedAfterDismount() {
+	m.setRideState(func(r *ride) {
+		if r.state == RideDismounting {
+			*r = ride{}
+		}
+	})
+}
+
+// waitDismount counts down RideDismounting, for servers that don't place
+// the player (it stays where it sat).
+func (m *Module) waitDismount() {
+	m.setRideState(func(r *ride) {
+		if r.wait--; r.wait <= 0 {
+			*r = ride{}
+		}
+	})
+}
+
+// followVehicle puts the player on its seat (vanilla Entity.positionRider):
+// the vehicle's passenger attachment point minus the player's vehicle
+// attachment point. A passenger sees the vehicle where the server last
+// reported it; a driver where it simulated it.
+func (m *Module) followVehicle(s *self.Module) {
+	m.mu.RLock()
+	r := m.ride
+	m.mu.RUnlock()
+
+	x, y, z, yaw := r.x, r.y, r.z, r.yaw
+	height := BoatHeight
+	if r.state != RideControlling {
+		ents := entities.From(m.client)
+		if ents == nil {
+			return
+		}
+		e := ents.GetEntity(r.vehicle)
+		if e == nil {
+			return
+		}
+		x, y, z, yaw, height = e.X, e.Y, e.Z, float64(e.Yaw), e.Height
+	}
+
+	dy, forward := seatOffset(r.kind, height, r.seat, r.seats)
+	rad := yaw * math.Pi / 180
+	s.SetPosition(x-math.Sin(rad)*forward, y+dy-playerVehicleAttachment, z+math.Cos(rad)*forward)
+}
+
+// seatOffset returns the passenger attachment point of a vehicle of the
+// given type and height: how high above the vehicle's feet and how far ahead
+// of its center seat sits. Vehicles other than boats and minecarts seat
+// riders on top.
+func seatOffset(kind string, height float64, seat, seats int) (dy, forward float64) {
+	switch {
+	case strings.HasSuffix(kind, "minecart"):
+		return 0.1875, 0
+	case !isBoat(kind):
+		return height, 0
+	case strings.HasSuffix(kind, "_raft"):
+		dy = height * 0.8888889
+	default:
+		dy = height / 3
+	}
+	switch {
+	case seats > 1 && seat == 0:
+		forward = 0.2
+	case seats > 1:
+		forward = -0.6
+	case strings.Contains(kind, "chest"):
+		forward = 0.15
+	}
+	return dy, forward
+}
+
+// steerBoat runs one tick of the driven boat (vanilla AbstractBoat.tick on
+// the client: floatBoat, controlBoat, move) and turns the driver with it.
+func (m *Module) steerBoat(s *self.Module, w *world.Module, col *collisions.Module) {
+	m.mu.Lock()
+	r := &m.ride
+	forward, strafe := m.forwardImpulse, m.strafeImpulse
+
+	// floatBoat
+	status, waterLevel := boatStatus(w, r.x, r.y, r.z)
+	gravity := -boatGravity
+	friction := boatWaterFriction
+	buoyancy := 0.0
+	switch status {
+	case boatInWater:
+		buoyancy = (waterLevel - r.y) / BoatHeight
+	case boatUnderWater:
+		buoyancy, friction = 0.01, boatUnderFriction
+	case boatOnLand:
+		// a driver halves the ground friction
+		friction = GetBlockFriction(w.GetBlock(int(math.Floor(r.x)), int(math.Floor(r.y-0.001)), int(math.Floor(r.z)))) / 2
+	}
+	r.velX *= friction
+	r.velY += gravity
+	r.velZ *= friction
+	r.deltaRot *= friction
+	if buoyancy > 0 {
+		r.velY = (r.velY + buoyancy*boatBuoyancy) * boatBuoyancyDrag
+	}
+
+	// controlBoat: strafe turns, forward and back paddle
+	left, right := strafe > 0, strafe < 0
+	up, down := forward > 0, forward < 0
+	f := 0.0
+	if left {
+		r.deltaRot -= boatTurnSpeed
+	}
+	if right {
+		r.deltaRot += boatTurnSpeed
+	}
+	if right != left && !up && !down {
+		f += boatTurnBoost
+	}
+	r.yaw += r.deltaRot
+	if up {
+		f += boatForward
+	}
+	if down {
+		f -= boatBackward
+	}
+	rad := r.yaw * math.Pi / 180
+	r.velX += math.Sin(-rad) * f
+	r.velZ += math.Cos(rad) * f
+	r.paddleL, r.paddleR = right && !left || up, left && !right || up
+
+	// move
+	adjX, adjY, adjZ, _, vCol := col.CollideMovement(r.x, r.y, r.z, BoatWidth, BoatHeight, r.velX, r.velY, r.velZ)
+	if notEqual(r.velX, adjX) {
+		r.velX = 0
+	}
+	if notEqual(r.velZ, adjZ) {
+		r.velZ = 0
+	}
+	r.onGround = vCol && r.velY < 0
+	if vCol {
+		r.velY = 0
+	}
+	r.x, r.y, r.z = r.x+adjX, r.y+adjY, r.z+adjZ
+	deltaRot := r.deltaRot
+	m.mu.Unlock()
+
+	// the driver turns with the boat (vanilla AbstractBoat.positionRider)
+	yaw, pitch := s.Rotation()
+	s.SetRotation(float64(yaw)+deltaRot, float64(pitch))
+}
+
+// sendVehicleMove reports the driven boat: the paddles (sent from the boat's
+// tick in vanilla) and its position (LocalPlayer.tick, every tick).
+func (m *Module) sendVehicleMove() {
+	m.mu.RLock()
+	r := m.ride
+	m.mu.RUnlock()
+	m.send(&packets.C2SPaddleBoat{
+		LeftPaddleTurning:  ns.Boolean(r.paddleL),
+		RightPaddleTurning: ns.Boolean(r.paddleR),
+	})
+	m.send(&packets.C2SMoveVehicle{
+		X: ns.Float64(r.x), Y: ns.Float64(r.y), Z: ns.Float64(r.z),
+		Yaw:      ns.Float32(r.yaw),
+		OnGround: ns.Boolean(r.onGround),
+	})
+}
+
+type boatState int
+
+const (
+	boatInAir boatState = iota
+	boatInWater
+	boatUnderWater
+	boatOnLand
+)
+
+// boatStatus classifies where a boat at (x, y, z) is (vanilla
+// AbstractBoat.getStatus) and returns the water surface height when it
+// floats. Only the block columns under the boat's center and corners are
+// checked.
+func boatStatus(w *world.Module, x, y, z float64) (boatState, float64) {
+	half := BoatWidth / 2
+	corners := [5][2]float64{{0, 0}, {-half, -half}, {-half, half}, {half, -half}, {half, half}}
+
+	// under water: water over the top of the boat
+	for _, c := range corners {
+		bx, bz := int(math.Floor(x+c[0])), int(math.Floor(z+c[1]))
+		top := y + BoatHeight
+		if surface, ok := waterSurface(w, bx, int(math.Floor(top)), bz); ok && surface > top+0.001 {
+			return boatUnderWater, 0
+		}
+	}
+
+	level, inWater := 0.0, false
+	for _, c := range corners {
+		bx, bz := int(math.Floor(x+c[0])), int(math.Floor(z+c[1]))
+		for by := int(math.Floor(y)); by <= int(math.Floor(y+0.001)); by++ {
+			if surface, ok := waterSurface(w, bx, by, bz); ok && surface >= y {
+				level, inWater = max(level, surface), true
+			}
+		}
+	}
+	if inWater {
+		return boatInWater, level
+	}
+
+	below := w.GetBlock(int(math.Floor(x)), int(math.Floor(y-0.001)), int(math.Floor(z)))
+	if below != 0 && !IsFluid(below) {
+		return boatOnLand, 0
+	}
+	return boatInAir, 0
+}
+
+// waterSurface returns the height of the water surface in a block, if it
+// holds water.
+func waterSurface(w *world.Module, bx, by, bz int) (float64, bool) {
+	state := w.GetBlock(bx, by, bz)
+	if !IsWater(state) {
+		return 0, false
+	}
+	if IsWater(w.GetBlock(bx, by+1, bz)) {
+		return float64(by + 1), true
+	}
+	_, props := blocks.StateProperties(int(state))
+	level := parseLevel(props["level"])
+	if level >= 8 {
+		level = 0 // falling water fills the block
+	}
+	return float64(by) + float64(fluidAmount(level))/9, true
+}
+
+// isBoat reports whether an entity type is a boat or raft, which the
+// first passenger drives.
+func isBoat(typeName string) bool {
+	return strings.HasSuffix(typeName, "_boat") || strings.HasSuffix(typeName, "_raft")
+}
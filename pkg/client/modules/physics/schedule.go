@@ -0,0 +1,70 @@
+package physics
+
+import (
+	"errors"
+
+	"github.com/go-mclib/client/pkg/client"
+)
+
+// tickPhases is the number of client.TickPhase values.
+const tickPhases = int(client.TickAfterSend) + 1
+
+var errTickLoopStopped = errors.New("physics tick loop stopped")
+
+// scheduledSteps is one Schedule call: steps run in order, done gets the result.
+type scheduledSteps struct {
+	steps []func() error
+	done  chan error
+}
+
+// Schedule runs steps in order at the given phase of the next tick. Steps
+// scheduled while a tick is running go to the tick after it, so e.g. a
+// rotation set before scheduling at TickAfterSend is always sent first.
+// The returned channel receives the first step error, or nil.
+func (m *Module) Schedule(phase client.TickPhase, steps ...func() error) <-chan error {
+	done := make(chan error, 1)
+	if int(phase) >= tickPhases {
+		done <- errors.New("invalid tick phase")
+		return done
+	}
+	m.schedMu.Lock()
+	m.scheduled[phase] = append(m.scheduled[phase], scheduledSteps{steps: steps, done: done})
+	m.schedMu.Unlock()
+	return done
+}
+
+// takeScheduled removes and returns everything scheduled for the current tick.
+func (m *Module) takeScheduled() [tickPhases][]scheduledSteps {
+	m.schedMu.Lock()
+	defer m.schedMu.Unlock()
+	batches := m.scheduled
+	m.scheduled = [tickPhases][]scheduledSteps{}
+	return batches
+}
+
+// runScheduled runs the batches for one phase, after flushing queued packets
+// so that steps writing directly stay ordered behind them.
+func (m *Module) runScheduled(batches []scheduledSteps) {
+	if len(batches) == 0 {
+		return
+	}
+	m.client.FlushPackets()
+	for _, b := range batches {
+		var err error
+		for _, step := range b.steps {
+			if err = step(); err != nil {
+				break
+			}
+		}
+		b.done <- err
+	}
+}
+
+// failScheduled fails every pending batch (tick loop stopped).
+func (m *Module) failScheduled() {
+	for _, batches := range m.takeScheduled() {
+		for _, b := range batches {
+			b.done <- errTickLoopStopped
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package protocol
+
+import (
+	"sync"
+	"time"
+
+	"github.com/go-mclib/data/pkg/data/packet_ids"
+	"github.com/go-mclib/data/pkg/packets"
+	jp "github.com/go-mclib/protocol/java_protocol"
+	ns "github.com/go-mclib/protocol/java_protocol/net_structures"
+)
+
+// pingTimeout gives up on a ping that wasn't answered and sends a new one.
+const pingTimeout = 30 * time.Second
+
+// latency measures the round trip with ping requests, like the vanilla debug
+// ping chart (which only pings while the F3 network graph is open, hence
+// opt-in, see PingInterval). The estimate is smoothed like TCP's: rtt moves an eighth of the
+// way towards each sample and jitter a quarter of the way towards its
+// deviation.
+type latency struct {
+	mu      sync.Mutex
+	sent    time.Time // outstanding ping, zero if none
+	last    time.Time // when the last ping was sent
+	rtt     time.Duration
+	jitter  time.Duration
+	samples int
+	fresh   bool // a sample came in that OnLatency callbacks haven't seen
+}
+
+// Latency returns the smoothed round trip time to the server and how much it
+// varies. ok is false until the first ping was answered, so always while
+// PingInterval is 0.
+func (m *Module) Latency() (rtt, jitter time.Duration, ok bool) {
+	m.latency.mu.Lock()
+	defer m.latency.mu.Unlock()
+	return m.latency.rtt, m.latency.jitter, m.latency.samples > 0
+}
+
+// OnLatency registers a callback for each answered ping, with the updated
+// estimate (see Latency). Callbacks run in dispatch order, not on the read
+// goroutine that times the pong.
+func (m *Module) OnLatency(cb func(rtt, jitter time.Duration)) {
+	m.onLatency = append(m.onLatency, cb)
+}
+
+// measureLatency runs on the read goroutine for every play packet: it
+// piggybacks a ping request on incoming traffic when one is due, and times
+// the pong as soon as it's read, before dispatch can delay it. The callbacks
+// run when the pong is dispatched (see latencyDispatched).
+func (m *Module) measureLatency(pkt *jp.WirePacket) {
+	now := time.Now()
+	l := &m.latency
+	if pkt.PacketID == packet_ids.S2CPongResponsePlayID {
+		var d packets.S2CPongResponsePlay
+		if err := pkt.ReadInto(&d); err != nil {
+			return
+		}
+		l.mu.Lock()
+		if l.sent.IsZero() || int64(d.Payload) != l.sent.UnixNano() {
+			// a pong to someone else's ping
+			l.mu.Unlock()
+			return
+		}
+		sample := now.Sub(l.sent)
+		l.sent = time.Time{}
+		if l.samples == 0 {
+			l.rtt, l.jitter = sample, sample/2
+		} else {
+			dev := sample - l.rtt
+			if dev < 0 {
+				dev = -dev
+			}
+			l.jitter += (dev - l.jitter) / 4
+			l.rtt += (sample - l.rtt) / 8
+		}
+		l.samples++
+		l.fresh = true
+		l.mu.Unlock()
+		return
+	}
+	if m.PingInterval <= 0 {
+		return
+	}
+
+	l.mu.Lock()
+	due := now.Sub(l.last) >= m.PingInterval && (l.sent.IsZero() || now.Sub(l.sent) >= pingTimeout)
+	if due {
+		l.sent, l.last = now, now
+	}
+	l.mu.Unlock()
+	if due {
+		_ = m.client.WritePacket(&packets.C2SPingRequestPlay{Payload: ns.Int64(now.UnixNano())})
+	}
+}
+
+// latencyDispatched runs the OnLatency callbacks for a pong that measureLatency
+// took a sample from.
+func (m *Module) latencyDispatched() {
+	l := &m.latency
+	l.mu.Lock()
+	fresh, rtt, jitter := l.fresh, l.rtt, l.jitter
+	l.fresh = false
+	l.mu.Unlock()
+	if !fresh {
+		return
+	}
+	for _, cb := range m.onLatency {
+		cb(rtt, jitter)
+	}
+}
+
+func (l *latency) reset() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.sent, l.last = time.Time{}, time.Time{}
+	l.rtt, l.jitter, l.samples = 0, 0, 0
+	l.fresh = false
+}
@@ -14,7 +14,13 @@ import (
 
 const (
 	ModuleName      = "protocol"
-	ProtocolVersion = 775 // 26.1
+	ProtocolVersion = 775
+	// GameVersion is the release ProtocolVersion belongs to. scripts/scenario.sh
+	// reads it to start a matching test server.
+	GameVersion = "26.1"
+
+	// DefaultViewDistance is the view distance requested in client information.
+	DefaultViewDistance = 32
 )
 
 // Module drives the client through login -> configuration -> play.
@@ -25,6 +31,19 @@ type Module struct {
 	// as a disconnect instead of transitioning back to configuration.
 	TreatTransferAsDisconnect bool
 
+	// ViewDistance is the view distance (in chunks, 2-32) requested from the
+	// server. The server sends min(this, its own view distance), so lowering
+	// it reduces chunk traffic and memory per bot.
+	ViewDistance int
+
+	// PingInterval is how often the round trip to the server is measured
+	// (see Latency), 0 (the default) to not measure it. A vanilla client
+	// only pings while its network graph is open, so pinging is traffic an
+	// idle vanilla client doesn't send.
+	PingInterval time.Duration
+	latency      latency
+	onLatency    []func(rtt, jitter time.Duration)
+
 	// typed config-phase state
 	registryData []packets.S2CRegistryData
 	tags         *packets.S2CUpdateTagsConfiguration
@@ -33,7 +52,7 @@ type Module struct {
 }
 
 func New() *Module {
-	return &Module{}
+	return &Module{ViewDistance: DefaultViewDistance}
 }
 
 func (m *Module) Name() string { return ModuleName }
@@ -49,6 +68,7 @@ func (m *Module) Reset() {
 	m.tags = nil
 	m.featureFlags = nil
 	m.knownPacks = nil
+	m.latency.reset()
 }
 
 // From retrieves the protocol module from a client.
@@ -93,6 +113,30 @@ func (m *Module) HandlePacket(pkt *jp.WirePacket) {
 	}
 }
 
+// HandleUrgent answers play keepalives on the read goroutine, so a blocking
+// callback elsewhere doesn't get the player timed out, and measures latency
+// there for the same reason (see Latency). Pings stay in dispatch
+// order: anti-cheats use them to tell when preceding packets were applied.
+// Outside play, and on the switch back to configuration, the reader waits
+// for dispatch since state and encryption changes affect the next read.
+func (m *Module) HandleUrgent(pkt *jp.WirePacket) bool {
+	c := m.client
+	if c.State() != jp.StatePlay {
+		return true
+	}
+	m.measureLatency(pkt)
+	switch pkt.PacketID {
+	case packet_ids.S2CKeepAlivePlayID:
+		var d packets.S2CKeepAlivePlay
+		if err := pkt.ReadInto(&d); err == nil {
+			_ = c.WritePacket(&packets.C2SKeepAlivePlay{KeepAliveId: d.KeepAliveId})
+		}
+	case packet_ids.S2CStartConfigurationID:
+		return true
+	}
+	return false
+}
+
 func (m *Module) handleLogin(pkt *jp.WirePacket) {
 	c := m.client
 
@@ -109,7 +153,7 @@ func (m *Module) handleLogin(pkt *jp.WirePacket) {
 		} else {
 			c.Logger.Printf("login disconnect: %s", d.Reason)
 		}
-		c.Disconnect(false)
+		c.DisconnectWithReason(client.ClassifyDisconnect(d.Reason), d.Reason)
 	case packet_ids.S2CLoginFinishedID:
 		c.Logger.Println("login successful")
 		_ = c.WritePacket(&packets.C2SLoginAcknowledged{})
@@ -208,7 +252,7 @@ func (m *Module) handleConfiguration(pkt *jp.WirePacket) {
 			c.Logger.Println("failed to parse disconnect configuration data:", err)
 		}
 		c.Logger.Printf("disconnected during configuration: %s", d.Reason)
-		c.Disconnect(false)
+		c.DisconnectWithReason(client.ClassifyDisconnect(d.Reason), d.Reason)
 	case packet_ids.S2CFinishConfigurationID:
 		_ = c.WritePacket(&packets.C2SFinishConfiguration{})
 		c.SetState(jp.StatePlay)
@@ -257,11 +301,11 @@ func (m *Module) handlePlay(pkt *jp.WirePacket) {
 		if err := pkt.ReadInto(&d); err == nil {
 			c.Logger.Printf("disconnect: %s", d.Reason)
 		}
-		c.Disconnect(false)
+		c.DisconnectWithReason(client.ClassifyDisconnect(d.Reason), d.Reason)
 	case packet_ids.S2CStartConfigurationID:
 		if m.TreatTransferAsDisconnect {
 			c.Logger.Println("server transfer detected, treating as disconnect")
-			c.Disconnect(false)
+			c.DisconnectWithReason(client.DisconnectTransfer, ns.TextComponent{Text: "server transfer"})
 			return
 		}
 
@@ -272,23 +316,20 @@ func (m *Module) handlePlay(pkt *jp.WirePacket) {
 			c.Logger.Println("failed to send configuration_acknowledged:", err)
 		}
 		c.SetState(jp.StateConfiguration)
-	case packet_ids.S2CKeepAlivePlayID:
-		var d packets.S2CKeepAlivePlay
-		if err := pkt.ReadInto(&d); err == nil {
-			_ = c.WritePacket(&packets.C2SKeepAlivePlay{KeepAliveId: d.KeepAliveId})
-		}
 	case packet_ids.S2CPingPlayID:
 		var d packets.S2CPingPlay
 		if err := pkt.ReadInto(&d); err == nil {
 			_ = c.WritePacket(&packets.C2SPongPlay{Id: d.Id})
 		}
+	case packet_ids.S2CPongResponsePlayID:
+		m.latencyDispatched()
 	}
 }
 
 func (m *Module) sendClientInformation() {
 	_ = m.client.WritePacket(&packets.C2SClientInformationConfiguration{
 		Locale:              "en_us",
-		ViewDistance:        32,
+		ViewDistance:        ns.Int8(max(2, min(32, m.ViewDistance))),
 		ChatMode:            0,
 		ChatColors:          true,
 		DisplayedSkinParts:  0x7F,
@@ -304,11 +345,53 @@ func (m *Module) RegistryData() []packets.S2CRegistryData {
 	return m.registryData
 }
 
+// RegistryEntryName returns the name of entry id in a synced (data-driven)
+// registry such as "minecraft:jukebox_song", or "" if unknown. Ids are the
+// entries' order in the registry data.
+func (m *Module) RegistryEntryName(registry string, id int32) string {
+	for _, r := range m.registryData {
+		if string(r.RegistryId) != registry {
+			continue
+		}
+		if id < 0 || int(id) >= len(r.Entries) {
+			return ""
+		}
+		return string(r.Entries[id].EntryId)
+	}
+	return ""
+}
+
 // Tags returns the parsed tags received during configuration.
 func (m *Module) Tags() *packets.S2CUpdateTagsConfiguration {
 	return m.tags
 }
 
+// TagContains reports whether entry id of a synced registry is in a tag, e.g.
+// registry "minecraft:damage_type" and tag "minecraft:is_fire". False if the
+// tags weren't received.
+func (m *Module) TagContains(registry, tag string, id int32) bool {
+	if m.tags == nil {
+		return false
+	}
+	for _, r := range m.tags.ArrayOfTags {
+		if string(r.Registry) != registry {
+			continue
+		}
+		for _, t := range r.Tags {
+			if string(t.TagName) != tag {
+				continue
+			}
+			for _, e := range t.Entries {
+				if int32(e) == id {
+					return true
+				}
+			}
+			return false
+		}
+	}
+	return false
+}
+
 // FeatureFlags returns the feature flags received during configuration.
 func (m *Module) FeatureFlags() []ns.Identifier {
 	return m.featureFlags
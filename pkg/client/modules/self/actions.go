@@ -1,11 +1,11 @@
 package self
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"math"
 	"sync/atomic"
-	"time"
 
 	"github.com/go-mclib/client/pkg/client/modules/inventory"
 	"github.com/go-mclib/data/pkg/packets"
@@ -39,7 +39,8 @@ func (m *Module) MoveRelative(dx, dy, dz float64, onGround, pushingAgainstWall b
 // LookAt sets yaw/pitch to face the given world position.
 func (m *Module) LookAt(x, y, z float64) {
 	m.mu.Lock()
-	yaw, pitch := WorldPosToYawPitch(m.x, m.y+EyeHeight, m.z, x, y, z)
+	_, eye := m.pose.Dimensions()
+	yaw, pitch := WorldPosToYawPitch(m.x, m.y+eye, m.z, x, y, z)
 	m.yaw = float32(yaw)
 	m.pitch = float32(pitch)
 	m.mu.Unlock()
@@ -90,6 +91,9 @@ func (m *Module) Use(hand int8) error {
 	return m.UseAt(hand, yaw, pitch)
 }
 
+// eatTimeoutTicks bounds how long Eat waits for the food level to change.
+const eatTimeoutTicks = 80
+
 // Eat finds a food item from the given list, holds it, and eats it.
 // Blocks until the food level changes or times out.
 func (m *Module) Eat(foodItemIDs []int32) error {
@@ -126,7 +130,11 @@ func (m *Module) Eat(foodItemIDs []int32) error {
 		return fmt.Errorf("select slot: %w", err)
 	}
 	defer inv.SetHeldSlot(prevSlot)
-	time.Sleep(50 * time.Millisecond)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := m.client.WaitTicks(ctx, 1); err != nil {
+		return err
+	}
 
 	// one-shot callback to detect food change (disarms itself after firing)
 	done := make(chan struct{}, 1)
@@ -145,15 +153,18 @@ func (m *Module) Eat(foodItemIDs []int32) error {
 		}
 	})
 
-	if err := m.Use(0); err != nil {
+	inv.ExpectCause(inventory.CauseConsumed)
+	if err := m.StartUsingItem(0); err != nil {
 		return fmt.Errorf("use item: %w", err)
 	}
+	defer m.stopUsingItem()
 
-	// wait for food level to change (eating takes ~1.6s in vanilla)
+	// wait for food level to change (eating takes 32 ticks in vanilla); the
+	// player may keep walking meanwhile, at UseItemSpeedFactor
 	select {
 	case <-done:
 		return nil
-	case <-time.After(4 * time.Second):
+	case <-m.client.AfterTicks(ctx, eatTimeoutTicks):
 		return errors.New("eating timed out")
 	}
 }
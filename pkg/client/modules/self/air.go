@@ -0,0 +1,41 @@
+package self
+
+// MaxAir is the player's full air supply, in ticks (15 seconds).
+const MaxAir = 300
+
+// DefaultLowAirThreshold is the air left, in ticks, at which OnLowAir fires:
+// 5 seconds, enough to swim a few blocks up. Drowning damage starts at 0.
+const DefaultLowAirThreshold = 100
+
+// Air returns the player's air supply in ticks, MaxAir when not underwater.
+// It follows the own entity's air supply metadata.
+func (m *Module) Air() int32 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.air
+}
+
+// LowOnAir reports whether the air supply is at or below LowAirThreshold.
+func (m *Module) LowOnAir() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.lowAir
+}
+
+// OnLowAir is called once when the air supply drops to LowAirThreshold,
+// and again only after it has risen back above it.
+func (m *Module) OnLowAir(cb func(air int32)) { m.onLowAir = append(m.onLowAir, cb) }
+
+// OnAirChange is called whenever the server updates the air supply, which
+// it does every tick while it drops or refills.
+func (m *Module) OnAirChange(cb func(air int32)) { m.onAirChange = append(m.onAirChange, cb) }
+
+// setAir records the air supply and reports whether it just ran low. Must be
+// called with mu held.
+func (m *Module) setAir(air int32) (ranLow bool) {
+	m.air = air
+	low := air <= m.LowAirThreshold
+	ranLow = low && !m.lowAir
+	m.lowAir = low
+	return ranLow
+}
@@ -0,0 +1,93 @@
+package self
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-mclib/client/pkg/client"
+	"github.com/go-mclib/data/pkg/packets"
+	jp "github.com/go-mclib/protocol/java_protocol"
+	ns "github.com/go-mclib/protocol/java_protocol/net_structures"
+)
+
+// GamemodeSpectator is the spectator game mode.
+const GamemodeSpectator = 3
+
+// Camera returns the entity the player views the world through: the
+// player's own ID, or the entity a spectator is attached to (S2CSetCamera).
+func (m *Module) Camera() int32 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.camera < 0 {
+		return m.entityID
+	}
+	return m.camera
+}
+
+// SpectatingEntity returns the entity the camera is attached to, if it isn't
+// the player. While attached, the server moves the player with that entity
+// and ignores its movement.
+func (m *Module) SpectatingEntity() (int32, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.camera, m.camera >= 0
+}
+
+// Spectator reports whether the player is in spectator mode.
+func (m *Module) Spectator() bool {
+	return m.Gamemode() == GamemodeSpectator
+}
+
+// OnCameraChange is called when the camera moves to another entity, with the
+// player's own ID when it returns to the player.
+func (m *Module) OnCameraChange(cb func(entityID int32)) {
+	m.onCameraChange = append(m.onCameraChange, cb)
+}
+
+// SpectatorTeleport asks to be teleported to an entity, the way a spectator
+// picks a player from the hotbar menu. Only spectators can do it.
+func (m *Module) SpectatorTeleport(target ns.UUID) error {
+	if !m.Spectator() {
+		return errors.New("not in spectator mode")
+	}
+	m.client.SendPacket(&packets.C2STeleportToEntity{TargetPlayer: target})
+	return nil
+}
+
+// ReleaseCamera detaches the camera from the spectated entity by sneaking for
+// a tick, which is how vanilla players leave it. Returns once the sneak was
+// released.
+func (m *Module) ReleaseCamera(ctx context.Context) error {
+	if _, ok := m.SpectatingEntity(); !ok {
+		return nil
+	}
+	holder, ok := m.client.Module("physics").(client.SneakHolder)
+	if !ok {
+		return errors.New("releasing the camera needs the physics module")
+	}
+	release := holder.HoldSneak()
+	defer release()
+	return m.client.WaitTicks(ctx, 1)
+}
+
+func (m *Module) handleSetCamera(pkt *jp.WirePacket) {
+	var d packets.S2CSetCamera
+	if err := pkt.ReadInto(&d); err != nil {
+		return
+	}
+	m.mu.Lock()
+	camera := int32(d.CameraId)
+	id := camera
+	if camera == m.entityID {
+		camera = -1
+	}
+	changed := camera != m.camera
+	m.camera = camera
+	m.mu.Unlock()
+
+	if changed {
+		for _, cb := range m.onCameraChange {
+			cb(id)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package self
+
+import (
+	"encoding/json"
+	"slices"
+)
+
+// StateDump is the self module's section of a client state dump (see
+// client.DumpState), encoded as JSON.
+type StateDump struct {
+	EntityID   int32
+	X, Y, Z    float64
+	Yaw, Pitch float32
+	Dimension  string
+	Gamemode   uint8
+	Health     float32
+	Food       int32
+	Saturation float32
+	Air        int32
+	Pose       Pose
+	Sneaking   bool
+	Sprinting  bool
+	Vehicle    int32
+	Dead       bool
+	Effects    []EffectInstance
+}
+
+// DumpState implements client.StateDumper.
+func (m *Module) DumpState() ([]byte, error) {
+	d := StateDump{
+		EntityID:   m.EntityID(),
+		Dimension:  m.DimensionName(),
+		Gamemode:   m.Gamemode(),
+		Health:     m.Health(),
+		Food:       m.Food(),
+		Saturation: m.FoodSaturation(),
+		Air:        m.Air(),
+		Pose:       m.Pose(),
+		Sneaking:   m.Sneaking(),
+		Sprinting:  m.Sprinting(),
+		Vehicle:    m.Vehicle(),
+		Dead:       m.IsDead(),
+	}
+	d.X, d.Y, d.Z = m.Position()
+	d.Yaw, d.Pitch = m.Rotation()
+
+	m.effectsMu.Lock()
+	for _, e := range m.activeEffects {
+		d.Effects = append(d.Effects, *e)
+	}
+	m.effectsMu.Unlock()
+	slices.SortFunc(d.Effects, func(a, b EffectInstance) int { return int(a.ID - b.ID) })
+
+	return json.Marshal(d)
+}
@@ -1,6 +1,7 @@
 package self
 
 import (
+	"github.com/go-mclib/data/pkg/data/registries"
 	"github.com/go-mclib/data/pkg/packets"
 	jp "github.com/go-mclib/protocol/java_protocol"
 )
@@ -31,6 +32,72 @@ func (m *Module) EffectAmplifier(effectID int32) int32 {
 	return e.Amplifier
 }
 
+var (
+	effectHaste          = registries.MobEffect.Get("minecraft:haste")
+	effectConduitPower   = registries.MobEffect.Get("minecraft:conduit_power")
+	effectWaterBreathing = registries.MobEffect.Get("minecraft:water_breathing")
+
+	// omens, in the order one turns into the next
+	effectBadOmen   = registries.MobEffect.Get("minecraft:bad_omen")
+	effectRaidOmen  = registries.MobEffect.Get("minecraft:raid_omen")
+	effectTrialOmen = registries.MobEffect.Get("minecraft:trial_omen")
+)
+
+// Effect returns a copy of the given active effect.
+func (m *Module) Effect(effectID int32) (EffectInstance, bool) {
+	m.effectsMu.Lock()
+	defer m.effectsMu.Unlock()
+	e, ok := m.activeEffects[effectID]
+	if !ok {
+		return EffectInstance{}, false
+	}
+	return *e, true
+}
+
+// ConduitPower returns the ticks of conduit power left, or 0 if the player
+// isn't near an active conduit. Conduits refresh the effect every 2 seconds
+// to 13 seconds (260 ticks), so it lapses 11 seconds after leaving the range
+// or the water.
+func (m *Module) ConduitPower() int32 {
+	e, ok := m.Effect(effectConduitPower)
+	if !ok {
+		return 0
+	}
+	return e.Duration
+}
+
+// Omen returns the omen the player carries, if any. Drinking an ominous
+// bottle gives bad omen, which turns into raid omen in a village (starting a
+// raid when it runs out) or into trial omen at a trial spawner, making the
+// spawners and vaults nearby ominous.
+func (m *Module) Omen() (EffectInstance, bool) {
+	for _, id := range []int32{effectTrialOmen, effectRaidOmen, effectBadOmen} {
+		if e, ok := m.Effect(id); ok {
+			return e, true
+		}
+	}
+	return EffectInstance{}, false
+}
+
+// HasTrialOmen reports whether trial spawners the player activates turn
+// ominous.
+func (m *Module) HasTrialOmen() bool {
+	return m.HasEffect(effectTrialOmen)
+}
+
+// CanBreatheUnderwater returns whether conduit power or water breathing keep
+// the player's air supply from dropping underwater.
+func (m *Module) CanBreatheUnderwater() bool {
+	return m.HasEffect(effectConduitPower) || m.HasEffect(effectWaterBreathing)
+}
+
+// DigSpeedAmplifier returns the mining speed bonus amplifier from haste or
+// conduit power (the higher one), or -1 if neither is active. Matches vanilla
+// MobEffectUtil.getDigSpeedAmplification.
+func (m *Module) DigSpeedAmplifier() int32 {
+	return max(m.EffectAmplifier(effectHaste), m.EffectAmplifier(effectConduitPower))
+}
+
 // TickEffects decrements durations and removes expired effects.
 // Matches vanilla MobEffectInstance.tickClient. Called once per tick by the physics module.
 func (m *Module) TickEffects() {
@@ -0,0 +1,232 @@
+package self
+
+import (
+	"fmt"
+
+	"github.com/go-mclib/data/pkg/data/entities"
+	"github.com/go-mclib/data/pkg/packets"
+	jp "github.com/go-mclib/protocol/java_protocol"
+	ns "github.com/go-mclib/protocol/java_protocol/net_structures"
+)
+
+// Pose is the player's pose, which sets its hitbox height and eye height.
+// Values match the protocol's pose enum (Entity metadata index 6).
+type Pose int32
+
+const (
+	PoseStanding   Pose = 0
+	PoseFallFlying Pose = 1 // elytra gliding
+	PoseSleeping   Pose = 2
+	PoseSwimming   Pose = 3 // also crawling under 1-block gaps
+	PoseSpinAttack Pose = 4 // riptide
+	PoseCrouching  Pose = 5
+	PoseDying      Pose = 7
+)
+
+func (p Pose) String() string {
+	switch p {
+	case PoseStanding:
+		return "standing"
+	case PoseFallFlying:
+		return "fall flying"
+	case PoseSleeping:
+		return "sleeping"
+	case PoseSwimming:
+		return "swimming"
+	case PoseSpinAttack:
+		return "spin attack"
+	case PoseCrouching:
+		return "crouching"
+	case PoseDying:
+		return "dying"
+	}
+	return fmt.Sprintf("Pose(%d)", int32(p))
+}
+
+// player dimensions per pose (vanilla Player.POSES)
+const (
+	PlayerHeight         = 1.8
+	PlayerCrouchHeight   = 1.5
+	PlayerCrouchEye      = 1.27
+	PlayerSwimHeight     = 0.6 // swimming, gliding, riptide
+	PlayerSwimEye        = 0.4
+	PlayerSleepingHeight = 0.2
+	PlayerSleepingEye    = 0.2
+)
+
+// Dimensions returns the player's hitbox height and eye height in pose p.
+func (p Pose) Dimensions() (height, eyeHeight float64) {
+	switch p {
+	case PoseCrouching:
+		return PlayerCrouchHeight, PlayerCrouchEye
+	case PoseSwimming, PoseFallFlying, PoseSpinAttack:
+		return PlayerSwimHeight, PlayerSwimEye
+	case PoseSleeping, PoseDying:
+		return PlayerSleepingHeight, PlayerSleepingEye
+	}
+	return PlayerHeight, EyeHeight
+}
+
+// Pose returns the player's current pose.
+func (m *Module) Pose() Pose {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.pose
+}
+
+// Height returns the height of the player's hitbox in its current pose.
+func (m *Module) Height() float64 {
+	h, _ := m.Pose().Dimensions()
+	return h
+}
+
+// CurrentEyeHeight returns the eye height in the current pose. EyeHeight is
+// the standing value; riding keeps it.
+func (m *Module) CurrentEyeHeight() float64 {
+	_, eye := m.Pose().Dimensions()
+	return eye
+}
+
+// EyePosition returns the point attacks, interactions and raycasts start from.
+func (m *Module) EyePosition() (x, y, z float64) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	_, eye := m.pose.Dimensions()
+	return m.x, m.y + eye, m.z
+}
+
+// Swimming reports whether the player is swimming (sprinting in water).
+func (m *Module) Swimming() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.swimming
+}
+
+// SetSwimming sets the swimming state (used by physics module).
+func (m *Module) SetSwimming(v bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.swimming = v
+}
+
+// FallFlying reports whether the player is gliding with an elytra.
+func (m *Module) FallFlying() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.fallFlying
+}
+
+// UpdatePose picks the pose for the tick, like vanilla
+// Player.updatePlayerPose: the pose the player's state asks for if the hitbox
+// fits there, else crouching, else swimming (crawling). fits reports whether
+// the hitbox fits at the player's position in a pose. Used by physics module.
+func (m *Module) UpdatePose(fits func(Pose) bool) Pose {
+	m.mu.RLock()
+	desired := m.desiredPose()
+	current := m.pose
+	riding := m.vehicle >= 0
+	m.mu.RUnlock()
+
+	// vanilla keeps the pose when even crawling doesn't fit
+	if !fits(PoseSwimming) {
+		return current
+	}
+	pose := desired
+	switch {
+	case riding || fits(desired):
+	case fits(PoseCrouching):
+		pose = PoseCrouching
+	default:
+		pose = PoseSwimming
+	}
+	m.mu.Lock()
+	m.pose = pose
+	m.mu.Unlock()
+	return pose
+}
+
+// desiredPose is vanilla Player.getDesiredPose. Must be called with mu held.
+func (m *Module) desiredPose() Pose {
+	switch {
+	case m.health <= 0:
+		return PoseDying
+	case m.sleeping:
+		return PoseSleeping
+	case m.swimming:
+		return PoseSwimming
+	case m.fallFlying:
+		return PoseFallFlying
+	case m.spinAttack:
+		return PoseSpinAttack
+	case m.sneaking && m.abilityFlags&0x02 == 0: // not flying
+		return PoseCrouching
+	}
+	return PoseStanding
+}
+
+// resetPose stands the player up. Must be called with mu held.
+func (m *Module) resetPose() {
+	m.pose = PoseStanding
+	m.swimming = false
+	m.fallFlying = false
+	m.sleeping = false
+	m.spinAttack = false
+}
+
+// handleSetEntityData tracks the metadata of the player's own entity that
+// the pose depends on (gliding, sleeping, riptide and the server's pose),
+// whether an item is being used and the air supply.
+func (m *Module) handleSetEntityData(pkt *jp.WirePacket) {
+	var d packets.S2CSetEntityData
+	if err := pkt.ReadInto(&d); err != nil {
+		return
+	}
+	m.mu.Lock()
+	if int32(d.EntityId) != m.entityID {
+		m.mu.Unlock()
+		return
+	}
+	air, airSet, ranLow := int32(0), false, false
+	for _, entry := range d.Metadata {
+		if len(entry.Data) == 0 {
+			continue
+		}
+		switch entry.Index {
+		case entities.EntityIndexFlags:
+			m.fallFlying = entry.Data[0]&0x80 != 0
+		case entities.EntityIndexAirSupply:
+			v, err := ns.NewReader(entry.Data).ReadVarInt()
+			if err != nil {
+				continue
+			}
+			air, airSet = int32(v), true
+			ranLow = m.setAir(air)
+		case entities.LivingEntityIndexLivingFlags:
+			m.spinAttack = entry.Data[0]&0x04 != 0
+			m.usingItem = entry.Data[0]&0x01 != 0
+			m.useHand = int8(entry.Data[0]&0x02) >> 1 // set for the off hand
+		case entities.EntityIndexPose:
+			pose, err := ns.NewReader(entry.Data).ReadVarInt()
+			if err != nil {
+				continue
+			}
+			m.sleeping = Pose(pose) == PoseSleeping
+			// the server derives the pose the same way; take it until the
+			// next tick recomputes it (or for good, without physics)
+			m.pose = Pose(pose)
+		}
+	}
+	m.mu.Unlock()
+
+	if !airSet {
+		return
+	}
+	for _, cb := range m.onAirChange {
+		cb(air)
+	}
+	if ranLow {
+		for _, cb := range m.onLowAir {
+			cb(air)
+		}
+	}
+}
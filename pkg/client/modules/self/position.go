@@ -0,0 +1,161 @@
+package self
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	jp "github.com/go-mclib/protocol/java_protocol"
+	ns "github.com/go-mclib/protocol/java_protocol/net_structures"
+)
+
+// PositionCause is why the server set the player's position (S2CPlayerPosition),
+// derived from the packets around it.
+type PositionCause int
+
+const (
+	PositionInitial         PositionCause = iota // first sync after login
+	PositionCorrection                           // rubber-band: the server rejected a move
+	PositionTeleport                             // /tp, ender pearl, chorus fruit, plugin teleport
+	PositionRespawn                              // respawn after death
+	PositionDimensionChange                      // portal or teleport into another dimension
+	PositionDismount                             // left a vehicle
+)
+
+func (c PositionCause) String() string {
+	switch c {
+	case PositionInitial:
+		return "initial"
+	case PositionCorrection:
+		return "correction"
+	case PositionTeleport:
+		return "teleport"
+	case PositionRespawn:
+		return "respawn"
+	case PositionDimensionChange:
+		return "dimension change"
+	case PositionDismount:
+		return "dismount"
+	}
+	return fmt.Sprintf("PositionCause(%d)", int(c))
+}
+
+// correctionDistance is the farthest a rubber-band moves the player: vanilla
+// rejects moves longer than 10 blocks per tick and resets to the last
+// accepted position, so corrections are short absolute hops.
+const correctionDistance = 10.0
+
+// dismountWindow is how long after leaving a vehicle the next position sync
+// counts as the dismount (vanilla sends it right after the passenger update).
+const dismountWindow = time.Second
+
+// relative position flags of S2CPlayerPosition
+const relativeXYZ = 0x01 | 0x02 | 0x04
+
+// OnPositionChange is called like OnPosition, with the reason the server moved
+// the player. Consumers can tell a rubber-band (keep going) from a teleport
+// (re-plan).
+func (m *Module) OnPositionChange(cb func(x, y, z float64, cause PositionCause)) (remove func()) {
+	return m.onPositionChange.Add(cb)
+}
+
+// LastPositionCause returns the cause of the last server position sync.
+func (m *Module) LastPositionCause() PositionCause {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.lastPositionCause
+}
+
+// Vehicle returns the entity ID of the vehicle the player rides, or -1.
+func (m *Module) Vehicle() int32 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.vehicle
+}
+
+// Seat returns the player's index among its vehicle's passengers (0 drives)
+// and their number, or -1 and 0 when not riding.
+func (m *Module) Seat() (seat, seats int) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.vehicle < 0 {
+		return -1, 0
+	}
+	return m.seat, m.seats
+}
+
+// OnVehicleChange registers a callback for changes to where the player
+// rides: mounting, moving seats or the vehicle's passengers changing, with
+// the vehicle, the player's seat and the number of passengers. Leaving the
+// vehicle calls it with the vehicle left and a seat of -1.
+func (m *Module) OnVehicleChange(cb func(vehicle int32, seat, seats int)) {
+	m.onVehicleChange = append(m.onVehicleChange, cb)
+}
+
+// expectPosition sets the cause of the next position sync. Must be called
+// with mu held.
+func (m *Module) expectPosition(cause PositionCause, window time.Duration) {
+	m.pendingCause = &cause
+	m.pendingUntil = time.Time{}
+	if window > 0 {
+		m.pendingUntil = m.client.Now().Add(window)
+	}
+}
+
+// positionCause classifies a position sync moving the player from (ox, oy, oz)
+// to (x, y, z) and clears the pending cause. Must be called with mu held.
+func (m *Module) positionCause(flags int32, ox, oy, oz, x, y, z float64) PositionCause {
+	pending := m.pendingCause
+	m.pendingCause = nil
+	if pending != nil && (m.pendingUntil.IsZero() || m.client.Now().Before(m.pendingUntil)) {
+		return *pending
+	}
+	// corrections reset to an absolute position; /tp ~ ~ ~ is relative
+	dist := math.Sqrt((x-ox)*(x-ox) + (y-oy)*(y-oy) + (z-oz)*(z-oz))
+	if flags&relativeXYZ == 0 && dist <= correctionDistance {
+		return PositionCorrection
+	}
+	return PositionTeleport
+}
+
+// handleSetPassengers tracks the vehicle the player rides and its seat.
+func (m *Module) handleSetPassengers(pkt *jp.WirePacket) {
+	// parse manually: the packet struct reads the passengers as a byte array,
+	// but it's a VarInt array
+	buf := ns.NewReader(pkt.Data)
+	vehicle, err := buf.ReadVarInt()
+	if err != nil {
+		return
+	}
+	count, err := buf.ReadVarInt()
+	if err != nil {
+		return
+	}
+	passengers := make([]int32, 0, int(count))
+	for range int(count) {
+		id, err := buf.ReadVarInt()
+		if err != nil {
+			return
+		}
+		passengers = append(passengers, int32(id))
+	}
+
+	m.mu.Lock()
+	seat := slices.Index(passengers, m.entityID)
+	switch {
+	case seat >= 0:
+		m.vehicle, m.seat, m.seats = int32(vehicle), seat, len(passengers)
+	case m.vehicle == int32(vehicle):
+		m.vehicle = -1
+		m.expectPosition(PositionDismount, dismountWindow)
+	default:
+		m.mu.Unlock()
+		return
+	}
+	m.mu.Unlock()
+
+	for _, cb := range m.onVehicleChange {
+		cb(int32(vehicle), seat, len(passengers))
+	}
+}
@@ -3,6 +3,7 @@ package self
 import (
 	"encoding/binary"
 	"sync"
+	"time"
 
 	"github.com/go-mclib/client/pkg/client"
 	"github.com/go-mclib/data/pkg/data/packet_ids"
@@ -13,7 +14,7 @@ import (
 
 const (
 	ModuleName = "self"
-	EyeHeight  = 1.62
+	EyeHeight  = 1.62 // standing; see CurrentEyeHeight
 )
 
 type Module struct {
@@ -83,9 +84,35 @@ type Module struct {
 	// when true, an external controller (e.g. pproxy) handles teleport confirms
 	suppressPositionEcho bool
 
+	// vanilla LocalPlayer.hasClientLoaded: false after login/respawn until the
+	// first position sync, during which the player doesn't move or send position
+	loaded bool
+
+	// position cause tracking (see position.go)
+	vehicle           int32 // -1 when not riding
+	seat, seats       int   // the player's index among the vehicle's passengers, and their number
+	camera            int32 // spectated entity, -1 when viewing from the player (see camera.go)
+	pendingCause      *PositionCause
+	pendingUntil      time.Time // zero: no expiry
+	lastPositionCause PositionCause
+
 	// movement state flags
 	sprinting bool
 	sneaking  bool
+	usingItem bool // see use.go
+	useHand   int8
+
+	// pose (see pose.go)
+	pose       Pose
+	swimming   bool
+	fallFlying bool
+	sleeping   bool
+	spinAttack bool
+
+	// air supply (see air.go)
+	LowAirThreshold int32
+	air             int32
+	lowAir          bool
 
 	attributes map[string]*Attribute
 
@@ -96,9 +123,12 @@ type Module struct {
 	onSpawn            []func()
 	onRespawn          []func()
 	onHealthSet        []func(health, food float32)
-	onPosition         []func(x, y, z float64)
+	onPosition         client.Callbacks[func(x, y, z float64)]
+	onPositionChange   client.Callbacks[func(x, y, z float64, cause PositionCause)]
 	onGameEvent        []func(event uint8, value float32)
-	onGamemodeChange   []func(gamemode uint8)
+	onGamemodeChange   client.Callbacks[func(gamemode uint8)]
+	onCameraChange     []func(entityID int32)
+	onVehicleChange    []func(vehicle int32, seat, seats int)
 	onDimensionChange  []func(dimensionName string)
 	onEffectAdded      []func(effectID, amplifier, duration int32)
 	onEffectRemoved    []func(effectID int32)
@@ -107,6 +137,8 @@ type Module struct {
 	onTimeUpdate       []func(worldAge, timeOfDay int64)
 	onExperienceChange []func(bar float32, level, total int32)
 	onAttributeUpdate  []func(name string, value float64)
+	onLowAir           []func(air int32)
+	onAirChange        []func(air int32)
 }
 
 func New() *Module {
@@ -117,8 +149,13 @@ func New() *Module {
 		foodSaturation: 5,
 		flyingSpeed:    0.05,
 		fovModifier:    0.1,
+		vehicle:        -1,
+		camera:         -1,
+		air:            MaxAir,
 		activeEffects:  make(map[int32]*EffectInstance),
 		attributes:     make(map[string]*Attribute),
+
+		LowAirThreshold: DefaultLowAirThreshold,
 	}
 }
 
@@ -157,6 +194,10 @@ func (m *Module) Reset() {
 	m.pitch = 0
 	m.sprinting = false
 	m.sneaking = false
+	m.usingItem = false
+	m.resetPose()
+	m.air = MaxAir
+	m.lowAir = false
 	m.difficulty = 0
 	m.difficultyLocked = false
 	m.abilityFlags = 0
@@ -170,6 +211,10 @@ func (m *Module) Reset() {
 	m.timeOfDay = 0
 	m.timeIncreasing = false
 	m.opLevel = 0
+	m.loaded = false
+	m.vehicle = -1
+	m.camera = -1
+	m.pendingCause = nil
 	clear(m.attributes)
 	m.mu.Unlock()
 	m.effectsMu.Lock()
@@ -425,6 +470,11 @@ func (m *Module) SuppressPositionEcho() bool {
 	defer m.mu.RUnlock()
 	return m.suppressPositionEcho
 }
+func (m *Module) Loaded() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.loaded
+}
 func (m *Module) SetSuppressPositionEcho(v bool) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -448,12 +498,14 @@ func (m *Module) OnRespawn(cb func()) { m.onRespawn = append(m.onRespawn, cb) }
 func (m *Module) OnHealthSet(cb func(health, food float32)) {
 	m.onHealthSet = append(m.onHealthSet, cb)
 }
-func (m *Module) OnPosition(cb func(x, y, z float64)) { m.onPosition = append(m.onPosition, cb) }
+func (m *Module) OnPosition(cb func(x, y, z float64)) (remove func()) {
+	return m.onPosition.Add(cb)
+}
 func (m *Module) OnGameEvent(cb func(event uint8, value float32)) {
 	m.onGameEvent = append(m.onGameEvent, cb)
 }
-func (m *Module) OnGamemodeChange(cb func(gamemode uint8)) {
-	m.onGamemodeChange = append(m.onGamemodeChange, cb)
+func (m *Module) OnGamemodeChange(cb func(gamemode uint8)) (remove func()) {
+	return m.onGamemodeChange.Add(cb)
 }
 func (m *Module) OnDimensionChange(cb func(dimensionName string)) {
 	m.onDimensionChange = append(m.onDimensionChange, cb)
@@ -517,6 +569,12 @@ func (m *Module) HandlePacket(pkt *jp.WirePacket) {
 		m.handleRespawn(pkt)
 	case packet_ids.S2CUpdateAttributesID:
 		m.handleUpdateAttributes(pkt)
+	case packet_ids.S2CSetPassengersID:
+		m.handleSetPassengers(pkt)
+	case packet_ids.S2CSetEntityDataID:
+		m.handleSetEntityData(pkt)
+	case packet_ids.S2CSetCameraID:
+		m.handleSetCamera(pkt)
 	}
 }
 
@@ -529,6 +587,7 @@ func (m *Module) handleLogin(pkt *jp.WirePacket) {
 
 	m.mu.Lock()
 	m.entityID = int32(d.EntityId)
+	m.camera = -1
 	m.isHardcore = bool(d.IsHardcore)
 	m.dimensionNames = make([]string, len(d.DimensionNames))
 	for i, name := range d.DimensionNames {
@@ -551,6 +610,9 @@ func (m *Module) handleLogin(pkt *jp.WirePacket) {
 	m.portalCooldown = int32(d.PortalCooldown)
 	m.seaLevel = int32(d.SeaLevel)
 	m.enforcesSecureChat = bool(d.EnforcesSecureChat)
+	m.loaded = false
+	m.vehicle = -1
+	m.expectPosition(PositionInitial, 0)
 	autoRespawn := m.autoRespawn
 	m.mu.Unlock()
 
@@ -560,8 +622,6 @@ func (m *Module) handleLogin(pkt *jp.WirePacket) {
 		m.client.EnableInput()
 	}
 
-	_ = m.client.WritePacket(&packets.C2SPlayerLoaded{})
-
 	if autoRespawn {
 		m.Respawn()
 	}
@@ -581,6 +641,20 @@ func (m *Module) handleRespawn(pkt *jp.WirePacket) {
 	m.mu.Lock()
 	oldDim := m.dimensionName
 	oldGamemode := m.gamemode
+	// the respawn packet also moves living players between dimensions
+	if m.health > 0 && string(d.DimensionName) != oldDim {
+		m.expectPosition(PositionDimensionChange, 0)
+	} else {
+		m.expectPosition(PositionRespawn, 0)
+	}
+	m.vehicle = -1
+	m.usingItem = false
+	m.resetPose()
+	m.air = MaxAir
+	m.lowAir = false
+	// the respawned player is a new entity and the camera returns to it
+	cameraReset := m.camera >= 0
+	m.camera = -1
 
 	m.dimensionType = int32(d.DimensionType)
 	m.dimensionName = string(d.DimensionName)
@@ -595,6 +669,7 @@ func (m *Module) handleRespawn(pkt *jp.WirePacket) {
 	m.x = 0
 	m.y = 0
 	m.z = 0
+	m.loaded = false
 
 	if d.DataKept&0x01 == 0 {
 		m.health = 20
@@ -604,6 +679,7 @@ func (m *Module) handleRespawn(pkt *jp.WirePacket) {
 
 	newDim := m.dimensionName
 	newGamemode := m.gamemode
+	ownID := m.entityID
 	m.mu.Unlock()
 
 	m.effectsMu.Lock()
@@ -621,10 +697,15 @@ func (m *Module) handleRespawn(pkt *jp.WirePacket) {
 		}
 	}
 	if newGamemode != oldGamemode {
-		for _, cb := range m.onGamemodeChange {
+		for _, cb := range m.onGamemodeChange.All() {
 			cb(newGamemode)
 		}
 	}
+	if cameraReset {
+		for _, cb := range m.onCameraChange {
+			cb(ownID)
+		}
+	}
 }
 
 func (m *Module) handleSetHealth(pkt *jp.WirePacket) {
@@ -680,6 +761,7 @@ func (m *Module) handlePlayerPosition(pkt *jp.WirePacket) {
 	flags := int32(d.Flags)
 
 	m.mu.Lock()
+	ox, oy, oz := m.x, m.y, m.z
 	if flags&0x01 != 0 {
 		m.x += float64(d.X)
 	} else {
@@ -709,6 +791,10 @@ func (m *Module) handlePlayerPosition(pkt *jp.WirePacket) {
 	suppress := m.suppressPositionEcho
 	x, y, z := m.x, m.y, m.z
 	yaw, pitch := m.yaw, m.pitch
+	justLoaded := !m.loaded
+	m.loaded = true
+	cause := m.positionCause(flags, ox, oy, oz, x, y, z)
+	m.lastPositionCause = cause
 	m.mu.Unlock()
 
 	if !suppress {
@@ -722,9 +808,18 @@ func (m *Module) handlePlayerPosition(pkt *jp.WirePacket) {
 		})
 	}
 
-	for _, cb := range m.onPosition {
+	// vanilla sends this once the level loading screen closes after
+	// login/respawn, i.e. after the first position sync
+	if justLoaded {
+		_ = m.client.WritePacket(&packets.C2SPlayerLoaded{})
+	}
+
+	for _, cb := range m.onPosition.All() {
 		cb(x, y, z)
 	}
+	for _, cb := range m.onPositionChange.All() {
+		cb(x, y, z, cause)
+	}
 }
 
 func (m *Module) handleGameEvent(pkt *jp.WirePacket) {
@@ -750,7 +845,7 @@ func (m *Module) handleGameEvent(pkt *jp.WirePacket) {
 	}
 
 	if gamemodeChanged {
-		for _, cb := range m.onGamemodeChange {
+		for _, cb := range m.onGamemodeChange.All() {
 			cb(newMode)
 		}
 	}
@@ -0,0 +1,95 @@
+package self
+
+import (
+	"errors"
+	"math"
+
+	"github.com/go-mclib/client/pkg/client"
+)
+
+// item entity physics constants from the Minecraft source (Player.drop, ItemEntity.tick).
+const (
+	TossSpeed       = 0.3  // initial horizontal/vertical scale in Player.drop
+	TossUpBoost     = 0.1  // extra upward velocity added to every dropped item
+	TossSpawnOffset = 0.3  // items spawn at eye height minus this offset
+	ItemGravity     = 0.04 // ItemEntity.getDefaultGravity
+	ItemDrag        = float64(float32(0.98))
+	tossMaxTicks    = 100
+	tossMaxError    = 0.5 // blocks; targets the toss can't hit closer than this are rejected
+	tossTolerance   = 0.1 // blocks; within this, faster (flatter) trajectories win
+	tossPitchStep   = 0.25
+	tossMinPitch    = -90.0
+	tossMaxPitch    = 90.0
+)
+
+// TossItemTo rotates toward (x, y, z) with a pitch whose drop trajectory lands
+// the item at that position, then drops the held item (or the whole stack).
+// y is the height the item should land at, e.g. a player's feet.
+// Returns an error if the target is outside the range of a dropped item.
+func (m *Module) TossItemTo(x, y, z float64, dropStack bool) error {
+	px, py, pz := m.Position()
+	dx, dz := x-px, z-pz
+	dist := math.Sqrt(dx*dx + dz*dz)
+	dy := y - (py + m.CurrentEyeHeight() - TossSpawnOffset)
+
+	pitch, ok := TossPitch(dist, dy)
+	if !ok {
+		return errors.New("target out of toss range")
+	}
+	yaw, _ := WorldPosToYawPitch(px, py, pz, x, py, z)
+	m.SetRotation(yaw, pitch)
+
+	// drop once the physics tick has sent the new rotation
+	return m.client.RunOnTick(client.TickAfterSend, func() error {
+		return m.client.DropItem(dropStack)
+	})
+}
+
+// TossPitch returns the pitch (degrees, vanilla convention: positive looks down)
+// at which a dropped item travels dist blocks horizontally before falling to dy
+// blocks relative to its spawn height. Among pitches that land close enough,
+// the one with the shortest flight wins. Random spread is ignored.
+func TossPitch(dist, dy float64) (pitch float64, ok bool) {
+	bestErr := math.MaxFloat64
+	bestTicks := math.MaxInt
+	for p := tossMinPitch; p <= tossMaxPitch; p += tossPitchStep {
+		d, ticks, landed := tossDistance(p, dy)
+		if !landed {
+			continue
+		}
+		e := math.Abs(d - dist)
+		better := e < bestErr
+		if e <= tossTolerance && bestErr <= tossTolerance {
+			better = ticks < bestTicks
+		}
+		if better {
+			bestErr, bestTicks = e, ticks
+			pitch = p
+		}
+	}
+	return pitch, bestErr <= tossMaxError
+}
+
+// tossDistance simulates an item dropped at the given pitch and returns the
+// horizontal distance it has covered when it descends through dy, and the
+// number of ticks that took. Follows ItemEntity.tick: gravity, move, drag.
+func tossDistance(pitch, dy float64) (dist float64, ticks int, ok bool) {
+	rad := pitch * math.Pi / 180
+	velH := math.Cos(rad) * TossSpeed
+	velY := -math.Sin(rad)*TossSpeed + TossUpBoost
+
+	var y float64
+	for tick := range tossMaxTicks {
+		velY -= ItemGravity
+		prevY, prevDist := y, dist
+		y += velY
+		dist += velH
+		if velY < 0 && y <= dy {
+			frac := (prevY - dy) / (prevY - y)
+			return prevDist + velH*frac, tick + 1, true
+		}
+		velH *= ItemDrag
+		velY *= ItemDrag
+	}
+	return 0, 0, false
+}
@@ -0,0 +1,55 @@
+package self
+
+import "github.com/go-mclib/data/pkg/packets"
+
+// UseItemSpeedFactor scales movement input while an item is in use (eating,
+// drinking, drawing a bow, blocking with a shield), as in vanilla
+// LocalPlayer.modifyInput.
+const UseItemSpeedFactor = 0.2
+
+// playerActionReleaseUseItem is ServerboundPlayerActionPacket.Action
+// RELEASE_USE_ITEM.
+const playerActionReleaseUseItem = 5
+
+// UsingItem returns the hand of the item in use, or false if none is. The
+// state is predicted by StartUsingItem and follows the server's living
+// entity flags.
+func (m *Module) UsingItem() (hand int8, ok bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.useHand, m.usingItem
+}
+
+// StartUsingItem uses the item in hand and, like the vanilla client, treats
+// it as in use right away, slowing movement until the server says otherwise.
+// Only call it for items that are used over time: food, potions, bows,
+// crossbows, tridents, shields, spyglasses and the like.
+func (m *Module) StartUsingItem(hand int8) error {
+	if err := m.Use(hand); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	m.usingItem = true
+	m.useHand = hand
+	m.mu.Unlock()
+	return nil
+}
+
+// ReleaseUsingItem stops using the item, e.g. to shoot a drawn bow or lower
+// a shield.
+func (m *Module) ReleaseUsingItem() error {
+	m.mu.Lock()
+	m.usingItem = false
+	m.mu.Unlock()
+	return m.client.WritePacket(&packets.C2SPlayerAction{
+		Status: playerActionReleaseUseItem,
+	})
+}
+
+// stopUsingItem clears the predicted use once its effect was seen, in case
+// the flags update is still in flight.
+func (m *Module) stopUsingItem() {
+	m.mu.Lock()
+	m.usingItem = false
+	m.mu.Unlock()
+}
@@ -0,0 +1,137 @@
+package sounds
+
+import (
+	"time"
+
+	"github.com/go-mclib/client/pkg/client/modules/entities"
+	"github.com/go-mclib/client/pkg/client/modules/self"
+	"github.com/go-mclib/client/pkg/geom"
+)
+
+const (
+	// DefaultDetectionRadius is the default distance from the player within
+	// which sounds are checked for invisible sources.
+	DefaultDetectionRadius = 24.0
+	// DefaultMatchRadius is the default distance between a sound's origin and
+	// a tracked entity for the entity to count as its source. Step sounds are
+	// played at the feet, quantized to 1/8 block.
+	DefaultMatchRadius = 2.0
+)
+
+// suspectCooldown suppresses repeated reports for the same source (every
+// footstep would otherwise fire).
+const suspectCooldown = time.Second
+
+// Suspect is a probable invisible entity given away by a sound.
+type Suspect struct {
+	// EntityID is the tracked entity with the invisible flag the sound came
+	// from, or -1 if no entity is tracked there at all (e.g. a vanish plugin
+	// hiding the entity but not its sounds).
+	EntityID int32
+	X, Y, Z  float64 // estimated position: the entity's, or the sound's origin
+	Sound    string  // the sound that gave it away
+	Time     time.Time
+}
+
+type suspectKey struct {
+	entityID int32
+	cell     geom.BlockPos // for untracked sources
+}
+
+// OnSuspectedInvisibleEntity is called when a sound made by an entity (a
+// footstep, hurt or attack sound, ...) comes from an invisible tracked entity
+// or from a spot near the player where no visible entity is tracked. Reports
+// for the same source are rate limited to one per second. Requires the
+// entities module for positions and invisibility flags.
+func (m *Module) OnSuspectedInvisibleEntity(cb func(s Suspect)) {
+	m.onSuspect = append(m.onSuspect, cb)
+}
+
+// entityCategory reports whether sounds of this category are made by entities.
+func entityCategory(category int32) bool {
+	return category == CategoryHostile || category == CategoryNeutral || category == CategoryPlayers
+}
+
+// locateEntitySound fills in the position of a sound attached to a tracked entity.
+func (m *Module) locateEntitySound(s *Sound) {
+	ents := entities.From(m.client)
+	if ents == nil {
+		return
+	}
+	if e := ents.GetEntity(s.EntityID); e != nil {
+		s.X, s.Y, s.Z = e.X, e.Y, e.Z
+	}
+}
+
+func (m *Module) detect(s Sound) {
+	if m.onSuspect == nil || !entityCategory(s.Category) {
+		return
+	}
+	ents := entities.From(m.client)
+	slf := self.From(m.client)
+	if ents == nil || slf == nil {
+		return
+	}
+
+	if s.EntityID >= 0 {
+		// the server told us who made it
+		if e := ents.GetEntity(s.EntityID); e != nil && e.Invisible() {
+			m.suspect(Suspect{EntityID: e.ID, X: e.X, Y: e.Y, Z: e.Z, Sound: s.Name})
+		}
+		return
+	}
+
+	x, y, z := slf.Position()
+	origin := geom.Vec3{X: s.X, Y: s.Y, Z: s.Z}
+	dist := origin.Distance(geom.Vec3{X: x, Y: y, Z: z})
+	if dist > m.DetectionRadius || dist <= m.MatchRadius {
+		// too far to matter, or most likely our own
+		return
+	}
+
+	var hidden *entities.Entity
+	for _, e := range ents.GetNearbyEntities(s.X, s.Y, s.Z, m.MatchRadius) {
+		if !e.Invisible() {
+			return // a visible entity explains the sound
+		}
+		if hidden == nil || distSq(e, origin) < distSq(hidden, origin) {
+			hidden = e
+		}
+	}
+	if hidden != nil {
+		m.suspect(Suspect{EntityID: hidden.ID, X: hidden.X, Y: hidden.Y, Z: hidden.Z, Sound: s.Name})
+		return
+	}
+	m.suspect(Suspect{EntityID: -1, X: s.X, Y: s.Y, Z: s.Z, Sound: s.Name})
+}
+
+func distSq(e *entities.Entity, p geom.Vec3) float64 {
+	dx, dy, dz := e.X-p.X, e.Y-p.Y, e.Z-p.Z
+	return dx*dx + dy*dy + dz*dz
+}
+
+func (m *Module) suspect(s Suspect) {
+	s.Time = m.client.Now()
+	key := suspectKey{entityID: s.EntityID}
+	if s.EntityID < 0 {
+		key.cell = geom.Vec3{X: s.X, Y: s.Y, Z: s.Z}.Block()
+	}
+
+	m.mu.Lock()
+	if last, ok := m.lastSuspects[key]; ok && s.Time.Sub(last) < suspectCooldown {
+		m.mu.Unlock()
+		return
+	}
+	m.lastSuspects[key] = s.Time
+	// forget stale entries so the map doesn't grow with every step
+	for k, t := range m.lastSuspects {
+		if s.Time.Sub(t) >= suspectCooldown {
+			delete(m.lastSuspects, k)
+		}
+	}
+	m.mu.Unlock()
+
+	for _, cb := range m.onSuspect {
+		cb(s)
+	}
+}
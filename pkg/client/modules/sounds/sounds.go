@@ -0,0 +1,270 @@
+package sounds
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-mclib/client/pkg/client"
+	"github.com/go-mclib/data/pkg/data/packet_ids"
+	"github.com/go-mclib/data/pkg/data/registries"
+	jp "github.com/go-mclib/protocol/java_protocol"
+	ns "github.com/go-mclib/protocol/java_protocol/net_structures"
+)
+
+const ModuleName = "sounds"
+
+// sound categories (SoundSource ordinals)
+const (
+	CategoryMaster = iota
+	CategoryMusic
+	CategoryRecords
+	CategoryWeather
+	CategoryBlocks
+	CategoryHostile
+	CategoryNeutral
+	CategoryPlayers
+	CategoryAmbient
+	CategoryVoice
+	CategoryUI
+)
+
+// Sound is a sound effect the server played near the player.
+type Sound struct {
+	Name     string // e.g. "minecraft:block.stone.step"
+	Category int32  // see Category*
+	X, Y, Z  float64
+	// EntityID is the entity the sound follows (S2CSoundEntity), or -1 for
+	// positional sounds. X/Y/Z are the entity's tracked position if known.
+	EntityID int32
+	Volume   float32
+	Pitch    float32
+}
+
+type Module struct {
+	client *client.Client
+
+	// DetectionRadius limits invisible-entity detection to sounds this close
+	// to the player (default: DefaultDetectionRadius).
+	DetectionRadius float64
+	// MatchRadius is how close a tracked entity must be to a sound's origin to
+	// be considered its source (default: DefaultMatchRadius).
+	MatchRadius float64
+	// MaxSoundsPerTick caps the sounds handled per tick; the rest are
+	// dropped before OnSound and detection see them, so a server spamming
+	// sounds costs little (0 = no cap). See DroppedSoundCount.
+	MaxSoundsPerTick int
+
+	mu           sync.Mutex
+	lastSuspects map[suspectKey]time.Time
+	tickStart    time.Time // current tick window for MaxSoundsPerTick
+	tickSounds   int
+	dropped      int
+
+	onSound   []func(s Sound)
+	onSuspect []func(s Suspect)
+}
+
+func New() *Module {
+	return &Module{
+		DetectionRadius: DefaultDetectionRadius,
+		MatchRadius:     DefaultMatchRadius,
+		lastSuspects:    make(map[suspectKey]time.Time),
+	}
+}
+
+func (m *Module) Name() string { return ModuleName }
+
+func (m *Module) Init(c *client.Client) {
+	m.client = c
+	c.OnTransfer(m.Reset)
+}
+
+// PrepareConfig implements client.Reloadable for the "sounds" section.
+func (m *Module) PrepareConfig(raw json.RawMessage) (func(), error) {
+	var cfg struct {
+		MaxSoundsPerTick *int `json:"max_sounds_per_tick"`
+	}
+	if err := json.Unmarshal(raw, &cfg); err != nil {
+		return nil, err
+	}
+	if cfg.MaxSoundsPerTick != nil && *cfg.MaxSoundsPerTick < 0 {
+		return nil, fmt.Errorf("max_sounds_per_tick must not be negative, got %d", *cfg.MaxSoundsPerTick)
+	}
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		if cfg.MaxSoundsPerTick != nil {
+			m.MaxSoundsPerTick = *cfg.MaxSoundsPerTick
+		}
+	}, nil
+}
+
+func (m *Module) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.lastSuspects = make(map[suspectKey]time.Time)
+}
+
+// From retrieves the sounds module from a client.
+func From(c *client.Client) *Module {
+	mod := c.Module(ModuleName)
+	if mod == nil {
+		return nil
+	}
+	return mod.(*Module)
+}
+
+// OnSound is called for every sound effect received.
+func (m *Module) OnSound(cb func(s Sound)) { m.onSound = append(m.onSound, cb) }
+
+func (m *Module) HandlePacket(pkt *jp.WirePacket) {
+	if m.client.State() != jp.StatePlay {
+		return
+	}
+
+	isSound := pkt.PacketID == packet_ids.S2CSoundID || pkt.PacketID == packet_ids.S2CSoundEntityID
+	if !isSound || !m.admit() {
+		return
+	}
+
+	var s Sound
+	var err error
+	switch pkt.PacketID {
+	case packet_ids.S2CSoundID:
+		s, err = readSound(pkt.Data)
+	case packet_ids.S2CSoundEntityID:
+		s, err = readSoundEntity(pkt.Data)
+		if err == nil {
+			m.locateEntitySound(&s)
+		}
+	}
+	if err != nil {
+		m.client.Debugf("sounds: failed to read packet 0x%02x: %v", pkt.PacketID, err)
+		return
+	}
+
+	for _, cb := range m.onSound {
+		cb(s)
+	}
+	m.detect(s)
+}
+
+// admit counts a sound against MaxSoundsPerTick and reports whether it's
+// handled.
+func (m *Module) admit() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.MaxSoundsPerTick <= 0 {
+		return true
+	}
+	if now := m.client.Now(); now.Sub(m.tickStart) >= client.TickDuration {
+		m.tickStart, m.tickSounds = now, 0
+	}
+	if m.tickSounds >= m.MaxSoundsPerTick {
+		m.dropped++
+		return false
+	}
+	m.tickSounds++
+	return true
+}
+
+// DroppedSoundCount returns how many sounds were dropped for being over
+// MaxSoundsPerTick.
+func (m *Module) DroppedSoundCount() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.dropped
+}
+
+// parse manually: the packet structs read the sound event as a length-prefixed
+// byte array, but it's an IdOr<SoundEvent> (registry id + 1, or 0 and inline)
+func readSoundEvent(buf *ns.PacketBuffer) (string, error) {
+	id, err := buf.ReadVarInt()
+	if err != nil {
+		return "", err
+	}
+	if id != 0 {
+		name := registries.SoundEvent.ByID(int32(id) - 1)
+		if name == "" {
+			name = fmt.Sprintf("sound#%d", id-1)
+		}
+		return name, nil
+	}
+	name, err := buf.ReadIdentifier()
+	if err != nil {
+		return "", err
+	}
+	hasRange, err := buf.ReadBool()
+	if err != nil {
+		return "", err
+	}
+	if hasRange {
+		if _, err := buf.ReadFloat32(); err != nil {
+			return "", err
+		}
+	}
+	return string(name), nil
+}
+
+func readSound(data []byte) (Sound, error) {
+	buf := ns.NewReader(data)
+	s := Sound{EntityID: -1}
+	var err error
+	if s.Name, err = readSoundEvent(buf); err != nil {
+		return s, err
+	}
+	category, err := buf.ReadVarInt()
+	if err != nil {
+		return s, err
+	}
+	s.Category = int32(category)
+	// fixed-point, 1/8 block
+	var pos [3]ns.Int32
+	for i := range pos {
+		if pos[i], err = buf.ReadInt32(); err != nil {
+			return s, err
+		}
+	}
+	s.X, s.Y, s.Z = float64(pos[0])/8, float64(pos[1])/8, float64(pos[2])/8
+	volume, err := buf.ReadFloat32()
+	if err != nil {
+		return s, err
+	}
+	pitch, err := buf.ReadFloat32()
+	if err != nil {
+		return s, err
+	}
+	s.Volume, s.Pitch = float32(volume), float32(pitch)
+	return s, nil
+}
+
+func readSoundEntity(data []byte) (Sound, error) {
+	buf := ns.NewReader(data)
+	var s Sound
+	var err error
+	if s.Name, err = readSoundEvent(buf); err != nil {
+		return s, err
+	}
+	category, err := buf.ReadVarInt()
+	if err != nil {
+		return s, err
+	}
+	s.Category = int32(category)
+	id, err := buf.ReadVarInt()
+	if err != nil {
+		return s, err
+	}
+	s.EntityID = int32(id)
+	volume, err := buf.ReadFloat32()
+	if err != nil {
+		return s, err
+	}
+	pitch, err := buf.ReadFloat32()
+	if err != nil {
+		return s, err
+	}
+	s.Volume, s.Pitch = float32(volume), float32(pitch)
+	return s, nil
+}
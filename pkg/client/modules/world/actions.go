@@ -1,6 +1,7 @@
 package world
 
 import (
+	"github.com/go-mclib/client/pkg/geom"
 	"github.com/go-mclib/data/pkg/data/blocks"
 	"github.com/go-mclib/data/pkg/data/chunks"
 )
@@ -45,7 +46,7 @@ func (m *Module) GetLoadedChunkCount() int {
 func (m *Module) GetBlockEntity(x, y, z int) *BlockEntityData {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
-	return m.blockEntities[[3]int{x, y, z}]
+	return m.blockEntities[geom.BlockPos{X: x, Y: y, Z: z}]
 }
 
 // FindBlocks calls fn for every block in loaded chunks whose block ID matches
@@ -53,7 +54,9 @@ func (m *Module) GetBlockEntity(x, y, z int) *BlockEntityData {
 // If fn returns false, iteration stops early.
 //
 // The callback is invoked without holding the world lock, so it is safe
-// to call other world methods (e.g. GetBlockEntity) from within fn.
+// to call other world methods (e.g. GetBlockEntity) from within fn. For
+// repeated searches of an area, Scan spreads the work over ticks and keeps
+// the result current.
 func (m *Module) FindBlocks(blockIDs []int32, fn func(x, y, z int, stateID int32) bool) {
 	idSet := make(map[int32]bool, len(blockIDs))
 	for _, id := range blockIDs {
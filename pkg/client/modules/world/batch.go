@@ -0,0 +1,129 @@
+package world
+
+import (
+	"runtime/metrics"
+	"time"
+
+	"github.com/go-mclib/data/pkg/packets"
+	jp "github.com/go-mclib/protocol/java_protocol"
+	ns "github.com/go-mclib/protocol/java_protocol/net_structures"
+)
+
+// DefaultMaxChunksPerTick is the highest rate the server accepts; it clamps
+// the reply to [0.01, 64].
+const DefaultMaxChunksPerTick = 64
+
+const (
+	// minChunksPerTick is the lowest rate the server accepts.
+	minChunksPerTick = 0.01
+	// vanilla ChunkBatchSizeCalculator constants: a batch may take 7ms of
+	// each tick, starting from an estimate of 2ms per chunk
+	batchBudgetNanos   = 7_000_000.0
+	initialNanosChunk  = 2_000_000.0
+	maxOldSampleWeight = 49
+	sampleClamp        = 3.0
+)
+
+const heapMetric = "/memory/classes/heap/objects:bytes"
+
+// ChunkBatchStats describes how the client paces chunk sending.
+type ChunkBatchStats struct {
+	ChunksPerTick float32       // rate last asked of the server
+	PerChunk      time.Duration // smoothed time per chunk from batch start to finish
+	ParsePerChunk time.Duration // average parse time of the last batch's chunks
+	HeapBytes     uint64        // heap in use when the last batch finished
+	Batches       int
+}
+
+// batchPacer is vanilla's ChunkBatchSizeCalculator, with the extra limits of
+// MaxChunksPerTick and HeapLimit.
+type batchPacer struct {
+	nanosPerChunk float64
+	sampleWeight  int
+	start         time.Time
+	parsed        int
+	parseTime     time.Duration
+	stats         ChunkBatchStats
+}
+
+func newBatchPacer() batchPacer {
+	return batchPacer{nanosPerChunk: initialNanosChunk, sampleWeight: 1}
+}
+
+// ChunkBatchStats returns the chunk pacing measurements.
+func (m *Module) ChunkBatchStats() ChunkBatchStats {
+	m.batchMu.Lock()
+	defer m.batchMu.Unlock()
+	return m.pacer.stats
+}
+
+func (m *Module) handleChunkBatchStart() {
+	m.batchMu.Lock()
+	defer m.batchMu.Unlock()
+	m.pacer.start = time.Now()
+	m.pacer.parsed = 0
+	m.pacer.parseTime = 0
+}
+
+// recordChunkParse adds a chunk's parse time to the current batch.
+func (m *Module) recordChunkParse(d time.Duration) {
+	m.batchMu.Lock()
+	defer m.batchMu.Unlock()
+	m.pacer.parsed++
+	m.pacer.parseTime += d
+}
+
+// handleChunkBatchFinished folds the batch's time per chunk into the running
+// estimate and asks for as many chunks per tick as fit in the budget, like
+// the vanilla client. The time is measured on the wall clock: it paces the
+// host, not the game.
+func (m *Module) handleChunkBatchFinished(pkt *jp.WirePacket) {
+	var d packets.S2CChunkBatchFinished
+	if err := pkt.ReadInto(&d); err != nil {
+		return
+	}
+	heap := heapInUse()
+
+	m.batchMu.Lock()
+	p := &m.pacer
+	if size := int(d.BatchSize); size > 0 && !p.start.IsZero() {
+		sample := float64(time.Since(p.start).Nanoseconds()) / float64(size)
+		sample = min(max(sample, p.nanosPerChunk/sampleClamp), p.nanosPerChunk*sampleClamp)
+		p.nanosPerChunk = (p.nanosPerChunk*float64(p.sampleWeight) + sample) / float64(p.sampleWeight+1)
+		p.sampleWeight = min(maxOldSampleWeight, p.sampleWeight+1)
+	}
+	cpt := batchBudgetNanos / p.nanosPerChunk
+	if m.MaxChunksPerTick > 0 {
+		cpt = min(cpt, float64(m.MaxChunksPerTick))
+	}
+	// over the heap limit, slow down in proportion until GC catches up
+	if m.HeapLimit > 0 && heap > m.HeapLimit {
+		cpt *= float64(m.HeapLimit) / float64(heap)
+	}
+	cpt = min(max(cpt, minChunksPerTick), DefaultMaxChunksPerTick)
+
+	p.stats.ChunksPerTick = float32(cpt)
+	p.stats.PerChunk = time.Duration(p.nanosPerChunk)
+	p.stats.ParsePerChunk = 0
+	if p.parsed > 0 {
+		p.stats.ParsePerChunk = p.parseTime / time.Duration(p.parsed)
+	}
+	p.stats.HeapBytes = heap
+	p.stats.Batches++
+	p.start = time.Time{}
+	m.batchMu.Unlock()
+
+	m.client.SendPacket(&packets.C2SChunkBatchReceived{
+		ChunksPerTick: ns.Float32(cpt),
+	})
+}
+
+// heapInUse returns the bytes of live and not yet swept heap objects.
+func heapInUse() uint64 {
+	sample := []metrics.Sample{{Name: heapMetric}}
+	metrics.Read(sample)
+	if sample[0].Value.Kind() != metrics.KindUint64 {
+		return 0
+	}
+	return sample[0].Value.Uint64()
+}
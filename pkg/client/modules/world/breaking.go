@@ -0,0 +1,118 @@
+package world
+
+import (
+	"time"
+
+	"github.com/go-mclib/client/pkg/geom"
+	"github.com/go-mclib/data/pkg/packets"
+	jp "github.com/go-mclib/protocol/java_protocol"
+)
+
+// BreakingStopped is the stage reported when an entity stops breaking a
+// block: it gave up, or the block changed (usually: it broke).
+const BreakingStopped = -1
+
+// breakingTimeout forgets a breaking entity the server stopped updating, as
+// vanilla LevelRenderer does after 400 ticks.
+const breakingTimeout = 20 * time.Second
+
+// Breaking is a block another entity is breaking (S2CBlockDestruction).
+type Breaking struct {
+	Entity  int32
+	Pos     geom.BlockPos
+	Stage   int // crack stage, 0-9
+	Updated time.Time
+}
+
+// OnBlockBreakingNearby is called when another player (or entity) within
+// view starts or progresses breaking a block, with the crack stage 0-9, and
+// with BreakingStopped when it stops or the block changes. Fires ahead of the
+// block update, so guards can react to griefing in progress. The server
+// doesn't send the player's own progress.
+func (m *Module) OnBlockBreakingNearby(cb func(entityID int32, pos geom.BlockPos, stage int)) {
+	m.onBlockBreaking = append(m.onBlockBreaking, cb)
+}
+
+// BlocksBeingBroken returns the blocks other entities are breaking.
+func (m *Module) BlocksBeingBroken() []Breaking {
+	now := m.client.Now()
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	result := make([]Breaking, 0, len(m.breaking))
+	for _, b := range m.breaking {
+		if now.Sub(b.Updated) < breakingTimeout {
+			result = append(result, b)
+		}
+	}
+	return result
+}
+
+func (m *Module) handleBlockDestruction(pkt *jp.WirePacket) {
+	var d packets.S2CBlockDestruction
+	if err := pkt.ReadInto(&d); err != nil {
+		return
+	}
+	m.updateBreaking(int32(d.EntityId), geom.BlockPos{X: d.Location.X, Y: d.Location.Y, Z: d.Location.Z}, int(d.DestroyStage))
+}
+
+// updateBreaking records a breaking progress; stages outside 0-9 mean the
+// entity stopped (vanilla sends 255, or -1 cast to a byte).
+func (m *Module) updateBreaking(entityID int32, pos geom.BlockPos, stage int) {
+	now := m.client.Now()
+	var stopped *Breaking
+	m.mu.Lock()
+	if stage < 0 || stage > 9 {
+		if b, ok := m.breaking[entityID]; ok {
+			stopped = &b
+			delete(m.breaking, entityID)
+		}
+	} else {
+		if b, ok := m.breaking[entityID]; ok && b.Pos != pos {
+			// moved on to another block
+			stopped = &b
+		}
+		m.breaking[entityID] = Breaking{Entity: entityID, Pos: pos, Stage: stage, Updated: now}
+	}
+	m.mu.Unlock()
+
+	if stopped != nil {
+		for _, cb := range m.onBlockBreaking {
+			cb(entityID, stopped.Pos, BreakingStopped)
+		}
+	}
+	if stage >= 0 && stage <= 9 {
+		for _, cb := range m.onBlockBreaking {
+			cb(entityID, pos, stage)
+		}
+	}
+}
+
+// endBreaking stops the breaking progress on a block that changed.
+func (m *Module) endBreaking(x, y, z int, _ int32) {
+	pos := geom.BlockPos{X: x, Y: y, Z: z}
+	var ended []int32
+	m.mu.Lock()
+	for id, b := range m.breaking {
+		if b.Pos == pos {
+			ended = append(ended, id)
+			delete(m.breaking, id)
+		}
+	}
+	m.mu.Unlock()
+	for _, id := range ended {
+		for _, cb := range m.onBlockBreaking {
+			cb(id, pos, BreakingStopped)
+		}
+	}
+}
+
+// forgetBreaking drops breaking progress in an unloaded chunk.
+func (m *Module) forgetBreaking(cx, cz int32) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for id, b := range m.breaking {
+		if b.Pos.Chunk() == (geom.ChunkPos{X: cx, Z: cz}) {
+			delete(m.breaking, id)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package world
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/go-mclib/client/pkg/geom"
+)
+
+func TestBlockBreakingNearby(t *testing.T) {
+	m, _ := testModule()
+
+	var events []string
+	m.OnBlockBreakingNearby(func(id int32, pos geom.BlockPos, stage int) {
+		events = append(events, fmt.Sprintf("%d %v %d", id, pos, stage))
+	})
+
+	a, b := geom.BlockPos{X: 1, Y: 64, Z: 1}, geom.BlockPos{X: 2, Y: 64, Z: 1}
+	m.updateBreaking(7, a, 0)
+	m.updateBreaking(7, a, 5)
+	m.updateBreaking(8, a, 2)
+	m.updateBreaking(7, b, 0) // moved on
+	if got := m.BlocksBeingBroken(); len(got) != 2 {
+		t.Fatalf("BlocksBeingBroken = %v, want 2 entries", got)
+	}
+	m.endBreaking(a.X, a.Y, a.Z, 0) // a broke
+	m.updateBreaking(7, b, 255)     // gave up
+
+	want := []string{
+		fmt.Sprintf("7 %v 0", a),
+		fmt.Sprintf("7 %v 5", a),
+		fmt.Sprintf("8 %v 2", a),
+		fmt.Sprintf("7 %v -1", a),
+		fmt.Sprintf("7 %v 0", b),
+		fmt.Sprintf("8 %v -1", a),
+		fmt.Sprintf("7 %v -1", b),
+	}
+	if !slices.Equal(events, want) {
+		t.Errorf("events = %q, want %q", events, want)
+	}
+	if got := m.BlocksBeingBroken(); len(got) != 0 {
+		t.Errorf("BlocksBeingBroken = %v after all stopped", got)
+	}
+}
@@ -1,6 +1,8 @@
 package world
 
+import "github.com/go-mclib/client/pkg/geom"
+
 // ChunkKey encodes chunk coordinates into a single int64 map key.
 func ChunkKey(chunkX, chunkZ int32) int64 {
-	return int64(chunkX)<<32 | int64(uint32(chunkZ))
+	return geom.ChunkPos{X: chunkX, Z: chunkZ}.Key()
 }
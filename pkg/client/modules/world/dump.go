@@ -0,0 +1,161 @@
+package world
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+
+	"github.com/go-mclib/client/pkg/geom"
+)
+
+// DumpRadius is how far around the player, in blocks along each axis, the
+// world's section of a state dump reaches.
+const DumpRadius = 32
+
+// positioner is implemented by the self module, looked up by interface so
+// world doesn't depend on it.
+type positioner interface {
+	Position() (x, y, z float64)
+}
+
+// DumpedRegion is the world's section of a state dump (see client.DumpState):
+// the block states of a cuboid around the player. Blocks in chunks that
+// weren't loaded are -1.
+type DumpedRegion struct {
+	Bounds geom.Cuboid
+	States []int32 // by Y, then Z, then X
+}
+
+// At returns the state of the block at pos, -1 if outside the region or not
+// loaded.
+func (r *DumpedRegion) At(pos geom.BlockPos) int32 {
+	if !r.Bounds.Contains(pos) {
+		return -1
+	}
+	dx, _, dz := r.Bounds.Size()
+	p := pos.Offset(-r.Bounds.Min.X, -r.Bounds.Min.Y, -r.Bounds.Min.Z)
+	return r.States[(p.Y*dz+p.Z)*dx+p.X]
+}
+
+// DumpState implements client.StateDumper. The section is binary: the
+// region's min corner (varints) and size (uvarints), then runs of equal
+// states in Y, Z, X order, each a uvarint count and a varint state. Decode
+// it with DecodeDumpRegion.
+func (m *Module) DumpState() ([]byte, error) {
+	s, ok := m.client.Module("self").(positioner)
+	if !ok {
+		return nil, errors.New("no self module to center the region on")
+	}
+	x, y, z := s.Position()
+	center := geom.BlockPos{X: int(math.Floor(x)), Y: int(math.Floor(y)), Z: int(math.Floor(z))}
+	bounds := geom.Cuboid{
+		Min: center.Offset(-DumpRadius, -DumpRadius, -DumpRadius),
+		Max: center.Offset(DumpRadius, DumpRadius, DumpRadius),
+	}
+
+	var buf []byte
+	for _, v := range []int{bounds.Min.X, bounds.Min.Y, bounds.Min.Z} {
+		buf = binary.AppendVarint(buf, int64(v))
+	}
+	dx, dy, dz := bounds.Size()
+	for _, v := range []int{dx, dy, dz} {
+		buf = binary.AppendUvarint(buf, uint64(v))
+	}
+
+	run, count := int32(0), uint64(0)
+	for _, state := range m.snapshot(bounds).States {
+		if count > 0 && state == run {
+			count++
+			continue
+		}
+		if count > 0 {
+			buf = binary.AppendUvarint(buf, count)
+			buf = binary.AppendVarint(buf, int64(run))
+		}
+		run, count = state, 1
+	}
+	buf = binary.AppendUvarint(buf, count)
+	buf = binary.AppendVarint(buf, int64(run))
+	return buf, nil
+}
+
+// snapshot copies the block states of bounds, holding the world lock a chunk
+// column at a time.
+func (m *Module) snapshot(bounds geom.Cuboid) *DumpedRegion {
+	dx, dy, dz := bounds.Size()
+	r := &DumpedRegion{Bounds: bounds, States: make([]int32, dx*dy*dz)}
+	minChunk, maxChunk := bounds.Min.Chunk(), bounds.Max.Chunk()
+	for cx := minChunk.X; cx <= maxChunk.X; cx++ {
+		for cz := minChunk.Z; cz <= maxChunk.Z; cz++ {
+			minX, maxX := max(bounds.Min.X, int(cx)*16), min(bounds.Max.X, int(cx)*16+15)
+			minZ, maxZ := max(bounds.Min.Z, int(cz)*16), min(bounds.Max.Z, int(cz)*16+15)
+			m.mu.RLock()
+			chunk := m.chunks[ChunkKey(cx, cz)]
+			for y := bounds.Min.Y; y <= bounds.Max.Y; y++ {
+				for z := minZ; z <= maxZ; z++ {
+					i := ((y-bounds.Min.Y)*dz+z-bounds.Min.Z)*dx + minX - bounds.Min.X
+					for x := minX; x <= maxX; x, i = x+1, i+1 {
+						if chunk == nil {
+							r.States[i] = -1
+						} else {
+							r.States[i] = chunk.GetBlockState(x, y, z)
+						}
+					}
+				}
+			}
+			m.mu.RUnlock()
+		}
+	}
+	return r
+}
+
+// DecodeDumpRegion decodes the world's section of a state dump.
+func DecodeDumpRegion(data []byte) (*DumpedRegion, error) {
+	errShort := errors.New("truncated region")
+	var header [6]int64
+	for i := range header {
+		var n int
+		if i < 3 {
+			header[i], n = binary.Varint(data)
+		} else {
+			var v uint64
+			v, n = binary.Uvarint(data)
+			header[i] = int64(min(v, math.MaxInt32))
+		}
+		if n <= 0 {
+			return nil, errShort
+		}
+		data = data[n:]
+	}
+	dx, dy, dz := header[3], header[4], header[5]
+	if dx*dy*dz > 1<<24 {
+		return nil, errors.New("region too large")
+	}
+	lo := geom.BlockPos{X: int(header[0]), Y: int(header[1]), Z: int(header[2])}
+	r := &DumpedRegion{
+		Bounds: geom.Cuboid{Min: lo, Max: lo.Offset(int(dx)-1, int(dy)-1, int(dz)-1)},
+		States: make([]int32, 0, dx*dy*dz),
+	}
+	for len(data) > 0 {
+		count, n := binary.Uvarint(data)
+		if n <= 0 {
+			return nil, errShort
+		}
+		data = data[n:]
+		state, n := binary.Varint(data)
+		if n <= 0 {
+			return nil, errShort
+		}
+		data = data[n:]
+		if count > uint64(cap(r.States)-len(r.States)) {
+			return nil, errors.New("region has more blocks than its size")
+		}
+		for range count {
+			r.States = append(r.States, int32(state))
+		}
+	}
+	if len(r.States) != cap(r.States) {
+		return nil, errShort
+	}
+	return r, nil
+}
@@ -0,0 +1,134 @@
+package world
+
+import (
+	"time"
+
+	"github.com/go-mclib/client/pkg/geom"
+)
+
+// DefaultSuspectTimeout is how long a block stays suspect when the server
+// says nothing more about it. After that the stored state is trusted again.
+const DefaultSuspectTimeout = 5 * time.Second
+
+// GhostStats counts suspect blocks and how they were resolved.
+type GhostStats struct {
+	Marked int // positions marked suspect
+	// Ghosts is how many suspects the server then reported in a different
+	// state than the stored one: the store was out of date.
+	Ghosts    int
+	Confirmed int // the server reported the stored state
+	Expired   int // no word from the server within SuspectTimeout
+}
+
+// suspect is a block whose stored state may not match the server.
+type suspect struct {
+	state int32 // stored state when marked
+	until time.Time
+}
+
+// MarkSuspect flags the block at pos as possibly out of date, e.g. after a
+// break or placement the server didn't answer. The world only stores what
+// the server sent, but a server that rejects an action without resending
+// the block (or a lost ack) leaves callers guessing. Suspect blocks are
+// resolved by the next block update or chunk for them; the vanilla server
+// resends the target and the clicked face's neighbor on every use-item-on,
+// so a later Interact there re-requests them.
+func (m *Module) MarkSuspect(pos geom.BlockPos) {
+	state := m.GetBlock(pos.X, pos.Y, pos.Z)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.expireSuspects()
+	if _, ok := m.suspects[pos]; !ok {
+		m.ghostStats.Marked++
+	}
+	m.suspects[pos] = suspect{state: state, until: m.client.Now().Add(m.SuspectTimeout)}
+}
+
+// Suspect reports whether the block at pos is suspect (see MarkSuspect).
+// The pathfinder avoids suspect blocks while it has a choice.
+func (m *Module) Suspect(pos geom.BlockPos) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if len(m.suspects) == 0 {
+		return false
+	}
+	s, ok := m.suspects[pos]
+	return ok && m.client.Now().Before(s.until)
+}
+
+// Suspects returns the blocks currently suspect.
+func (m *Module) Suspects() []geom.BlockPos {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.expireSuspects()
+	result := make([]geom.BlockPos, 0, len(m.suspects))
+	for pos := range m.suspects {
+		result = append(result, pos)
+	}
+	return result
+}
+
+// GhostStats returns how often blocks were suspect and turned out stale.
+func (m *Module) GhostStats() GhostStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.expireSuspects()
+	return m.ghostStats
+}
+
+// expireSuspects drops the suspects past their timeout. Must be called with
+// mu held.
+func (m *Module) expireSuspects() {
+	now := m.client.Now()
+	for pos, s := range m.suspects {
+		if !now.Before(s.until) {
+			delete(m.suspects, pos)
+			m.ghostStats.Expired++
+		}
+	}
+}
+
+// resolveSuspect settles a suspect block once the server sends its state.
+func (m *Module) resolveSuspect(x, y, z int, stateID int32) {
+	pos := geom.BlockPos{X: x, Y: y, Z: z}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.settleSuspect(pos, stateID)
+}
+
+// resolveChunkSuspects settles the suspects in a chunk the server (re)sent.
+func (m *Module) resolveChunkSuspects(cx, cz int32) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	chunk := m.chunks[ChunkKey(cx, cz)]
+	for pos := range m.suspects {
+		if chunk != nil && pos.Chunk() == (geom.ChunkPos{X: cx, Z: cz}) {
+			m.settleSuspect(pos, chunk.GetBlockState(pos.X, pos.Y, pos.Z))
+		}
+	}
+}
+
+// forgetSuspects drops the suspects in an unloaded chunk.
+func (m *Module) forgetSuspects(cx, cz int32) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for pos := range m.suspects {
+		if pos.Chunk() == (geom.ChunkPos{X: cx, Z: cz}) {
+			delete(m.suspects, pos)
+		}
+	}
+}
+
+// settleSuspect must be called with mu held.
+func (m *Module) settleSuspect(pos geom.BlockPos, stateID int32) {
+	s, ok := m.suspects[pos]
+	if !ok {
+		return
+	}
+	delete(m.suspects, pos)
+	if stateID != s.state {
+		m.ghostStats.Ghosts++
+	} else {
+		m.ghostStats.Confirmed++
+	}
+}
@@ -0,0 +1,365 @@
+package world
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-mclib/client/pkg/client"
+	"github.com/go-mclib/client/pkg/geom"
+	"github.com/go-mclib/data/pkg/data/packet_ids"
+	"github.com/go-mclib/data/pkg/packets"
+	jp "github.com/go-mclib/protocol/java_protocol"
+)
+
+const (
+	// DefaultInteractRetries is how many times a rejected interaction is retried.
+	DefaultInteractRetries = 2
+	// DefaultInteractAckTimeout is how long to wait for the server to ack an interaction.
+	DefaultInteractAckTimeout = time.Second
+)
+
+var (
+	// ErrBlockChanged is returned when the target block no longer matches the
+	// expected state, either right before sending or after the server's ack.
+	ErrBlockChanged = errors.New("block changed")
+	// ErrNoAck is returned when the server doesn't acknowledge the interaction in time.
+	ErrNoAck = errors.New("interaction not acknowledged")
+	// ErrNotConfirmed is returned when the interaction was acked but its Confirm check failed.
+	ErrNotConfirmed = errors.New("interaction not confirmed")
+)
+
+// Interaction describes a right-click on a block for Interact.
+type Interaction struct {
+	Pos                       geom.BlockPos
+	Face                      geom.Face
+	Hand                      int8
+	CursorX, CursorY, CursorZ float32
+	Sneak                     bool
+
+	// Expect reports whether the block state is the one we meant to click.
+	// Checked right before sending and again after the server's ack.
+	// nil accepts any non-air state.
+	Expect func(stateID int32) bool
+	// Prepare runs before each attempt, before the tick the packet is sent on
+	// (e.g. to look at the block).
+	Prepare func()
+	// Confirm waits for the interaction's effect (e.g. a container opening)
+	// after the ack. nil treats the ack alone as success.
+	Confirm func() bool
+}
+
+// Effect is what a block interaction did, as seen in the packets the server
+// sent in response to it.
+type Effect int
+
+const (
+	EffectNone            Effect = iota // acked, but nothing observable happened
+	EffectRejected                      // not acked in time
+	EffectContainerOpened               // a screen opened (chest, furnace, crafting table, ...)
+	EffectBlockPlaced                   // a block appeared at the target or against the clicked face
+	EffectBlockChanged                  // the target's state changed (door, lever, trapdoor, ...)
+)
+
+func (e Effect) String() string {
+	switch e {
+	case EffectNone:
+		return "none"
+	case EffectRejected:
+		return "rejected"
+	case EffectContainerOpened:
+		return "container opened"
+	case EffectBlockPlaced:
+		return "block placed"
+	case EffectBlockChanged:
+		return "block changed"
+	}
+	return fmt.Sprintf("Effect(%d)", int(e))
+}
+
+// BlockChange is a block whose state changed in response to an interaction.
+type BlockChange struct {
+	Pos           geom.BlockPos
+	Before, After int32
+}
+
+// BlockEvent is a block action (S2CBlockEvent) such as a chest lid opening.
+type BlockEvent struct {
+	Pos       geom.BlockPos
+	Action    uint8
+	Parameter uint8
+}
+
+// InteractionResult is the final outcome of Interact. The observed fields
+// describe the last attempt.
+type InteractionResult struct {
+	Attempts int
+	StateID  int32 // block state at the last check
+	Err      error // nil on success, otherwise the last attempt's error
+
+	Effect      Effect
+	WindowID    int32         // set for EffectContainerOpened
+	MenuType    int32         // set for EffectContainerOpened
+	Changes     []BlockChange // target and neighbors that changed
+	BlockEvents []BlockEvent  // block actions at the target
+	LevelEvents []int32       // level event IDs at or next to the target
+}
+
+// observer collects the packets that follow an interaction. Vanilla handles a
+// use-item-on packet in full (block updates, open screen, block and level
+// events) before acking its sequence, so everything up to the ack belongs to
+// it. Sounds aren't tracked: the server doesn't send the acting player the
+// sounds its own interaction makes.
+type observer struct {
+	pos         geom.BlockPos
+	screen      bool
+	windowID    int32
+	menuType    int32
+	blockEvents []BlockEvent
+	levelEvents []int32
+}
+
+// Interact right-clicks a block, retrying when the server rejects it. Each
+// attempt verifies the target state on the tick it's sent, waits for the
+// server to ack the sequence, then re-checks the state (a rejection rolls the
+// block back before the ack arrives). Interactions are serialized, so
+// concurrent callers queue up behind each other.
+//
+// Clicks that toggle the block (doors, levers, trapdoors) aren't repeated:
+// an attempt that changed the target's state is final, and a retry whose
+// target changed since the previous click (a late update from a click that
+// wasn't acked in time) takes that as the click's effect instead of
+// clicking again.
+//
+// The result also reports what the interaction did (see Effect), so callers
+// can tell a container opening from a door toggling or nothing happening.
+func (m *Module) Interact(in Interaction) InteractionResult {
+	m.interactMu.Lock()
+	defer m.interactMu.Unlock()
+
+	expect := in.Expect
+	if expect == nil {
+		expect = func(stateID int32) bool { return stateID != 0 }
+	}
+
+	var res InteractionResult
+	clicked := int32(-1) // target state the last click was sent at
+	for res.Attempts <= m.InteractRetries {
+		attempts := res.Attempts + 1
+		res, clicked = m.interactOnce(in, expect, clicked)
+		res.Attempts = attempts
+		if res.Err == nil {
+			return res
+		}
+		m.client.Logger.Printf("interact at %v: attempt %d: %v", in.Pos, res.Attempts, res.Err)
+		if !retryable(res.Err) || res.Effect == EffectBlockChanged {
+			// clicking a toggled block again would toggle it back
+			return res
+		}
+	}
+	return res
+}
+
+// neighborhood returns the target followed by its six neighbors.
+func neighborhood(p geom.BlockPos) []geom.BlockPos {
+	ps := []geom.BlockPos{p}
+	for _, f := range geom.Faces {
+		ps = append(ps, p.Neighbor(f))
+	}
+	return ps
+}
+
+// interactOnce runs an attempt of Interact and returns the target state it
+// clicked at. clicked is the state of the previous attempt's click, or -1:
+// if the target has changed from it since, that click landed after all and
+// the attempt reports it rather than clicking again.
+func (m *Module) interactOnce(in Interaction, expect func(int32) bool, clicked int32) (InteractionResult, int32) {
+	if in.Prepare != nil {
+		in.Prepare()
+	}
+
+	p := in.Pos
+	area := neighborhood(p)
+	before := make([]int32, len(area))
+	obs := &observer{pos: p}
+	defer m.setObserver(nil)
+
+	var res InteractionResult
+	var seq int32
+	landed := false
+	if err := m.client.RunOnTick(client.TickAfterSend, func() error {
+		res.StateID = m.GetBlock(p.X, p.Y, p.Z)
+		if clicked >= 0 && res.StateID != clicked {
+			landed = true
+			return nil
+		}
+		if !expect(res.StateID) {
+			return fmt.Errorf("%w before send (state %d)", ErrBlockChanged, res.StateID)
+		}
+		for i, bp := range area {
+			before[i] = m.GetBlock(bp.X, bp.Y, bp.Z)
+		}
+		m.setObserver(obs)
+		click := m.client.InteractBlock
+		if in.Sneak {
+			click = m.client.InteractBlockSneaking
+		}
+		if err := click(p.X, p.Y, p.Z, int8(in.Face), in.Hand, in.CursorX, in.CursorY, in.CursorZ); err != nil {
+			return err
+		}
+		seq = m.client.LastBISequence()
+		return nil
+	}); err != nil {
+		res.Err = err
+		return res, clicked
+	}
+	if landed {
+		res.Effect = EffectBlockChanged
+		res.Changes = []BlockChange{{Pos: p, Before: clicked, After: res.StateID}}
+		if in.Confirm != nil && !in.Confirm() {
+			res.Err = ErrNotConfirmed
+		}
+		return res, clicked
+	}
+	clicked = before[0]
+
+	if !m.waitAck(seq, m.InteractAckTimeout) {
+		// whatever the server did, it hasn't told us yet
+		m.MarkSuspect(p)
+		m.MarkSuspect(p.Neighbor(in.Face))
+		res.Effect = EffectRejected
+		res.Err = ErrNoAck
+		return res, clicked
+	}
+	m.setObserver(nil)
+	m.describe(&res, in, obs, area, before)
+
+	res.StateID = m.GetBlock(p.X, p.Y, p.Z)
+	if !expect(res.StateID) && res.Effect != EffectBlockChanged {
+		res.Err = fmt.Errorf("%w after ack (state %d)", ErrBlockChanged, res.StateID)
+		return res, clicked
+	}
+	if in.Confirm != nil && !in.Confirm() {
+		res.Err = ErrNotConfirmed
+	}
+	return res, clicked
+}
+
+// describe fills in the observed fields of res and classifies the effect.
+func (m *Module) describe(res *InteractionResult, in Interaction, obs *observer, area []geom.BlockPos, before []int32) {
+	m.obsMu.Lock()
+	res.WindowID, res.MenuType = obs.windowID, obs.menuType
+	res.BlockEvents, res.LevelEvents = obs.blockEvents, obs.levelEvents
+	screen := obs.screen
+	m.obsMu.Unlock()
+
+	placed := false
+	for i, bp := range area {
+		after := m.GetBlock(bp.X, bp.Y, bp.Z)
+		if after == before[i] {
+			continue
+		}
+		res.Changes = append(res.Changes, BlockChange{Pos: bp, Before: before[i], After: after})
+		if before[i] == 0 && (i == 0 || bp == in.Pos.Neighbor(in.Face)) {
+			placed = true
+		}
+	}
+
+	switch {
+	case screen:
+		res.Effect = EffectContainerOpened
+	case placed:
+		res.Effect = EffectBlockPlaced
+	case len(res.Changes) > 0 && res.Changes[0].Pos == in.Pos:
+		res.Effect = EffectBlockChanged
+	default:
+		res.Effect = EffectNone
+	}
+}
+
+func (m *Module) setObserver(obs *observer) {
+	m.obsMu.Lock()
+	m.observer = obs
+	m.obsMu.Unlock()
+}
+
+// observe records packets relevant to the in-flight interaction, if any.
+func (m *Module) observe(pkt *jp.WirePacket) {
+	m.obsMu.Lock()
+	defer m.obsMu.Unlock()
+	obs := m.observer
+	if obs == nil {
+		return
+	}
+
+	switch pkt.PacketID {
+	case packet_ids.S2COpenScreenID:
+		var d packets.S2COpenScreen
+		if err := pkt.ReadInto(&d); err == nil {
+			obs.screen = true
+			obs.windowID, obs.menuType = int32(d.WindowId), int32(d.WindowType)
+		}
+	case packet_ids.S2CBlockEventID:
+		var d packets.S2CBlockEvent
+		if err := pkt.ReadInto(&d); err == nil {
+			pos := geom.BlockPos{X: d.Location.X, Y: d.Location.Y, Z: d.Location.Z}
+			if pos == obs.pos {
+				obs.blockEvents = append(obs.blockEvents, BlockEvent{Pos: pos, Action: uint8(d.ActionId), Parameter: uint8(d.ActionParameter)})
+			}
+		}
+	case packet_ids.S2CLevelEventID:
+		var d packets.S2CLevelEvent
+		if err := pkt.ReadInto(&d); err == nil {
+			pos := geom.BlockPos{X: d.Location.X, Y: d.Location.Y, Z: d.Location.Z}
+			if pos.ManhattanDistance(obs.pos) <= 1 {
+				obs.levelEvents = append(obs.levelEvents, int32(d.Event))
+			}
+		}
+	}
+}
+
+func retryable(err error) bool {
+	return errors.Is(err, ErrBlockChanged) || errors.Is(err, ErrNoAck) || errors.Is(err, ErrNotConfirmed)
+}
+
+// waitAck blocks until the server has acked seq or timeout elapses (in
+// ticks, see client.WaitTicks).
+func (m *Module) waitAck(seq int32, timeout time.Duration) bool {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	deadline := m.client.AfterTicks(ctx, client.Ticks(timeout))
+	for {
+		m.ackMu.Lock()
+		acked, ch := m.ackedSeq, m.ackCh
+		m.ackMu.Unlock()
+		if acked >= seq {
+			return true
+		}
+		select {
+		case <-ch:
+		case <-deadline:
+			return false
+		}
+	}
+}
+
+func (m *Module) handleBlockChangedAck(pkt *jp.WirePacket) {
+	var d packets.S2CBlockChangedAck
+	if err := pkt.ReadInto(&d); err != nil {
+		return
+	}
+	m.ackMu.Lock()
+	if seq := int32(d.SequenceId); seq > m.ackedSeq {
+		m.ackedSeq = seq
+	}
+	close(m.ackCh)
+	m.ackCh = make(chan struct{})
+	m.ackMu.Unlock()
+}
+
+func (m *Module) resetAcks() {
+	m.ackMu.Lock()
+	m.ackedSeq = 0
+	m.ackMu.Unlock()
+}
@@ -0,0 +1,177 @@
+package world
+
+import (
+	"encoding/json"
+	"io"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/go-mclib/client/pkg/geom"
+	"github.com/go-mclib/data/pkg/data/blocks"
+)
+
+// DefaultJournalSize is how many block changes the journal keeps.
+const DefaultJournalSize = 10000
+
+// BulkEditSize is how many protected blocks one update must change to be
+// reported as a bulk edit: a WorldEdit-style //set, /fill or an explosion
+// rather than a player working by hand.
+const BulkEditSize = 64
+
+// JournalEntry is a change to a block in a protected region.
+type JournalEntry struct {
+	Time   time.Time     `json:"time"`
+	Region string        `json:"region"`
+	Pos    geom.BlockPos `json:"pos"`
+	Old    int32         `json:"old"` // -1 if the chunk wasn't loaded
+	New    int32         `json:"new"`
+	// Entity is who was breaking the block when it changed (see
+	// OnBlockBreakingNearby), 0 if unknown: placements, instant breaks,
+	// the bot's own changes and anything not done by hand.
+	Entity int32 `json:"entity,omitempty"`
+}
+
+// RegionModification is the changes one block update made to a protected
+// region.
+type RegionModification struct {
+	Region  string
+	Changes []JournalEntry
+	Bulk    bool // at least BulkEditSize blocks at once
+}
+
+// JournalQuery selects journal entries; zero fields match everything.
+type JournalQuery struct {
+	Region string
+	Since  time.Time
+	Until  time.Time
+	Entity int32
+}
+
+func (q JournalQuery) matches(c JournalEntry) bool {
+	return (q.Region == "" || c.Region == q.Region) &&
+		(q.Since.IsZero() || !c.Time.Before(q.Since)) &&
+		(q.Until.IsZero() || c.Time.Before(q.Until)) &&
+		(q.Entity == 0 || c.Entity == q.Entity)
+}
+
+// Protect starts journaling block changes in r under name, replacing a
+// region of the same name. Chunk loads aren't changes: only block updates
+// the server sends while the chunk is loaded are journaled.
+func (m *Module) Protect(name string, r geom.Region) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.protected[name] = r
+}
+
+// Unprotect stops journaling the named region. Its entries stay in the
+// journal.
+func (m *Module) Unprotect(name string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.protected, name)
+}
+
+// ProtectedRegions returns the journaled regions by name.
+func (m *Module) ProtectedRegions() map[string]geom.Region {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return maps.Clone(m.protected)
+}
+
+// OnRegionModified is called for each block update changing blocks in a
+// protected region, once per region.
+func (m *Module) OnRegionModified(cb func(mod RegionModification)) {
+	m.onRegionModified = append(m.onRegionModified, cb)
+}
+
+// Journal returns the journal entries matching q, oldest first.
+func (m *Module) Journal(q JournalQuery) []JournalEntry {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var result []JournalEntry
+	for _, c := range m.journal {
+		if q.matches(c) {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+// ExportJournal writes the journaled block changes matching q as JSON lines,
+// with the block names next to the states, e.g.
+//
+//	{"time":"...","region":"base","pos":{"X":1,"Y":64,"Z":2},"old":1,"new":0,"old_block":"minecraft:stone","new_block":"minecraft:air"}
+func (m *Module) ExportJournal(w io.Writer, q JournalQuery) error {
+	enc := json.NewEncoder(w)
+	for _, c := range m.Journal(q) {
+		entry := struct {
+			JournalEntry
+			OldBlock string `json:"old_block,omitempty"`
+			NewBlock string `json:"new_block"`
+		}{JournalEntry: c, NewBlock: stateBlockName(c.New)}
+		if c.Old >= 0 {
+			entry.OldBlock = stateBlockName(c.Old)
+		}
+		if err := enc.Encode(entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func stateBlockName(stateID int32) string {
+	blockID, _ := blocks.StateProperties(int(stateID))
+	return blocks.BlockName(blockID)
+}
+
+// noteChange appends the change of the block at pos to changes for each
+// protected region containing it. Must be called with mu held, before the
+// new state is stored.
+func (m *Module) noteChange(changes []JournalEntry, pos geom.BlockPos, old, state int32) []JournalEntry {
+	if old == state {
+		return changes
+	}
+	for name, r := range m.protected {
+		if !r.Contains(pos) {
+			continue
+		}
+		c := JournalEntry{Time: m.client.Now(), Region: name, Pos: pos, Old: old, New: state}
+		for _, b := range m.breaking {
+			if b.Pos == pos {
+				c.Entity = b.Entity
+				break
+			}
+		}
+		changes = append(changes, c)
+	}
+	return changes
+}
+
+// journalChanges records the changes of one block update and reports them
+// per region. Must be called without mu held.
+func (m *Module) journalChanges(changes []JournalEntry) {
+	if len(changes) == 0 {
+		return
+	}
+	m.mu.Lock()
+	m.journal = append(m.journal, changes...)
+	if n := len(m.journal) - m.JournalSize; n > 0 && m.JournalSize > 0 {
+		m.journal = slices.Delete(m.journal, 0, n)
+	}
+	m.mu.Unlock()
+
+	if len(m.onRegionModified) == 0 {
+		return
+	}
+	byRegion := make(map[string][]JournalEntry)
+	for _, c := range changes {
+		byRegion[c.Region] = append(byRegion[c.Region], c)
+	}
+	for _, name := range slices.Sorted(maps.Keys(byRegion)) {
+		mod := RegionModification{Region: name, Changes: byRegion[name], Bulk: len(byRegion[name]) >= BulkEditSize}
+		for _, cb := range m.onRegionModified {
+			cb(mod)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package world
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/go-mclib/client/pkg/geom"
+	"github.com/go-mclib/data/pkg/packets"
+	jp "github.com/go-mclib/protocol/java_protocol"
+	ns "github.com/go-mclib/protocol/java_protocol/net_structures"
+)
+
+func TestJournal(t *testing.T) {
+	m, col := testModule()
+	col.SetBlockState(3, 64, 5, 1)
+	m.Protect("base", geom.NewCuboid(geom.BlockPos{X: 0, Y: 60, Z: 0}, geom.BlockPos{X: 7, Y: 70, Z: 7}))
+
+	var mods []RegionModification
+	m.OnRegionModified(func(mod RegionModification) { mods = append(mods, mod) })
+
+	update := func(x, y, z int, state int32) {
+		t.Helper()
+		pkt, err := jp.ToWire(&packets.S2CBlockUpdate{
+			Location: ns.Position{X: x, Y: y, Z: z},
+			BlockId:  ns.VarInt(state),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		m.handleBlockUpdate(pkt)
+	}
+
+	m.updateBreaking(42, geom.BlockPos{X: 3, Y: 64, Z: 5}, 9)
+	update(3, 64, 5, 0)  // broken by entity 42
+	update(12, 64, 5, 1) // outside the region
+	update(4, 64, 5, 0)  // unchanged air
+
+	got := m.Journal(JournalQuery{})
+	if len(got) != 1 {
+		t.Fatalf("journal = %v, want 1 entry", got)
+	}
+	if e := got[0]; e.Region != "base" || e.Old != 1 || e.New != 0 || e.Entity != 42 {
+		t.Errorf("entry = %+v", e)
+	}
+	if len(mods) != 1 || mods[0].Bulk {
+		t.Errorf("modifications = %+v, want one non-bulk", mods)
+	}
+	if got := m.Journal(JournalQuery{Entity: 7}); len(got) != 0 {
+		t.Errorf("query by another entity = %v", got)
+	}
+
+	var buf bytes.Buffer
+	if err := m.ExportJournal(&buf, JournalQuery{Region: "base"}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), `"new_block":"minecraft:air"`) {
+		t.Errorf("export = %s", buf.String())
+	}
+}
@@ -0,0 +1,194 @@
+package world
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/go-mclib/client/pkg/geom"
+	"github.com/go-mclib/data/pkg/data/blocks"
+	"github.com/go-mclib/data/pkg/packets"
+	jp "github.com/go-mclib/protocol/java_protocol"
+)
+
+// level events (LevelEvent.SOUND_PLAY_JUKEBOX_SONG, SOUND_STOP_JUKEBOX_SONG)
+const (
+	LevelEventJukeboxPlay = 1010
+	LevelEventJukeboxStop = 1011
+)
+
+var (
+	jukeboxBlockID   = blocks.BlockID("minecraft:jukebox")
+	noteBlockBlockID = blocks.BlockID("minecraft:note_block")
+)
+
+// registryNamer is implemented by the protocol module, looked up by interface
+// so world doesn't depend on it.
+type registryNamer interface {
+	RegistryEntryName(registry string, id int32) string
+}
+
+// Note is a note block playing, as seen in its block event.
+type Note struct {
+	Pos        geom.BlockPos
+	Instrument string // e.g. "harp", "bass", "bell"; from the block state
+	Note       int    // 0-24, two octaves of semitones
+}
+
+// OnRecordPlaying is called when a jukebox starts playing. disc is the
+// jukebox song (e.g. "minecraft:cat"), or "song#<id>" if the server's
+// registry wasn't received.
+func (m *Module) OnRecordPlaying(cb func(pos geom.BlockPos, disc string)) {
+	m.onRecordPlaying = append(m.onRecordPlaying, cb)
+}
+
+// OnRecordStopped is called when a jukebox stops playing: the disc was
+// ejected, the song ended or the jukebox was broken.
+func (m *Module) OnRecordStopped(cb func(pos geom.BlockPos)) {
+	m.onRecordStopped = append(m.onRecordStopped, cb)
+}
+
+// OnNotePlayed is called when a note block within range plays.
+func (m *Module) OnNotePlayed(cb func(n Note)) {
+	m.onNotePlayed = append(m.onNotePlayed, cb)
+}
+
+// PlayingRecord returns the song the jukebox at pos is playing. Only songs
+// started while the chunk was loaded are known.
+func (m *Module) PlayingRecord(pos geom.BlockPos) (string, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	disc, ok := m.records[pos]
+	return disc, ok
+}
+
+// PlayingRecords returns every jukebox known to be playing.
+func (m *Module) PlayingRecords() map[geom.BlockPos]string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	result := make(map[geom.BlockPos]string, len(m.records))
+	for pos, disc := range m.records {
+		result[pos] = disc
+	}
+	return result
+}
+
+func (m *Module) songName(id int32) string {
+	if r, ok := m.client.Module("protocol").(registryNamer); ok {
+		if name := r.RegistryEntryName("minecraft:jukebox_song", id); name != "" {
+			return name
+		}
+	}
+	return fmt.Sprintf("song#%d", id)
+}
+
+func (m *Module) handleLevelEvent(pkt *jp.WirePacket) {
+	var d packets.S2CLevelEvent
+	if err := pkt.ReadInto(&d); err != nil {
+		return
+	}
+	pos := geom.BlockPos{X: d.Location.X, Y: d.Location.Y, Z: d.Location.Z}
+
+	switch d.Event {
+	case LevelEventJukeboxPlay:
+		disc := m.songName(int32(d.Data))
+		m.mu.Lock()
+		m.records[pos] = disc
+		m.mu.Unlock()
+		for _, cb := range m.onRecordPlaying {
+			cb(pos, disc)
+		}
+	case LevelEventJukeboxStop:
+		m.mu.Lock()
+		delete(m.records, pos)
+		m.mu.Unlock()
+		for _, cb := range m.onRecordStopped {
+			cb(pos)
+		}
+	}
+	if isTrialEvent(int32(d.Event)) {
+		for _, cb := range m.onTrialEvent {
+			cb(pos, int32(d.Event), int32(d.Data))
+		}
+	}
+}
+
+// handleBlockEvent reports note blocks. NoteBlock.playNote sends a bare
+// block event; the client takes instrument and pitch from the block state.
+func (m *Module) handleBlockEvent(pkt *jp.WirePacket) {
+	if m.onNotePlayed == nil {
+		return
+	}
+	var d packets.S2CBlockEvent
+	if err := pkt.ReadInto(&d); err != nil || int32(d.BlockType) != noteBlockBlockID {
+		return
+	}
+	n := Note{Pos: geom.BlockPos{X: d.Location.X, Y: d.Location.Y, Z: d.Location.Z}}
+	blockID, props := blocks.StateProperties(int(m.GetBlock(n.Pos.X, n.Pos.Y, n.Pos.Z)))
+	if blockID != noteBlockBlockID {
+		return // chunk not loaded
+	}
+	n.Instrument = props["instrument"]
+	n.Note, _ = strconv.Atoi(props["note"])
+	for _, cb := range m.onNotePlayed {
+		cb(n)
+	}
+}
+
+// forgetRecords drops jukeboxes in an unloaded chunk; the server doesn't
+// resend the song when the chunk comes back.
+func (m *Module) forgetRecords(cx, cz int32) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for pos := range m.records {
+		if pos.Chunk() == (geom.ChunkPos{X: cx, Z: cz}) {
+			delete(m.records, pos)
+		}
+	}
+}
+
+func jukeboxHasRecord(stateID int32) (jukebox, hasRecord bool) {
+	blockID, props := blocks.StateProperties(int(stateID))
+	return blockID == jukeboxBlockID, props["has_record"] == "true"
+}
+
+// InsertDisc right-clicks the empty jukebox at pos with the music disc held
+// in hand (HandMain or HandOff) and confirms that it started playing. prepare
+// runs before each attempt, e.g. to look at the jukebox.
+func (m *Module) InsertDisc(pos geom.BlockPos, hand int8, prepare func()) InteractionResult {
+	return m.Interact(Interaction{
+		Pos:     pos,
+		Face:    FaceTop,
+		Hand:    hand,
+		CursorX: 0.5, CursorY: 1, CursorZ: 0.5,
+		Expect: func(stateID int32) bool {
+			jukebox, hasRecord := jukeboxHasRecord(stateID)
+			return jukebox && !hasRecord
+		},
+		Prepare: prepare,
+		// the play level event is sent before the ack
+		Confirm: func() bool {
+			_, playing := m.PlayingRecord(pos)
+			return playing
+		},
+	})
+}
+
+// EjectDisc right-clicks the jukebox at pos to pop its disc out as an item
+// entity and confirms that the jukebox is empty.
+func (m *Module) EjectDisc(pos geom.BlockPos, prepare func()) InteractionResult {
+	return m.Interact(Interaction{
+		Pos:     pos,
+		Face:    FaceTop,
+		Hand:    HandMain,
+		CursorX: 0.5, CursorY: 1, CursorZ: 0.5,
+		Expect: func(stateID int32) bool {
+			jukebox, hasRecord := jukeboxHasRecord(stateID)
+			return jukebox && hasRecord
+		},
+		Prepare: prepare,
+		Confirm: func() bool {
+			_, hasRecord := jukeboxHasRecord(m.GetBlock(pos.X, pos.Y, pos.Z))
+			return !hasRecord
+		},
+	})
+}
@@ -0,0 +1,106 @@
+package world
+
+import (
+	"github.com/go-mclib/client/pkg/geom"
+	"github.com/go-mclib/data/pkg/data/chunks"
+	"github.com/go-mclib/data/pkg/packets"
+	jp "github.com/go-mclib/protocol/java_protocol"
+	ns "github.com/go-mclib/protocol/java_protocol/net_structures"
+)
+
+// lightColumn holds the light of a chunk column, a nibble per block and
+// 2048 bytes per section. Index 0 is the section below the world, as in the
+// light masks. A nil section has no data; an empty one is all dark.
+type lightColumn struct {
+	sky, block [][]byte
+}
+
+var darkSection = []byte{}
+
+func newLightColumn(sections int) *lightColumn {
+	return &lightColumn{
+		sky:   make([][]byte, sections+2),
+		block: make([][]byte, sections+2),
+	}
+}
+
+// apply merges light data from a chunk or light update packet: sections in
+// a mask get the next array, sections in an empty mask go dark and the rest
+// keep what they had.
+func (l *lightColumn) apply(d *ns.LightData) {
+	merge := func(dst [][]byte, mask, empty *ns.BitSet, arrays [][]byte) {
+		next := 0
+		for i := range dst {
+			switch {
+			case mask.Get(i) && next < len(arrays):
+				dst[i] = arrays[next]
+				next++
+			case empty.Get(i):
+				dst[i] = darkSection
+			}
+		}
+	}
+	merge(l.sky, &d.SkyLightMask, &d.EmptySkyLightMask, d.SkyLightArrays)
+	merge(l.block, &d.BlockLightMask, &d.EmptyBlockLightMask, d.BlockLightArrays)
+}
+
+// level returns the light at pos from one of the column's section lists.
+func lightLevel(sections [][]byte, pos geom.BlockPos) (int, bool) {
+	i := chunks.SectionIndex(pos.Y) + 1
+	if i < 0 || i >= len(sections) || sections[i] == nil {
+		return 0, false
+	}
+	data := sections[i]
+	if len(data) == 0 {
+		return 0, true
+	}
+	idx := (pos.Y&15)<<8 | (pos.Z&15)<<4 | pos.X&15
+	if idx>>1 >= len(data) {
+		return 0, false
+	}
+	return int(data[idx>>1]>>(4*(idx&1))) & 15, true
+}
+
+// BlockLight returns the light from light sources (torches, lava, ...) at
+// pos, 0-15, as last sent by the server. ok is false if the chunk isn't
+// loaded or the server sent no light for the section.
+func (m *Module) BlockLight(pos geom.BlockPos) (level int, ok bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	l := m.light[pos.Chunk().Key()]
+	if l == nil {
+		return 0, false
+	}
+	return lightLevel(l.block, pos)
+}
+
+// SkyLight returns the light from the sky at pos, 0-15, regardless of the
+// time of day. ok is false as for BlockLight; the server sends no sky light
+// for sections high above the terrain, which are fully lit.
+func (m *Module) SkyLight(pos geom.BlockPos) (level int, ok bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	l := m.light[pos.Chunk().Key()]
+	if l == nil {
+		return 0, false
+	}
+	return lightLevel(l.sky, pos)
+}
+
+func (m *Module) handleLightUpdate(pkt *jp.WirePacket) {
+	var d packets.S2CLightUpdate
+	if err := pkt.ReadInto(&d); err != nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if l := m.light[ChunkKey(int32(d.ChunkX), int32(d.ChunkZ))]; l != nil {
+		l.apply(&d.LightData)
+	}
+}
+
+func (m *Module) forgetLight(cx, cz int32) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.light, ChunkKey(cx, cz))
+}
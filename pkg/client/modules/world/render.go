@@ -0,0 +1,221 @@
+package world
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"sync"
+
+	"github.com/go-mclib/data/pkg/data/blocks"
+	"github.com/go-mclib/data/pkg/data/chunks"
+	"github.com/go-mclib/data/pkg/data/items"
+)
+
+// Region is an inclusive rectangle of block columns.
+type Region struct {
+	MinX, MinZ int
+	MaxX, MaxZ int
+}
+
+// RegionAround returns the square region of the given radius centered on (x, z).
+func RegionAround(x, z, radius int) Region {
+	return Region{MinX: x - radius, MinZ: z - radius, MaxX: x + radius, MaxZ: z + radius}
+}
+
+// Contains reports whether the world position (x, z) lies in one of the region's columns.
+func (r Region) Contains(x, z float64) bool {
+	return x >= float64(r.MinX) && x < float64(r.MaxX+1) &&
+		z >= float64(r.MinZ) && z < float64(r.MaxZ+1)
+}
+
+// Marker is a point drawn on top of a rendered map, e.g. an entity position.
+type Marker struct {
+	X, Z  float64
+	Color color.RGBA
+}
+
+// RenderTopDown renders the loaded blocks of region as a top-down map with
+// zoom pixels per block (minimum 1). Each column shows its topmost non-air
+// block, shaded by height relative to the column to the north (like vanilla
+// maps); unloaded columns are transparent. Markers are drawn on top.
+//
+// Encode with image/png for debugging dumps (entities.MapMarkers provides
+// markers for tracked entities):
+//
+//	region := world.RegionAround(x, z, 64)
+//	img := w.RenderTopDown(region, 2, ents.MapMarkers(region)...)
+//	png.Encode(f, img)
+func (m *Module) RenderTopDown(region Region, zoom int, markers ...Marker) image.Image {
+	zoom = max(zoom, 1)
+	width := region.MaxX - region.MinX + 1
+	height := region.MaxZ - region.MinZ + 1
+	img := image.NewRGBA(image.Rect(0, 0, max(width, 0)*zoom, max(height, 0)*zoom))
+	if width <= 0 || height <= 0 {
+		return img
+	}
+
+	// surface heights of the row north of the current one, for shading
+	prevY := make([]int, width)
+	for i := range width {
+		_, prevY[i], _ = m.surfaceAt(region.MinX+i, region.MinZ-1)
+	}
+
+	for row := range height {
+		z := region.MinZ + row
+		for col := range width {
+			x := region.MinX + col
+			stateID, y, ok := m.surfaceAt(x, z)
+			northY := prevY[col]
+			prevY[col] = y
+			if !ok {
+				continue
+			}
+
+			c := blockColor(stateID)
+			switch {
+			case y > northY:
+				c = shade(c, 255)
+			case y < northY:
+				c = shade(c, 180)
+			default:
+				c = shade(c, 220)
+			}
+			fillRect(img, col*zoom, row*zoom, zoom, c)
+		}
+	}
+
+	size := max(zoom, 3)
+	for _, mk := range markers {
+		px := int(math.Floor((mk.X-float64(region.MinX))*float64(zoom))) - size/2
+		pz := int(math.Floor((mk.Z-float64(region.MinZ))*float64(zoom))) - size/2
+		fillRect(img, px, pz, size, mk.Color)
+	}
+	return img
+}
+
+// marker colors
+var (
+	MarkerSelf    = color.RGBA{255, 255, 255, 255}
+	MarkerPlayer  = color.RGBA{0, 160, 255, 255}
+	MarkerHostile = color.RGBA{255, 40, 40, 255}
+	MarkerOther   = color.RGBA{255, 200, 0, 255}
+)
+
+// surfaceAt returns the topmost non-air block state of a column and its Y.
+// ok is false if the chunk isn't loaded or the column is empty.
+func (m *Module) surfaceAt(x, z int) (stateID int32, y int, ok bool) {
+	chunkX, chunkZ := chunks.ChunkPos(x, z)
+	m.mu.RLock()
+	chunk := m.chunks[ChunkKey(chunkX, chunkZ)]
+	m.mu.RUnlock()
+	if chunk == nil {
+		return 0, chunks.MinY, false
+	}
+
+	for secIdx := len(chunk.Sections) - 1; secIdx >= 0; secIdx-- {
+		if chunk.Sections[secIdx] == nil {
+			continue
+		}
+		base := chunks.MinY + secIdx*16
+		for y := base + 15; y >= base; y-- {
+			if s := chunk.GetBlockState(x, y, z); s != 0 && !isAirState(s) {
+				return s, y, true
+			}
+		}
+	}
+	return 0, chunks.MinY, false
+}
+
+func fillRect(img *image.RGBA, x0, y0, size int, c color.RGBA) {
+	for y := y0; y < y0+size; y++ {
+		for x := x0; x < x0+size; x++ {
+			img.SetRGBA(x, y, c) // out-of-bounds writes are ignored
+		}
+	}
+}
+
+func shade(c color.RGBA, brightness uint32) color.RGBA {
+	return color.RGBA{
+		R: uint8(uint32(c.R) * brightness / 255),
+		G: uint8(uint32(c.G) * brightness / 255),
+		B: uint8(uint32(c.B) * brightness / 255),
+		A: c.A,
+	}
+}
+
+// blockPalette maps blocks to colors (vanilla MapColor values), by exact
+// name or by item tag (block items are named after their blocks); the first
+// entry naming a block wins.
+var blockPalette = []struct {
+	blocks []string // block names without the minecraft: namespace
+	tags   []string // item tags without the minecraft: namespace
+	color  color.RGBA
+}{
+	{blocks: []string{"water", "bubble_column", "kelp", "kelp_plant", "seagrass", "tall_seagrass"}, color: color.RGBA{64, 64, 255, 255}},
+	{blocks: []string{"lava"}, color: color.RGBA{255, 0, 0, 255}},
+	{blocks: []string{"grass_block"}, color: color.RGBA{127, 178, 56, 255}},
+	{blocks: []string{"short_grass", "tall_grass", "fern", "large_fern", "vine"}, tags: []string{"leaves"}, color: color.RGBA{0, 124, 0, 255}},
+	{blocks: []string{"snow", "snow_block", "powder_snow"}, color: color.RGBA{255, 255, 255, 255}},
+	{blocks: []string{"ice", "packed_ice", "blue_ice", "frosted_ice"}, color: color.RGBA{160, 160, 255, 255}},
+	{blocks: []string{"sandstone", "cut_sandstone", "chiseled_sandstone", "smooth_sandstone", "end_stone", "end_stone_bricks"}, tags: []string{"sand"}, color: color.RGBA{247, 233, 163, 255}},
+	{blocks: []string{"clay"}, color: color.RGBA{164, 168, 184, 255}},
+	{blocks: []string{"podzol"}, color: color.RGBA{129, 86, 49, 255}},
+	{blocks: []string{"farmland", "dirt_path", "mud"}, tags: []string{"dirt"}, color: color.RGBA{151, 109, 77, 255}},
+	{tags: []string{"logs", "planks"}, color: color.RGBA{143, 119, 72, 255}},
+	{blocks: []string{"netherrack"}, color: color.RGBA{112, 2, 0, 255}},
+	{blocks: []string{"obsidian", "crying_obsidian"}, color: color.RGBA{25, 25, 25, 255}},
+	{blocks: []string{"deepslate", "cobbled_deepslate", "polished_deepslate", "deepslate_bricks", "deepslate_tiles"}, color: color.RGBA{100, 100, 100, 255}},
+	{blocks: []string{"diorite", "polished_diorite"}, color: color.RGBA{255, 252, 245, 255}},
+	{blocks: []string{"granite", "polished_granite"}, color: color.RGBA{151, 109, 77, 255}},
+	{
+		blocks: []string{"stone", "cobblestone", "mossy_cobblestone", "smooth_stone", "andesite", "polished_andesite", "gravel", "tuff"},
+		tags:   []string{"stone_bricks", "coal_ores", "iron_ores", "copper_ores", "gold_ores", "redstone_ores", "emerald_ores", "lapis_ores", "diamond_ores"},
+		color:  color.RGBA{112, 112, 112, 255},
+	},
+	{tags: []string{"terracotta"}, color: color.RGBA{216, 127, 51, 255}},
+}
+
+var defaultBlockColor = color.RGBA{128, 128, 128, 255}
+
+// blockColors maps block ID -> color, built from blockPalette on first use
+var (
+	blockColors     map[int32]color.RGBA
+	blockColorsOnce sync.Once
+)
+
+func blockColor(stateID int32) color.RGBA {
+	blockColorsOnce.Do(func() {
+		blockColors = make(map[int32]color.RGBA)
+		add := func(name string, c color.RGBA) {
+			if id := blocks.BlockID(name); id >= 0 {
+				if _, ok := blockColors[id]; !ok {
+					blockColors[id] = c
+				}
+			}
+		}
+		for _, p := range blockPalette {
+			for _, name := range p.blocks {
+				add("minecraft:"+name, p.color)
+			}
+			for _, tag := range p.tags {
+				for _, item := range items.ItemTag("minecraft:" + tag) {
+					add(items.ItemName(item), p.color)
+				}
+			}
+		}
+	})
+	blockID, _ := blocks.StateProperties(int(stateID))
+	if c, ok := blockColors[blockID]; ok {
+		return c
+	}
+	return defaultBlockColor
+}
+
+func isAirState(stateID int32) bool {
+	blockID, _ := blocks.StateProperties(int(stateID))
+	switch blocks.BlockName(blockID) {
+	case "minecraft:air", "minecraft:cave_air", "minecraft:void_air":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,30 @@
+package world
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/go-mclib/data/pkg/data/blocks"
+)
+
+func TestBlockColorMatchesExactNames(t *testing.T) {
+	wood := color.RGBA{143, 119, 72, 255}
+	stone := color.RGBA{112, 112, 112, 255}
+	for _, tt := range []struct {
+		block string
+		want  color.RGBA
+	}{
+		{"minecraft:grass_block", color.RGBA{127, 178, 56, 255}},
+		{"minecraft:oak_log", wood},             // item tag minecraft:logs
+		{"minecraft:stripped_birch_wood", wood}, // also tagged
+		{"minecraft:deepslate_iron_ore", stone}, // minecraft:iron_ores
+		{"minecraft:sandstone", color.RGBA{247, 233, 163, 255}},
+		{"minecraft:redstone_wire", defaultBlockColor}, // not stone
+		{"minecraft:glowstone", defaultBlockColor},
+		{"minecraft:soul_sand", defaultBlockColor},
+	} {
+		if got := blockColor(blocks.DefaultStateID(blocks.BlockID(tt.block))); got != tt.want {
+			t.Errorf("blockColor(%s) = %v, want %v", tt.block, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,424 @@
+package world
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/go-mclib/client/pkg/client"
+	"github.com/go-mclib/client/pkg/geom"
+	"github.com/go-mclib/data/pkg/data/chunks"
+)
+
+const (
+	// DefaultScanBudget is the time the scanner spends per tick when it
+	// runs without the physics tick loop.
+	DefaultScanBudget = 2 * time.Millisecond
+	// DefaultScanCacheSize is how many finished scans are kept up to date
+	// for later requests.
+	DefaultScanCacheSize = 16
+)
+
+var errScanReset = errors.New("world reset during scan")
+
+// ScanJob asks the scanner for the blocks in a region matching a predicate.
+type ScanJob struct {
+	// Key names the predicate, e.g. "signs". Jobs with the same Key share
+	// scans: one within the bounds of a queued, running or cached scan is
+	// answered from it. Leave it empty for a one-off scan that isn't shared.
+	Key string
+	// Match reports whether a block state is wanted. It's called from the
+	// scanner and from block update handling, possibly at the same time,
+	// so keep it cheap and don't call into the world from it.
+	Match    func(stateID int32) bool
+	Region   geom.Region
+	Priority int // higher goes first
+}
+
+// ScanHit is a block found by a scan.
+type ScanHit struct {
+	Pos   geom.BlockPos
+	State int32
+}
+
+// scan is a scan of a job's bounding box, running or finished. Finished
+// scans stay cached and follow block updates.
+type scan struct {
+	key      string
+	match    func(stateID int32) bool
+	bounds   geom.Cuboid
+	priority int
+	seq      int // queue order among equal priorities
+
+	columns []geom.ChunkPos
+	next    int // columns[next:] are left to scan
+	scanned map[geom.ChunkPos]bool
+	hits    map[geom.BlockPos]int32
+
+	// updates to the column being read, applied over what was read
+	reading  bool
+	inflight geom.ChunkPos
+	touched  map[geom.BlockPos]int32
+
+	ready    chan struct{} // closed when columns are done (replaced on rescan)
+	err      error
+	lastUsed time.Time
+}
+
+func (s *scan) done() bool { return s.next == len(s.columns) }
+
+// Scan returns the blocks in job.Region whose state job.Match accepts, in
+// Y, Z, X order. The scanner works through queued jobs by priority in the
+// background, a column per work slice (see client.Submit) so large scans
+// share the tick's work budget with re-paths and don't hold up movement or
+// packet handling, and keeps up to ScanCacheSize finished scans
+// current as blocks change and chunks load, so repeating a scan is cheap.
+func (m *Module) Scan(ctx context.Context, job ScanJob) ([]ScanHit, error) {
+	if job.Match == nil || job.Region == nil {
+		return nil, errors.New("scan needs a predicate and a region")
+	}
+	bounds := job.Region.Bounds()
+	bounds.Min.Y = max(bounds.Min.Y, chunks.MinY)
+	bounds.Max.Y = min(bounds.Max.Y, chunks.MaxY-1)
+	if bounds.Volume() == 0 {
+		return nil, nil
+	}
+
+	m.scanMu.Lock()
+	s := m.findScan(job.Key, bounds)
+	if s == nil {
+		s = m.queueScan(job, bounds)
+	}
+	s.priority = max(s.priority, job.Priority)
+	ready := s.ready
+	m.scanMu.Unlock()
+
+	select {
+	case <-ready:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	m.scanMu.Lock()
+	defer m.scanMu.Unlock()
+	if s.err != nil {
+		return nil, s.err
+	}
+	s.lastUsed = time.Now()
+	var result []ScanHit
+	for pos, state := range s.hits {
+		if job.Region.Contains(pos) {
+			result = append(result, ScanHit{pos, state})
+		}
+	}
+	slices.SortFunc(result, func(a, b ScanHit) int {
+		if a.Pos.Y != b.Pos.Y {
+			return a.Pos.Y - b.Pos.Y
+		}
+		if a.Pos.Z != b.Pos.Z {
+			return a.Pos.Z - b.Pos.Z
+		}
+		return a.Pos.X - b.Pos.X
+	})
+	return result, nil
+}
+
+// findScan returns a shared scan covering bounds. Must be called with
+// scanMu held.
+func (m *Module) findScan(key string, bounds geom.Cuboid) *scan {
+	if key == "" {
+		return nil
+	}
+	for _, s := range m.scans {
+		if s.key == key && s.bounds.Contains(bounds.Min) && s.bounds.Contains(bounds.Max) {
+			return s
+		}
+	}
+	return nil
+}
+
+// queueScan adds a scan for job and starts the scanner if it's idle. Must
+// be called with scanMu held.
+func (m *Module) queueScan(job ScanJob, bounds geom.Cuboid) *scan {
+	minChunk, maxChunk := bounds.Min.Chunk(), bounds.Max.Chunk()
+	s := &scan{
+		key:      job.Key,
+		match:    job.Match,
+		bounds:   bounds,
+		priority: job.Priority,
+		seq:      m.scanSeq,
+		scanned:  make(map[geom.ChunkPos]bool),
+		hits:     make(map[geom.BlockPos]int32),
+		touched:  make(map[geom.BlockPos]int32),
+		ready:    make(chan struct{}),
+		lastUsed: time.Now(),
+	}
+	m.scanSeq++
+	for cx := minChunk.X; cx <= maxChunk.X; cx++ {
+		for cz := minChunk.Z; cz <= maxChunk.Z; cz++ {
+			s.columns = append(s.columns, geom.ChunkPos{X: cx, Z: cz})
+		}
+	}
+	m.scans = append(m.scans, s)
+	m.evictScans()
+	m.wakeScanner()
+	return s
+}
+
+// evictScans drops the least recently used finished scans over
+// ScanCacheSize. Must be called with scanMu held.
+func (m *Module) evictScans() {
+	finished := 0
+	for _, s := range m.scans {
+		if s.done() {
+			finished++
+		}
+	}
+	for ; finished > m.ScanCacheSize; finished-- {
+		oldest := -1
+		for i, s := range m.scans {
+			if s.done() && (oldest < 0 || s.lastUsed.Before(m.scans[oldest].lastUsed)) {
+				oldest = i
+			}
+		}
+		m.scans = slices.Delete(m.scans, oldest, oldest+1)
+	}
+}
+
+// wakeScanner starts the scanner unless it's running: as sliced work on
+// the tick thread (see client.Submit), or, with no physics module to run
+// that, on a goroutine of its own. Must be called with scanMu held.
+func (m *Module) wakeScanner() {
+	if m.scanCancel != nil {
+		return
+	}
+	gen := m.scanGen
+	if _, ok := m.client.Module("physics").(client.TickScheduler); ok {
+		m.scanCancel = m.client.Submit("world.scan", func() bool { return m.scanStep(gen) })
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	m.scanCancel = cancel
+	go m.runScanner(ctx, gen)
+}
+
+// scanStep scans a column of the most urgent unfinished scan. It reports
+// true once none are left or the scans were reset since gen.
+func (m *Module) scanStep(gen int) (done bool) {
+	m.scanMu.Lock()
+	if gen != m.scanGen {
+		m.scanMu.Unlock()
+		return true
+	}
+	s := m.nextScan()
+	if s == nil {
+		m.scanCancel()
+		m.scanCancel = nil
+		m.scanMu.Unlock()
+		return true
+	}
+	col := s.columns[s.next]
+	s.reading, s.inflight = true, col
+	m.scanMu.Unlock()
+
+	found := m.scanColumn(s, col)
+
+	m.scanMu.Lock()
+	defer m.scanMu.Unlock()
+	if gen == m.scanGen {
+		m.finishColumn(s, col, found)
+	}
+	return false
+}
+
+// runScanner runs scanner steps without a tick loop, up to ScanBudget per
+// tick, until none are left.
+func (m *Module) runScanner(ctx context.Context, gen int) {
+	start := time.Now()
+	for !m.scanStep(gen) {
+		m.scanMu.Lock()
+		budget := m.ScanBudget
+		m.scanMu.Unlock()
+		if time.Since(start) >= budget {
+			if m.client.WaitTicks(ctx, 1) != nil {
+				return
+			}
+			start = time.Now()
+		}
+	}
+}
+
+// nextScan returns the unfinished scan with the highest priority, the
+// oldest among equals. Must be called with scanMu held.
+func (m *Module) nextScan() *scan {
+	var best *scan
+	for _, s := range m.scans {
+		if s.done() {
+			continue
+		}
+		if best == nil || s.priority > best.priority || s.priority == best.priority && s.seq < best.seq {
+			best = s
+		}
+	}
+	return best
+}
+
+// scanColumn reads the blocks of a column within the scan's bounds and
+// returns those matching. The world lock is held one section at a time.
+func (m *Module) scanColumn(s *scan, col geom.ChunkPos) map[geom.BlockPos]int32 {
+	found := make(map[geom.BlockPos]int32)
+	minX, maxX := max(s.bounds.Min.X, int(col.X)*16), min(s.bounds.Max.X, int(col.X)*16+15)
+	minZ, maxZ := max(s.bounds.Min.Z, int(col.Z)*16), min(s.bounds.Max.Z, int(col.Z)*16+15)
+	matches := make(map[int32]bool)
+	states := make([]int32, 0, 16*16*16)
+
+	for secY := s.bounds.Min.Y &^ 15; secY <= s.bounds.Max.Y; secY += 16 {
+		minY, maxY := max(s.bounds.Min.Y, secY), min(s.bounds.Max.Y, secY+15)
+
+		states = states[:0]
+		m.mu.RLock()
+		chunk := m.chunks[col.Key()]
+		if chunk != nil {
+			for y := minY; y <= maxY; y++ {
+				for z := minZ; z <= maxZ; z++ {
+					for x := minX; x <= maxX; x++ {
+						states = append(states, chunk.GetBlockState(x, y, z))
+					}
+				}
+			}
+		}
+		m.mu.RUnlock()
+		if chunk == nil {
+			return found
+		}
+
+		i := 0
+		for y := minY; y <= maxY; y++ {
+			for z := minZ; z <= maxZ; z++ {
+				for x := minX; x <= maxX; x++ {
+					state := states[i]
+					i++
+					match, ok := matches[state]
+					if !ok {
+						match = s.match(state)
+						matches[state] = match
+					}
+					if match {
+						found[geom.BlockPos{X: x, Y: y, Z: z}] = state
+					}
+				}
+			}
+		}
+	}
+	return found
+}
+
+// finishColumn records a scanned column, with the updates that came in
+// while it was read. Must be called with scanMu held.
+func (m *Module) finishColumn(s *scan, col geom.ChunkPos, found map[geom.BlockPos]int32) {
+	for pos, state := range found {
+		s.hits[pos] = state
+	}
+	for pos, state := range s.touched {
+		s.setHit(pos, state)
+	}
+	clear(s.touched)
+	s.reading = false
+	s.scanned[col] = true
+	s.next++
+	if !s.done() {
+		return
+	}
+	close(s.ready)
+	if s.key == "" {
+		m.scans = slices.DeleteFunc(m.scans, func(o *scan) bool { return o == s })
+	}
+	m.evictScans()
+}
+
+// setHit records the state of a block in a scanned column.
+func (s *scan) setHit(pos geom.BlockPos, state int32) {
+	if s.match(state) {
+		s.hits[pos] = state
+	} else {
+		delete(s.hits, pos)
+	}
+}
+
+// updateScans keeps scans current with a changed block.
+func (m *Module) updateScans(x, y, z int, stateID int32) {
+	pos := geom.BlockPos{X: x, Y: y, Z: z}
+	m.scanMu.Lock()
+	defer m.scanMu.Unlock()
+	for _, s := range m.scans {
+		if !s.bounds.Contains(pos) {
+			continue
+		}
+		switch col := pos.Chunk(); {
+		case s.scanned[col]:
+			s.setHit(pos, stateID)
+		case s.reading && s.inflight == col:
+			s.touched[pos] = stateID
+		}
+	}
+}
+
+// rescanColumn queues a (re)loaded column again in the scans covering it.
+func (m *Module) rescanColumn(cx, cz int32) {
+	col := geom.ChunkPos{X: cx, Z: cz}
+	m.scanMu.Lock()
+	defer m.scanMu.Unlock()
+	for _, s := range m.scans {
+		// a column being read may have been read before the load
+		if !s.scanned[col] && !(s.reading && s.inflight == col) {
+			continue
+		}
+		delete(s.scanned, col)
+		s.dropHits(col)
+		if s.done() {
+			s.ready = make(chan struct{})
+		}
+		s.columns = append(s.columns, col)
+		m.wakeScanner()
+	}
+}
+
+// forgetColumn drops the hits of an unloaded column. The column counts as
+// scanned (empty) until it loads again.
+func (m *Module) forgetColumn(cx, cz int32) {
+	col := geom.ChunkPos{X: cx, Z: cz}
+	m.scanMu.Lock()
+	defer m.scanMu.Unlock()
+	for _, s := range m.scans {
+		if s.scanned[col] {
+			s.dropHits(col)
+		}
+	}
+}
+
+func (s *scan) dropHits(col geom.ChunkPos) {
+	for pos := range s.hits {
+		if pos.Chunk() == col {
+			delete(s.hits, pos)
+		}
+	}
+}
+
+// resetScans stops the scanner and fails the waiting scans.
+func (m *Module) resetScans() {
+	m.scanMu.Lock()
+	defer m.scanMu.Unlock()
+	m.scanGen++
+	if m.scanCancel != nil {
+		m.scanCancel()
+		m.scanCancel = nil
+	}
+	for _, s := range m.scans {
+		if !s.done() {
+			s.err = errScanReset
+			close(s.ready)
+		}
+	}
+	m.scans = nil
+}
@@ -0,0 +1,55 @@
+package world
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-mclib/client/pkg/geom"
+)
+
+func TestScanCachesAndFollowsUpdates(t *testing.T) {
+	m, col := testModule()
+	col.SetBlockState(3, 64, 5, 7)
+
+	calls := 0
+	job := ScanJob{
+		Key:    "sevens",
+		Match:  func(state int32) bool { calls++; return state == 7 },
+		Region: geom.NewCuboid(geom.BlockPos{X: 0, Y: 60, Z: 0}, geom.BlockPos{X: 15, Y: 70, Z: 15}),
+	}
+	hits, err := m.Scan(context.Background(), job)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hits) != 1 || hits[0].Pos != (geom.BlockPos{X: 3, Y: 64, Z: 5}) {
+		t.Fatalf("hits = %v", hits)
+	}
+
+	// a smaller region with the same key is answered from the cache, and
+	// block updates are applied to it
+	calls = 0
+	col.SetBlockState(4, 65, 5, 7)
+	m.updateScans(4, 65, 5, 7)
+	job.Region = geom.NewCuboid(geom.BlockPos{X: 0, Y: 64, Z: 0}, geom.BlockPos{X: 8, Y: 66, Z: 8})
+	hits, err = m.Scan(context.Background(), job)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hits) != 2 || hits[1].Pos != (geom.BlockPos{X: 4, Y: 65, Z: 5}) {
+		t.Fatalf("hits after update = %v", hits)
+	}
+	if calls != 1 {
+		t.Errorf("cached scan called Match %d times, want 1 (the update)", calls)
+	}
+
+	// a reloaded chunk is scanned again
+	col.SetBlockState(3, 64, 5, 0)
+	m.rescanColumn(0, 0)
+	hits, err = m.Scan(context.Background(), job)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hits) != 1 {
+		t.Fatalf("hits after reload = %v", hits)
+	}
+}
@@ -0,0 +1,187 @@
+package world
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/go-mclib/client/pkg/geom"
+	"github.com/go-mclib/data/pkg/packets"
+	jp "github.com/go-mclib/protocol/java_protocol"
+	ns "github.com/go-mclib/protocol/java_protocol/net_structures"
+	"github.com/go-mclib/protocol/nbt"
+)
+
+// block entity types of signs
+const (
+	SignBlockEntityType        = 7
+	HangingSignBlockEntityType = 8
+)
+
+// MaxSignLineLength is the longest line the server accepts in a sign update.
+const MaxSignLineLength = 384
+
+// ErrNoSignEditor is returned by WriteSign when the server hasn't opened a
+// sign editor for the sign.
+var ErrNoSignEditor = errors.New("no sign editor open")
+
+// SignEditor is a sign the server opened the editor for, after the player
+// placed or right-clicked it. Front is the side being edited.
+type SignEditor struct {
+	Pos   geom.BlockPos
+	Front bool
+}
+
+// OnSignEditor registers a callback for when the server opens a sign editor.
+func (m *Module) OnSignEditor(cb func(e SignEditor)) {
+	m.onSignEditor = append(m.onSignEditor, cb)
+}
+
+// OpenSignEditor returns the sign editor the server last opened, if it
+// hasn't been written yet.
+func (m *Module) OpenSignEditor() (SignEditor, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.signEditor == nil {
+		return SignEditor{}, false
+	}
+	return *m.signEditor, true
+}
+
+// EditSign right-clicks the sign at pos with hand and confirms that the
+// server opened its editor; write the text with WriteSign. Clicking a sign
+// with an empty hand (or any item without a use on signs) opens the side
+// facing the player. prepare runs before each attempt, e.g. to look at it.
+func (m *Module) EditSign(pos geom.BlockPos, face geom.Face, hand int8, prepare func()) InteractionResult {
+	m.closeSignEditor()
+	// the editor opens before the ack
+	return m.Interact(Interaction{
+		Pos:     pos,
+		Face:    face,
+		Hand:    hand,
+		CursorX: 0.5, CursorY: 0.5, CursorZ: 0.5,
+		Prepare: prepare,
+		Confirm: func() bool {
+			e, ok := m.OpenSignEditor()
+			return ok && e.Pos == pos
+		},
+	})
+}
+
+// PlaceSign places the sign held in hand against face of the block at pos,
+// sneaking so that containers don't open, and confirms that the server
+// opened the new sign's editor. A sign placed on the side of a block hangs
+// on its wall, facing away from it.
+func (m *Module) PlaceSign(pos geom.BlockPos, face geom.Face, hand int8, prepare func()) InteractionResult {
+	m.closeSignEditor()
+	sign := pos.Neighbor(face)
+	dx, dy, dz := face.Offset()
+	return m.Interact(Interaction{
+		Pos:     pos,
+		Face:    face,
+		Hand:    hand,
+		CursorX: 0.5 + float32(dx)/2, CursorY: 0.5 + float32(dy)/2, CursorZ: 0.5 + float32(dz)/2,
+		Sneak:   true,
+		Prepare: prepare,
+		Confirm: func() bool {
+			e, ok := m.OpenSignEditor()
+			return ok && e.Pos == sign
+		},
+	})
+}
+
+// WriteSign sends lines for the sign editor the server opened at pos. The
+// server updates the sign's block entity in response; see SignText.
+func (m *Module) WriteSign(pos geom.BlockPos, lines [4]string) error {
+	for i, l := range lines {
+		if utf8.RuneCountInString(l) > MaxSignLineLength {
+			return fmt.Errorf("sign line %d longer than %d characters", i, MaxSignLineLength)
+		}
+	}
+	m.mu.Lock()
+	e := m.signEditor
+	if e == nil || e.Pos != pos {
+		m.mu.Unlock()
+		return fmt.Errorf("%w at %v", ErrNoSignEditor, pos)
+	}
+	m.signEditor = nil
+	m.mu.Unlock()
+
+	return m.client.WritePacket(&packets.C2SSignUpdate{
+		Location:    ns.Position{X: pos.X, Y: pos.Y, Z: pos.Z},
+		IsFrontText: ns.Boolean(e.Front),
+		Line1:       ns.String(lines[0]),
+		Line2:       ns.String(lines[1]),
+		Line3:       ns.String(lines[2]),
+		Line4:       ns.String(lines[3]),
+	})
+}
+
+// SignText returns the plain text of the lines on one side of the sign at
+// pos, trimmed, four of them. ok is false if there's no sign block entity.
+func (m *Module) SignText(pos geom.BlockPos, front bool) (lines []string, ok bool) {
+	m.mu.RLock()
+	be := m.blockEntities[pos]
+	m.mu.RUnlock()
+	if be == nil || (be.Type != SignBlockEntityType && be.Type != HangingSignBlockEntityType) {
+		return nil, false
+	}
+	side := "back_text"
+	if front {
+		side = "front_text"
+	}
+	return signLines(be.Data.GetCompound(side)), true
+}
+
+// signLines reads the messages of a sign side, which hold text components
+// either as NBT or, from older servers, as JSON strings.
+func signLines(text nbt.Compound) []string {
+	lines := make([]string, 4)
+	if text == nil {
+		return lines
+	}
+	for i, msg := range text.GetList("messages").Elements {
+		if i >= len(lines) {
+			break
+		}
+		var s string
+		switch v := msg.(type) {
+		case nbt.String:
+			s = string(v)
+		case nbt.Compound:
+			s = v.GetString("text")
+		}
+		var tc ns.TextComponent
+		if json.Unmarshal([]byte(s), &tc) == nil {
+			s = tc.String()
+		}
+		lines[i] = strings.TrimSpace(s)
+	}
+	return lines
+}
+
+func (m *Module) handleOpenSignEditor(pkt *jp.WirePacket) {
+	var d packets.S2COpenSignEditor
+	if err := pkt.ReadInto(&d); err != nil {
+		return
+	}
+	e := SignEditor{
+		Pos:   geom.BlockPos{X: d.Location.X, Y: d.Location.Y, Z: d.Location.Z},
+		Front: bool(d.IsFrontText),
+	}
+	m.mu.Lock()
+	m.signEditor = &e
+	m.mu.Unlock()
+
+	for _, cb := range m.onSignEditor {
+		cb(e)
+	}
+}
+
+func (m *Module) closeSignEditor() {
+	m.mu.Lock()
+	m.signEditor = nil
+	m.mu.Unlock()
+}
@@ -0,0 +1,43 @@
+package world
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/go-mclib/client/pkg/geom"
+	"github.com/go-mclib/protocol/nbt"
+)
+
+func TestSignText(t *testing.T) {
+	m := New()
+	pos := geom.BlockPos{X: 1, Y: 64, Z: 2}
+	m.blockEntities[pos] = &BlockEntityData{
+		Type: SignBlockEntityType,
+		Data: nbt.Compound{
+			"front_text": nbt.Compound{
+				"messages": nbt.List{ElementType: nbt.TagString, Elements: []nbt.Tag{
+					nbt.String(`{"text":"#logs"}`),
+					nbt.String(" :food "),
+					nbt.String(`""`),
+					nbt.String(""),
+				}},
+			},
+		},
+	}
+
+	lines, ok := m.SignText(pos, true)
+	if want := []string{"#logs", ":food", "", ""}; !ok || !slices.Equal(lines, want) {
+		t.Errorf("front = %q, %v, want %q", lines, ok, want)
+	}
+	if lines, ok := m.SignText(pos, false); !ok || !slices.Equal(lines, make([]string, 4)) {
+		t.Errorf("back = %q, %v, want four empty lines", lines, ok)
+	}
+	if _, ok := m.SignText(pos.Offset(1, 0, 0), true); ok {
+		t.Error("read text where there's no sign")
+	}
+
+	if err := m.WriteSign(pos, [4]string{"x"}); !errors.Is(err, ErrNoSignEditor) {
+		t.Errorf("WriteSign without an editor = %v, want ErrNoSignEditor", err)
+	}
+}
@@ -0,0 +1,402 @@
+package world
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/go-mclib/client/pkg/geom"
+	"github.com/go-mclib/data/pkg/data/blocks"
+	"github.com/go-mclib/data/pkg/data/chunks"
+	block_shapes "github.com/go-mclib/data/pkg/data/hitboxes/blocks"
+)
+
+// DefaultTerrainLimit is how many candidates a terrain query returns unless
+// it says otherwise.
+const DefaultTerrainLimit = 10
+
+const (
+	defaultClearance   = 2  // blocks a player needs above the floor
+	defaultShelterSize = 64 // open blocks in a shelter
+)
+
+var (
+	waterID = blocks.BlockID("minecraft:water")
+	lavaID  = blocks.BlockID("minecraft:lava")
+)
+
+// isFluid reports whether a block state is or holds water or lava.
+func isFluid(stateID int32) bool {
+	blockID, props := blocks.StateProperties(int(stateID))
+	return blockID == waterID || blockID == lavaID || props["waterlogged"] == "true"
+}
+
+// isOpen reports whether a player can be in a block: no collision and no
+// fluid. Unloaded blocks (-1) aren't open.
+func isOpen(stateID int32) bool {
+	return stateID >= 0 && !block_shapes.HasCollision(stateID) && !isFluid(stateID)
+}
+
+// isFloor reports whether a player can stand on a block.
+func isFloor(stateID int32) bool {
+	return stateID > 0 && block_shapes.HasCollision(stateID) && !isFluid(stateID)
+}
+
+// canStand reports whether a player fits with their feet at p.
+func canStand(at func(geom.BlockPos) int32, p geom.BlockPos) bool {
+	return isFloor(at(p.Offset(0, -1, 0))) && isOpen(at(p)) && isOpen(at(p.Offset(0, 1, 0)))
+}
+
+// queryBounds returns the cube of radius around center, within the world's
+// height.
+func queryBounds(center geom.BlockPos, radius int) geom.Cuboid {
+	b := geom.Cuboid{
+		Min: center.Offset(-radius, -radius, -radius),
+		Max: center.Offset(radius, radius, radius),
+	}
+	b.Min.Y = max(b.Min.Y, chunks.MinY)
+	b.Max.Y = min(b.Max.Y, chunks.MaxY-1)
+	return b
+}
+
+func limitOr(limit int) int {
+	if limit <= 0 {
+		return DefaultTerrainLimit
+	}
+	return limit
+}
+
+var errNoRadius = errors.New("terrain query needs a positive radius")
+
+// FlatAreaQuery asks FindFlatAreas for places to build.
+type FlatAreaQuery struct {
+	Center geom.BlockPos
+	Radius int // blocks along each axis
+
+	Width, Length int // floor size along X and Z
+	// Clearance is the free space needed above the floor (0 = 2).
+	Clearance int
+	// MaxStep allows floor blocks up to this many blocks above or below the
+	// rest, to fill or dig away. 0 wants them level.
+	MaxStep int
+	Limit   int // candidates returned (0 = DefaultTerrainLimit)
+}
+
+// FlatArea is a place to build found by FindFlatAreas.
+type FlatArea struct {
+	// Floor is the layer of blocks to build on, at the height most of the
+	// floor has.
+	Floor geom.Cuboid
+	// Unevenness is the number of blocks to fill or dig to level the floor.
+	Unevenness int
+	Distance   float64 // from the query center to the middle of the floor
+}
+
+// FindFlatAreas returns places within q.Radius of q.Center with a floor of
+// q.Width×q.Length blocks to stand on, open sky or not, and q.Clearance
+// free above each, levelest first, then nearest. Candidates don't overlap.
+// The floor of a column is its topmost block with room above.
+func (m *Module) FindFlatAreas(ctx context.Context, q FlatAreaQuery) ([]FlatArea, error) {
+	if q.Radius <= 0 {
+		return nil, errNoRadius
+	}
+	if q.Width <= 0 || q.Length <= 0 {
+		return nil, errors.New("flat area needs a positive size")
+	}
+	clearance := q.Clearance
+	if clearance <= 0 {
+		clearance = defaultClearance
+	}
+	bounds := queryBounds(q.Center, q.Radius)
+	r := m.snapshot(geom.Cuboid{Min: bounds.Min, Max: bounds.Max.Offset(0, clearance, 0)})
+
+	// floor height per column, by Z then X; ok false where there's none
+	dx, _, dz := bounds.Size()
+	type floor struct {
+		y  int
+		ok bool
+	}
+	floors := make([]floor, dx*dz)
+	for z := bounds.Min.Z; z <= bounds.Max.Z; z++ {
+		for x := bounds.Min.X; x <= bounds.Max.X; x++ {
+		column:
+			for y := bounds.Max.Y; y >= bounds.Min.Y; y-- {
+				if !isFloor(r.At(geom.BlockPos{X: x, Y: y, Z: z})) {
+					continue
+				}
+				for k := 1; k <= clearance; k++ {
+					if !isOpen(r.At(geom.BlockPos{X: x, Y: y + k, Z: z})) {
+						continue column
+					}
+				}
+				floors[(z-bounds.Min.Z)*dx+x-bounds.Min.X] = floor{y, true}
+				break
+			}
+		}
+	}
+
+	var found []FlatArea
+	heights := make(map[int]int)
+	for z0 := bounds.Min.Z; z0+q.Length-1 <= bounds.Max.Z; z0++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+	window:
+		for x0 := bounds.Min.X; x0+q.Width-1 <= bounds.Max.X; x0++ {
+			clear(heights)
+			lo, hi := chunks.MaxY, chunks.MinY
+			for z := z0; z < z0+q.Length; z++ {
+				for x := x0; x < x0+q.Width; x++ {
+					f := floors[(z-bounds.Min.Z)*dx+x-bounds.Min.X]
+					if !f.ok {
+						continue window
+					}
+					heights[f.y]++
+					lo, hi = min(lo, f.y), max(hi, f.y)
+				}
+			}
+			if hi-lo > q.MaxStep {
+				continue
+			}
+			level := lo
+			for y, n := range heights {
+				if n > heights[level] || n == heights[level] && y < level {
+					level = y
+				}
+			}
+			uneven := 0
+			for y, n := range heights {
+				uneven += n * max(y-level, level-y)
+			}
+			area := geom.Cuboid{
+				Min: geom.BlockPos{X: x0, Y: level, Z: z0},
+				Max: geom.BlockPos{X: x0 + q.Width - 1, Y: level, Z: z0 + q.Length - 1},
+			}
+			middle := geom.Vec3{
+				X: float64(x0) + float64(q.Width)/2,
+				Y: float64(level + 1),
+				Z: float64(z0) + float64(q.Length)/2,
+			}
+			found = append(found, FlatArea{area, uneven, middle.Distance(q.Center.Bottom())})
+		}
+	}
+
+	slices.SortStableFunc(found, func(a, b FlatArea) int {
+		if a.Unevenness != b.Unevenness {
+			return a.Unevenness - b.Unevenness
+		}
+		return compareFloat(a.Distance, b.Distance)
+	})
+	var result []FlatArea
+	for _, a := range found {
+		if len(result) == limitOr(q.Limit) {
+			break
+		}
+		if !slices.ContainsFunc(result, func(o FlatArea) bool { return overlaps(a.Floor, o.Floor) }) {
+			result = append(result, a)
+		}
+	}
+	return result, nil
+}
+
+// overlaps reports whether two floors share a column.
+func overlaps(a, b geom.Cuboid) bool {
+	return a.Min.X <= b.Max.X && b.Min.X <= a.Max.X && a.Min.Z <= b.Max.Z && b.Min.Z <= a.Max.Z
+}
+
+func compareFloat(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// ShelterQuery asks FindShelters for enclosed spaces.
+type ShelterQuery struct {
+	Center geom.BlockPos
+	Radius int // blocks along each axis; shelters lie entirely within
+	// MaxSize is the most open blocks a shelter may have (0 = 64).
+	MaxSize int
+	// Lit only returns shelters whose whole floor has block light, where
+	// monsters can't spawn.
+	Lit   bool
+	Limit int // candidates returned (0 = DefaultTerrainLimit)
+}
+
+// Shelter is an enclosed space found by FindShelters.
+type Shelter struct {
+	Stand geom.BlockPos // the spot to stand in nearest the query center
+	Size  int           // open blocks inside
+	// Dark counts the floor spots without block light, where monsters can
+	// spawn. Spots whose light the server hasn't sent count as dark.
+	Dark     int
+	Distance float64 // from the query center to Stand
+}
+
+// Lit reports whether monsters can't spawn inside.
+func (s Shelter) Lit() bool { return s.Dark == 0 }
+
+// FindShelters returns spaces within q.Radius of q.Center that a player fits
+// in and that are closed off on all sides, roof included, by blocks with
+// collision (closed doors count): built huts, sealed caves and the like. Lit
+// ones come first, then the nearest. Spaces holding lava are left out.
+func (m *Module) FindShelters(ctx context.Context, q ShelterQuery) ([]Shelter, error) {
+	if q.Radius <= 0 {
+		return nil, errNoRadius
+	}
+	maxSize := q.MaxSize
+	if maxSize <= 0 {
+		maxSize = defaultShelterSize
+	}
+	bounds := queryBounds(q.Center, q.Radius)
+	r := m.snapshot(bounds)
+	dx, _, dz := bounds.Size()
+	index := func(p geom.BlockPos) int {
+		return ((p.Y-bounds.Min.Y)*dz+p.Z-bounds.Min.Z)*dx + p.X - bounds.Min.X
+	}
+	visited := make([]bool, len(r.States))
+	passable := func(state int32) bool { return state >= 0 && !block_shapes.HasCollision(state) }
+
+	var found []Shelter
+	var space []geom.BlockPos
+	for y := bounds.Min.Y; y <= bounds.Max.Y; y++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		for z := bounds.Min.Z; z <= bounds.Max.Z; z++ {
+			for x := bounds.Min.X; x <= bounds.Max.X; x++ {
+				seed := geom.BlockPos{X: x, Y: y, Z: z}
+				if visited[index(seed)] || !canStand(r.At, seed) {
+					continue
+				}
+
+				// flood the space around seed; reaching the edge of the
+				// bounds (or unloaded chunks) means it's open
+				space = append(space[:0], seed)
+				visited[index(seed)] = true
+				enclosed := true
+				for i := 0; i < len(space); i++ {
+					for f := geom.Face(0); f < 6; f++ {
+						n := space[i].Neighbor(f)
+						state := r.At(n)
+						if state < 0 {
+							enclosed = false
+							continue
+						}
+						if visited[index(n)] || !passable(state) {
+							continue
+						}
+						if blockID, _ := blocks.StateProperties(int(state)); blockID == lavaID {
+							enclosed = false
+						}
+						visited[index(n)] = true
+						space = append(space, n)
+					}
+				}
+				if !enclosed || len(space) > maxSize {
+					continue
+				}
+
+				s := Shelter{Size: len(space), Distance: -1}
+				for _, p := range space {
+					if !isFloor(r.At(p.Offset(0, -1, 0))) {
+						continue
+					}
+					if level, _ := m.BlockLight(p); level == 0 {
+						s.Dark++
+					}
+					if d := p.Bottom().Distance(q.Center.Bottom()); canStand(r.At, p) && (s.Distance < 0 || d < s.Distance) {
+						s.Stand, s.Distance = p, d
+					}
+				}
+				if q.Lit && !s.Lit() {
+					continue
+				}
+				found = append(found, s)
+			}
+		}
+	}
+
+	slices.SortStableFunc(found, func(a, b Shelter) int {
+		if a.Lit() != b.Lit() {
+			if a.Lit() {
+				return -1
+			}
+			return 1
+		}
+		return compareFloat(a.Distance, b.Distance)
+	})
+	return found[:min(len(found), limitOr(q.Limit))], nil
+}
+
+// FluidQuery asks FindFluidAccess for places to fill a bucket.
+type FluidQuery struct {
+	Center geom.BlockPos
+	Radius int  // blocks along each axis
+	Lava   bool // look for lava instead of water
+	Limit  int  // candidates returned (0 = DefaultTerrainLimit)
+}
+
+// FluidAccess is a place to fill a bucket found by FindFluidAccess.
+type FluidAccess struct {
+	Source geom.BlockPos // a source block with its top open
+	// Stand is a dry spot next to Source, level with it or one block up.
+	Stand    geom.BlockPos
+	Distance float64 // from the query center to Stand
+}
+
+// FindFluidAccess returns water (or lava) source blocks within q.Radius of
+// q.Center that can be reached from dry ground, nearest first, one per
+// standing spot. Sources are found with Scan, so repeated queries are cheap.
+func (m *Module) FindFluidAccess(ctx context.Context, q FluidQuery) ([]FluidAccess, error) {
+	if q.Radius <= 0 {
+		return nil, errNoRadius
+	}
+	key, fluidID := "terrain:water", waterID
+	if q.Lava {
+		key, fluidID = "terrain:lava", lavaID
+	}
+	hits, err := m.Scan(ctx, ScanJob{
+		Key: key,
+		Match: func(stateID int32) bool {
+			blockID, props := blocks.StateProperties(int(stateID))
+			return blockID == fluidID && props["level"] == "0" ||
+				!q.Lava && props["waterlogged"] == "true"
+		},
+		Region: queryBounds(q.Center, q.Radius),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	at := func(p geom.BlockPos) int32 { return m.GetBlock(p.X, p.Y, p.Z) }
+	stands := make(map[geom.BlockPos]bool)
+	var found []FluidAccess
+	for _, hit := range hits {
+		if !isOpen(at(hit.Pos.Offset(0, 1, 0))) {
+			continue
+		}
+		best := FluidAccess{Source: hit.Pos, Distance: -1}
+		for _, f := range []geom.Face{geom.FaceNorth, geom.FaceSouth, geom.FaceWest, geom.FaceEast} {
+			for dy := range 2 {
+				p := hit.Pos.Neighbor(f).Offset(0, dy, 0)
+				if stands[p] || !canStand(at, p) {
+					continue
+				}
+				if d := p.Bottom().Distance(q.Center.Bottom()); best.Distance < 0 || d < best.Distance {
+					best.Stand, best.Distance = p, d
+				}
+			}
+		}
+		if best.Distance >= 0 {
+			stands[best.Stand] = true
+			found = append(found, best)
+		}
+	}
+
+	slices.SortStableFunc(found, func(a, b FluidAccess) int { return compareFloat(a.Distance, b.Distance) })
+	return found[:min(len(found), limitOr(q.Limit))], nil
+}
@@ -0,0 +1,91 @@
+package world
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-mclib/client/pkg/geom"
+	"github.com/go-mclib/data/pkg/data/blocks"
+	"github.com/go-mclib/data/pkg/data/chunks"
+)
+
+// terrainModule returns a world of one chunk with a stone floor at y 63.
+func terrainModule() (*Module, *chunks.ChunkColumn) {
+	m, col := testModule()
+	stone := blocks.DefaultStateID(blocks.BlockID("minecraft:stone"))
+	for x := range 16 {
+		for z := range 16 {
+			col.SetBlockState(x, 63, z, stone)
+		}
+	}
+	return m, col
+}
+
+func TestFindFlatAreas(t *testing.T) {
+	m, col := terrainModule()
+	col.SetBlockState(2, 64, 2, blocks.DefaultStateID(blocks.BlockID("minecraft:stone")))
+
+	q := FlatAreaQuery{Center: geom.BlockPos{X: 8, Y: 64, Z: 8}, Radius: 7, Width: 3, Length: 3}
+	areas, err := m.FindFlatAreas(context.Background(), q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(areas) != DefaultTerrainLimit {
+		t.Fatalf("got %d areas, want %d", len(areas), DefaultTerrainLimit)
+	}
+	bump := geom.BlockPos{X: 2, Y: 63, Z: 2}
+	for i, a := range areas {
+		if a.Floor.Min.Y != 63 || a.Unevenness != 0 || a.Floor.Contains(bump) {
+			t.Errorf("area %d = %+v", i, a)
+		}
+		for _, o := range areas[:i] {
+			if overlaps(a.Floor, o.Floor) {
+				t.Errorf("areas %+v and %+v overlap", a, o)
+			}
+		}
+	}
+
+	// the bump is dug away when a step is allowed
+	q.MaxStep, q.Limit = 1, 1000
+	areas, err = m.FindFlatAreas(context.Background(), q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	last := areas[len(areas)-1]
+	if last.Unevenness != 1 || !last.Floor.Contains(bump) {
+		t.Errorf("last area = %+v, want the one with the bump", last)
+	}
+}
+
+func TestFindShelters(t *testing.T) {
+	m, col := terrainModule()
+	stone := blocks.DefaultStateID(blocks.BlockID("minecraft:stone"))
+	// a hollow box with a 2×2×2 room at x, z 10-11, y 64-65
+	for x := 9; x <= 12; x++ {
+		for y := 64; y <= 66; y++ {
+			for z := 9; z <= 12; z++ {
+				if x < 10 || x > 11 || y > 65 || z < 10 || z > 11 {
+					col.SetBlockState(x, y, z, stone)
+				}
+			}
+		}
+	}
+
+	q := ShelterQuery{Center: geom.BlockPos{X: 8, Y: 64, Z: 8}, Radius: 7}
+	shelters, err := m.FindShelters(context.Background(), q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(shelters) != 1 {
+		t.Fatalf("shelters = %+v, want the room", shelters)
+	}
+	s := shelters[0]
+	if s.Size != 8 || s.Stand != (geom.BlockPos{X: 10, Y: 64, Z: 10}) || s.Dark != 4 {
+		t.Errorf("shelter = %+v", s)
+	}
+
+	q.Lit = true
+	if shelters, _ := m.FindShelters(context.Background(), q); len(shelters) != 0 {
+		t.Errorf("lit shelters = %+v, want none", shelters)
+	}
+}
@@ -0,0 +1,228 @@
+package world
+
+import (
+	"time"
+
+	"github.com/go-mclib/client/pkg/client"
+	"github.com/go-mclib/client/pkg/geom"
+	"github.com/go-mclib/data/pkg/data/blocks"
+	"github.com/go-mclib/data/pkg/data/items"
+	ns "github.com/go-mclib/protocol/java_protocol/net_structures"
+	"github.com/go-mclib/protocol/nbt"
+)
+
+// trial chamber level events (LevelEvent.PARTICLES_TRIAL_SPAWNER_*, *_VAULT_*)
+const (
+	LevelEventTrialSpawnerSpawn         = 3011
+	LevelEventTrialSpawnerSpawnMob      = 3012
+	LevelEventTrialSpawnerDetectPlayer  = 3013 // data: number of players detected
+	LevelEventTrialSpawnerEjectItem     = 3014
+	LevelEventVaultActivate             = 3015
+	LevelEventVaultDeactivate           = 3016
+	LevelEventVaultEjectItem            = 3017
+	LevelEventTrialSpawnerOminousDetect = 3019
+	LevelEventTrialSpawnerBecomeOminous = 3020
+	LevelEventTrialSpawnerSpawnItem     = 3021 // ominous item spawner drops a potion or projectile
+)
+
+// DefaultTrialSpawnerCooldown is how long a trial spawner rests after
+// ejecting its reward (TrialSpawnerConfig target_cooldown_length, 36000
+// ticks). Datapacks may change it; it isn't sent to the client.
+const DefaultTrialSpawnerCooldown = 36000 * client.TickDuration
+
+var (
+	trialSpawnerBlockID = blocks.BlockID("minecraft:trial_spawner")
+	vaultBlockID        = blocks.BlockID("minecraft:vault")
+)
+
+// TrialSpawnerState is the trial_spawner_state block state property.
+type TrialSpawnerState string
+
+const (
+	TrialSpawnerInactive                 TrialSpawnerState = "inactive"
+	TrialSpawnerWaitingForPlayers        TrialSpawnerState = "waiting_for_players"
+	TrialSpawnerActive                   TrialSpawnerState = "active"
+	TrialSpawnerWaitingForRewardEjection TrialSpawnerState = "waiting_for_reward_ejection"
+	TrialSpawnerEjectingReward           TrialSpawnerState = "ejecting_reward"
+	TrialSpawnerCoolingDown              TrialSpawnerState = "cooldown"
+)
+
+// VaultState is the vault_state block state property.
+type VaultState string
+
+const (
+	VaultInactive  VaultState = "inactive"  // no player in range that can unlock it
+	VaultActive    VaultState = "active"    // accepts a key
+	VaultUnlocking VaultState = "unlocking" // key inserted, about to eject
+	VaultEjecting  VaultState = "ejecting"  // dropping its loot one item at a time
+)
+
+// TrialSpawner is a trial spawner as seen from its block state.
+type TrialSpawner struct {
+	Pos     geom.BlockPos
+	State   TrialSpawnerState
+	Ominous bool
+	// CooldownEnds estimates when a spawner in cooldown becomes active again
+	// (see DefaultTrialSpawnerCooldown). Zero if it isn't in cooldown or
+	// entered it before the chunk was loaded.
+	CooldownEnds time.Time
+}
+
+// Vault is a vault as seen from its block state and block entity.
+type Vault struct {
+	Pos     geom.BlockPos
+	State   VaultState
+	Ominous bool
+	// Key is the item that unlocks it: a trial key, or an ominous trial key
+	// for ominous vaults.
+	Key int32
+	// DisplayItem is the loot item the vault shows cycling inside, 0 if unknown.
+	DisplayItem int32
+	// Players are the UUIDs of players in range that haven't unlocked it yet.
+	// Vaults reward each player once, so the player can only unlock it if
+	// listed here.
+	Players []ns.UUID
+}
+
+// CanUnlock reports whether the player with uuid can put a key in the vault.
+func (v Vault) CanUnlock(uuid ns.UUID) bool {
+	if v.State != VaultActive {
+		return false
+	}
+	for _, p := range v.Players {
+		if p == uuid {
+			return true
+		}
+	}
+	return false
+}
+
+// GetTrialSpawner returns the trial spawner at pos, or false if there's none
+// (or its chunk isn't loaded).
+func (m *Module) GetTrialSpawner(pos geom.BlockPos) (TrialSpawner, bool) {
+	blockID, props := blocks.StateProperties(int(m.GetBlock(pos.X, pos.Y, pos.Z)))
+	if blockID != trialSpawnerBlockID {
+		return TrialSpawner{}, false
+	}
+	ts := TrialSpawner{
+		Pos:     pos,
+		State:   TrialSpawnerState(props["trial_spawner_state"]),
+		Ominous: props["ominous"] == "true",
+	}
+	if ts.State == TrialSpawnerCoolingDown {
+		m.mu.RLock()
+		if since, ok := m.spawnerCooldowns[pos]; ok {
+			ts.CooldownEnds = since.Add(DefaultTrialSpawnerCooldown)
+		}
+		m.mu.RUnlock()
+	}
+	return ts, true
+}
+
+// GetVault returns the vault at pos, or false if there's none (or its chunk
+// isn't loaded).
+func (m *Module) GetVault(pos geom.BlockPos) (Vault, bool) {
+	blockID, props := blocks.StateProperties(int(m.GetBlock(pos.X, pos.Y, pos.Z)))
+	if blockID != vaultBlockID {
+		return Vault{}, false
+	}
+	v := Vault{
+		Pos:     pos,
+		State:   VaultState(props["vault_state"]),
+		Ominous: props["ominous"] == "true",
+		Key:     items.TrialKey,
+	}
+	if v.Ominous {
+		v.Key = items.OminousTrialKey
+	}
+	// VaultBlockEntity update tag: the shared data the client renders from
+	if be := m.GetBlockEntity(pos.X, pos.Y, pos.Z); be != nil {
+		shared := be.Data.GetCompound("shared_data")
+		if display := shared.GetCompound("display_item"); display != nil {
+			v.DisplayItem = items.ItemID(display.GetString("id"))
+		}
+		for _, tag := range shared.GetList("connected_players").Elements {
+			if ints, ok := tag.(nbt.IntArray); ok && len(ints) == 4 {
+				v.Players = append(v.Players, uuidFromInts(ints))
+			}
+		}
+	}
+	return v, true
+}
+
+// uuidFromInts decodes a UUID stored as four big-endian ints (UUIDUtil.CODEC).
+func uuidFromInts(ints nbt.IntArray) ns.UUID {
+	var u ns.UUID
+	for i, v := range ints {
+		u[i*4] = byte(v >> 24)
+		u[i*4+1] = byte(v >> 16)
+		u[i*4+2] = byte(v >> 8)
+		u[i*4+3] = byte(v)
+	}
+	return u
+}
+
+// OnTrialEvent is called for the trial chamber level events (see
+// LevelEventTrialSpawnerSpawn and the constants after it): spawners detecting
+// players, spawning mobs, turning ominous, and vaults activating or ejecting.
+func (m *Module) OnTrialEvent(cb func(pos geom.BlockPos, event, data int32)) {
+	m.onTrialEvent = append(m.onTrialEvent, cb)
+}
+
+func isTrialEvent(event int32) bool {
+	return event >= LevelEventTrialSpawnerSpawn && event <= LevelEventTrialSpawnerSpawnItem
+}
+
+// trackSpawnerCooldown notes when a trial spawner enters cooldown.
+func (m *Module) trackSpawnerCooldown(x, y, z int, stateID int32) {
+	pos := geom.BlockPos{X: x, Y: y, Z: z}
+	blockID, props := blocks.StateProperties(int(stateID))
+	cooling := blockID == trialSpawnerBlockID && TrialSpawnerState(props["trial_spawner_state"]) == TrialSpawnerCoolingDown
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.spawnerCooldowns[pos]; ok == cooling {
+		return
+	}
+	if cooling {
+		m.spawnerCooldowns[pos] = m.client.Now()
+	} else {
+		delete(m.spawnerCooldowns, pos)
+	}
+}
+
+// forgetSpawnerCooldowns drops the cooldowns in an unloaded chunk.
+func (m *Module) forgetSpawnerCooldowns(cx, cz int32) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for pos := range m.spawnerCooldowns {
+		if pos.Chunk() == (geom.ChunkPos{X: cx, Z: cz}) {
+			delete(m.spawnerCooldowns, pos)
+		}
+	}
+}
+
+// UnlockVault uses the key held in hand (HandMain or HandOff) on the vault at
+// pos and confirms that it started unlocking. Check Vault.CanUnlock first: a
+// vault that already rewarded the player ignores the key. prepare runs before
+// each attempt, e.g. to look at the vault.
+func (m *Module) UnlockVault(pos geom.BlockPos, face geom.Face, hand int8, prepare func()) InteractionResult {
+	// aim at the middle of the clicked face
+	dx, dy, dz := face.Offset()
+	return m.Interact(Interaction{
+		Pos:     pos,
+		Face:    face,
+		Hand:    hand,
+		CursorX: 0.5 + 0.5*float32(dx), CursorY: 0.5 + 0.5*float32(dy), CursorZ: 0.5 + 0.5*float32(dz),
+		Expect: func(stateID int32) bool {
+			blockID, props := blocks.StateProperties(int(stateID))
+			return blockID == vaultBlockID && VaultState(props["vault_state"]) == VaultActive
+		},
+		Prepare: prepare,
+		// the state changes before the ack
+		Confirm: func() bool {
+			v, ok := m.GetVault(pos)
+			return ok && (v.State == VaultUnlocking || v.State == VaultEjecting)
+		},
+	})
+}
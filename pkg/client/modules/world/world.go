@@ -1,14 +1,18 @@
 package world
 
 import (
+	"encoding/json"
+	"fmt"
+	"slices"
 	"sync"
+	"time"
 
 	"github.com/go-mclib/client/pkg/client"
+	"github.com/go-mclib/client/pkg/geom"
 	"github.com/go-mclib/data/pkg/data/chunks"
 	"github.com/go-mclib/data/pkg/data/packet_ids"
 	"github.com/go-mclib/data/pkg/packets"
 	jp "github.com/go-mclib/protocol/java_protocol"
-	ns "github.com/go-mclib/protocol/java_protocol/net_structures"
 	"github.com/go-mclib/protocol/nbt"
 )
 
@@ -16,12 +20,12 @@ const ModuleName = "world"
 
 // block face constants
 const (
-	FaceBottom = 0 // -Y
-	FaceTop    = 1 // +Y
-	FaceNorth  = 2 // -Z
-	FaceSouth  = 3 // +Z
-	FaceWest   = 4 // -X
-	FaceEast   = 5 // +X
+	FaceBottom = geom.FaceBottom // -Y
+	FaceTop    = geom.FaceTop    // +Y
+	FaceNorth  = geom.FaceNorth  // -Z
+	FaceSouth  = geom.FaceSouth  // +Z
+	FaceWest   = geom.FaceWest   // -X
+	FaceEast   = geom.FaceEast   // +X
 )
 
 // hand constants
@@ -41,26 +45,115 @@ type Module struct {
 
 	mu            sync.RWMutex
 	chunks        map[int64]*chunks.ChunkColumn
-	blockEntities map[[3]int]*BlockEntityData // [x,y,z] -> data
-	centerChunkX  int32
-	centerChunkZ  int32
-	viewDistance  int32
+	light         map[int64]*lightColumn // see light.go
+	blockEntities map[geom.BlockPos]*BlockEntityData
+	records       map[geom.BlockPos]string // playing jukeboxes (see jukebox.go)
+	// when trial spawners were seen entering cooldown (see trial.go)
+	spawnerCooldowns map[geom.BlockPos]time.Time
+	centerChunkX     int32
+	centerChunkZ     int32
+	viewDistance     int32
+
+	// ProcessingRadius discards chunk columns farther than this many chunks
+	// (chebyshev distance) from the chunk cache center on arrival, and drops
+	// loaded ones that fall outside it when the center moves. 0 keeps everything.
+	ProcessingRadius int32
+	discarded        int
+
+	// MaxBlockEntities caps the stored block entities (chest contents,
+	// signs, ...): past it, the ones farthest from the player are dropped
+	// (0 = no cap). See DroppedBlockEntityCount.
+	MaxBlockEntities     int
+	droppedBlockEntities int
+
+	// chunk batch pacing (see batch.go). MaxChunksPerTick caps the rate
+	// asked of the server; HeapLimit (bytes, 0 for none) slows it down while
+	// the heap is larger. For slow or memory-constrained hosts.
+	MaxChunksPerTick float32
+	HeapLimit        uint64
+	batchMu          sync.Mutex
+	pacer            batchPacer
 
 	// border state (from S2CInitializeBorder)
 	border *packets.S2CInitializeBorder
 
+	// sign editor the server opened (see sign.go)
+	signEditor *SignEditor
+
+	// other entities' block breaking progress by entity (see breaking.go)
+	breaking map[int32]Breaking
+
+	// journal of changes in protected regions (see journal.go), kept across
+	// reconnects. JournalSize caps it (0 = no cap).
+	JournalSize int
+	protected   map[string]geom.Region
+	journal     []JournalEntry
+
+	// interact queue (see interact.go)
+	InteractRetries    int
+	InteractAckTimeout time.Duration
+	interactMu         sync.Mutex
+	ackMu              sync.Mutex
+	ackedSeq           int32
+	ackCh              chan struct{}
+	obsMu              sync.Mutex
+	observer           *observer
+
+	// blocks that may be out of date (see ghost.go)
+	SuspectTimeout time.Duration
+	suspects       map[geom.BlockPos]suspect
+	ghostStats     GhostStats
+
+	// block scanner (see scan.go). ScanBudget is the time spent scanning
+	// per tick when there's no physics tick loop to run the scanner as
+	// sliced work; ScanCacheSize caps the finished scans kept current.
+	ScanBudget    time.Duration
+	ScanCacheSize int
+	scanMu        sync.Mutex
+	scans         []*scan
+	scanSeq       int
+	scanGen       int // bumped on reset; stale scanner slices drop their column
+	scanCancel    func()
+
 	onChunkLoad         []func(x, z int32)
 	onChunkUnload       []func(x, z int32)
 	onBlockUpdate       []func(x, y, z int, stateID int32)
 	onViewDistChange    []func(distance int32)
 	onCenterChunkChange []func(x, z int32)
+	onRecordPlaying     []func(pos geom.BlockPos, disc string)
+	onRecordStopped     []func(pos geom.BlockPos)
+	onNotePlayed        []func(n Note)
+	onTrialEvent        []func(pos geom.BlockPos, event, data int32)
+	onSignEditor        []func(e SignEditor)
+	onBlockBreaking     []func(entityID int32, pos geom.BlockPos, stage int)
+	onRegionModified    []func(mod RegionModification)
 }
 
 func New() *Module {
 	return &Module{
 		chunks:        make(map[int64]*chunks.ChunkColumn),
-		blockEntities: make(map[[3]int]*BlockEntityData),
+		light:         make(map[int64]*lightColumn),
+		blockEntities: make(map[geom.BlockPos]*BlockEntityData),
+		records:       make(map[geom.BlockPos]string),
+		breaking:      make(map[int32]Breaking),
+		protected:     make(map[string]geom.Region),
+		JournalSize:   DefaultJournalSize,
 		viewDistance:  10,
+
+		spawnerCooldowns: make(map[geom.BlockPos]time.Time),
+
+		MaxChunksPerTick: DefaultMaxChunksPerTick,
+		pacer:            newBatchPacer(),
+
+		InteractRetries:    DefaultInteractRetries,
+		InteractAckTimeout: DefaultInteractAckTimeout,
+		ackCh:              make(chan struct{}),
+
+		SuspectTimeout: DefaultSuspectTimeout,
+		suspects:       make(map[geom.BlockPos]suspect),
+
+		ScanBudget:    DefaultScanBudget,
+		ScanCacheSize: DefaultScanCacheSize,
 	}
 }
 
@@ -69,23 +162,171 @@ func (m *Module) Name() string { return ModuleName }
 func (m *Module) Init(c *client.Client) {
 	m.client = c
 	c.OnTransfer(m.Reset)
+	m.OnChunkUnload(m.forgetRecords)
+	m.OnChunkUnload(m.forgetSpawnerCooldowns)
+	m.OnBlockUpdate(m.trackSpawnerCooldown)
+	m.OnBlockUpdate(m.resolveSuspect)
+	m.OnChunkLoad(m.resolveChunkSuspects)
+	m.OnChunkUnload(m.forgetSuspects)
+	m.OnBlockUpdate(m.updateScans)
+	m.OnChunkLoad(m.rescanColumn)
+	m.OnChunkUnload(m.forgetColumn)
+	m.OnChunkUnload(m.forgetLight)
+	m.OnBlockUpdate(m.endBreaking)
+	m.OnChunkUnload(m.forgetBreaking)
+}
+
+// PrepareConfig implements client.Reloadable for the "world" section. A
+// smaller processing_radius takes effect immediately.
+func (m *Module) PrepareConfig(raw json.RawMessage) (func(), error) {
+	var cfg struct {
+		InteractRetries    *int             `json:"interact_retries"`
+		InteractAckTimeout *client.Duration `json:"interact_ack_timeout"`
+		SuspectTimeout     *client.Duration `json:"suspect_timeout"`
+		ProcessingRadius   *int32           `json:"processing_radius"`
+		MaxChunksPerTick   *float32         `json:"max_chunks_per_tick"`
+		HeapLimitMB        *uint64          `json:"heap_limit_mb"`
+		ScanBudget         *client.Duration `json:"scan_budget"`
+		ScanCacheSize      *int             `json:"scan_cache_size"`
+		MaxBlockEntities   *int             `json:"max_block_entities"`
+		JournalSize        *int             `json:"journal_size"`
+		// name -> region, as geom.MarshalRegion encodes it
+		ProtectedRegions map[string]json.RawMessage `json:"protected_regions"`
+	}
+	if err := json.Unmarshal(raw, &cfg); err != nil {
+		return nil, err
+	}
+	if cfg.InteractRetries != nil && *cfg.InteractRetries < 0 {
+		return nil, fmt.Errorf("interact_retries must not be negative, got %d", *cfg.InteractRetries)
+	}
+	if cfg.InteractAckTimeout != nil && *cfg.InteractAckTimeout <= 0 {
+		return nil, fmt.Errorf("interact_ack_timeout must be positive")
+	}
+	if cfg.SuspectTimeout != nil && *cfg.SuspectTimeout <= 0 {
+		return nil, fmt.Errorf("suspect_timeout must be positive")
+	}
+	if cfg.ProcessingRadius != nil && *cfg.ProcessingRadius < 0 {
+		return nil, fmt.Errorf("processing_radius must not be negative, got %d", *cfg.ProcessingRadius)
+	}
+	if cfg.MaxChunksPerTick != nil && (*cfg.MaxChunksPerTick < minChunksPerTick || *cfg.MaxChunksPerTick > DefaultMaxChunksPerTick) {
+		return nil, fmt.Errorf("max_chunks_per_tick must be between %v and %v, got %v", minChunksPerTick, DefaultMaxChunksPerTick, *cfg.MaxChunksPerTick)
+	}
+	if cfg.ScanBudget != nil && *cfg.ScanBudget <= 0 {
+		return nil, fmt.Errorf("scan_budget must be positive")
+	}
+	if cfg.ScanCacheSize != nil && *cfg.ScanCacheSize < 0 {
+		return nil, fmt.Errorf("scan_cache_size must not be negative, got %d", *cfg.ScanCacheSize)
+	}
+	if cfg.MaxBlockEntities != nil && *cfg.MaxBlockEntities < 0 {
+		return nil, fmt.Errorf("max_block_entities must not be negative, got %d", *cfg.MaxBlockEntities)
+	}
+	if cfg.JournalSize != nil && *cfg.JournalSize < 0 {
+		return nil, fmt.Errorf("journal_size must not be negative, got %d", *cfg.JournalSize)
+	}
+	var protected map[string]geom.Region
+	if cfg.ProtectedRegions != nil {
+		protected = make(map[string]geom.Region, len(cfg.ProtectedRegions))
+		for name, raw := range cfg.ProtectedRegions {
+			r, err := geom.UnmarshalRegion(raw)
+			if err != nil {
+				return nil, fmt.Errorf("protected region %q: %w", name, err)
+			}
+			protected[name] = r
+		}
+	}
+	return func() {
+		// interactions read these between attempts; holding interactMu keeps
+		// an in-flight one consistent
+		m.interactMu.Lock()
+		if cfg.InteractRetries != nil {
+			m.InteractRetries = *cfg.InteractRetries
+		}
+		if cfg.InteractAckTimeout != nil {
+			m.InteractAckTimeout = time.Duration(*cfg.InteractAckTimeout)
+		}
+		m.interactMu.Unlock()
+
+		if cfg.SuspectTimeout != nil {
+			m.mu.Lock()
+			m.SuspectTimeout = time.Duration(*cfg.SuspectTimeout)
+			m.mu.Unlock()
+		}
+		if cfg.ProcessingRadius != nil {
+			m.mu.Lock()
+			m.ProcessingRadius = *cfg.ProcessingRadius
+			m.mu.Unlock()
+			m.dropOutsideRadius()
+		}
+		if cfg.JournalSize != nil || protected != nil {
+			m.mu.Lock()
+			if cfg.JournalSize != nil {
+				m.JournalSize = *cfg.JournalSize
+			}
+			if protected != nil {
+				m.protected = protected
+			}
+			m.mu.Unlock()
+		}
+		if cfg.MaxBlockEntities != nil {
+			center := m.playerBlock()
+			m.mu.Lock()
+			m.MaxBlockEntities = *cfg.MaxBlockEntities
+			m.dropFarBlockEntities(center)
+			m.mu.Unlock()
+		}
+
+		m.batchMu.Lock()
+		if cfg.MaxChunksPerTick != nil {
+			m.MaxChunksPerTick = *cfg.MaxChunksPerTick
+		}
+		if cfg.HeapLimitMB != nil {
+			m.HeapLimit = *cfg.HeapLimitMB << 20
+		}
+		m.batchMu.Unlock()
+
+		m.scanMu.Lock()
+		if cfg.ScanBudget != nil {
+			m.ScanBudget = time.Duration(*cfg.ScanBudget)
+		}
+		if cfg.ScanCacheSize != nil {
+			m.ScanCacheSize = *cfg.ScanCacheSize
+			m.evictScans()
+		}
+		m.scanMu.Unlock()
+	}, nil
 }
 
 // ClearChunks removes all loaded chunks and block entities.
 // Called on respawn/dimension change.
 func (m *Module) ClearChunks() {
+	m.resetScans()
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	m.chunks = make(map[int64]*chunks.ChunkColumn)
-	m.blockEntities = make(map[[3]int]*BlockEntityData)
+	m.light = make(map[int64]*lightColumn)
+	m.blockEntities = make(map[geom.BlockPos]*BlockEntityData)
+	m.suspects = make(map[geom.BlockPos]suspect)
+	m.breaking = make(map[int32]Breaking)
 }
 
 func (m *Module) Reset() {
+	m.resetScans()
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	m.chunks = make(map[int64]*chunks.ChunkColumn)
-	m.blockEntities = make(map[[3]int]*BlockEntityData)
+	m.light = make(map[int64]*lightColumn)
+	m.blockEntities = make(map[geom.BlockPos]*BlockEntityData)
+	m.records = make(map[geom.BlockPos]string)
+	m.spawnerCooldowns = make(map[geom.BlockPos]time.Time)
+	m.suspects = make(map[geom.BlockPos]suspect)
+	m.ghostStats = GhostStats{}
 	m.border = nil
+	m.signEditor = nil
+	m.breaking = make(map[int32]Breaking)
+	m.resetAcks()
+	m.batchMu.Lock()
+	m.pacer = newBatchPacer()
+	m.batchMu.Unlock()
 }
 
 // From retrieves the world module from a client.
@@ -118,6 +359,8 @@ func (m *Module) HandlePacket(pkt *jp.WirePacket) {
 	switch pkt.PacketID {
 	case packet_ids.S2CLevelChunkWithLightID:
 		m.handleChunkData(pkt)
+	case packet_ids.S2CLightUpdateID:
+		m.handleLightUpdate(pkt)
 	case packet_ids.S2CForgetLevelChunkID:
 		m.handleUnloadChunk(pkt)
 	case packet_ids.S2CBlockUpdateID:
@@ -128,16 +371,28 @@ func (m *Module) HandlePacket(pkt *jp.WirePacket) {
 		m.handleSetChunkCacheCenter(pkt)
 	case packet_ids.S2CSetChunkCacheRadiusID:
 		m.handleSetChunkCacheRadius(pkt)
+	case packet_ids.S2CChunkBatchStartID:
+		m.handleChunkBatchStart()
 	case packet_ids.S2CChunkBatchFinishedID:
-		m.handleChunkBatchFinished()
+		m.handleChunkBatchFinished(pkt)
 	case packet_ids.S2CBlockEntityDataID:
 		m.handleBlockEntityData(pkt)
 	case packet_ids.S2CInitializeBorderID:
 		m.handleInitializeBorder(pkt)
 	case packet_ids.S2CBlockChangedAckID:
-		// acknowledge block prediction — the server confirms our sequence.
-		// currently a no-op since we trust server state, but this prevents
-		// "unhandled packet" warnings in verbose mode.
+		m.handleBlockChangedAck(pkt)
+	case packet_ids.S2COpenScreenID:
+		m.observe(pkt)
+	case packet_ids.S2CBlockEventID:
+		m.observe(pkt)
+		m.handleBlockEvent(pkt)
+	case packet_ids.S2CLevelEventID:
+		m.observe(pkt)
+		m.handleLevelEvent(pkt)
+	case packet_ids.S2COpenSignEditorID:
+		m.handleOpenSignEditor(pkt)
+	case packet_ids.S2CBlockDestructionID:
+		m.handleBlockDestruction(pkt)
 	}
 }
 
@@ -148,28 +403,53 @@ func (m *Module) handleChunkData(pkt *jp.WirePacket) {
 		return
 	}
 
+	cx, cz := int32(d.ChunkX), int32(d.ChunkZ)
+	if !m.withinRadius(cx, cz) {
+		m.mu.Lock()
+		m.discarded++
+		m.mu.Unlock()
+		return
+	}
+
+	parseStart := time.Now()
 	column, err := chunks.ParseChunkColumn(int32(d.ChunkX), int32(d.ChunkZ), d.ChunkData, &d.LightData)
+	m.recordChunkParse(time.Since(parseStart))
 	if err != nil {
 		m.client.Logger.Printf("failed to parse chunk column at (%d, %d): %v", d.ChunkX, d.ChunkZ, err)
 		return
 	}
+	m.LoadColumn(column)
+}
+
+// LoadColumn stores a chunk column as if the server had sent it, replacing
+// the column at its position, and fires OnChunkLoad. The view distance isn't
+// applied. Used for synthetic worlds (see the fixtures package) and offline
+// simulation.
+func (m *Module) LoadColumn(column *chunks.ChunkColumn) {
+	cx, cz := column.X, column.Z
+	light := newLightColumn(len(column.Sections))
+	if column.Light != nil {
+		light.apply(column.Light)
+	}
 
-	cx, cz := int32(d.ChunkX), int32(d.ChunkZ)
 	key := ChunkKey(cx, cz)
+	center := m.playerBlock()
 	m.mu.Lock()
 	m.chunks[key] = column
+	m.light[key] = light
 	// store block entities from chunk data
 	for _, be := range column.BlockEntities {
 		x := int(cx)*16 + be.X()
 		y := int(be.Y)
 		z := int(cz)*16 + be.Z()
 		if c, ok := be.Data.(nbt.Compound); ok {
-			m.blockEntities[[3]int{x, y, z}] = &BlockEntityData{
+			m.blockEntities[geom.BlockPos{X: x, Y: y, Z: z}] = &BlockEntityData{
 				Type: int32(be.Type),
 				Data: c,
 			}
 		}
 	}
+	m.dropFarBlockEntities(center)
 	m.mu.Unlock()
 
 	for _, cb := range m.onChunkLoad {
@@ -185,12 +465,11 @@ func (m *Module) handleUnloadChunk(pkt *jp.WirePacket) {
 
 	cx, cz := int32(d.ChunkX), int32(d.ChunkZ)
 	key := ChunkKey(cx, cz)
-	baseX, baseZ := int(cx)*16, int(cz)*16
 	m.mu.Lock()
 	delete(m.chunks, key)
-	for key := range m.blockEntities {
-		if key[0] >= baseX && key[0] < baseX+16 && key[2] >= baseZ && key[2] < baseZ+16 {
-			delete(m.blockEntities, key)
+	for pos := range m.blockEntities {
+		if pos.Chunk() == (geom.ChunkPos{X: cx, Z: cz}) {
+			delete(m.blockEntities, pos)
 		}
 	}
 	m.mu.Unlock()
@@ -206,7 +485,8 @@ func (m *Module) handleBlockEntityData(pkt *jp.WirePacket) {
 		return
 	}
 
-	key := [3]int{d.Location.X, d.Location.Y, d.Location.Z}
+	key := geom.BlockPos{X: d.Location.X, Y: d.Location.Y, Z: d.Location.Z}
+	center := m.playerBlock()
 	m.mu.Lock()
 	if d.NbtData == nil {
 		delete(m.blockEntities, key)
@@ -215,6 +495,7 @@ func (m *Module) handleBlockEntityData(pkt *jp.WirePacket) {
 			Type: int32(d.Type),
 			Data: c,
 		}
+		m.dropFarBlockEntities(center)
 	}
 	m.mu.Unlock()
 }
@@ -232,14 +513,23 @@ func (m *Module) handleBlockUpdate(pkt *jp.WirePacket) {
 
 	m.mu.Lock()
 	chunk := m.chunks[ChunkKey(chunkX, chunkZ)]
+	var changes []JournalEntry
+	if len(m.protected) > 0 {
+		old := int32(-1)
+		if chunk != nil {
+			old = chunk.GetBlockState(bx, by, bz)
+		}
+		changes = m.noteChange(nil, geom.BlockPos{X: bx, Y: by, Z: bz}, old, stateID)
+	}
 	if chunk != nil {
 		chunk.SetBlockState(bx, by, bz, stateID)
 	}
 	// clean up stale block entity when block changes to air
 	if stateID == 0 {
-		delete(m.blockEntities, [3]int{bx, by, bz})
+		delete(m.blockEntities, geom.BlockPos{X: bx, Y: by, Z: bz})
 	}
 	m.mu.Unlock()
+	m.journalChanges(changes)
 
 	for _, cb := range m.onBlockUpdate {
 		cb(bx, by, bz, stateID)
@@ -255,26 +545,34 @@ func (m *Module) handleSectionBlocksUpdate(pkt *jp.WirePacket) {
 	sectionX, sectionY, sectionZ := chunks.DecodeSectionPosition(int64(d.ChunkSectionPosition))
 
 	m.mu.Lock()
-	chunk := m.chunks[ChunkKey(sectionX, sectionZ)]
-	if chunk != nil {
+	var section *chunks.ChunkSection
+	if chunk := m.chunks[ChunkKey(sectionX, sectionZ)]; chunk != nil {
 		sectionIndex := chunks.SectionIndex(int(sectionY) * 16)
 		if sectionIndex >= 0 && sectionIndex < len(chunk.Sections) {
-			section := chunk.Sections[sectionIndex]
+			section = chunk.Sections[sectionIndex]
+		}
+	}
+	var changes []JournalEntry
+	for _, block := range d.Blocks {
+		stateID, localX, localY, localZ := chunks.DecodeBlockEntry(int64(block))
+		pos := geom.BlockPos{X: int(sectionX)*16 + localX, Y: int(sectionY)*16 + localY, Z: int(sectionZ)*16 + localZ}
+		if len(m.protected) > 0 {
+			old := int32(-1)
 			if section != nil {
-				for _, block := range d.Blocks {
-					stateID, localX, localY, localZ := chunks.DecodeBlockEntry(int64(block))
-					section.SetBlockState(localX, localY, localZ, stateID)
-					if stateID == 0 {
-						wx := int(sectionX)*16 + localX
-						wy := int(sectionY)*16 + localY
-						wz := int(sectionZ)*16 + localZ
-						delete(m.blockEntities, [3]int{wx, wy, wz})
-					}
-				}
+				old = section.GetBlockState(localX, localY, localZ)
 			}
+			changes = m.noteChange(changes, pos, old, stateID)
+		}
+		if section == nil {
+			continue
+		}
+		section.SetBlockState(localX, localY, localZ, stateID)
+		if stateID == 0 {
+			delete(m.blockEntities, pos)
 		}
 	}
 	m.mu.Unlock()
+	m.journalChanges(changes)
 
 	for _, block := range d.Blocks {
 		stateID, localX, localY, localZ := chunks.DecodeBlockEntry(int64(block))
@@ -298,11 +596,110 @@ func (m *Module) handleSetChunkCacheCenter(pkt *jp.WirePacket) {
 	m.centerChunkZ = z
 	m.mu.Unlock()
 
+	m.dropOutsideRadius()
+
 	for _, cb := range m.onCenterChunkChange {
 		cb(x, z)
 	}
 }
 
+// withinRadius reports whether a chunk is inside ProcessingRadius.
+func (m *Module) withinRadius(cx, cz int32) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.ProcessingRadius <= 0 {
+		return true
+	}
+	dx, dz := cx-m.centerChunkX, cz-m.centerChunkZ
+	return max(dx, -dx) <= m.ProcessingRadius && max(dz, -dz) <= m.ProcessingRadius
+}
+
+// dropOutsideRadius unloads chunks that fell outside ProcessingRadius.
+func (m *Module) dropOutsideRadius() {
+	m.mu.RLock()
+	radius := m.ProcessingRadius
+	m.mu.RUnlock()
+	if radius <= 0 {
+		return
+	}
+	var dropped []geom.ChunkPos
+	for _, col := range m.GetChunks() {
+		if !m.withinRadius(col.X, col.Z) {
+			dropped = append(dropped, geom.ChunkPos{X: col.X, Z: col.Z})
+		}
+	}
+	if len(dropped) == 0 {
+		return
+	}
+
+	m.mu.Lock()
+	for _, c := range dropped {
+		delete(m.chunks, c.Key())
+	}
+	for pos := range m.blockEntities {
+		for _, c := range dropped {
+			if pos.Chunk() == c {
+				delete(m.blockEntities, pos)
+				break
+			}
+		}
+	}
+	m.mu.Unlock()
+
+	for _, c := range dropped {
+		for _, cb := range m.onChunkUnload {
+			cb(c.X, c.Z)
+		}
+	}
+}
+
+// playerBlock returns the block the player is in, or the origin without a
+// self module.
+func (m *Module) playerBlock() geom.BlockPos {
+	s, ok := m.client.Module("self").(positioner)
+	if !ok {
+		return geom.BlockPos{}
+	}
+	x, y, z := s.Position()
+	return geom.Vec3{X: x, Y: y, Z: z}.Block()
+}
+
+// dropFarBlockEntities drops the block entities farthest from center over
+// MaxBlockEntities. Must be called with mu held.
+func (m *Module) dropFarBlockEntities(center geom.BlockPos) {
+	over := len(m.blockEntities) - m.MaxBlockEntities
+	if m.MaxBlockEntities <= 0 || over <= 0 {
+		return
+	}
+	positions := make([]geom.BlockPos, 0, len(m.blockEntities))
+	for pos := range m.blockEntities {
+		positions = append(positions, pos)
+	}
+	slices.SortFunc(positions, func(a, b geom.BlockPos) int {
+		return b.ManhattanDistance(center) - a.ManhattanDistance(center)
+	})
+	for _, pos := range positions[:over] {
+		delete(m.blockEntities, pos)
+	}
+	m.droppedBlockEntities += over
+}
+
+// DroppedBlockEntityCount returns how many block entities were dropped for
+// being over MaxBlockEntities.
+func (m *Module) DroppedBlockEntityCount() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.droppedBlockEntities
+}
+
+// DiscardedChunkCount returns how many chunk columns were discarded on arrival
+// for being outside ProcessingRadius.
+func (m *Module) DiscardedChunkCount() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.discarded
+}
+
 func (m *Module) handleSetChunkCacheRadius(pkt *jp.WirePacket) {
 	var d packets.S2CSetChunkCacheRadius
 	if err := pkt.ReadInto(&d); err != nil {
@@ -318,12 +715,6 @@ func (m *Module) handleSetChunkCacheRadius(pkt *jp.WirePacket) {
 	}
 }
 
-func (m *Module) handleChunkBatchFinished() {
-	m.client.SendPacket(&packets.C2SChunkBatchReceived{
-		ChunksPerTick: ns.Float32(25.0),
-	})
-}
-
 func (m *Module) handleInitializeBorder(pkt *jp.WirePacket) {
 	var d packets.S2CInitializeBorder
 	if err := pkt.ReadInto(&d); err != nil {
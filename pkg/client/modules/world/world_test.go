@@ -0,0 +1,16 @@
+package world
+
+import (
+	"github.com/go-mclib/client/pkg/client"
+	"github.com/go-mclib/data/pkg/data/chunks"
+)
+
+// testModule returns a world module registered on an unconnected client,
+// with an empty chunk loaded at 0, 0.
+func testModule() (*Module, *chunks.ChunkColumn) {
+	m := New()
+	client.New("localhost:25565", "Bot", false).Register(m)
+	col := &chunks.ChunkColumn{}
+	m.chunks[ChunkKey(0, 0)] = col
+	return m, col
+}
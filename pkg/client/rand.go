@@ -0,0 +1,84 @@
+package client
+
+import (
+	"hash/fnv"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+// Rand is a goroutine-safe pseudo-random stream obtained from Client.Rand.
+// Use it for behavior decisions (rotation noise, timing jitter, ...) so runs
+// can be replayed with the same seed; use crypto/rand for anything secret.
+type Rand struct {
+	mu sync.Mutex
+	r  *rand.Rand
+}
+
+// Float64 returns a number in [0, 1).
+func (r *Rand) Float64() float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.r.Float64()
+}
+
+// IntN returns a number in [0, n). Panics if n <= 0.
+func (r *Rand) IntN(n int) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.r.IntN(n)
+}
+
+// NormFloat64 returns a normally distributed number (mean 0, stddev 1).
+func (r *Rand) NormFloat64() float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.r.NormFloat64()
+}
+
+// Between returns a number in [lo, hi).
+func (r *Rand) Between(lo, hi float64) float64 {
+	return lo + r.Float64()*(hi-lo)
+}
+
+// Jitter returns d scaled by a random factor in [1-frac, 1+frac).
+func (r *Rand) Jitter(d time.Duration, frac float64) time.Duration {
+	return time.Duration(float64(d) * r.Between(1-frac, 1+frac))
+}
+
+// RandSeed returns the seed all Rand streams derive from. If Seed was left at
+// 0, one is picked (and logged) on first use so the run can be reproduced.
+func (c *Client) RandSeed() uint64 {
+	c.randMu.Lock()
+	defer c.randMu.Unlock()
+	return c.randSeed()
+}
+
+func (c *Client) randSeed() uint64 {
+	if c.Seed == 0 {
+		c.Seed = rand.Uint64() | 1
+		c.Logger.Printf("random seed: %d (pass it back to reproduce this run)", c.Seed)
+	}
+	return c.Seed
+}
+
+// Rand returns the named random stream (e.g. the module name). Each stream is
+// derived from the client's seed, username and name only, so one module
+// drawing more numbers doesn't shift another module's sequence.
+func (c *Client) Rand(stream string) *Rand {
+	c.randMu.Lock()
+	defer c.randMu.Unlock()
+	if r, ok := c.randStreams[stream]; ok {
+		return r
+	}
+	h := fnv.New64a()
+	h.Write([]byte(c.Username))
+	h.Write([]byte{0})
+	h.Write([]byte(stream))
+	r := &Rand{r: rand.New(rand.NewPCG(c.randSeed(), h.Sum64()))}
+	if c.randStreams == nil {
+		c.randStreams = make(map[string]*Rand)
+	}
+	c.randStreams[stream] = r
+	return r
+}
@@ -0,0 +1,30 @@
+package client
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestRandStreamsReproducible(t *testing.T) {
+	draw := func(c *Client, stream string) []int {
+		r := c.Rand(stream)
+		out := make([]int, 5)
+		for i := range out {
+			out[i] = r.IntN(1000)
+		}
+		return out
+	}
+
+	a := &Client{Username: "Bot", Seed: 42}
+	b := &Client{Username: "Bot", Seed: 42}
+	// drawing from another stream first must not shift "afk"
+	draw(b, "physics")
+	if x, y := draw(a, "afk"), draw(b, "afk"); !slices.Equal(x, y) {
+		t.Errorf("same seed gave different sequences: %v vs %v", x, y)
+	}
+
+	other := &Client{Username: "Bot2", Seed: 42}
+	if x, y := draw(&Client{Username: "Bot", Seed: 42}, "afk"), draw(other, "afk"); slices.Equal(x, y) {
+		t.Errorf("different usernames gave the same sequence %v", x)
+	}
+}
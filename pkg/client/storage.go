@@ -0,0 +1,262 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultStorageDir is where Storage keeps its files unless StorageDir is set.
+const DefaultStorageDir = ".mclib"
+
+// DefaultStorageFlushDelay is how long Storage batches changes before
+// writing them to disk.
+const DefaultStorageFlushDelay = 5 * time.Second
+
+// Storage is a small persistent key-value store scoped to one bot on one
+// server, kept as a JSON file at <StorageDir>/<server>/<username>.json.
+// Values are stored as JSON, grouped in namespaces (usually a module or
+// behavior name). Changes are written after FlushDelay and when the client
+// disconnects or stops.
+type Storage struct {
+	FlushDelay time.Duration
+
+	path   string
+	logger func(format string, args ...any)
+
+	mu       sync.Mutex
+	loaded   bool
+	data     map[string]map[string]json.RawMessage // namespace -> key -> value
+	dirty    bool
+	timer    *time.Timer
+	onChange map[string][]func(key string, value json.RawMessage)
+}
+
+// Storage returns the bot's store for the current server. The file is named
+// after Username, which online-mode clients only learn during login: without
+// an explicit username, don't use it before OnConnect.
+func (c *Client) Storage() *Storage {
+	c.storageMu.Lock()
+	defer c.storageMu.Unlock()
+	if c.storage == nil {
+		dir := c.StorageDir
+		if dir == "" {
+			dir = DefaultStorageDir
+		}
+		c.storage = &Storage{
+			FlushDelay: DefaultStorageFlushDelay,
+			path:       filepath.Join(dir, storageFileName(c.Address), storageFileName(c.Username)+".json"),
+			logger:     c.Logger.Printf,
+			onChange:   make(map[string][]func(string, json.RawMessage)),
+		}
+	}
+	return c.storage
+}
+
+// flushStorage writes pending changes, if the store was used.
+func (c *Client) flushStorage() {
+	c.storageMu.Lock()
+	s := c.storage
+	c.storageMu.Unlock()
+	if s == nil {
+		return
+	}
+	if err := s.Flush(); err != nil {
+		c.Logger.Printf("storage: %v", err)
+	}
+}
+
+// storageFileName makes an address or username safe to use as a file name.
+func storageFileName(s string) string {
+	if s == "" {
+		return "_"
+	}
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-', r == '_':
+			return r
+		}
+		return '_'
+	}, s)
+}
+
+// Path returns the file the store is kept in.
+func (s *Storage) Path() string { return s.path }
+
+// Namespace returns the keys under name, e.g. a module's Name().
+func (s *Storage) Namespace(name string) *Bucket {
+	return &Bucket{s: s, name: name}
+}
+
+// Namespaces lists the namespaces holding at least one key.
+func (s *Storage) Namespaces() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.load()
+	names := make([]string, 0, len(s.data))
+	for name, keys := range s.data {
+		if len(keys) > 0 {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Flush writes pending changes to disk now.
+func (s *Storage) Flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+	if !s.dirty {
+		return nil
+	}
+	data, err := json.MarshalIndent(s.data, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return err
+	}
+	// write and rename, so a crash mid-write keeps the previous file
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return err
+	}
+	s.dirty = false
+	return nil
+}
+
+// load reads the file on first use. A broken file is moved aside rather than
+// overwritten. Must be called with mu held.
+func (s *Storage) load() {
+	if s.loaded {
+		return
+	}
+	s.loaded = true
+	s.data = make(map[string]map[string]json.RawMessage)
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			s.logger("storage: %v", err)
+		}
+		return
+	}
+	if err := json.Unmarshal(data, &s.data); err != nil {
+		s.logger("storage: %s is broken, starting empty: %v", s.path, err)
+		_ = os.Rename(s.path, s.path+".broken")
+		s.data = make(map[string]map[string]json.RawMessage)
+	}
+}
+
+// set stores value (nil deletes) and schedules a flush. Returns the change
+// callbacks to run, or nil if nothing changed. Must be called with mu held.
+func (s *Storage) set(namespace, key string, value json.RawMessage) []func(string, json.RawMessage) {
+	s.load()
+	keys := s.data[namespace]
+	old, exists := keys[key]
+	if value == nil {
+		if !exists {
+			return nil
+		}
+		delete(keys, key)
+	} else {
+		if exists && bytes.Equal(old, value) {
+			return nil
+		}
+		if keys == nil {
+			keys = make(map[string]json.RawMessage)
+			s.data[namespace] = keys
+		}
+		keys[key] = value
+	}
+
+	s.dirty = true
+	if s.timer == nil {
+		s.timer = time.AfterFunc(s.FlushDelay, func() {
+			if err := s.Flush(); err != nil {
+				s.logger("storage: %v", err)
+			}
+		})
+	}
+	return slices.Clone(s.onChange[namespace])
+}
+
+// Bucket is one namespace of a Storage.
+type Bucket struct {
+	s    *Storage
+	name string
+}
+
+// Get decodes the value at key into v. Returns false if the key is absent.
+func (b *Bucket) Get(key string, v any) (bool, error) {
+	b.s.mu.Lock()
+	b.s.load()
+	raw, ok := b.s.data[b.name][key]
+	b.s.mu.Unlock()
+	if !ok {
+		return false, nil
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return true, fmt.Errorf("storage %s/%s: %w", b.name, key, err)
+	}
+	return true, nil
+}
+
+// Set stores v (encoded as JSON) at key.
+func (b *Bucket) Set(key string, v any) error {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("storage %s/%s: %w", b.name, key, err)
+	}
+	b.s.mu.Lock()
+	cbs := b.s.set(b.name, key, raw)
+	b.s.mu.Unlock()
+	for _, cb := range cbs {
+		cb(key, raw)
+	}
+	return nil
+}
+
+// Delete removes key.
+func (b *Bucket) Delete(key string) {
+	b.s.mu.Lock()
+	cbs := b.s.set(b.name, key, nil)
+	b.s.mu.Unlock()
+	for _, cb := range cbs {
+		cb(key, nil)
+	}
+}
+
+// Keys returns the keys in the namespace, sorted.
+func (b *Bucket) Keys() []string {
+	b.s.mu.Lock()
+	defer b.s.mu.Unlock()
+	b.s.load()
+	keys := make([]string, 0, len(b.s.data[b.name]))
+	for k := range b.s.data[b.name] {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// OnChange is called after a key in the namespace is set to a different
+// value (value is the new JSON) or deleted (value is nil).
+func (b *Bucket) OnChange(cb func(key string, value json.RawMessage)) {
+	b.s.mu.Lock()
+	defer b.s.mu.Unlock()
+	b.s.onChange[b.name] = append(b.s.onChange[b.name], cb)
+}
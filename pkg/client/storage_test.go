@@ -0,0 +1,101 @@
+package client
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func storageClient(dir string) *Client {
+	c := New("localhost:25565", "Bot", false)
+	c.StorageDir = dir
+	return c
+}
+
+func TestStorageRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	s := storageClient(dir).Storage()
+	if want := filepath.Join(dir, "localhost_25565", "Bot.json"); s.Path() != want {
+		t.Errorf("path = %s, want %s", s.Path(), want)
+	}
+
+	type spot struct{ X, Y, Z int }
+	b := s.Namespace("waypoints")
+	if err := b.Set("home", spot{1, 64, -3}); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Set("mine", spot{40, 12, 7}); err != nil {
+		t.Fatal(err)
+	}
+	b.Delete("mine")
+	if err := s.Namespace("empty").Set("gone", 1); err != nil {
+		t.Fatal(err)
+	}
+	s.Namespace("empty").Delete("gone")
+	if err := s.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(s.Path() + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("temporary file left behind: %v", err)
+	}
+
+	// a new client reads what the first one wrote
+	s = storageClient(dir).Storage()
+	b = s.Namespace("waypoints")
+	var home spot
+	if ok, err := b.Get("home", &home); !ok || err != nil || home != (spot{1, 64, -3}) {
+		t.Errorf("Get(home) = %v, %v, %+v", ok, err, home)
+	}
+	if ok, _ := b.Get("mine", &home); ok {
+		t.Error("deleted key came back")
+	}
+	if keys := b.Keys(); !slices.Equal(keys, []string{"home"}) {
+		t.Errorf("keys = %q", keys)
+	}
+	if names := s.Namespaces(); !slices.Equal(names, []string{"waypoints"}) {
+		t.Errorf("namespaces = %q", names)
+	}
+}
+
+func TestStorageBrokenFile(t *testing.T) {
+	s := storageClient(t.TempDir()).Storage()
+	if err := os.MkdirAll(filepath.Dir(s.Path()), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(s.Path(), []byte("{not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if keys := s.Namespace("x").Keys(); len(keys) != 0 {
+		t.Errorf("keys = %q, want none", keys)
+	}
+	if _, err := os.Stat(s.Path() + ".broken"); err != nil {
+		t.Errorf("broken file not moved aside: %v", err)
+	}
+}
+
+func TestStorageOnChange(t *testing.T) {
+	s := storageClient(t.TempDir()).Storage()
+	defer s.Flush()
+	b := s.Namespace("settings")
+
+	var changes []string
+	b.OnChange(func(key string, value json.RawMessage) {
+		changes = append(changes, key+"="+string(value))
+	})
+	s.Namespace("other").OnChange(func(key string, _ json.RawMessage) {
+		t.Errorf("other namespace notified of %s", key)
+	})
+
+	_ = b.Set("radius", 8)
+	_ = b.Set("radius", 8) // unchanged
+	_ = b.Set("radius", 16)
+	b.Delete("radius")
+	b.Delete("radius") // already gone
+	b.Delete("never")
+
+	if want := []string{"radius=8", "radius=16", "radius="}; !slices.Equal(changes, want) {
+		t.Errorf("changes = %q, want %q", changes, want)
+	}
+}
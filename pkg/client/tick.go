@@ -0,0 +1,67 @@
+package client
+
+import (
+	"errors"
+	"time"
+
+	jp "github.com/go-mclib/protocol/java_protocol"
+	ns "github.com/go-mclib/protocol/java_protocol/net_structures"
+)
+
+// TickPhase is a point within a physics tick at which scheduled steps run.
+type TickPhase uint8
+
+const (
+	// TickStart runs before movement input is applied, alongside OnTick callbacks.
+	TickStart TickPhase = iota
+	// TickBeforeSend runs after movement is simulated, before the tick's input
+	// and position packets (a rotation set here goes out this tick).
+	TickBeforeSend
+	// TickAfterSend runs after the tick's input and position packets, before
+	// ClientTickEnd (the server already sees this tick's position and rotation).
+	TickAfterSend
+)
+
+// flushTimeout bounds FlushPackets in case the queue is replaced on reconnect.
+const flushTimeout = time.Second
+
+// RunOnTick schedules steps to run in order at the given phase of the next
+// physics tick and waits until they ran. Steps may use any client action;
+// packets queued before the phase are written first. Returns the first step
+// error (later steps are skipped), or an error if no physics module is registered.
+// Must not be called from a step or an OnTick callback (it would wait on itself).
+//
+// Example (look, then open a chest once the server has the new rotation):
+//
+//	s.LookAt(x+0.5, y+0.5, z+0.5)
+//	err := c.RunOnTick(client.TickAfterSend, func() error {
+//		return c.InteractBlock(x, y, z, 1, 0, 0.5, 0.5, 0.5)
+//	})
+func (c *Client) RunOnTick(phase TickPhase, steps ...func() error) error {
+	ts, ok := c.Module("physics").(TickScheduler)
+	if !ok {
+		return errors.New("physics module not registered")
+	}
+	return <-ts.Schedule(phase, steps...)
+}
+
+// FlushPackets blocks until every packet queued with SendPacket so far has
+// been written, so a following WritePacket can't overtake them.
+func (c *Client) FlushPackets() {
+	done := make(chan struct{})
+	c.SendPacket(&flushMarker{done: done})
+	select {
+	case <-done:
+	case <-time.After(flushTimeout):
+	}
+}
+
+// flushMarker is queued by FlushPackets; the queue worker closes done instead
+// of writing it.
+type flushMarker struct{ done chan struct{} }
+
+func (*flushMarker) ID() ns.VarInt                    { return -1 }
+func (*flushMarker) State() jp.State                  { return jp.StatePlay }
+func (*flushMarker) Bound() jp.Bound                  { return jp.C2S }
+func (*flushMarker) Read(buf *ns.PacketBuffer) error  { return nil }
+func (*flushMarker) Write(buf *ns.PacketBuffer) error { return nil }
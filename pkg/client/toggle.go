@@ -0,0 +1,72 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+)
+
+// SetModuleEnabled disables a registered module, or enables it again.
+// Disabled modules keep their state but see no packets, and modules that run
+// on ticks skip their own tick work, e.g. to stop fighting while trading or
+// to hold the player still during a scripted teleport: a disabled
+// pathfinding or combat module skips its OnTick callback, and disabled
+// physics skips only moving the player, so other modules' OnTick callbacks,
+// position reminders and ClientTickEnd go on. Modules implementing Toggler
+// are told, then OnModuleToggle callbacks run. Reset still reaches disabled
+// modules on reconnect, and they stay disabled. Since packets were missed,
+// state a module tracks may be out of date when it's enabled again. The
+// protocol module can't be disabled.
+func (c *Client) SetModuleEnabled(name string, enabled bool) error {
+	m := c.Module(name)
+	if m == nil {
+		return fmt.Errorf("module %q not registered", name)
+	}
+	if name == "protocol" && !enabled {
+		return errors.New("the protocol module can't be disabled")
+	}
+
+	c.toggleMu.Lock()
+	if c.disabled[name] != enabled {
+		c.toggleMu.Unlock()
+		return nil
+	}
+	disabled := maps.Clone(c.disabled)
+	if disabled == nil {
+		disabled = make(map[string]bool)
+	}
+	if enabled {
+		delete(disabled, name)
+	} else {
+		disabled[name] = true
+	}
+	c.disabled = disabled
+	c.toggleMu.Unlock()
+
+	if t, ok := m.(Toggler); ok {
+		t.SetEnabled(enabled)
+	}
+	for _, cb := range c.onToggle {
+		cb(name, enabled)
+	}
+	return nil
+}
+
+// ModuleEnabled reports whether a module is registered and not disabled.
+func (c *Client) ModuleEnabled(name string) bool {
+	return c.Module(name) != nil && !c.disabledModules()[name]
+}
+
+// OnModuleToggle is called after a module was disabled or enabled again, for
+// modules depending on it.
+func (c *Client) OnModuleToggle(cb func(name string, enabled bool)) {
+	c.onToggle = append(c.onToggle, cb)
+}
+
+// disabledModules returns the set of disabled modules. It's never modified,
+// so it can be read without the lock.
+func (c *Client) disabledModules() map[string]bool {
+	c.toggleMu.Lock()
+	defer c.toggleMu.Unlock()
+	return c.disabled
+}
@@ -0,0 +1,62 @@
+package client
+
+import (
+	"testing"
+
+	jp "github.com/go-mclib/protocol/java_protocol"
+)
+
+type toggleModule struct {
+	packets int
+	toggles []bool
+}
+
+func (*toggleModule) Name() string                  { return "toggle" }
+func (*toggleModule) Init(*Client)                  {}
+func (m *toggleModule) HandlePacket(*jp.WirePacket) { m.packets++ }
+func (*toggleModule) Reset()                        {}
+func (m *toggleModule) SetEnabled(enabled bool)     { m.toggles = append(m.toggles, enabled) }
+
+func TestSetModuleEnabled(t *testing.T) {
+	c := New("localhost:25565", "Bot", false)
+	m := &toggleModule{}
+	c.Register(m)
+	var events []bool
+	c.OnModuleToggle(func(name string, enabled bool) {
+		if name == "toggle" {
+			events = append(events, enabled)
+		}
+	})
+
+	c.dispatch(&jp.WirePacket{})
+	if err := c.SetModuleEnabled("toggle", false); err != nil {
+		t.Fatal(err)
+	}
+	if c.ModuleEnabled("toggle") {
+		t.Error("module still enabled")
+	}
+	c.dispatch(&jp.WirePacket{})
+	// disabling twice changes nothing
+	_ = c.SetModuleEnabled("toggle", false)
+	if err := c.SetModuleEnabled("toggle", true); err != nil {
+		t.Fatal(err)
+	}
+	c.dispatch(&jp.WirePacket{})
+
+	if m.packets != 2 {
+		t.Errorf("module saw %d packets, want 2 (none while disabled)", m.packets)
+	}
+	if len(m.toggles) != 2 || m.toggles[0] || !m.toggles[1] {
+		t.Errorf("SetEnabled calls = %v, want [false true]", m.toggles)
+	}
+	if len(events) != 2 || events[0] || !events[1] {
+		t.Errorf("toggle events = %v, want [false true]", events)
+	}
+	if len(c.PacketHistory()) != 3 {
+		t.Errorf("recorded %d packets, want all 3", len(c.PacketHistory()))
+	}
+
+	if err := c.SetModuleEnabled("missing", false); err == nil {
+		t.Error("disabled a module that isn't registered")
+	}
+}
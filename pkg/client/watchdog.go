@@ -0,0 +1,165 @@
+package client
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultCallbackTimeout is the default deadline for a watched callback.
+const DefaultCallbackTimeout = time.Second
+
+// Watchdog runs watched module callbacks (e.g. physics OnTick, inventory
+// OnSlotUpdate) with a deadline, so a blocking callback can't freeze the tick
+// or packet loop. A callback that overruns is logged and left running in the
+// background; further invocations of it are skipped until it returns.
+//
+// Set Client.Watchdog to enable it; with a nil Watchdog callbacks run inline.
+type Watchdog struct {
+	// Timeout is how long the calling loop waits for a callback (default: 1s).
+	Timeout time.Duration
+	// DisableAfter permanently disables a callback after this many timeouts (0 = never).
+	DisableAfter int
+
+	mu    sync.Mutex
+	stats map[string]*CallbackStats
+}
+
+// CallbackStats are the execution statistics of one watched callback.
+type CallbackStats struct {
+	Calls    int           // completed invocations
+	Timeouts int           // invocations that overran Timeout
+	Skipped  int           // invocations skipped (still running or disabled)
+	Total    time.Duration // total run time of completed invocations
+	Max      time.Duration // longest completed invocation
+	Disabled bool
+
+	running bool
+}
+
+// NewWatchdog creates a watchdog with the default timeout.
+func NewWatchdog() *Watchdog {
+	return &Watchdog{Timeout: DefaultCallbackTimeout}
+}
+
+// Stats returns a copy of the per-callback statistics, keyed by callback name
+// (e.g. "physics.OnTick#0", numbered in registration order).
+func (w *Watchdog) Stats() map[string]CallbackStats {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	out := make(map[string]CallbackStats, len(w.stats))
+	for name, st := range w.stats {
+		out[name] = *st
+	}
+	return out
+}
+
+// Enable re-enables a callback disabled after repeated timeouts.
+func (w *Watchdog) Enable(name string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if st, ok := w.stats[name]; ok {
+		st.Disabled = false
+		st.Timeouts = 0
+	}
+}
+
+// ResetStats clears all statistics (disabled callbacks are re-enabled).
+func (w *Watchdog) ResetStats() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for name, st := range w.stats {
+		w.stats[name] = &CallbackStats{running: st.running}
+	}
+}
+
+// stat returns the stats entry for name, creating it. Must be called under w.mu lock.
+func (w *Watchdog) stat(name string) *CallbackStats {
+	if w.stats == nil {
+		w.stats = make(map[string]*CallbackStats)
+	}
+	st, ok := w.stats[name]
+	if !ok {
+		st = &CallbackStats{}
+		w.stats[name] = st
+	}
+	return st
+}
+
+// run executes fn in its own goroutine and waits up to Timeout for it.
+// Returns false if fn was skipped or overran.
+func (w *Watchdog) run(c *Client, name string, fn func()) bool {
+	w.mu.Lock()
+	st := w.stat(name)
+	if st.Disabled || st.running {
+		st.Skipped++
+		w.mu.Unlock()
+		return false
+	}
+	st.running = true
+	w.mu.Unlock()
+
+	done := make(chan struct{})
+	start := time.Now()
+	go func() {
+		defer close(done)
+		fn()
+		elapsed := time.Since(start)
+		w.mu.Lock()
+		st := w.stat(name)
+		st.running = false
+		st.Calls++
+		st.Total += elapsed
+		st.Max = max(st.Max, elapsed)
+		w.mu.Unlock()
+	}()
+
+	timeout := w.Timeout
+	if timeout <= 0 {
+		timeout = DefaultCallbackTimeout
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-done:
+		return true
+	case <-timer.C:
+	}
+
+	w.mu.Lock()
+	st = w.stat(name)
+	st.Timeouts++
+	if w.DisableAfter > 0 && st.Timeouts >= w.DisableAfter {
+		st.Disabled = true
+	}
+	disabled := st.Disabled
+	w.mu.Unlock()
+
+	if disabled {
+		c.Logger.Printf("watchdog: callback %s exceeded %v, disabled", name, timeout)
+	} else {
+		c.Logger.Printf("watchdog: callback %s exceeded %v, skipping until it returns", name, timeout)
+	}
+	return false
+}
+
+// Watched returns a runner for one callback registered under name. Modules
+// call it when a callback is registered and invoke callbacks through the
+// runner; it uses Client.Watchdog if set at call time, else runs inline.
+func (c *Client) Watched(name string) func(fn func()) {
+	c.watchMu.Lock()
+	if c.watchCounts == nil {
+		c.watchCounts = make(map[string]int)
+	}
+	key := fmt.Sprintf("%s#%d", name, c.watchCounts[name])
+	c.watchCounts[name]++
+	c.watchMu.Unlock()
+
+	return func(fn func()) {
+		if w := c.Watchdog; w != nil {
+			w.run(c, key, fn)
+			return
+		}
+		fn()
+	}
+}
@@ -0,0 +1,122 @@
+package client
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+// DefaultWorkBudget is how long job slices may run per tick unless
+// WorkBudget is set: about a third of the 50ms tick, leaving the rest to
+// physics, callbacks and packet dispatch.
+const DefaultWorkBudget = 15 * time.Millisecond
+
+// Job is heavy work sliced to run on the tick thread (see Client.Submit).
+// Each call does one small, bounded piece of it (a millisecond or less) and
+// returns true once the job is finished.
+type Job func() (done bool)
+
+// WorkStats are the statistics of the sliced job scheduler.
+type WorkStats struct {
+	Ticks      int           // ticks that ran job slices
+	Slices     int           // slices run
+	Completed  int           // jobs finished
+	Deferred   int           // ticks that left work for the next tick
+	Overruns   int           // ticks whose slices ran past the budget
+	MaxOverrun time.Duration // furthest a tick's slices ran past the budget
+	Pending    int           // jobs queued now
+}
+
+// workState is the queue of sliced jobs.
+type workState struct {
+	mu    sync.Mutex
+	jobs  []*workJob
+	stats WorkStats
+}
+
+type workJob struct {
+	name      string
+	step      Job
+	cancelled bool
+}
+
+// Submit queues a heavy job, e.g. a re-path or a large scan, to run in
+// slices at the end of physics ticks: jobs take turns, a slice each, until
+// the tick's WorkBudget is spent, and the rest waits for the next tick, so
+// movement stays smooth however much work is queued. The job runs on the
+// tick thread, between ticks, and may use any module state the tick uses.
+// cancel drops the job before its next slice. Jobs only run while the
+// physics tick loop does.
+func (c *Client) Submit(name string, job Job) (cancel func()) {
+	j := &workJob{name: name, step: job}
+	c.work.mu.Lock()
+	c.work.jobs = append(c.work.jobs, j)
+	c.work.mu.Unlock()
+	return func() {
+		c.work.mu.Lock()
+		defer c.work.mu.Unlock()
+		j.cancelled = true
+		c.work.jobs = slices.DeleteFunc(c.work.jobs, func(o *workJob) bool { return o == j })
+	}
+}
+
+// RunWork runs queued job slices until the tick's budget is spent. Called
+// by the physics module at the end of every tick.
+func (c *Client) RunWork() {
+	budget := c.WorkBudget
+	if budget <= 0 {
+		budget = DefaultWorkBudget
+	}
+	start := time.Now()
+	var last string
+	ran := false
+	for time.Since(start) < budget {
+		c.work.mu.Lock()
+		if len(c.work.jobs) == 0 {
+			c.work.mu.Unlock()
+			break
+		}
+		j := c.work.jobs[0]
+		c.work.jobs = c.work.jobs[1:]
+		c.work.mu.Unlock()
+
+		done := j.step()
+		ran, last = true, j.name
+
+		c.work.mu.Lock()
+		c.work.stats.Slices++
+		if done {
+			c.work.stats.Completed++
+		} else if !j.cancelled {
+			c.work.jobs = append(c.work.jobs, j)
+		}
+		c.work.mu.Unlock()
+	}
+	if !ran {
+		return
+	}
+
+	over := time.Since(start) - budget
+	c.work.mu.Lock()
+	c.work.stats.Ticks++
+	if len(c.work.jobs) > 0 {
+		c.work.stats.Deferred++
+	}
+	if over > 0 {
+		c.work.stats.Overruns++
+		c.work.stats.MaxOverrun = max(c.work.stats.MaxOverrun, over)
+	}
+	c.work.mu.Unlock()
+	if over > 0 {
+		c.Debugf("work: slices ran %v over the %v tick budget (last: %s)", over, budget, last)
+	}
+}
+
+// WorkStats returns the statistics of the sliced job scheduler.
+func (c *Client) WorkStats() WorkStats {
+	c.work.mu.Lock()
+	defer c.work.mu.Unlock()
+	st := c.work.stats
+	st.Pending = len(c.work.jobs)
+	return st
+}
@@ -0,0 +1,49 @@
+package client
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRunWork(t *testing.T) {
+	c := New("localhost:25565", "Bot", false)
+	var order []string
+	slices := func(name string, n int) Job {
+		return func() bool {
+			order = append(order, name)
+			n--
+			return n == 0
+		}
+	}
+	c.Submit("a", slices("a", 3))
+	c.Submit("b", slices("b", 1))
+	c.Submit("c", slices("c", 5))
+
+	c.RunWork()
+	// jobs take turns until done
+	if got := len(order); got != 9 {
+		t.Fatalf("ran %d slices, want 9: %v", got, order)
+	}
+	if order[0] != "a" || order[1] != "b" || order[2] != "c" || order[3] != "a" {
+		t.Fatalf("slices not interleaved: %v", order)
+	}
+	cancel := c.Submit("d", slices("d", 2))
+	cancel()
+	c.RunWork()
+	st := c.WorkStats()
+	if len(order) != 9 || st.Completed != 3 || st.Pending != 0 || st.Ticks != 1 || st.Deferred != 0 {
+		t.Fatalf("stats %+v", st)
+	}
+
+	// over the budget: the rest waits for the next tick
+	c.WorkBudget = time.Millisecond
+	c.Submit("slow", func() bool {
+		time.Sleep(2 * time.Millisecond)
+		return false
+	})
+	c.RunWork()
+	st = c.WorkStats()
+	if st.Deferred != 1 || st.Overruns != 1 || st.Pending != 1 || st.MaxOverrun <= 0 {
+		t.Fatalf("stats %+v", st)
+	}
+}
@@ -0,0 +1,157 @@
+package fixtures
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+
+	"github.com/go-mclib/client/pkg/geom"
+)
+
+// Course is a synthetic world with a route through it: stand at Start, get
+// to Goal. Both are feet blocks.
+type Course struct {
+	Name        string
+	World       *World
+	Start, Goal geom.BlockPos
+}
+
+// Courses returns one of each course, for table-driven tests and
+// benchmarks.
+func Courses() []Course {
+	return []Course{
+		Stairs(8),
+		ParkourGap(1),
+		ParkourGap(2),
+		ParkourGap(3),
+		DoorCorridor(12, 2),
+		WaterChannel(16),
+		Cave(1, 24),
+	}
+}
+
+func stone() int32 { return State("minecraft:stone", nil) }
+
+// cuboid is geom.NewCuboid over coordinates.
+func cuboid(x1, y1, z1, x2, y2, z2 int) geom.Cuboid {
+	return geom.NewCuboid(geom.BlockPos{X: x1, Y: y1, Z: z1}, geom.BlockPos{X: x2, Y: y2, Z: z2})
+}
+
+// Stairs is a one block wide staircase of full blocks rising east, one block
+// per step, over the void: step-up moves only.
+func Stairs(steps int) Course {
+	w := NewWorld()
+	for i := 0; i <= steps; i++ {
+		w.Fill(cuboid(i, Ground-1, 0, i, Ground-1+i, 0), stone())
+	}
+	return Course{
+		Name:  "stairs_" + strconv.Itoa(steps),
+		World: w,
+		Start: geom.BlockPos{X: 0, Y: Ground, Z: 0},
+		Goal:  geom.BlockPos{X: steps, Y: Ground + steps, Z: 0},
+	}
+}
+
+// ParkourGap is two three block wide platforms gap blocks apart east to
+// west, over a pit three deep that can't be climbed out of: the only way
+// across is a (sprint) jump. Gaps up to 3 are jumpable.
+func ParkourGap(gap int) Course {
+	w := NewWorld()
+	far := 3 + gap
+	w.Fill(cuboid(-2, Ground-1, -1, 2, Ground-1, 1), stone())
+	w.Fill(cuboid(far-2, Ground-1, -1, far+2, Ground-1, 1), stone())
+	w.Fill(cuboid(3, Ground-4, -1, far-3, Ground-4, 1), stone())
+	return Course{
+		Name:  fmt.Sprintf("parkour_gap_%d", gap),
+		World: w,
+		Start: geom.BlockPos{X: 2, Y: Ground, Z: 0},
+		Goal:  geom.BlockPos{X: far, Y: Ground, Z: 0},
+	}
+}
+
+// DoorCorridor is a walled and roofed corridor, one wide and two high,
+// running length blocks east with doors closed oak doors evenly along it.
+func DoorCorridor(length, doors int) Course {
+	w := NewWorld()
+	w.Fill(cuboid(-1, Ground-1, -1, length, Ground+2, 1), stone())
+	w.Fill(cuboid(0, Ground, 0, length-1, Ground+1, 0), 0)
+	for i := 1; i <= doors; i++ {
+		x := i * length / (doors + 1)
+		door := map[string]string{"facing": "east", "hinge": "left", "open": "false", "powered": "false"}
+		door["half"] = "lower"
+		w.Set(geom.BlockPos{X: x, Y: Ground, Z: 0}, State("minecraft:oak_door", door))
+		door["half"] = "upper"
+		w.Set(geom.BlockPos{X: x, Y: Ground + 1, Z: 0}, State("minecraft:oak_door", door))
+	}
+	return Course{
+		Name:  fmt.Sprintf("door_corridor_%d_%d", length, doors),
+		World: w,
+		Start: geom.BlockPos{X: 0, Y: Ground, Z: 0},
+		Goal:  geom.BlockPos{X: length - 1, Y: Ground, Z: 0},
+	}
+}
+
+// WaterChannel is a three wide channel of water one deep, length blocks
+// long, between walls too high to leave it by. The water flows east from a
+// source at the west end, its level rising block by block as on flat
+// ground, with a new source every 8 blocks. Start is at the east end and
+// Goal at the west end: upstream.
+func WaterChannel(length int) Course {
+	w := NewWorld()
+	w.Fill(cuboid(-1, Ground-1, -2, length, Ground+2, 2), stone())
+	for x := range length {
+		level := strconv.Itoa(x % 8)
+		w.Fill(cuboid(x, Ground, -1, x, Ground, 1), State("minecraft:water", map[string]string{"level": level}))
+		w.Fill(cuboid(x, Ground+1, -1, x, Ground+2, 1), 0)
+	}
+	return Course{
+		Name:  "water_channel_" + strconv.Itoa(length),
+		World: w,
+		Start: geom.BlockPos{X: length - 1, Y: Ground, Z: 0},
+		Goal:  geom.BlockPos{X: 0, Y: Ground, Z: 0},
+	}
+}
+
+// Cave is a winding tunnel through solid stone, from Start to a Goal about
+// length blocks east. The tunnel is three wide and three high, turns
+// sideways and steps up or down by a block at random; the same seed builds
+// the same cave.
+func Cave(seed uint64, length int) Course {
+	rng := rand.New(rand.NewPCG(seed, seed))
+	var route []geom.BlockPos
+	p := geom.BlockPos{X: 0, Y: Ground, Z: 0}
+	route = append(route, p)
+	for p.X < length {
+		switch rng.IntN(4) {
+		case 0:
+			p.Z += rng.IntN(3) - 1
+		default:
+			p.X++
+		}
+		if rng.IntN(4) == 0 {
+			p.Y += rng.IntN(3) - 1
+		}
+		route = append(route, p)
+	}
+
+	w := NewWorld()
+	lo, hi := route[0], route[0]
+	for _, r := range route {
+		lo = geom.BlockPos{X: min(lo.X, r.X), Y: min(lo.Y, r.Y), Z: min(lo.Z, r.Z)}
+		hi = geom.BlockPos{X: max(hi.X, r.X), Y: max(hi.Y, r.Y), Z: max(hi.Z, r.Z)}
+	}
+	w.Fill(geom.NewCuboid(lo.Offset(-3, -3, -3), hi.Offset(3, 5, 3)), stone())
+	for _, r := range route {
+		w.Fill(cuboid(r.X-1, r.Y, r.Z-1, r.X+1, r.Y+2, r.Z+1), 0)
+	}
+	// carving a lower stretch next to a higher one can undercut it
+	start, goal := route[0], route[len(route)-1]
+	w.Set(start.Offset(0, -1, 0), stone())
+	w.Set(goal.Offset(0, -1, 0), stone())
+	return Course{
+		Name:  fmt.Sprintf("cave_%d_%d", seed, length),
+		World: w,
+		Start: start,
+		Goal:  goal,
+	}
+}
@@ -0,0 +1,120 @@
+// Package fixtures builds synthetic worlds block by block: reproducible
+// terrain for pathfinding and physics tests, benchmarks and offline
+// simulation, instead of a live server.
+//
+//	course := fixtures.Stairs(8)
+//	course.World.Load(world.From(c))
+//	start, goal := course.Start.Bottom(), course.Goal.Bottom()
+//	self.From(c).SetPosition(start.X, start.Y, start.Z)
+//	path, err := pathfinding.From(c).FindPath(goal.X, goal.Y, goal.Z)
+package fixtures
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/go-mclib/client/pkg/client/modules/world"
+	"github.com/go-mclib/client/pkg/geom"
+	"github.com/go-mclib/data/pkg/data/blocks"
+	"github.com/go-mclib/data/pkg/data/chunks"
+)
+
+// Ground is the feet Y of the courses' floors; the floor blocks are one
+// below.
+const Ground = 64
+
+// State returns the state of the named block with the given properties; the
+// ones not given keep their default. Panics on an unknown block or property
+// value, as fixtures are written by hand.
+func State(name string, props map[string]string) int32 {
+	blockID := blocks.BlockID(name)
+	if blockID < 0 {
+		panic(fmt.Sprintf("fixtures: unknown block %q", name))
+	}
+	state := blocks.DefaultStateID(blockID)
+	if len(props) == 0 {
+		return state
+	}
+	_, all := blocks.StateProperties(int(state))
+	all = maps.Clone(all)
+	maps.Copy(all, props)
+	state = blocks.StateID(int(blockID), all)
+	if state < 0 {
+		panic(fmt.Sprintf("fixtures: invalid properties %v for %s", props, name))
+	}
+	return state
+}
+
+// World is a synthetic world of chunk columns, air until set.
+type World struct {
+	columns map[geom.ChunkPos]*chunks.ChunkColumn
+}
+
+// NewWorld returns an empty world.
+func NewWorld() *World {
+	return &World{columns: make(map[geom.ChunkPos]*chunks.ChunkColumn)}
+}
+
+// Set sets the block state at pos, creating its chunk column if needed.
+func (w *World) Set(pos geom.BlockPos, state int32) {
+	cp := pos.Chunk()
+	col, ok := w.columns[cp]
+	if !ok {
+		col = &chunks.ChunkColumn{X: cp.X, Z: cp.Z}
+		w.columns[cp] = col
+	}
+	col.SetBlockState(pos.X, pos.Y, pos.Z, state)
+}
+
+// SetBlock sets the named block, in its default state, at pos.
+func (w *World) SetBlock(pos geom.BlockPos, name string) {
+	w.Set(pos, State(name, nil))
+}
+
+// Block returns the block state at pos, 0 (air) where nothing was set.
+func (w *World) Block(pos geom.BlockPos) int32 {
+	col, ok := w.columns[pos.Chunk()]
+	if !ok {
+		return 0
+	}
+	return col.GetBlockState(pos.X, pos.Y, pos.Z)
+}
+
+// Fill sets every block in r to state.
+func (w *World) Fill(r geom.Region, state int32) {
+	b := r.Bounds()
+	for x := b.Min.X; x <= b.Max.X; x++ {
+		for y := b.Min.Y; y <= b.Max.Y; y++ {
+			for z := b.Min.Z; z <= b.Max.Z; z++ {
+				if p := (geom.BlockPos{X: x, Y: y, Z: z}); r.Contains(p) {
+					w.Set(p, state)
+				}
+			}
+		}
+	}
+}
+
+// Columns returns the chunk columns by position, west to east then north to
+// south.
+func (w *World) Columns() []*chunks.ChunkColumn {
+	keys := slices.SortedFunc(maps.Keys(w.columns), func(a, b geom.ChunkPos) int {
+		if a.X != b.X {
+			return int(a.X - b.X)
+		}
+		return int(a.Z - b.Z)
+	})
+	result := make([]*chunks.ChunkColumn, len(keys))
+	for i, k := range keys {
+		result[i] = w.columns[k]
+	}
+	return result
+}
+
+// Load loads the world's chunk columns into m (see world.Module.LoadColumn).
+// The columns are shared, not copied; no light is set.
+func (w *World) Load(m *world.Module) {
+	for _, col := range w.Columns() {
+		m.LoadColumn(col)
+	}
+}
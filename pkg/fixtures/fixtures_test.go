@@ -0,0 +1,95 @@
+package fixtures
+
+import (
+	"testing"
+
+	"github.com/go-mclib/client/pkg/client"
+	"github.com/go-mclib/client/pkg/client/modules/collisions"
+	"github.com/go-mclib/client/pkg/client/modules/entities"
+	"github.com/go-mclib/client/pkg/client/modules/pathfinding"
+	"github.com/go-mclib/client/pkg/client/modules/physics"
+	"github.com/go-mclib/client/pkg/client/modules/self"
+	"github.com/go-mclib/client/pkg/client/modules/world"
+	"github.com/go-mclib/client/pkg/geom"
+)
+
+// courseClient returns a client standing at the course start.
+func courseClient(course Course) (*client.Client, *pathfinding.Module) {
+	c := client.New("localhost:25565", "Bot", false)
+	w, s, pf := world.New(), self.New(), pathfinding.New()
+	for _, m := range []client.Module{w, s, collisions.New(), entities.New(), physics.New(), pf} {
+		c.Register(m)
+	}
+	course.World.Load(w)
+	start := course.Start.Bottom()
+	s.SetPosition(start.X, start.Y, start.Z)
+	return c, pf
+}
+
+func TestWorld(t *testing.T) {
+	w := NewWorld()
+	stone := State("minecraft:stone", nil)
+	w.Fill(geom.NewCuboid(geom.BlockPos{X: -1, Y: 63, Z: -1}, geom.BlockPos{X: 16, Y: 63, Z: 0}), stone)
+	if got := len(w.Columns()); got != 6 {
+		t.Fatalf("got %d columns, want 6", got)
+	}
+	if w.Block(geom.BlockPos{X: 16, Y: 63, Z: -1}) != stone || w.Block(geom.BlockPos{X: 0, Y: 64, Z: 0}) != 0 {
+		t.Fatal("blocks not set as filled")
+	}
+
+	m := world.New()
+	client.New("localhost:25565", "Bot", false).Register(m)
+	var loaded int
+	m.OnChunkLoad(func(_, _ int32) { loaded++ })
+	w.Load(m)
+	if loaded != 6 || m.GetBlock(-1, 63, -1) != stone {
+		t.Fatalf("loaded %d columns, block %d", loaded, m.GetBlock(-1, 63, -1))
+	}
+}
+
+func TestState(t *testing.T) {
+	open := State("minecraft:oak_door", map[string]string{"open": "true"})
+	if open == State("minecraft:oak_door", nil) {
+		t.Fatal("property not applied")
+	}
+	defer func() {
+		if recover() == nil {
+			t.Fatal("no panic on unknown block")
+		}
+	}()
+	State("minecraft:no_such_block", nil)
+}
+
+func TestCoursesPathable(t *testing.T) {
+	for _, course := range Courses() {
+		t.Run(course.Name, func(t *testing.T) {
+			_, pf := courseClient(course)
+			goal := course.Goal.Bottom()
+			path, err := pf.FindPath(goal.X, goal.Y, goal.Z)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(path) == 0 {
+				t.Fatal("no path")
+			}
+			end := path[len(path)-1]
+			if (geom.BlockPos{X: end.X, Y: end.Y, Z: end.Z}) != course.Goal {
+				t.Fatalf("path ends at %d %d %d, want %v", end.X, end.Y, end.Z, course.Goal)
+			}
+		})
+	}
+}
+
+func BenchmarkFindPath(b *testing.B) {
+	for _, course := range Courses() {
+		b.Run(course.Name, func(b *testing.B) {
+			_, pf := courseClient(course)
+			goal := course.Goal.Bottom()
+			for b.Loop() {
+				if _, err := pf.FindPath(goal.X, goal.Y, goal.Z); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,227 @@
+// Package geom provides block, chunk and vector coordinate types with the
+// conversions between them, dimension heights and scaling, block faces, and
+// regions of blocks for behaviors that work on an area.
+package geom
+
+import (
+	"fmt"
+	"math"
+)
+
+// BlockPos is an integer block position.
+type BlockPos struct {
+	X, Y, Z int
+}
+
+// Offset returns the position moved by (dx, dy, dz).
+func (p BlockPos) Offset(dx, dy, dz int) BlockPos {
+	return BlockPos{p.X + dx, p.Y + dy, p.Z + dz}
+}
+
+// Neighbor returns the adjacent position on the given face.
+func (p BlockPos) Neighbor(f Face) BlockPos {
+	dx, dy, dz := f.Offset()
+	return p.Offset(dx, dy, dz)
+}
+
+// Chunk returns the chunk containing the block.
+func (p BlockPos) Chunk() ChunkPos {
+	return ChunkPos{int32(p.X >> 4), int32(p.Z >> 4)}
+}
+
+// Center returns the center of the block.
+func (p BlockPos) Center() Vec3 {
+	return Vec3{float64(p.X) + 0.5, float64(p.Y) + 0.5, float64(p.Z) + 0.5}
+}
+
+// Bottom returns the center of the block's bottom face (where an entity stands).
+func (p BlockPos) Bottom() Vec3 {
+	return Vec3{float64(p.X) + 0.5, float64(p.Y), float64(p.Z) + 0.5}
+}
+
+// ManhattanDistance returns |dx| + |dy| + |dz| to o.
+func (p BlockPos) ManhattanDistance(o BlockPos) int {
+	return abs(p.X-o.X) + abs(p.Y-o.Y) + abs(p.Z-o.Z)
+}
+
+func (p BlockPos) String() string {
+	return fmt.Sprintf("%d,%d,%d", p.X, p.Y, p.Z)
+}
+
+// ChunkPos is a chunk column position.
+type ChunkPos struct {
+	X, Z int32
+}
+
+// Key encodes the position into a single int64 map key.
+func (c ChunkPos) Key() int64 {
+	return int64(c.X)<<32 | int64(uint32(c.Z))
+}
+
+// ChunkPosFromKey decodes a Key.
+func ChunkPosFromKey(key int64) ChunkPos {
+	return ChunkPos{int32(key >> 32), int32(key)}
+}
+
+// MinBlock returns the block at the chunk's lowest X/Z corner at height y.
+func (c ChunkPos) MinBlock(y int) BlockPos {
+	return BlockPos{int(c.X) * 16, y, int(c.Z) * 16}
+}
+
+func (c ChunkPos) String() string {
+	return fmt.Sprintf("%d,%d", c.X, c.Z)
+}
+
+// Vec3 is a position or direction in world space.
+type Vec3 struct {
+	X, Y, Z float64
+}
+
+// Block returns the block containing the point.
+func (v Vec3) Block() BlockPos {
+	return BlockPos{int(math.Floor(v.X)), int(math.Floor(v.Y)), int(math.Floor(v.Z))}
+}
+
+func (v Vec3) Add(o Vec3) Vec3           { return Vec3{v.X + o.X, v.Y + o.Y, v.Z + o.Z} }
+func (v Vec3) Sub(o Vec3) Vec3           { return Vec3{v.X - o.X, v.Y - o.Y, v.Z - o.Z} }
+func (v Vec3) Scale(s float64) Vec3      { return Vec3{v.X * s, v.Y * s, v.Z * s} }
+func (v Vec3) Length() float64           { return math.Sqrt(v.X*v.X + v.Y*v.Y + v.Z*v.Z) }
+func (v Vec3) Distance(o Vec3) float64   { return v.Sub(o).Length() }
+func (v Vec3) HorizontalLength() float64 { return math.Sqrt(v.X*v.X + v.Z*v.Z) }
+
+func (v Vec3) String() string {
+	return fmt.Sprintf("%.2f,%.2f,%.2f", v.X, v.Y, v.Z)
+}
+
+// Dimension describes the height range and coordinate scale of a dimension
+// (vanilla DimensionType: min_y, height, coordinate_scale).
+type Dimension struct {
+	Name            string
+	MinY            int
+	Height          int
+	CoordinateScale float64
+}
+
+// vanilla dimensions
+var (
+	Overworld = Dimension{Name: "minecraft:overworld", MinY: -64, Height: 384, CoordinateScale: 1}
+	Nether    = Dimension{Name: "minecraft:the_nether", MinY: 0, Height: 256, CoordinateScale: 8}
+	End       = Dimension{Name: "minecraft:the_end", MinY: 0, Height: 256, CoordinateScale: 1}
+)
+
+// DimensionByName returns the vanilla dimension with the given name
+// (e.g. self.Module.DimensionName()); unknown names get overworld heights.
+func DimensionByName(name string) Dimension {
+	switch name {
+	case Nether.Name:
+		return Nether
+	case End.Name:
+		return End
+	case Overworld.Name:
+		return Overworld
+	}
+	d := Overworld
+	d.Name = name
+	return d
+}
+
+// MaxY returns the highest buildable Y (exclusive).
+func (d Dimension) MaxY() int { return d.MinY + d.Height }
+
+// SectionCount returns the number of 16-block sections per chunk column.
+func (d Dimension) SectionCount() int { return d.Height >> 4 }
+
+// SectionIndex returns the index of the section containing y, or -1 if y is
+// outside the dimension.
+func (d Dimension) SectionIndex(y int) int {
+	if y < d.MinY || y >= d.MaxY() {
+		return -1
+	}
+	return (y - d.MinY) >> 4
+}
+
+// Convert maps a horizontal position from dimension from to dimension to
+// (e.g. overworld to nether divides X/Z by 8); Y is unchanged.
+func Convert(v Vec3, from, to Dimension) Vec3 {
+	scale := from.CoordinateScale / to.CoordinateScale
+	return Vec3{v.X * scale, v.Y, v.Z * scale}
+}
+
+// ToNether converts overworld coordinates to nether coordinates.
+func ToNether(v Vec3) Vec3 { return Convert(v, Overworld, Nether) }
+
+// ToOverworld converts nether coordinates to overworld coordinates.
+func ToOverworld(v Vec3) Vec3 { return Convert(v, Nether, Overworld) }
+
+// Face is a block face / direction, numbered as in the protocol (vanilla Direction).
+type Face int8
+
+const (
+	FaceBottom Face = 0 // -Y
+	FaceTop    Face = 1 // +Y
+	FaceNorth  Face = 2 // -Z
+	FaceSouth  Face = 3 // +Z
+	FaceWest   Face = 4 // -X
+	FaceEast   Face = 5 // +X
+)
+
+// Faces lists all faces in protocol order.
+var Faces = [6]Face{FaceBottom, FaceTop, FaceNorth, FaceSouth, FaceWest, FaceEast}
+
+// HorizontalFaces lists the four horizontal faces.
+var HorizontalFaces = [4]Face{FaceNorth, FaceSouth, FaceWest, FaceEast}
+
+var faceOffsets = [6][3]int{
+	{0, -1, 0}, {0, 1, 0}, {0, 0, -1}, {0, 0, 1}, {-1, 0, 0}, {1, 0, 0},
+}
+
+var faceNames = [6]string{"bottom", "top", "north", "south", "west", "east"}
+
+// Offset returns the unit offset pointing out of the face.
+func (f Face) Offset() (dx, dy, dz int) {
+	if !f.Valid() {
+		return 0, 0, 0
+	}
+	o := faceOffsets[f]
+	return o[0], o[1], o[2]
+}
+
+// Opposite returns the face on the other side of the block.
+func (f Face) Opposite() Face { return f ^ 1 }
+
+// Valid reports whether f is one of the six faces.
+func (f Face) Valid() bool { return f >= 0 && f < 6 }
+
+func (f Face) String() string {
+	if !f.Valid() {
+		return fmt.Sprintf("Face(%d)", int8(f))
+	}
+	return faceNames[f]
+}
+
+// FaceByName returns the face for a block state "facing" value
+// ("down"/"up" are accepted for bottom/top).
+func FaceByName(name string) (Face, bool) {
+	switch name {
+	case "down", "bottom":
+		return FaceBottom, true
+	case "up", "top":
+		return FaceTop, true
+	case "north":
+		return FaceNorth, true
+	case "south":
+		return FaceSouth, true
+	case "west":
+		return FaceWest, true
+	case "east":
+		return FaceEast, true
+	}
+	return 0, false
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,81 @@
+package geom
+
+import "testing"
+
+func TestChunkPosKeyRoundtrip(t *testing.T) {
+	for _, c := range []ChunkPos{{0, 0}, {-1, -1}, {100, -100}, {2147483647, -2147483648}} {
+		if got := ChunkPosFromKey(c.Key()); got != c {
+			t.Errorf("ChunkPosFromKey(%v.Key()) = %v", c, got)
+		}
+	}
+}
+
+func TestBlockPosChunk(t *testing.T) {
+	tests := []struct {
+		pos  BlockPos
+		want ChunkPos
+	}{
+		{BlockPos{0, 64, 0}, ChunkPos{0, 0}},
+		{BlockPos{15, 0, 15}, ChunkPos{0, 0}},
+		{BlockPos{16, 0, -1}, ChunkPos{1, -1}},
+		{BlockPos{-17, 0, -16}, ChunkPos{-2, -1}},
+	}
+	for _, tt := range tests {
+		if got := tt.pos.Chunk(); got != tt.want {
+			t.Errorf("%v.Chunk() = %v, want %v", tt.pos, got, tt.want)
+		}
+	}
+}
+
+func TestSectionIndex(t *testing.T) {
+	tests := []struct {
+		dim  Dimension
+		y    int
+		want int
+	}{
+		{Overworld, -64, 0},
+		{Overworld, -49, 0},
+		{Overworld, 0, 4},
+		{Overworld, 319, 23},
+		{Overworld, 320, -1},
+		{Overworld, -65, -1},
+		{Nether, 0, 0},
+		{Nether, 255, 15},
+		{Nether, -1, -1},
+	}
+	for _, tt := range tests {
+		if got := tt.dim.SectionIndex(tt.y); got != tt.want {
+			t.Errorf("%s.SectionIndex(%d) = %d, want %d", tt.dim.Name, tt.y, got, tt.want)
+		}
+	}
+}
+
+func TestConvert(t *testing.T) {
+	v := Vec3{800, 70, -160}
+	n := ToNether(v)
+	if n != (Vec3{100, 70, -20}) {
+		t.Errorf("ToNether(%v) = %v", v, n)
+	}
+	if back := ToOverworld(n); back != v {
+		t.Errorf("ToOverworld(%v) = %v, want %v", n, back, v)
+	}
+	if e := Convert(v, Overworld, End); e != v {
+		t.Errorf("Convert to end = %v, want %v", e, v)
+	}
+}
+
+func TestFaces(t *testing.T) {
+	for _, f := range Faces {
+		dx, dy, dz := f.Offset()
+		ox, oy, oz := f.Opposite().Offset()
+		if dx != -ox || dy != -oy || dz != -oz {
+			t.Errorf("%v and %v offsets are not opposite", f, f.Opposite())
+		}
+		if got, ok := FaceByName(f.String()); !ok || got != f {
+			t.Errorf("FaceByName(%q) = %v, %v", f.String(), got, ok)
+		}
+	}
+	if p := (BlockPos{0, 0, 0}).Neighbor(FaceNorth); p != (BlockPos{0, 0, -1}) {
+		t.Errorf("north neighbor = %v", p)
+	}
+}
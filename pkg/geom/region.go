@@ -0,0 +1,228 @@
+package geom
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+)
+
+// Region is a set of blocks, such as an area to mine, build, farm or avoid.
+type Region interface {
+	// Contains reports whether the block at p is in the region.
+	Contains(p BlockPos) bool
+	// Bounds returns the inclusive bounding box of the region.
+	Bounds() Cuboid
+}
+
+// Cuboid is the box of blocks between two corners, inclusive.
+type Cuboid struct {
+	Min, Max BlockPos
+}
+
+// NewCuboid returns the cuboid spanned by two opposite corners in any order,
+// like a world edit selection.
+func NewCuboid(a, b BlockPos) Cuboid {
+	return Cuboid{
+		Min: BlockPos{min(a.X, b.X), min(a.Y, b.Y), min(a.Z, b.Z)},
+		Max: BlockPos{max(a.X, b.X), max(a.Y, b.Y), max(a.Z, b.Z)},
+	}
+}
+
+func (c Cuboid) Contains(p BlockPos) bool {
+	return p.X >= c.Min.X && p.X <= c.Max.X &&
+		p.Y >= c.Min.Y && p.Y <= c.Max.Y &&
+		p.Z >= c.Min.Z && p.Z <= c.Max.Z
+}
+
+func (c Cuboid) Bounds() Cuboid { return c }
+
+// Size returns the number of blocks along each axis.
+func (c Cuboid) Size() (dx, dy, dz int) {
+	return c.Max.X - c.Min.X + 1, c.Max.Y - c.Min.Y + 1, c.Max.Z - c.Min.Z + 1
+}
+
+// Volume returns the number of blocks in the cuboid.
+func (c Cuboid) Volume() int {
+	dx, dy, dz := c.Size()
+	if dx <= 0 || dy <= 0 || dz <= 0 {
+		return 0
+	}
+	return dx * dy * dz
+}
+
+// Sphere is the blocks whose position is within Radius of Center's.
+type Sphere struct {
+	Center BlockPos
+	Radius float64
+}
+
+func (s Sphere) Contains(p BlockPos) bool {
+	dx, dy, dz := float64(p.X-s.Center.X), float64(p.Y-s.Center.Y), float64(p.Z-s.Center.Z)
+	return dx*dx+dy*dy+dz*dz <= s.Radius*s.Radius
+}
+
+func (s Sphere) Bounds() Cuboid {
+	r := int(math.Floor(s.Radius))
+	return Cuboid{
+		Min: s.Center.Offset(-r, -r, -r),
+		Max: s.Center.Offset(r, r, r),
+	}
+}
+
+// Prism is a polygon of block columns extruded from MinY to MaxY inclusive,
+// like a world edit poly selection. Only X and Z of the points are used;
+// blocks on the polygon's edges are inside.
+type Prism struct {
+	Points     []BlockPos
+	MinY, MaxY int
+}
+
+func (p Prism) Contains(b BlockPos) bool {
+	if b.Y < p.MinY || b.Y > p.MaxY || len(p.Points) < 3 {
+		return false
+	}
+	// crossing test on block coordinates, inclusive of the edges (world
+	// edit's Polygonal2DRegion.contains)
+	inside := false
+	old := p.Points[len(p.Points)-1]
+	for _, cur := range p.Points {
+		if cur.X == b.X && cur.Z == b.Z {
+			return true
+		}
+		a, c := old, cur
+		if cur.X <= old.X {
+			a, c = cur, old
+		}
+		if a.X <= b.X && b.X <= c.X {
+			cross := int64(b.Z-a.Z)*int64(c.X-a.X) - int64(c.Z-a.Z)*int64(b.X-a.X)
+			if cross == 0 {
+				if (a.Z <= b.Z) == (b.Z <= c.Z) {
+					return true // on the edge
+				}
+			} else if cross < 0 && a.X != b.X {
+				inside = !inside
+			}
+		}
+		old = cur
+	}
+	return inside
+}
+
+func (p Prism) Bounds() Cuboid {
+	if len(p.Points) == 0 {
+		return Cuboid{Min: BlockPos{0, p.MinY, 0}, Max: BlockPos{-1, p.MaxY, -1}}
+	}
+	c := Cuboid{
+		Min: BlockPos{p.Points[0].X, p.MinY, p.Points[0].Z},
+		Max: BlockPos{p.Points[0].X, p.MaxY, p.Points[0].Z},
+	}
+	for _, pt := range p.Points[1:] {
+		c.Min.X, c.Max.X = min(c.Min.X, pt.X), max(c.Max.X, pt.X)
+		c.Min.Z, c.Max.Z = min(c.Min.Z, pt.Z), max(c.Max.Z, pt.Z)
+	}
+	return c
+}
+
+// Order is the order Blocks lists a region's blocks in.
+type Order int
+
+const (
+	// OrderBottomUp goes layer by layer from the lowest, as for building.
+	OrderBottomUp Order = iota
+	// OrderTopDown goes layer by layer from the highest, as for mining an
+	// area without undermining the blocks still to be mined.
+	OrderTopDown
+	// OrderNearest goes by distance from a point, nearest first.
+	OrderNearest
+)
+
+// Blocks lists the blocks of r in the given order. Within a layer blocks go
+// by Z, then X. from is the point OrderNearest measures from; the other
+// orders ignore it.
+func Blocks(r Region, order Order, from Vec3) []BlockPos {
+	b := r.Bounds()
+	blocks := make([]BlockPos, 0, b.Volume())
+	for y := b.Min.Y; y <= b.Max.Y; y++ {
+		for z := b.Min.Z; z <= b.Max.Z; z++ {
+			for x := b.Min.X; x <= b.Max.X; x++ {
+				if p := (BlockPos{x, y, z}); r.Contains(p) {
+					blocks = append(blocks, p)
+				}
+			}
+		}
+	}
+	switch order {
+	case OrderTopDown:
+		// reverse the layers, keeping the order within each
+		slices.SortStableFunc(blocks, func(a, b BlockPos) int { return b.Y - a.Y })
+	case OrderNearest:
+		slices.SortStableFunc(blocks, func(a, b BlockPos) int {
+			da, db := a.Center().Distance(from), b.Center().Distance(from)
+			switch {
+			case da < db:
+				return -1
+			case da > db:
+				return 1
+			}
+			return 0
+		})
+	}
+	return blocks
+}
+
+// regionJSON is the serialized form of a region, tagged with its shape.
+type regionJSON struct {
+	Type   string     `json:"type"`
+	Min    *BlockPos  `json:"min,omitempty"`
+	Max    *BlockPos  `json:"max,omitempty"`
+	Center *BlockPos  `json:"center,omitempty"`
+	Radius float64    `json:"radius,omitempty"`
+	Points []BlockPos `json:"points,omitempty"`
+	MinY   int        `json:"min_y,omitempty"`
+	MaxY   int        `json:"max_y,omitempty"`
+}
+
+// MarshalRegion encodes a Cuboid, Sphere or Prism as JSON, e.g.
+//
+//	{"type":"cuboid","min":{"X":0,"Y":60,"Z":0},"max":{"X":15,"Y":70,"Z":15}}
+func MarshalRegion(r Region) ([]byte, error) {
+	var j regionJSON
+	switch r := r.(type) {
+	case Cuboid:
+		j = regionJSON{Type: "cuboid", Min: &r.Min, Max: &r.Max}
+	case Sphere:
+		j = regionJSON{Type: "sphere", Center: &r.Center, Radius: r.Radius}
+	case Prism:
+		j = regionJSON{Type: "prism", Points: r.Points, MinY: r.MinY, MaxY: r.MaxY}
+	default:
+		return nil, fmt.Errorf("can't serialize region %T", r)
+	}
+	return json.Marshal(j)
+}
+
+// UnmarshalRegion decodes a region encoded by MarshalRegion.
+func UnmarshalRegion(data []byte) (Region, error) {
+	var j regionJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return nil, err
+	}
+	switch j.Type {
+	case "cuboid":
+		if j.Min == nil || j.Max == nil {
+			return nil, fmt.Errorf("cuboid region needs min and max")
+		}
+		return NewCuboid(*j.Min, *j.Max), nil
+	case "sphere":
+		if j.Center == nil {
+			return nil, fmt.Errorf("sphere region needs a center")
+		}
+		return Sphere{Center: *j.Center, Radius: j.Radius}, nil
+	case "prism":
+		if len(j.Points) < 3 {
+			return nil, fmt.Errorf("prism region needs at least 3 points")
+		}
+		return Prism{Points: j.Points, MinY: min(j.MinY, j.MaxY), MaxY: max(j.MinY, j.MaxY)}, nil
+	}
+	return nil, fmt.Errorf("unknown region type %q", j.Type)
+}
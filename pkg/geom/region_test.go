@@ -0,0 +1,68 @@
+package geom
+
+import "testing"
+
+func TestRegionContains(t *testing.T) {
+	cube := NewCuboid(BlockPos{2, 5, -1}, BlockPos{0, 3, 1})
+	sphere := Sphere{Center: BlockPos{0, 64, 0}, Radius: 2}
+	// an L-shaped prism
+	prism := Prism{Points: []BlockPos{{0, 0, 0}, {4, 0, 0}, {4, 0, 2}, {2, 0, 2}, {2, 0, 4}, {0, 0, 4}}, MinY: 10, MaxY: 12}
+	tests := []struct {
+		r    Region
+		p    BlockPos
+		want bool
+	}{
+		{cube, BlockPos{0, 3, -1}, true},
+		{cube, BlockPos{2, 5, 1}, true},
+		{cube, BlockPos{3, 4, 0}, false},
+		{sphere, BlockPos{2, 64, 0}, true},
+		{sphere, BlockPos{1, 65, 1}, true},
+		{sphere, BlockPos{2, 65, 0}, false},
+		{prism, BlockPos{1, 11, 1}, true},
+		{prism, BlockPos{4, 10, 1}, true},  // on an edge
+		{prism, BlockPos{2, 12, 4}, true},  // on a corner
+		{prism, BlockPos{3, 11, 3}, false}, // in the notch
+		{prism, BlockPos{1, 13, 1}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.r.Contains(tt.p); got != tt.want {
+			t.Errorf("%T.Contains(%v) = %v, want %v", tt.r, tt.p, got, tt.want)
+		}
+	}
+}
+
+func TestBlocksOrder(t *testing.T) {
+	cube := NewCuboid(BlockPos{0, 0, 0}, BlockPos{1, 1, 1})
+	up := Blocks(cube, OrderBottomUp, Vec3{})
+	if len(up) != 8 || up[0] != (BlockPos{0, 0, 0}) || up[7] != (BlockPos{1, 1, 1}) {
+		t.Errorf("bottom up: %v", up)
+	}
+	down := Blocks(cube, OrderTopDown, Vec3{})
+	if down[0] != (BlockPos{0, 1, 0}) || down[7] != (BlockPos{1, 0, 1}) {
+		t.Errorf("top down: %v", down)
+	}
+	near := Blocks(cube, OrderNearest, Vec3{X: 2, Y: 2, Z: 2})
+	if near[0] != (BlockPos{1, 1, 1}) || near[7] != (BlockPos{0, 0, 0}) {
+		t.Errorf("nearest: %v", near)
+	}
+}
+
+func TestRegionRoundtrip(t *testing.T) {
+	for _, r := range []Region{
+		NewCuboid(BlockPos{0, 60, 0}, BlockPos{15, 70, 15}),
+		Sphere{Center: BlockPos{5, 64, -5}, Radius: 3.5},
+		Prism{Points: []BlockPos{{0, 0, 0}, {8, 0, 0}, {0, 0, 8}}, MinY: -10, MaxY: 10},
+	} {
+		data, err := MarshalRegion(r)
+		if err != nil {
+			t.Fatalf("MarshalRegion(%v): %v", r, err)
+		}
+		got, err := UnmarshalRegion(data)
+		if err != nil {
+			t.Fatalf("UnmarshalRegion(%s): %v", data, err)
+		}
+		if got.Bounds() != r.Bounds() {
+			t.Errorf("roundtrip of %s: bounds %v, want %v", data, got.Bounds(), r.Bounds())
+		}
+	}
+}
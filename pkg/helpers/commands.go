@@ -0,0 +1,162 @@
+package helpers
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-mclib/client/pkg/client"
+	"github.com/go-mclib/client/pkg/client/modules/chat"
+	"github.com/go-mclib/client/pkg/client/modules/self"
+)
+
+// Gamemode IDs as sent by the server (S2CLogin, S2CGameEvent event 3).
+var Gamemodes = map[string]uint8{
+	"survival":  0,
+	"creative":  1,
+	"adventure": 2,
+	"spectator": 3,
+}
+
+// arrivalTolerance is how close a teleport must land to the requested position.
+const arrivalTolerance = 0.01
+
+// WaitForCommandResult sends a command and returns the first non-overlay
+// system chat message that arrives after it, which for vanilla commands is
+// their feedback (e.g. "Teleported Bot to Steve", "Unknown or incomplete command").
+// Unrelated system messages arriving at the same time may be mistaken for it.
+func WaitForCommandResult(c *client.Client, command string, timeout time.Duration) (string, error) {
+	ch := chat.From(c)
+	if ch == nil {
+		return "", errors.New("chat module not registered")
+	}
+
+	// one-shot callback, removed when this returns
+	result := make(chan string, 1)
+	var fired atomic.Bool
+	defer ch.OnSystemChat(func(message string, isOverlay bool) {
+		if isOverlay || !fired.CompareAndSwap(false, true) {
+			return
+		}
+		result <- message
+	})()
+
+	if err := ch.SendCommand(command); err != nil {
+		return "", fmt.Errorf("send command: %w", err)
+	}
+
+	select {
+	case msg := <-result:
+		return msg, nil
+	case <-time.After(timeout):
+		return "", fmt.Errorf("no result for /%s within %v", command, timeout)
+	}
+}
+
+// TeleportToPlayer runs /tp <player> and waits until the server moves the bot
+// (S2CPlayerPosition). Requires permission level 2.
+func TeleportToPlayer(c *client.Client, player string, timeout time.Duration) error {
+	_, err := teleport(c, "tp "+player, timeout)
+	return err
+}
+
+// TeleportTo runs /tp <x> <y> <z> and waits until the server moves the bot
+// there. Requires permission level 2.
+func TeleportTo(c *client.Client, x, y, z float64, timeout time.Duration) error {
+	pos, err := teleport(c, fmt.Sprintf("tp %.3f %.3f %.3f", x, y, z), timeout)
+	if err != nil {
+		return err
+	}
+	if math.Abs(pos[0]-x) > arrivalTolerance || math.Abs(pos[1]-y) > arrivalTolerance || math.Abs(pos[2]-z) > arrivalTolerance {
+		return fmt.Errorf("teleported to %.2f, %.2f, %.2f instead of %.2f, %.2f, %.2f", pos[0], pos[1], pos[2], x, y, z)
+	}
+	return nil
+}
+
+// teleport sends a teleport command and returns the position of the next
+// server position sync. On timeout the command feedback, if any, is included.
+func teleport(c *client.Client, command string, timeout time.Duration) ([3]float64, error) {
+	s := self.From(c)
+	ch := chat.From(c)
+	if s == nil || ch == nil {
+		return [3]float64{}, errors.New("self and chat modules required")
+	}
+
+	// one-shot callbacks, removed when this returns
+	arrived := make(chan [3]float64, 1)
+	var done atomic.Bool
+	defer s.OnPosition(func(x, y, z float64) {
+		if done.CompareAndSwap(false, true) {
+			arrived <- [3]float64{x, y, z}
+		}
+	})()
+	var feedback atomic.Value
+	defer ch.OnSystemChat(func(message string, isOverlay bool) {
+		if !isOverlay && !done.Load() && feedback.Load() == nil {
+			feedback.Store(message)
+		}
+	})()
+
+	if err := ch.SendCommand(command); err != nil {
+		return [3]float64{}, fmt.Errorf("send command: %w", err)
+	}
+
+	select {
+	case pos := <-arrived:
+		return pos, nil
+	case <-time.After(timeout):
+		if msg, ok := feedback.Load().(string); ok {
+			return [3]float64{}, fmt.Errorf("not teleported: %s", msg)
+		}
+		return [3]float64{}, fmt.Errorf("not teleported within %v", timeout)
+	}
+}
+
+// SetGamemode runs /gamemode <mode> and waits until the server confirms the
+// change, which also updates the self module's Gamemode. mode is one of the
+// Gamemodes keys. Requires permission level 2.
+func SetGamemode(c *client.Client, mode string, timeout time.Duration) error {
+	id, ok := Gamemodes[mode]
+	if !ok {
+		return fmt.Errorf("unknown gamemode %q", mode)
+	}
+	s := self.From(c)
+	ch := chat.From(c)
+	if s == nil || ch == nil {
+		return errors.New("self and chat modules required")
+	}
+	if s.Gamemode() == id {
+		return nil
+	}
+
+	// one-shot callback, removed when this returns
+	changed := make(chan struct{}, 1)
+	var done atomic.Bool
+	defer s.OnGamemodeChange(func(gamemode uint8) {
+		if gamemode == id && done.CompareAndSwap(false, true) {
+			changed <- struct{}{}
+		}
+	})()
+
+	if err := ch.SendCommand("gamemode " + mode); err != nil {
+		return fmt.Errorf("send command: %w", err)
+	}
+
+	select {
+	case <-changed:
+		return nil
+	case <-time.After(timeout):
+		return fmt.Errorf("gamemode not changed to %s within %v", mode, timeout)
+	}
+}
+
+// Spectate switches to spectator mode if needed and runs /spectate <target>,
+// returning the command feedback. Requires permission level 2.
+func Spectate(c *client.Client, target string, timeout time.Duration) (string, error) {
+	if err := SetGamemode(c, "spectator", timeout); err != nil {
+		return "", err
+	}
+	return WaitForCommandResult(c, "spectate "+target, timeout)
+}
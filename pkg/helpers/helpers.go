@@ -4,6 +4,7 @@ import (
 	"context"
 	"flag"
 	"os"
+	"time"
 
 	"github.com/go-mclib/client/pkg/client"
 	"github.com/go-mclib/client/pkg/client/modules/chat"
@@ -24,9 +25,24 @@ type Flags struct {
 	Interactive               bool
 	TreatTransferAsDisconnect bool
 	MaxReconnectAttempts      int
+	CallbackTimeout           time.Duration
+	WorkBudget                time.Duration
+	ViewDistance              int
+	PingInterval              time.Duration
+	ChunkRadius               int
+	Seed                      uint64
+	Config                    string
+	StorageDir                string
+	DumpPath                  string
 }
 
 // RegisterFlags registers the standard CLI flags on the default flag set.
+// See RegisterFlagsOn.
+func RegisterFlags(f *Flags) {
+	RegisterFlagsOn(flag.CommandLine, f)
+}
+
+// RegisterFlagsOn registers the standard CLI flags on fs (e.g. a subcommand's flag set).
 //
 //	// -s <string> (server-address:port, default: localhost:25565)
 //	// -u <string> (username, default: "" - determine from auth)
@@ -35,14 +51,32 @@ type Flags struct {
 //	// -i <bool> (interactive mode with chat input, default: false)
 //	// -d <bool> (treat server transfer packet as disconnect and reconnect, e.g. minehut sending player to lobby, default: false)
 //	// -reconnects <int> (max reconnect attempts, default: 5)
-func RegisterFlags(f *Flags) {
-	flag.StringVar(&f.Address, "s", "localhost:25565", "server address (host:port)")
-	flag.StringVar(&f.Username, "u", "", "username (offline or online)")
-	flag.BoolVar(&f.Verbose, "v", false, "verbose logging")
-	flag.BoolVar(&f.Online, "online", true, "assume online-mode server")
-	flag.BoolVar(&f.Interactive, "i", false, "enable interactive mode with chat input")
-	flag.BoolVar(&f.TreatTransferAsDisconnect, "d", false, "treat server transfer as disconnect")
-	flag.IntVar(&f.MaxReconnectAttempts, "reconnects", 5, "max reconnect attempts (-1 = infinite, 0 = none)")
+//	// -watchdog <duration> (deadline for OnTick/OnSlotUpdate callbacks, default: 0 - disabled)
+//	// -workbudget <duration> (time per tick for sliced heavy work like re-paths, default: 15ms)
+//	// -viewdist <int> (view distance requested from the server, default: 32)
+//	// -ping <duration> (measure latency this often, e.g. for combat latency compensation; not vanilla traffic, default: 0 - off)
+//	// -chunkradius <int> (discard chunks farther than this from the center, default: 0 - keep all)
+//	// -seed <uint> (seed for behavior randomness, default: 0 - random, logged on first use)
+//	// -config <string> (JSON config file, reloaded when it changes, default: "" - none)
+//	// -storage <string> (directory for persistent bot state, default: .mclib)
+//	// -dump <string> (write a state dump here on every disconnect, default: "" - none)
+func RegisterFlagsOn(fs *flag.FlagSet, f *Flags) {
+	fs.StringVar(&f.Address, "s", "localhost:25565", "server address (host:port)")
+	fs.StringVar(&f.Username, "u", "", "username (offline or online)")
+	fs.BoolVar(&f.Verbose, "v", false, "verbose logging")
+	fs.BoolVar(&f.Online, "online", true, "assume online-mode server")
+	fs.BoolVar(&f.Interactive, "i", false, "enable interactive mode with chat input")
+	fs.BoolVar(&f.TreatTransferAsDisconnect, "d", false, "treat server transfer as disconnect")
+	fs.IntVar(&f.MaxReconnectAttempts, "reconnects", 5, "max reconnect attempts (-1 = infinite, 0 = none)")
+	fs.DurationVar(&f.CallbackTimeout, "watchdog", 0, "deadline for module callbacks (0 = disabled)")
+	fs.DurationVar(&f.WorkBudget, "workbudget", client.DefaultWorkBudget, "time per tick for sliced heavy work like re-paths")
+	fs.IntVar(&f.ViewDistance, "viewdist", protocol.DefaultViewDistance, "view distance requested from the server (2-32)")
+	fs.DurationVar(&f.PingInterval, "ping", 0, "measure latency this often with ping requests, which idle vanilla clients don't send (0 = off)")
+	fs.IntVar(&f.ChunkRadius, "chunkradius", 0, "discard chunks farther than this from the center (0 = keep all)")
+	fs.Uint64Var(&f.Seed, "seed", 0, "seed for behavior randomness (0 = random)")
+	fs.StringVar(&f.Config, "config", "", "JSON config file with per-module options, reloaded when it changes")
+	fs.StringVar(&f.StorageDir, "storage", client.DefaultStorageDir, "directory for persistent bot state (per server and username)")
+	fs.StringVar(&f.DumpPath, "dump", "", "write a state dump for bug reports to this file on every disconnect (see botctl dump)")
 }
 
 // NewClient creates a client from parsed flags with default modules (protocol, self, world, chat).
@@ -53,12 +87,35 @@ func NewClient(f Flags) *client.Client {
 	c.ClientID = clientID
 	c.Interactive = f.Interactive
 	c.MaxReconnectAttempts = f.MaxReconnectAttempts
+	c.Seed = f.Seed
+	c.ConfigPath = f.Config
+	c.StorageDir = f.StorageDir
+	if f.DumpPath != "" {
+		c.OnDisconnect(func() {
+			if err := c.DumpState(f.DumpPath); err != nil {
+				c.Logger.Printf("state dump: %v", err)
+			}
+		})
+	}
+	if f.WorkBudget > 0 {
+		c.WorkBudget = f.WorkBudget
+	}
+	if f.CallbackTimeout > 0 {
+		c.Watchdog = client.NewWatchdog()
+		c.Watchdog.Timeout = f.CallbackTimeout
+	}
 
 	proto := protocol.New()
 	proto.TreatTransferAsDisconnect = f.TreatTransferAsDisconnect
+	if f.ViewDistance > 0 {
+		proto.ViewDistance = f.ViewDistance
+	}
+	proto.PingInterval = f.PingInterval
+	w := world.New()
+	w.ProcessingRadius = int32(f.ChunkRadius)
 	c.Register(proto)
 	c.Register(self.New())
-	c.Register(world.New())
+	c.Register(w)
 	c.Register(chat.New())
 	c.Register(playerlist.New())
 	c.Register(collisions.New())
@@ -67,9 +124,23 @@ func NewClient(f Flags) *client.Client {
 	return c
 }
 
-// Run connects and starts the client, logging errors.
+// configPollInterval is how often Run checks the config file for changes.
+const configPollInterval = 2 * time.Second
+
+// Run loads the config file (if any, watching it for changes), then connects
+// and starts the client, logging errors.
 func Run(c *client.Client) {
-	if err := c.ConnectAndStart(context.Background()); err != nil {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if c.ConfigPath != "" {
+		if err := c.LoadConfig(c.ConfigPath); err != nil {
+			c.Logger.Println(err)
+			return
+		}
+		go c.WatchConfig(ctx, configPollInterval)
+	}
+	if err := c.ConnectAndStart(ctx); err != nil {
 		c.Logger.Println(err)
 	}
 }
@@ -0,0 +1,49 @@
+#!/usr/bin/env bash
+# Runs a botctl subcommand against a fresh local server (compose.yaml) after
+# preparing the world for it over RCON.
+#
+#   scripts/scenario.sh <afk|chatbot|combat|pathfind|sorter> [botctl flags]
+set -euo pipefail
+cd "$(dirname "$0")/.."
+
+scenario=${1:?usage: scripts/scenario.sh <subcommand> [flags]}
+shift
+bot=Bot
+
+# the server runs the version the client speaks
+MC_VERSION=$(sed -n 's/^[[:space:]]*GameVersion[[:space:]]*=[[:space:]]*"\(.*\)".*/\1/p' pkg/client/modules/protocol/protocol.go)
+: "${MC_VERSION:?GameVersion not found in pkg/client/modules/protocol/protocol.go}"
+export MC_VERSION
+
+rcon() { docker compose exec -T server rcon-cli "$@"; }
+
+docker compose up -d server
+echo "waiting for server..."
+until rcon list >/dev/null 2>&1; do sleep 2; done
+
+docker compose run -d --name "botctl-$scenario" --rm bot \
+  "$scenario" -s server:25565 -u "$bot" -online=false "$@" >/dev/null
+trap 'docker rm -f "botctl-$scenario" >/dev/null 2>&1 || true' EXIT
+
+echo "waiting for $bot to join..."
+until rcon list | grep -q "$bot"; do sleep 1; done
+rcon op "$bot"
+
+case "$scenario" in
+combat)
+  rcon execute at "$bot" run summon minecraft:zombie "~2" "~" "~" '{NoAI:1b}'
+  rcon give "$bot" minecraft:iron_sword
+  ;;
+sorter)
+  rcon execute at "$bot" run setblock "~2" "~" "~" minecraft:chest
+  rcon execute at "$bot" run setblock "~2" "~" "~1" 'minecraft:oak_wall_sign[facing=south]{front_text:{messages:["\"filter me\"","\"\"","\"\"","\"\""]}}'
+  rcon execute at "$bot" run setblock "~-2" "~" "~" minecraft:chest
+  rcon execute at "$bot" run setblock "~-2" "~" "~1" 'minecraft:oak_wall_sign[facing=south]{front_text:{messages:["\"dirt\"","\"\"","\"\"","\"\""]}}'
+  rcon execute at "$bot" run item replace block "~2" "~" "~" container.0 with minecraft:dirt 32
+  ;;
+pathfind | chatbot)
+  echo "join localhost:25565 and talk to $bot in chat"
+  ;;
+esac
+
+docker logs -f "botctl-$scenario"